@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/starter"
+	"go.uber.org/zap"
+)
+
+var parityCmd = &cobra.Command{
+	Use:   "parity",
+	Short: "search random source vectors on both source and target milvus, report recall@k and rank overlap",
+
+	Run: func(cmd *cobra.Command, args []string) {
+
+		ctx := context.Background()
+
+		jobId := util.GenerateUUID("parity")
+		fmt.Println("jodId is ", jobId)
+
+		defer func() {
+			if _any := recover(); _any != nil {
+				handlePanic(_any, jobId)
+				return
+			}
+		}()
+		err := starter.Parity(ctx, configFile, collection, jobId)
+		if err != nil {
+			log.Error("[parity check error]", zap.Error(err))
+			return
+		}
+	},
+}
+
+func init() {
+	// ./milvus-migration parity --config=/{YourConfigFilePath}/migration.yaml
+	RootCmd.AddCommand(parityCmd)
+}
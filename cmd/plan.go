@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/starter"
+	"go.uber.org/zap"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "dry-run, print the target schemas, row counts, estimated file sizes and warnings of the migration without writing anything",
+
+	Run: func(cmd *cobra.Command, args []string) {
+
+		ctx := context.Background()
+
+		jobId := util.GenerateUUID("plan")
+		fmt.Println("jodId is ", jobId)
+
+		defer func() {
+			//plan job not registered to the job store, only print the panic
+			if _any := recover(); _any != nil {
+				fmt.Printf("Plan panic error! Job: %s , err: %v\n", jobId, _any)
+				return
+			}
+		}()
+		err := starter.Plan(ctx, configFile, collection, jobId)
+		if err != nil {
+			log.Error("[plan migration error]", zap.Error(err))
+			return
+		}
+	},
+}
+
+func init() {
+	// ./milvus-migration plan --config=/{YourConfigFilePath}/migration.yaml
+	RootCmd.AddCommand(planCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/starter"
+	"go.uber.org/zap"
+)
+
+var (
+	resumeJobId string
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "resume the failed milvus2x or es pit migration job from its checkpoint",
+
+	Run: func(cmd *cobra.Command, args []string) {
+
+		ctx := context.Background()
+
+		if resumeJobId == "" {
+			fmt.Println("resume job need --job param")
+			return
+		}
+		fmt.Println("resume jodId is ", resumeJobId)
+
+		defer func() {
+			if _any := recover(); _any != nil {
+				handlePanic(_any, resumeJobId)
+				return
+			}
+		}()
+		err := starter.Resume(ctx, configFile, collection, resumeJobId)
+		if err != nil {
+			log.Error("[resume migration error]", zap.Error(err))
+			return
+		}
+	},
+}
+
+func init() {
+	// ./milvus-migration resume --job=start-xxxx --config=/{YourConfigFilePath}/migration.yaml
+	resumeCmd.Flags().StringVarP(&resumeJobId, "job", "", "", "jobId of the migration job need to resume")
+
+	RootCmd.AddCommand(resumeCmd)
+}
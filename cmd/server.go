@@ -3,12 +3,16 @@ package cmd
 import (
 	"fmt"
 	"github.com/zilliztech/milvus-migration/server"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	port string
+	port      string
+	store     string
+	storeFile string
+	retention time.Duration
 )
 
 var serverCmd = &cobra.Command{
@@ -16,17 +20,25 @@ var serverCmd = &cobra.Command{
 	Short: "server subcommand start milvus-migration RESTAPI server.",
 
 	Run: func(cmd *cobra.Command, args []string) {
-		server, err := server.NewServer(server.Port(port))
+		server, err := server.NewServer(server.Port(port), server.Store(store, storeFile), server.Retention(retention))
 		if err != nil {
-			fmt.Errorf("fail to create migration server, %s", err.Error())
+			fmt.Printf("fail to create migration server, %s\n", err.Error())
+			return
+		}
+		err = server.Init()
+		if err != nil {
+			fmt.Printf("fail to init migration server, %s\n", err.Error())
+			return
 		}
-		server.Init()
 		server.Start()
 	},
 }
 
 func init() {
 	serverCmd.Flags().StringVarP(&port, "port", "p", "8080", "Port to listen")
+	serverCmd.Flags().StringVarP(&store, "store", "", "memory", "job store: memory or sqlite, sqlite keep the job history after restart")
+	serverCmd.Flags().StringVarP(&storeFile, "storeFile", "", "migration_jobs.db", "sqlite job store file")
+	serverCmd.Flags().DurationVarP(&retention, "retention", "", time.Hour*24*7, "keep time of the finished jobs in job store, 0 means keep forever")
 
 	RootCmd.AddCommand(serverCmd)
 }
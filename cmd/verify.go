@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/starter"
+	"go.uber.org/zap"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "verify the migrated target data with the source, by count, sampled rows and optional checksums",
+
+	Run: func(cmd *cobra.Command, args []string) {
+
+		ctx := context.Background()
+
+		jobId := util.GenerateUUID("verify")
+		fmt.Println("jodId is ", jobId)
+
+		defer func() {
+			if _any := recover(); _any != nil {
+				handlePanic(_any, jobId)
+				return
+			}
+		}()
+		err := starter.Verify(ctx, configFile, collection, jobId)
+		if err != nil {
+			log.Error("[verify migration error]", zap.Error(err))
+			return
+		}
+	},
+}
+
+func init() {
+	// ./milvus-migration verify --config=/{YourConfigFilePath}/migration.yaml
+	RootCmd.AddCommand(verifyCmd)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/zilliztech/milvus-migration/core/transform/es/convert"
 	"github.com/zilliztech/milvus-migration/core/type/estype"
 	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"strconv"
 )
 
 var LowerAlphabet = "abcdefghijklmnopqrstuvwxyz"
@@ -25,6 +26,10 @@ func VerifyESMetaCfg(metaJson *estype.MetaJSON) error {
 			idx.MilvusCfg = &milvustype.MilvusCfg{ShardNum: common.DEF_SHARD_NUM}
 		}
 
+		if len(idx.DynamicFields) > 0 && idx.MilvusCfg.CloseDynamicField {
+			return errors.New("[Verify ES Meta file] unsupportedFieldPolicy=dynamic need milvus dynamic field, but closeDynamicField=true, IndexName:" + idx.Index)
+		}
+
 		err := verifyShardNum(idx.MilvusCfg.ShardNum)
 		if err != nil {
 			return err
@@ -45,18 +50,35 @@ func VerifyESMetaCfg(metaJson *estype.MetaJSON) error {
 		}
 
 		for i, f := range idx.Fields {
-			var milvusType entity.FieldType
-			var ok bool
-			if milvusType, ok = esconvert.SupportESTypeMap[f.Type]; !ok {
-				return errors.New("[Verify ES Meta file]Index migration Field not support type: " + f.Type)
+			milvusType, _, err := esconvert.ToMilvusType(&idx.Fields[i])
+			if err != nil {
+				return errors.New("[Verify ES Meta file]Index migration Field " + err.Error())
+			}
+			if f.MaxCapacity > convert.MaxArrayCapacity {
+				return errors.New("[Verify ES Meta file]milvus array field maxCapacity cannot > " + strconv.Itoa(convert.MaxArrayCapacity))
 			}
 			if f.Type == string(esconvert.DenseVector) && f.Dims <= 0 {
 				return errors.New("[Verify ES Meta file]Index migration dense_vector type Field dims need > 0")
 			}
+			if f.Type == string(esconvert.KnnVector) && f.Dims <= 0 {
+				return errors.New("[Verify ES Meta file]Index migration knn_vector type Field dims need > 0, field: " + f.Name)
+			}
+			if milvusType == entity.FieldTypeBinaryVector && f.Dims%8 != 0 {
+				return errors.New("[Verify ES Meta file]Index migration binary knn_vector dims need be a multiple of 8, field: " + f.Name)
+			}
 			if f.MaxLen > 0 && f.MaxLen > convert.VarcharMaxLenNum {
 				return errors.New("[Verify ES Meta file]milvus field max len cannot > " + convert.VarcharMaxLen)
 			}
+			if milvusType == entity.FieldTypeSparseVector {
+				if idx.InnerVocabularies == nil {
+					idx.InnerVocabularies = make(map[string]*estype.SparseVocabulary)
+				}
+				idx.InnerVocabularies[f.Name] = estype.NewSparseVocabulary()
+			}
 			if f.PK {
+				if milvusType != entity.FieldTypeInt64 && milvusType != entity.FieldTypeVarChar {
+					return errors.New("[Verify ES Meta file]milvus pk field type only support int64 or varchar, field: " + f.Name)
+				}
 				if idx.InnerPkField != nil {
 					return errors.New("[Verify ES Meta file]milvus pk field more than one ")
 				}
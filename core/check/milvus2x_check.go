@@ -9,6 +9,7 @@ import (
 
 func VerifyMilvus2xMetaCfg(metaJson *milvus2xtype.MetaJSON) error {
 
+	targetColls := make(map[string]bool, len(metaJson.CollCfgs))
 	for _, coll := range metaJson.CollCfgs {
 
 		//if len(coll.Fields) <= 0 {
@@ -38,6 +39,16 @@ func VerifyMilvus2xMetaCfg(metaJson *milvus2xtype.MetaJSON) error {
 			}
 		}
 
+		//多个collection迁移时，target collection name不能重复
+		targetColl := coll.Collection
+		if len(coll.MilvusCfg.Collection) > 0 {
+			targetColl = coll.MilvusCfg.Collection
+		}
+		if targetColls[targetColl] {
+			return errors.New("[Verify Milvus2x Meta file] duplicate target collection name :" + targetColl)
+		}
+		targetColls[targetColl] = true
+
 		if len(coll.MilvusCfg.ConsistencyLevel) > 0 {
 			//如果存在ConsistencyLevel配置：
 			if _, ok := convert.ConsistencyLevelMap[coll.MilvusCfg.ConsistencyLevel]; !ok {
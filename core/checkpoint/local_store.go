@@ -0,0 +1,52 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/zilliztech/milvus-migration/core/data"
+	"os"
+	"sync"
+)
+
+type LocalStore struct {
+	dir  string
+	lock sync.Mutex
+}
+
+func newLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Save : write to tmp file then rename, avoid the state file broken when process killed
+func (this *LocalStore) Save(_ context.Context, ckpt *data.Checkpoint) error {
+	val, err := ckpt.Marshal()
+	if err != nil {
+		return err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	err = os.MkdirAll(this.dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	fileName := getStateFileName(this.dir, ckpt.JobId)
+	tmpFileName := fileName + ".tmp"
+	err = os.WriteFile(tmpFileName, val, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFileName, fileName)
+}
+
+func (this *LocalStore) Load(_ context.Context, jobId string) (*data.Checkpoint, error) {
+	val, err := os.ReadFile(getStateFileName(this.dir, jobId))
+	if err != nil {
+		return nil, err
+	}
+	ckpt := data.NewCheckpoint(jobId)
+	err = json.Unmarshal(val, ckpt)
+	if err != nil {
+		return nil, err
+	}
+	return ckpt, nil
+}
@@ -0,0 +1,64 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/data"
+	"github.com/zilliztech/milvus-migration/core/factory"
+	"github.com/zilliztech/milvus-migration/storage"
+	"io"
+	"sync"
+)
+
+type RemoteStore struct {
+	dir    string
+	bucket string
+	client storage.Client
+	lock   sync.Mutex
+}
+
+func newRemoteStore(cfg *config.RemoteConfig, dir string) *RemoteStore {
+	return &RemoteStore{
+		dir:    dir,
+		bucket: cfg.BucketName,
+		client: factory.GetStorageCli(cfg),
+	}
+}
+
+func (this *RemoteStore) Save(ctx context.Context, ckpt *data.Checkpoint) error {
+	val, err := ckpt.Marshal()
+	if err != nil {
+		return err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	i := storage.UploadObjectInput{
+		Bucket:    this.bucket,
+		Key:       getStateFileName(this.dir, ckpt.JobId),
+		Body:      bytes.NewReader(val),
+		WorkerNum: 1,
+		RPS:       1000,
+	}
+	return this.client.UploadObject(ctx, i)
+}
+
+func (this *RemoteStore) Load(ctx context.Context, jobId string) (*data.Checkpoint, error) {
+	i := storage.GetObjectInput{Bucket: this.bucket, Key: getStateFileName(this.dir, jobId)}
+	object, err := this.client.GetObject(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Body.Close()
+	val, err := io.ReadAll(object.Body)
+	if err != nil {
+		return nil, err
+	}
+	ckpt := data.NewCheckpoint(jobId)
+	err = json.Unmarshal(val, ckpt)
+	if err != nil {
+		return nil, err
+	}
+	return ckpt, nil
+}
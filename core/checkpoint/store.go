@@ -0,0 +1,31 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/data"
+	"path"
+)
+
+// Store : persist the job checkpoint, one state file per job
+type Store interface {
+	Save(ctx context.Context, ckpt *data.Checkpoint) error
+	Load(ctx context.Context, jobId string) (*data.Checkpoint, error)
+}
+
+func NewStore(cfg *config.CheckpointConfig) (Store, error) {
+	switch common.TargetMode(cfg.Mode) {
+	case common.T_LOCAL:
+		return newLocalStore(cfg.Dir), nil
+	case common.T_REMOTE:
+		return newRemoteStore(cfg.Remote, cfg.Dir), nil
+	default:
+		return nil, fmt.Errorf("not support [checkpoint.mode], %s", cfg.Mode)
+	}
+}
+
+func getStateFileName(dir string, jobId string) string {
+	return path.Join(dir, jobId+".json")
+}
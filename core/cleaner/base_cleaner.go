@@ -2,6 +2,7 @@ package cleaner
 
 import (
 	"fmt"
+	"github.com/zilliztech/milvus-migration/core/common"
 	"github.com/zilliztech/milvus-migration/core/config"
 )
 
@@ -16,7 +17,8 @@ type Clean interface {
 
 func NewCleaner(cfg *config.MigrationConfig, jobId string) (*Cleaner, error) {
 
-	if len(cfg.TargetMode) == 0 {
+	//files target output dir is the export result, not clean it
+	if len(cfg.TargetMode) == 0 || cfg.TargetType == string(common.T_FILES) {
 		return &Cleaner{
 			jobId:   jobId,
 			cleaner: newNoneCleaner(cfg.TargetMode),
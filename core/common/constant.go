@@ -12,6 +12,11 @@ const (
 	Milvus1x      DumpMode = "milvus1x"
 	Elasticsearch DumpMode = "elasticsearch"
 	Milvus2x      DumpMode = "milvus2x"
+	Manifest      DumpMode = "manifest" // files exported by target.type=files, read by the manifest.json
+	Pgvector      DumpMode = "pgvector" // postgres table with pgvector vector, halfvec, sparsevec columns
+	Qdrant        DumpMode = "qdrant"   // qdrant collection points read by the scroll api
+	Chroma        DumpMode = "chroma"   // chroma persist directory, read chroma.sqlite3 and hnsw segment files offline
+	Files         DumpMode = "files"    // jsonl, csv or parquet data files, the schema is declared by meta.fields
 )
 
 type SourceMode string
@@ -30,6 +35,43 @@ const (
 	T_REMOTE TargetMode = "remote"
 )
 
+type TargetType string
+
+// target type
+const (
+	T_MILVUS2X TargetType = "milvus2x" // default
+	T_FILES    TargetType = "files"    // offline export to parquet/jsonl files with manifest, not need target milvus
+)
+
+type OnExists string
+
+// target collection already exist policy
+const (
+	OnExistsFail     OnExists = "fail"     // stop the migration
+	OnExistsAppend   OnExists = "append"   // default, check the schema compatible then insert into it
+	OnExistsDrop     OnExists = "drop"     // drop it and create with the source schema
+	OnExistsTruncate OnExists = "truncate" // check the schema compatible, then recreate it with its own schema and indexes
+)
+
+type FileFormat string
+
+// dumper intermediate file format
+const (
+	FormatJSON    FileFormat = "json"    // es default
+	FormatNumpy   FileFormat = "numpy"   // milvus1x, faiss default
+	FormatParquet FileFormat = "parquet" // all bulkInsert source
+	FormatJSONL   FileFormat = "jsonl"   // files target, files source
+	FormatCSV     FileFormat = "csv"     // files source only
+)
+
+type InsertMode string
+
+// loader insert mode type
+const (
+	BulkInsert  InsertMode = "bulk"  // dump to json files, load by milvus bulkInsert
+	BatchInsert InsertMode = "batch" // write to milvus by insert/upsert directly, not need object storage
+)
+
 // ES connection auth type
 //const (
 //	Non         = "non"
@@ -57,6 +99,7 @@ var DEBUG = false
 var DUMP_SUB_TASK_NUM = 3
 var LOAD_CHECK_BULK_STATE_INTERVAL = time.Second * 10 //second
 var LOAD_CHECK_BACKLOG_INTERVAL = time.Second * 10    //second
+var JOB_STORE_FLUSH_INTERVAL = time.Second * 30       //second
 // const SUB_FILE_SIZE = 1024 * 1024 * 512 //512MB
 const SUB_FILE_SIZE = 1024 * 1024 * 300
 
@@ -23,6 +23,8 @@ type CollectionParam struct {
 	ConsistencyLevel   *entity.ConsistencyLevel
 	AutoId             bool
 	Description        string //collection description
+	CreateIndex        bool   //create index on target collection after data loaded
+	LoadData           bool   //load target collection after index created
 	// not common value
 	FileMapKey string
 }
@@ -31,4 +33,5 @@ type CollectionInfo struct {
 	Fields       []*entity.Field
 	Partitions   []*entity.Partition
 	PartitionKey string
+	Indexes      map[string]entity.Index //key: field name, val: index to create on target field
 }
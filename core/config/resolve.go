@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
 	"path/filepath"
 	"strings"
 )
@@ -18,11 +20,38 @@ func ResolveInsConfig(v *viper.Viper) (*MigrationConfig, error) {
 	}
 	//202405: milvus2x use iterator/batchInsert not need source/target mode param
 	dumpMode := common.DumpMode(dumperWorkMode)
-	if dumpMode == common.Milvus2x {
-		return assertBatchInsertMode(v, dumpMode)
+	var cfg *MigrationConfig
+	if isFilesTarget(v) {
+		cfg, err = assertFilesTargetMode(v, dumpMode)
+	} else if dumpMode == common.Milvus2x {
+		cfg, err = assertBatchInsertMode(v, dumpMode)
+	} else if dumpMode == common.Manifest {
+		cfg, err = assertManifestSourceMode(v)
+	} else if dumpMode == common.Pgvector {
+		cfg, err = assertPgvectorMode(v)
+	} else if dumpMode == common.Qdrant {
+		cfg, err = assertQdrantMode(v)
+	} else if dumpMode == common.Chroma {
+		cfg, err = assertChromaMode(v)
+	} else if dumpMode == common.Files {
+		cfg, err = assertFilesSourceMode(v)
+	} else if dumpMode == common.Elasticsearch && isBatchInsertMode(v) {
+		cfg, err = assertESBatchInsertMode(v)
 	} else {
-		return assertBulkInsertMode(v, dumpMode)
+		cfg, err = assertBulkInsertMode(v, dumpMode)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TargetMilvus2xCfg != nil {
+		cfg.TargetMilvus2xCfg.OnExists, err = resolveOnExists(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cfg.VerifyCfg = resolveVerifyConfig(v)
+	cfg.ParityCfg = resolveParityConfig(v)
+	return cfg, nil
 }
 
 func assertBulkInsertMode(v *viper.Viper, dumpMode common.DumpMode) (*MigrationConfig, error) {
@@ -107,11 +136,70 @@ func assertBulkInsertMode(v *viper.Viper, dumpMode common.DumpMode) (*MigrationC
 		if err != nil {
 			return nil, err
 		}
+		cfg.SparseVocabularyDir = resolveSparseVocabularyDir(v)
 	}
 
 	return &cfg, nil
 }
 
+func isBatchInsertMode(v *viper.Viper) bool {
+	return common.InsertMode(v.GetString("loader.insertMode")) == common.BatchInsert
+}
+
+// assertESBatchInsertMode : es hits convert to milvus columns and insert/upsert to target milvus directly,
+// not need target.mode and object storage
+func assertESBatchInsertMode(v *viper.Viper) (*MigrationConfig, error) {
+	dumpWrkLimit := v.GetInt("dumper.worker.limit")
+	if dumpWrkLimit <= 0 {
+		dumpWrkLimit = 2
+	}
+	dumpWorkCfg, err := resolveDumpWorkConfig(v, dumpWrkLimit)
+	if err != nil {
+		return nil, err
+	}
+	//concurrent batch insert writers of each index
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	sourceESConfig, err := getSourceESConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	metaCfg, err := resolveMetaConfig(v, common.Elasticsearch)
+	if err != nil {
+		return nil, err
+	}
+	cfg := MigrationConfig{
+		SourceESConfig:    sourceESConfig,
+		TargetMilvus2xCfg: resolveTargetMilvus2xConfig(v),
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWrkLimit,
+		//loader
+		LoaderWorkCfg: &LoaderWorkConfig{
+			WorkMode:   dumpWorkCfg.WorkMode,
+			InsertMode: string(common.BatchInsert),
+		},
+		LoaderWorkLimit:     loadWrkLimit,
+		MetaConfig:          metaCfg,
+		SparseVocabularyDir: resolveSparseVocabularyDir(v),
+	}
+	//pit read record the search_after of every slice, resume job continue from it
+	if sourceESConfig.ReadMode == ESReadModePIT {
+		cfg.CheckpointCfg = resolveCheckpointConfig(v)
+	}
+	return &cfg, nil
+}
+
+func resolveSparseVocabularyDir(v *viper.Viper) string {
+	dir := v.GetString("meta.sparseVocabularyDir")
+	if dir == "" {
+		dir = "vocabulary"
+	}
+	return dir
+}
+
 func assertTargetMode(v *viper.Viper) (string, error) {
 	mode := v.GetString("target.mode")
 	switch common.TargetMode(mode) {
@@ -226,6 +314,30 @@ func resolveTargetMilvus2xConfig(v *viper.Viper) *Milvus2xConfig {
 	}
 }
 
+// resolveOnExists : default append, keep insert into the exist collection
+func resolveOnExists(v *viper.Viper) (string, error) {
+	onExists := common.OnExists(strings.ToLower(v.GetString("target.onExists")))
+	switch onExists {
+	case "":
+		return string(common.OnExistsAppend), nil
+	case common.OnExistsFail, common.OnExistsAppend, common.OnExistsDrop, common.OnExistsTruncate:
+		return string(onExists), nil
+	default:
+		return "", fmt.Errorf("[target.onExists] not support %s, only support fail, append, drop or truncate", onExists)
+	}
+}
+
+// ForceResumeAppend : resume insert into the collections created by the failed job, fail can never succeed,
+// drop or truncate will lose the migrated rows, so resume always append into the exist collection
+func ForceResumeAppend(cfg *MigrationConfig) {
+	if cfg.TargetMilvus2xCfg == nil || cfg.TargetMilvus2xCfg.OnExists == string(common.OnExistsAppend) {
+		return
+	}
+	log.Warn("[Config] resume job will append into the exist collection, ignore target.onExists",
+		zap.String("onExists", cfg.TargetMilvus2xCfg.OnExists))
+	cfg.TargetMilvus2xCfg.OnExists = string(common.OnExistsAppend)
+}
+
 func resolveDumpWorkConfig(v *viper.Viper, limit int) (*DumperWorkConfig, error) {
 	workMode, err := resolveWorkMode(v)
 	if err != nil {
@@ -235,14 +347,36 @@ func resolveDumpWorkConfig(v *viper.Viper, limit int) (*DumperWorkConfig, error)
 	if writerBufferSize <= 0 {
 		writerBufferSize = 1048576
 	}
+	fileFormat, err := resolveFileFormat(v, common.DumpMode(workMode))
+	if err != nil {
+		return nil, err
+	}
 	return &DumperWorkConfig{
 		WorkMode:         workMode,
 		Limit:            limit,
 		ReaderBufferSize: v.GetInt("dumper.worker.reader.bufferSize"),
 		WriterBufferSize: writerBufferSize,
+		FileFormat:       fileFormat,
 	}, nil
 }
 
+// resolveFileFormat : es default json, milvus1x&faiss default numpy, all of them support parquet
+func resolveFileFormat(v *viper.Viper, workMode common.DumpMode) (string, error) {
+	defFormat := common.FormatNumpy
+	if workMode == common.Elasticsearch {
+		defFormat = common.FormatJSON
+	}
+	format := common.FileFormat(v.GetString("dumper.worker.fileFormat"))
+	switch format {
+	case "":
+		return string(defFormat), nil
+	case defFormat, common.FormatParquet:
+		return string(format), nil
+	default:
+		return "", fmt.Errorf("[dumper.worker.fileFormat] %s not support %s", workMode, format)
+	}
+}
+
 func resolveLoadWorkConfig(v *viper.Viper) (*LoaderWorkConfig, error) {
 	workMode, err := resolveWorkMode(v)
 	if err != nil {
@@ -254,9 +388,16 @@ func resolveLoadWorkConfig(v *viper.Viper) (*LoaderWorkConfig, error) {
 		return nil, err
 	}
 
+	fieldIndexes, err := resolveFieldIndexCfgs(v)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LoaderWorkConfig{
 		WorkMode:     workMode,
 		CreateColCfg: *createCol,
+		MilvusCfg:    resolveMilvusCfg(v),
+		FieldIndexes: fieldIndexes,
 	}, nil
 }
 
@@ -297,7 +438,7 @@ func resolveWorkMode(v *viper.Viper) (string, error) {
 	workMode := v.GetString("dumper.worker.workMode")
 
 	switch common.DumpMode(workMode) {
-	case common.Faiss, common.Milvus1x, common.Elasticsearch, common.Milvus2x:
+	case common.Faiss, common.Milvus1x, common.Elasticsearch, common.Milvus2x, common.Manifest, common.Pgvector, common.Qdrant, common.Chroma, common.Files:
 		break
 	default:
 		return "", errors.New("[dumper.worker.workMode] not support " + workMode)
@@ -314,6 +455,11 @@ func assertBatchInsertMode(v *viper.Viper, dumpMode common.DumpMode) (*Migration
 	loadWorkCfg := &LoaderWorkConfig{
 		WorkMode: dumpWorkCfg.WorkMode,
 	}
+	//concurrent batch insert writers of each collection
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
 	metaCfg, err := resolveMetaConfig(v, common.Milvus2x)
 	if err != nil {
 		return nil, err
@@ -326,12 +472,77 @@ func assertBatchInsertMode(v *viper.Viper, dumpMode common.DumpMode) (*Migration
 		DumperWorkLimit: dumpWorkCfg.Limit,
 		//loader
 		LoaderWorkCfg:   loadWorkCfg,
-		LoaderWorkLimit: dumpWorkCfg.Limit,
+		LoaderWorkLimit: loadWrkLimit,
 		MetaConfig:      metaCfg,
+		CheckpointCfg:   resolveCheckpointConfig(v),
 	}
 	return &cfg, nil
 }
 
+// resolveVerifyConfig : default sample 100 pks, vector tolerance 1e-6
+func resolveVerifyConfig(v *viper.Viper) *VerifyConfig {
+	sampleSize := v.GetInt("verify.sampleSize")
+	if sampleSize <= 0 {
+		sampleSize = 100
+	}
+	tolerance := v.GetFloat64("verify.tolerance")
+	if tolerance <= 0 {
+		tolerance = 1e-6
+	}
+	return &VerifyConfig{
+		Enable:     v.GetBool("verify.enable"),
+		SampleSize: sampleSize,
+		Tolerance:  tolerance,
+		Checksum:   v.GetBool("verify.checksum"),
+		ReportFile: v.GetString("verify.reportFile"),
+	}
+}
+
+// resolveParityConfig : default 20 queries, top 10, min recall 0.9
+func resolveParityConfig(v *viper.Viper) *ParityConfig {
+	queries := v.GetInt("parity.queries")
+	if queries <= 0 {
+		queries = 20
+	}
+	topK := v.GetInt("parity.topK")
+	if topK <= 0 {
+		topK = 10
+	}
+	minRecall := 0.9
+	if v.IsSet("parity.minRecall") {
+		minRecall = v.GetFloat64("parity.minRecall")
+	}
+	return &ParityConfig{
+		Queries:      queries,
+		TopK:         topK,
+		MinRecall:    minRecall,
+		VectorField:  v.GetString("parity.vectorField"),
+		SearchParams: v.GetStringMap("parity.searchParams"),
+		ReportFile:   v.GetString("parity.reportFile"),
+	}
+}
+
+// resolveCheckpointConfig : checkpoint file default store in local 'checkpoint' dir, remote mode use target.remote config
+func resolveCheckpointConfig(v *viper.Viper) *CheckpointConfig {
+	mode := v.GetString("checkpoint.mode")
+	if mode == "" {
+		mode = string(common.T_LOCAL)
+	}
+	dir := v.GetString("checkpoint.dir")
+	if dir == "" {
+		dir = "checkpoint"
+	}
+	ckptCfg := &CheckpointConfig{
+		Mode: mode,
+		Dir:  dir,
+	}
+	if common.TargetMode(mode) == common.T_REMOTE {
+		ckptCfg.Dir = strings.TrimPrefix(dir, "/")
+		ckptCfg.Remote = resolveTargetRemoteConfig(v)
+	}
+	return ckptCfg
+}
+
 func resolveMilvus2xDumpWorkConfig(v *viper.Viper, workMode common.DumpMode) (*DumperWorkConfig, error) {
 	dumpWrkLimit := v.GetInt("dumper.worker.limit")
 	if dumpWrkLimit <= 0 {
@@ -345,10 +556,15 @@ func resolveMilvus2xDumpWorkConfig(v *viper.Viper, workMode common.DumpMode) (*D
 	if readerBufferSize <= 0 {
 		writerBufferSize = 1000
 	}
+	var readerParallel = v.GetInt("dumper.worker.reader.parallel")
+	if readerParallel <= 0 {
+		readerParallel = 1
+	}
 	return &DumperWorkConfig{
 		WorkMode:         string(workMode),
 		ReaderBufferSize: readerBufferSize,
 		WriterBufferSize: writerBufferSize,
+		ReaderParallel:   readerParallel,
 		Limit:            dumpWrkLimit,
 	}, nil
 }
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"github.com/spf13/viper"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+)
+
+// assertChromaMode : read the chroma persist directory offline and batch insert to target milvus directly,
+// remote persist directory will be downloaded to a local temp dir
+func assertChromaMode(v *viper.Viper) (*MigrationConfig, error) {
+	sourceMode, err := assertSourceMode(v, common.Chroma)
+	if err != nil {
+		return nil, err
+	}
+	persistDir := v.GetString("source." + sourceMode + ".persistDir")
+	if persistDir == "" {
+		return nil, errors.New("[source." + sourceMode + ".persistDir] can not empty")
+	}
+	dumpWorkCfg, err := resolveMilvus2xDumpWorkConfig(v, common.Chroma)
+	if err != nil {
+		return nil, err
+	}
+	//concurrent batch insert writers of each collection
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	metaCfg, err := resolveMetaConfig(v, common.Chroma)
+	if err != nil {
+		return nil, err
+	}
+	cfg := MigrationConfig{
+		SourceMode:        sourceMode,
+		SourceRemote:      resolveSourceRemoteConfig(v),
+		SourceChromaDir:   persistDir,
+		TargetMilvus2xCfg: resolveTargetMilvus2xConfig(v),
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWorkCfg.Limit,
+		//loader
+		LoaderWorkCfg: &LoaderWorkConfig{
+			WorkMode:   dumpWorkCfg.WorkMode,
+			InsertMode: string(common.BatchInsert),
+		},
+		LoaderWorkLimit: loadWrkLimit,
+		MetaConfig:      metaCfg,
+	}
+	return &cfg, nil
+}
+
+// resolveChromaMeta : meta.collection for single collection, or meta.collections list, empty means all collections
+// of the persist directory. meta.idMaxLen, meta.documentMaxLen and meta.milvus as the default value of each collection
+func resolveChromaMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
+	base := &chromatype.CollectionCfg{
+		Collection:     v.GetString("meta.collection"),
+		IdMaxLen:       v.GetInt("meta.idMaxLen"),
+		DocumentMaxLen: v.GetInt("meta.documentMaxLen"),
+		MilvusCfg:      resolveMilvusCfg(v),
+	}
+	if base.MilvusCfg == nil {
+		base.MilvusCfg = &milvustype.MilvusCfg{}
+	}
+	var collCfgs []*chromatype.CollectionCfg
+	var err error
+	if v.IsSet("meta.collections") {
+		collCfgs, err = resolveChromaCollections(v.Get("meta.collections"), base)
+		if err != nil {
+			return nil, err
+		}
+	} else if base.Collection != "" {
+		collCfgs = []*chromatype.CollectionCfg{base}
+	}
+	return &MetaConfig{
+		MetaMode: metaMode,
+		ChromaMeta: &chromatype.MetaJSON{
+			CollectionCfgs: collCfgs,
+			DefaultCfg:     base,
+		},
+	}, nil
+}
+
+func resolveChromaCollections(ymlColls interface{}, base *chromatype.CollectionCfg) ([]*chromatype.CollectionCfg, error) {
+	items, ok := ymlColls.([]interface{})
+	if !ok {
+		return nil, errors.New("meta.collections format invalid, need a list")
+	}
+	//meta.milvus.collection only work for single collection, can't as default target collection name
+	baseMilvusCfg := copyMilvusCfg(base.MilvusCfg)
+	baseMilvusCfg.Collection = ""
+	collCfgs := make([]*chromatype.CollectionCfg, 0, len(items))
+	for _, item := range items {
+		collCfg := &chromatype.CollectionCfg{
+			IdMaxLen:       base.IdMaxLen,
+			DocumentMaxLen: base.DocumentMaxLen,
+			MilvusCfg:      copyMilvusCfg(baseMilvusCfg),
+		}
+		switch it := item.(type) {
+		case string:
+			collCfg.Collection = it
+		case map[string]interface{}:
+			//注意：v.Get()会把key全部转成小写, 如： idMaxLen -> idmaxlen
+			collCfg.Collection, _ = it["name"].(string)
+			if idMaxLen, ok := it["idmaxlen"].(int); ok {
+				collCfg.IdMaxLen = idMaxLen
+			}
+			if documentMaxLen, ok := it["documentmaxlen"].(int); ok {
+				collCfg.DocumentMaxLen = documentMaxLen
+			}
+			if milvusMap, ok := it["milvus"].(map[string]interface{}); ok {
+				collCfg.MilvusCfg = resolveMilvusCfgMap(milvusMap, baseMilvusCfg)
+			}
+		default:
+			return nil, errors.New("meta.collections item format invalid, need a string or map")
+		}
+		if collCfg.Collection == "" {
+			return nil, errors.New("meta.collections item name is empty")
+		}
+		collCfgs = append(collCfgs, collCfg)
+	}
+	return collCfgs, nil
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/spf13/viper"
 	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"os"
 	"strings"
+	"time"
 )
 
 func getSourceESConfig(v *viper.Viper) (*ESConfig, error) {
@@ -18,7 +21,18 @@ func getSourceESConfig(v *viper.Viper) (*ESConfig, error) {
 	var apiKey string
 	var urls []string
 
+	distribution := v.GetString("source.es.distribution")
+	switch distribution {
+	case "":
+		distribution = ESDistributionElasticsearch
+	case ESDistributionElasticsearch, ESDistributionOpenSearch:
+	default:
+		return nil, errors.New("source.es.distribution only support elasticsearch or opensearch, but is " + distribution)
+	}
 	cloudId = strings.TrimSpace(v.GetString("source.es.cloudId"))
+	if len(cloudId) > 0 && distribution == ESDistributionOpenSearch {
+		return nil, errors.New("opensearch not support source.es.cloudId, please use source.es.urls")
+	}
 	if len(cloudId) > 0 {
 		//use elastic cloud case:
 		apiKey = strings.TrimSpace(v.GetString("source.es.apiKey"))
@@ -40,7 +54,31 @@ func getSourceESConfig(v *viper.Viper) (*ESConfig, error) {
 			fingerprint = strings.TrimSpace(v.GetString("source.es.fingerprint"))
 		}
 	}
-	return &ESConfig{
+	readMode := v.GetString("source.es.readMode")
+	switch readMode {
+	case "":
+		readMode = ESReadModeScroll
+	case ESReadModeScroll, ESReadModePIT:
+	default:
+		return nil, errors.New("source.es.readMode only support scroll or pit, but is " + readMode)
+	}
+	keepAlive := time.Minute
+	if keepAliveStr := v.GetString("source.es.keepAlive"); keepAliveStr != "" {
+		var err error
+		keepAlive, err = time.ParseDuration(keepAliveStr)
+		if err != nil || keepAlive < time.Second {
+			return nil, errors.New("source.es.keepAlive invalid, need duration >= 1s like 5m, but is " + keepAliveStr)
+		}
+	}
+	slices := v.GetInt("source.es.slices")
+	if slices <= 0 {
+		slices = 1
+	}
+
+	esCfg := &ESConfig{
+		ReadMode:     readMode,
+		KeepAlive:    keepAlive,
+		Slices:       slices,
 		CloudId:      cloudId,
 		ApiKey:       apiKey,
 		ServiceToken: serviceToken,
@@ -49,7 +87,33 @@ func getSourceESConfig(v *viper.Viper) (*ESConfig, error) {
 		Urls:         urls,
 		Cert:         cert,
 		FingerPrint:  fingerprint,
-	}, nil
+		Distribution: distribution,
+	}
+	if distribution == ESDistributionOpenSearch {
+		resolveOpenSearchAws(v, esCfg)
+	}
+	return esCfg, nil
+}
+
+// resolveOpenSearchAws : aws opensearch service sign the request by sigv4 when source.es.aws.region is set,
+// the access key not config will use the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN env
+func resolveOpenSearchAws(v *viper.Viper, esCfg *ESConfig) {
+	esCfg.AwsRegion = strings.TrimSpace(v.GetString("source.es.aws.region"))
+	if esCfg.AwsRegion == "" {
+		return
+	}
+	esCfg.AwsService = v.GetString("source.es.aws.service")
+	if esCfg.AwsService == "" {
+		esCfg.AwsService = "es"
+	}
+	esCfg.AwsAccessKey = strings.TrimSpace(v.GetString("source.es.aws.accessKey"))
+	esCfg.AwsSecretKey = strings.TrimSpace(v.GetString("source.es.aws.secretKey"))
+	esCfg.AwsSessionToken = strings.TrimSpace(v.GetString("source.es.aws.sessionToken"))
+	if esCfg.AwsAccessKey == "" {
+		esCfg.AwsAccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		esCfg.AwsSecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		esCfg.AwsSessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
 }
 
 func resolveEsMeta(v *viper.Viper) (*MetaConfig, error) {
@@ -62,10 +126,37 @@ func resolveEsMeta(v *viper.Viper) (*MetaConfig, error) {
 	}
 	milvusCfg := resolveMilvusCfg(v)
 
+	unsupportedPolicy := v.GetString("meta.unsupportedFieldPolicy")
+	switch unsupportedPolicy {
+	case "":
+		unsupportedPolicy = estype.UnsupportedFieldDrop
+	case estype.UnsupportedFieldDrop, estype.UnsupportedFieldDynamic:
+	default:
+		return nil, errors.New("meta.unsupportedFieldPolicy only support drop or dynamic, but is " + unsupportedPolicy)
+	}
+
+	query := strings.TrimSpace(v.GetString("meta.query"))
+	if query != "" && !json.Valid([]byte(query)) {
+		return nil, errors.New("meta.query need a valid query dsl json string")
+	}
+	multiIndexMode := v.GetString("meta.multiIndexMode")
+	switch multiIndexMode {
+	case "":
+		multiIndexMode = estype.MultiIndexMerge
+	case estype.MultiIndexMerge, estype.MultiIndexSplit:
+	default:
+		return nil, errors.New("meta.multiIndexMode only support merge or split, but is " + multiIndexMode)
+	}
+
 	idxCfg := &estype.IdxCfg{
-		Index:     esIndex,
-		Fields:    esFields,
-		MilvusCfg: milvusCfg,
+		Index:          esIndex,
+		Fields:         esFields,
+		MilvusCfg:      milvusCfg,
+		Query:          query,
+		MultiIndexMode: multiIndexMode,
+		//not config fields will auto read fields from es index mapping
+		AutoMapping:            v.GetBool("meta.autoMapping") || len(esFields) == 0,
+		UnsupportedFieldPolicy: unsupportedPolicy,
 	}
 	idxCfgs := []*estype.IdxCfg{idxCfg}
 	esMeta := &estype.MetaJSON{
@@ -81,6 +172,9 @@ func resolveEsMeta(v *viper.Viper) (*MetaConfig, error) {
 func resolveEsFields(v *viper.Viper) ([]estype.FieldCfg, error) {
 
 	var ymlFields []interface{}
+	if v.Get("meta.fields") == nil {
+		return make([]estype.FieldCfg, 0), nil
+	}
 	//注意：这里v.Get()会把里面的key全部转成小写, 如： maxLen -> maxlen
 	ymlFields, ok := v.Get("meta.fields").([]interface{})
 	if !ok {
@@ -110,12 +204,25 @@ func resolveEsFields(v *viper.Viper) ([]estype.FieldCfg, error) {
 		if ok {
 			pk = pkObj.(bool)
 		}
+		target, _ := yamlMap["target"].(string)
+		dataType, _ := yamlMap["datatype"].(string)
+		spaceType, _ := yamlMap["spacetype"].(string)
+		maxCapacity, _ := yamlMap["maxcapacity"].(int)
+		indexCfg, err := resolveIndexCfg(yamlMap)
+		if err != nil {
+			return nil, err
+		}
 		field := estype.FieldCfg{
-			Name:   name,
-			Type:   _type,
-			Dims:   dims,
-			MaxLen: maxLen,
-			PK:     pk,
+			Name:           name,
+			Type:           _type,
+			Dims:           dims,
+			MaxLen:         maxLen,
+			PK:             pk,
+			Index:          indexCfg,
+			Target:         target,
+			MaxCapacity:    maxCapacity,
+			VectorDataType: dataType,
+			SpaceType:      spaceType,
 		}
 		esFields = append(esFields, field)
 	}
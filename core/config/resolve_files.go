@@ -0,0 +1,351 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/filestype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"strings"
+)
+
+func isFilesTarget(v *viper.Viper) bool {
+	return common.TargetType(v.GetString("target.type")) == common.T_FILES
+}
+
+// assertFilesTargetMode : export source data to parquet/jsonl files with a manifest.json, not need target milvus
+func assertFilesTargetMode(v *viper.Viper, dumpMode common.DumpMode) (*MigrationConfig, error) {
+	var dumpWorkCfg *DumperWorkConfig
+	var err error
+	switch dumpMode {
+	case common.Milvus2x:
+		dumpWorkCfg, err = resolveMilvus2xDumpWorkConfig(v, dumpMode)
+	case common.Elasticsearch:
+		dumpWrkLimit := v.GetInt("dumper.worker.limit")
+		if dumpWrkLimit <= 0 {
+			dumpWrkLimit = 2
+		}
+		dumpWorkCfg, err = resolveDumpWorkConfig(v, dumpWrkLimit)
+	case common.Manifest, common.Pgvector, common.Qdrant, common.Chroma, common.Files:
+		return assertBatchSourceFilesTarget(v, dumpMode)
+	default:
+		return nil, fmt.Errorf("[target.type] files not support workMode %s, faiss and milvus1x source only support milvus target", dumpMode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	//concurrent file writers of each collection
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	metaCfg, err := resolveMetaConfig(v, dumpMode)
+	if err != nil {
+		return nil, err
+	}
+	cfg := MigrationConfig{
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWorkCfg.Limit,
+		//loader
+		LoaderWorkCfg:   &LoaderWorkConfig{WorkMode: dumpWorkCfg.WorkMode},
+		LoaderWorkLimit: loadWrkLimit,
+		MetaConfig:      metaCfg,
+	}
+	if dumpMode == common.Milvus2x {
+		cfg.SourceMilvus2xConfig = resolveSourceMilvus2xConfig(v)
+	} else {
+		cfg.SourceESConfig, err = getSourceESConfig(v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SparseVocabularyDir = resolveSparseVocabularyDir(v)
+	}
+	err = resolveFilesTarget(v, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// assertBatchSourceFilesTarget : the batch insert sources resolve the source config same as milvus target,
+// only the target milvus replaced by the files
+func assertBatchSourceFilesTarget(v *viper.Viper, dumpMode common.DumpMode) (*MigrationConfig, error) {
+	var cfg *MigrationConfig
+	var err error
+	switch dumpMode {
+	case common.Manifest:
+		cfg, err = assertManifestSourceMode(v)
+	case common.Pgvector:
+		cfg, err = assertPgvectorMode(v)
+	case common.Qdrant:
+		cfg, err = assertQdrantMode(v)
+	case common.Chroma:
+		cfg, err = assertChromaMode(v)
+	default:
+		cfg, err = assertFilesSourceMode(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg.TargetMilvus2xCfg = nil
+	err = resolveFilesTarget(v, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// resolveFilesTarget : files target format and output dir
+func resolveFilesTarget(v *viper.Viper, cfg *MigrationConfig) error {
+	format, err := resolveFilesFormat(v)
+	if err != nil {
+		return err
+	}
+	targetMode, err := assertTargetMode(v)
+	if err != nil {
+		return err
+	}
+	//files target output dir not add the magic suffix, the dir is the export result and will not be cleaned
+	var outputDir string
+	if common.TargetMode(targetMode) == common.T_REMOTE {
+		outputDir = getOutputDirByRemote(v)
+	} else {
+		outputDir = v.GetString("target.local.outputDir")
+	}
+	if outputDir == "" {
+		return fmt.Errorf("[target.%s.outputDir] can not empty", targetMode)
+	}
+	cfg.TargetType = string(common.T_FILES)
+	cfg.TargetFilesFormat = format
+	cfg.TargetMode = targetMode
+	cfg.TargetOutputDir = outputDir
+	cfg.TargetRemote = resolveTargetRemoteConfig(v)
+	return nil
+}
+
+func resolveFilesFormat(v *viper.Viper) (string, error) {
+	format := common.FileFormat(v.GetString("target.files.format"))
+	switch format {
+	case "":
+		return string(common.FormatParquet), nil
+	case common.FormatParquet, common.FormatJSONL:
+		return string(format), nil
+	default:
+		return "", fmt.Errorf("[target.files.format] not support %s, should be parquet or jsonl", format)
+	}
+}
+
+// assertManifestSourceMode : read the files exported by files target, batch insert to target milvus
+func assertManifestSourceMode(v *viper.Viper) (*MigrationConfig, error) {
+	sourceMode, err := assertSourceMode(v, common.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	manifestFile := v.GetString("source." + sourceMode + ".manifestFile")
+	if manifestFile == "" {
+		return nil, errors.New("[source." + sourceMode + ".manifestFile] can not empty")
+	}
+	dumpWorkCfg, err := resolveMilvus2xDumpWorkConfig(v, common.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	//concurrent batch insert writers of each collection
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	cfg := MigrationConfig{
+		SourceMode:         sourceMode,
+		SourceRemote:       resolveSourceRemoteConfig(v),
+		SourceManifestFile: manifestFile,
+		TargetMilvus2xCfg:  resolveTargetMilvus2xConfig(v),
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWorkCfg.Limit,
+		//loader
+		LoaderWorkCfg:   &LoaderWorkConfig{WorkMode: dumpWorkCfg.WorkMode},
+		LoaderWorkLimit: loadWrkLimit,
+		//collections are described by the manifest
+		MetaConfig: &MetaConfig{},
+	}
+	return &cfg, nil
+}
+
+// assertFilesSourceMode : read jsonl, csv or parquet data files with the meta.fields schema, batch insert to target milvus
+func assertFilesSourceMode(v *viper.Viper) (*MigrationConfig, error) {
+	sourceMode, err := assertSourceMode(v, common.Files)
+	if err != nil {
+		return nil, err
+	}
+	dumpWorkCfg, err := resolveMilvus2xDumpWorkConfig(v, common.Files)
+	if err != nil {
+		return nil, err
+	}
+	//concurrent batch insert writers of each collection
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	metaCfg, err := resolveMetaConfig(v, common.Files)
+	if err != nil {
+		return nil, err
+	}
+	cfg := MigrationConfig{
+		SourceMode:        sourceMode,
+		SourceRemote:      resolveSourceRemoteConfig(v),
+		SourceFilesDir:    v.GetString("source." + sourceMode + ".dir"),
+		TargetMilvus2xCfg: resolveTargetMilvus2xConfig(v),
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWorkCfg.Limit,
+		//loader
+		LoaderWorkCfg: &LoaderWorkConfig{
+			WorkMode:   dumpWorkCfg.WorkMode,
+			InsertMode: string(common.BatchInsert),
+		},
+		LoaderWorkLimit: loadWrkLimit,
+		MetaConfig:      metaCfg,
+	}
+	if cfg.SourceFilesDir == "" {
+		for _, collCfg := range metaCfg.FilesMeta.CollectionCfgs {
+			if len(collCfg.Files) == 0 {
+				return nil, fmt.Errorf("[source.%s.dir] and the files of collection %s cannot both be empty", sourceMode, collCfg.Collection)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+// resolveFilesMeta : meta.collection for single collection, or meta.collections list,
+// meta.format, meta.files, meta.fields and meta.milvus as the default value of each collection
+func resolveFilesMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
+	var fields []filestype.FieldCfg
+	var err error
+	if v.IsSet("meta.fields") {
+		fields, err = resolveFilesFields(v.Get("meta.fields"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	base := &filestype.CollectionCfg{
+		Collection: v.GetString("meta.collection"),
+		Format:     strings.ToLower(v.GetString("meta.format")),
+		Files:      v.GetStringSlice("meta.files"),
+		Fields:     fields,
+		MilvusCfg:  resolveMilvusCfg(v),
+	}
+	if base.MilvusCfg == nil {
+		base.MilvusCfg = &milvustype.MilvusCfg{}
+	}
+	var collCfgs []*filestype.CollectionCfg
+	if v.IsSet("meta.collections") {
+		collCfgs, err = resolveFilesCollections(v.Get("meta.collections"), base)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if base.Collection == "" && base.MilvusCfg.Collection == "" {
+			return nil, errors.New("[meta.collection] and [meta.collections] cannot both be empty")
+		}
+		collCfgs = []*filestype.CollectionCfg{base}
+	}
+	for _, collCfg := range collCfgs {
+		switch common.FileFormat(collCfg.Format) {
+		case "", common.FormatJSONL, common.FormatCSV, common.FormatParquet:
+		default:
+			return nil, fmt.Errorf("files collection %s format %s not support, should be jsonl, csv or parquet", collCfg.Collection, collCfg.Format)
+		}
+		if len(collCfg.Fields) == 0 {
+			return nil, fmt.Errorf("files collection %s meta.fields can not empty", collCfg.Collection)
+		}
+	}
+	return &MetaConfig{
+		MetaMode:  metaMode,
+		FilesMeta: &filestype.MetaJSON{CollectionCfgs: collCfgs},
+	}, nil
+}
+
+func resolveFilesCollections(ymlColls interface{}, base *filestype.CollectionCfg) ([]*filestype.CollectionCfg, error) {
+	items, ok := ymlColls.([]interface{})
+	if !ok {
+		return nil, errors.New("meta.collections format invalid, need a list")
+	}
+	//meta.milvus.collection only work for single collection, can't as default target collection name
+	baseMilvusCfg := copyMilvusCfg(base.MilvusCfg)
+	baseMilvusCfg.Collection = ""
+	collCfgs := make([]*filestype.CollectionCfg, 0, len(items))
+	for _, item := range items {
+		it, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("meta.collections item format invalid, need a map")
+		}
+		collCfg := &filestype.CollectionCfg{
+			Format:    base.Format,
+			Files:     base.Files,
+			Fields:    base.Fields,
+			MilvusCfg: copyMilvusCfg(baseMilvusCfg),
+		}
+		collCfg.Collection, _ = it["name"].(string)
+		if format, ok := it["format"].(string); ok {
+			collCfg.Format = strings.ToLower(format)
+		}
+		switch files := it["files"].(type) {
+		case string:
+			collCfg.Files = []string{files}
+		case []interface{}:
+			collCfg.Files = make([]string, 0, len(files))
+			for _, file := range files {
+				collCfg.Files = append(collCfg.Files, fmt.Sprint(file))
+			}
+		}
+		if ymlFields, ok := it["fields"]; ok {
+			fields, err := resolveFilesFields(ymlFields)
+			if err != nil {
+				return nil, err
+			}
+			collCfg.Fields = fields
+		}
+		if milvusMap, ok := it["milvus"].(map[string]interface{}); ok {
+			collCfg.MilvusCfg = resolveMilvusCfgMap(milvusMap, baseMilvusCfg)
+		}
+		if collCfg.Collection == "" {
+			return nil, errors.New("meta.collections item name is empty")
+		}
+		collCfgs = append(collCfgs, collCfg)
+	}
+	return collCfgs, nil
+}
+
+// resolveFilesFields : field item is a map like: {name: xx, type: xx, dims: xx, maxLen: xx, pk: xx, index: {...}}
+func resolveFilesFields(ymlFields interface{}) ([]filestype.FieldCfg, error) {
+	items, ok := ymlFields.([]interface{})
+	if !ok {
+		return nil, errors.New("files meta.fields format invalid, need a list")
+	}
+	fields := make([]filestype.FieldCfg, 0, len(items))
+	for _, item := range items {
+		//注意：v.Get()会把key全部转成小写, 如： maxLen -> maxlen
+		it, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("files meta.fields item format invalid, need a map")
+		}
+		var field filestype.FieldCfg
+		field.Name, _ = it["name"].(string)
+		field.Type, _ = it["type"].(string)
+		field.Dims, _ = it["dims"].(int)
+		field.MaxLen, _ = it["maxlen"].(int)
+		field.PK, _ = it["pk"].(bool)
+		indexCfg, err := resolveIndexCfg(it)
+		if err != nil {
+			return nil, err
+		}
+		field.Index = indexCfg
+		if field.Name == "" || field.Type == "" {
+			return nil, errors.New("files meta.fields item name and type can not empty")
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
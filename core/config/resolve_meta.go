@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/spf13/viper"
@@ -74,44 +75,130 @@ func resolveMetaInConfig(v *viper.Viper, metaMode string, mode common.DumpMode)
 		return resolveEsMeta(v)
 	case common.Milvus2x:
 		return resolveMilvus2xMeta(v, metaMode)
+	case common.Pgvector:
+		return resolvePgvectorMeta(v, metaMode)
+	case common.Qdrant:
+		return resolveQdrantMeta(v, metaMode)
+	case common.Chroma:
+		return resolveChromaMeta(v, metaMode)
+	case common.Files:
+		return resolveFilesMeta(v, metaMode)
 	default:
 		return nil, errors.New("meta mode 'config' have not support work in" + string(mode))
 	}
 }
 
 func resolveMilvusCfg(v *viper.Viper) *milvustype.MilvusCfg {
-	var milvus *milvustype.MilvusCfg
 	//注意：这里v.Get()会把里面的key全部转成小写，比如：shardNum -> shardnum, closeDynamicField -> closedynamicfield
 	ymlMilvusCfg := v.Get("meta.milvus")
 	if ymlMilvusCfg != nil {
 		milvusMap, ok := ymlMilvusCfg.(map[string]interface{})
 		if ok {
-			milvus = &milvustype.MilvusCfg{}
-			collName, ok := milvusMap["collection"].(string)
-			if ok {
-				milvus.Collection = collName
-			}
-			autoId, ok := milvusMap["autoid"].(string)
-			if ok {
-				milvus.AutoId = autoId
-			}
-			shardNum, ok := milvusMap["shardnum"].(int)
-			if ok {
-				milvus.ShardNum = shardNum
-			}
-			closeDynamicField, ok := milvusMap["closedynamicfield"].(bool)
-			if ok {
-				milvus.CloseDynamicField = closeDynamicField
-			}
-			consistencyLevel, ok := milvusMap["consistencylevel"].(string)
-			if ok {
-				milvus.ConsistencyLevel = consistencyLevel
-			}
+			return resolveMilvusCfgMap(milvusMap, nil)
 		}
 	}
+	return nil
+}
+
+// resolveMilvusCfgMap : resolve milvus cfg yaml map, the key not in yaml map will use base value
+func resolveMilvusCfgMap(milvusMap map[string]interface{}, base *milvustype.MilvusCfg) *milvustype.MilvusCfg {
+	milvus := &milvustype.MilvusCfg{}
+	if base != nil {
+		*milvus = *base
+	}
+	collName, ok := milvusMap["collection"].(string)
+	if ok {
+		milvus.Collection = collName
+	}
+	autoId, ok := milvusMap["autoid"].(string)
+	if ok {
+		milvus.AutoId = autoId
+	}
+	shardNum, ok := milvusMap["shardnum"].(int)
+	if ok {
+		milvus.ShardNum = shardNum
+	}
+	closeDynamicField, ok := milvusMap["closedynamicfield"].(bool)
+	if ok {
+		milvus.CloseDynamicField = closeDynamicField
+	}
+	consistencyLevel, ok := milvusMap["consistencylevel"].(string)
+	if ok {
+		milvus.ConsistencyLevel = consistencyLevel
+	}
+	createIndex, ok := milvusMap["createindex"].(bool)
+	if ok {
+		milvus.CreateIndex = createIndex
+	}
+	loadData, ok := milvusMap["loaddata"].(bool)
+	if ok {
+		milvus.LoadData = loadData
+	}
 	return milvus
 }
 
+// resolveIndexCfg : resolve meta.fields[].index config,
+// params can be a json string (recommend, yaml map keys will be lowercased by viper, eg: efConstruction -> efconstruction) or a map
+func resolveIndexCfg(yamlMap map[string]interface{}) (*milvustype.IndexCfg, error) {
+	indexObj, ok := yamlMap["index"]
+	if !ok || indexObj == nil {
+		return nil, nil
+	}
+	indexMap, ok := indexObj.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("meta.fields index format invalid, convert to map failed")
+	}
+	indexCfg := &milvustype.IndexCfg{}
+	indexCfg.IndexType, _ = indexMap["indextype"].(string)
+	indexCfg.MetricType, _ = indexMap["metrictype"].(string)
+	switch params := indexMap["params"].(type) {
+	case nil:
+	case string:
+		var paramMap map[string]interface{}
+		if err := json.Unmarshal([]byte(params), &paramMap); err != nil {
+			return nil, fmt.Errorf("meta.fields index params is not a valid json: %s", params)
+		}
+		indexCfg.Params = toStringMap(paramMap)
+	case map[string]interface{}:
+		indexCfg.Params = toStringMap(params)
+	default:
+		return nil, errors.New("meta.fields index params format invalid")
+	}
+	return indexCfg, nil
+}
+
+func toStringMap(m map[string]interface{}) map[string]string {
+	strMap := make(map[string]string, len(m))
+	for k, v := range m {
+		strMap[k] = fmt.Sprint(v)
+	}
+	return strMap
+}
+
+// resolveFieldIndexCfgs : resolve meta.fields[].index config for no meta mode(faiss, milvus1x), key is field name
+func resolveFieldIndexCfgs(v *viper.Viper) (map[string]*milvustype.IndexCfg, error) {
+	ymlFields, ok := v.Get("meta.fields").([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	indexCfgs := make(map[string]*milvustype.IndexCfg)
+	for _, yf := range ymlFields {
+		yamlMap, ok := yf.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("meta.fields format invalid, convert to map failed")
+		}
+		name, _ := yamlMap["name"].(string)
+		indexCfg, err := resolveIndexCfg(yamlMap)
+		if err != nil {
+			return nil, err
+		}
+		if indexCfg != nil {
+			indexCfgs[name] = indexCfg
+		}
+	}
+	return indexCfgs, nil
+}
+
 func resolveMetaInSqlite(v *viper.Viper) (*MetaConfig, error) {
 	sqliteFile := v.GetString("meta.sqliteFile")
 	if sqliteFile == "" {
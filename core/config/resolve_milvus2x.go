@@ -4,12 +4,14 @@ import (
 	"errors"
 	"github.com/spf13/viper"
 	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
 	"github.com/zilliztech/milvus-migration/internal/log"
 )
 
 func resolveMilvus2xMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
 	milvusVersion := v.GetString("meta.version")
 	milvusColName := v.GetString("meta.collection")
+	filter := v.GetString("meta.filter")
 
 	//milvux2xFields, err := resolveMilvus2xFields(v)
 	milvux2xFields, err := resolveMilvus2xFieldsSimple(v)
@@ -18,12 +20,21 @@ func resolveMilvus2xMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
 	}
 	milvusCfg := resolveMilvusCfg(v)
 
-	collCfg := &milvus2xtype.CollectionCfg{
-		Collection: milvusColName,
-		Fields:     milvux2xFields,
-		MilvusCfg:  milvusCfg,
+	var collCfgs []*milvus2xtype.CollectionCfg
+	if v.IsSet("meta.collections") {
+		collCfgs, err = resolveMilvus2xCollections(v.Get("meta.collections"), milvux2xFields, milvusCfg, filter)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		collCfg := &milvus2xtype.CollectionCfg{
+			Collection: milvusColName,
+			Fields:     milvux2xFields,
+			MilvusCfg:  milvusCfg,
+			Filter:     filter,
+		}
+		collCfgs = []*milvus2xtype.CollectionCfg{collCfg}
 	}
-	collCfgs := []*milvus2xtype.CollectionCfg{collCfg}
 	milvus2xMeta := &milvus2xtype.MetaJSON{
 		Version:  milvusVersion,
 		CollCfgs: collCfgs,
@@ -34,6 +45,81 @@ func resolveMilvus2xMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
 	}, nil
 }
 
+// resolveMilvus2xCollections : resolve meta.collections, support below formats:
+//  1. a string: collection name or glob pattern, eg: "*" means all collections in source database
+//  2. a list, each item is a collection name/glob pattern string, or a map like: {name: xx, filter: xx, fields: [...], milvus: {...}}
+//
+// meta.fields, meta.milvus and meta.filter as the default value of each collection
+func resolveMilvus2xCollections(ymlColls interface{}, defFields []milvus2xtype.FieldCfg,
+	defMilvusCfg *milvustype.MilvusCfg, defFilter string) ([]*milvus2xtype.CollectionCfg, error) {
+
+	var items []interface{}
+	switch colls := ymlColls.(type) {
+	case string:
+		items = []interface{}{colls}
+	case []interface{}:
+		items = colls
+	default:
+		return nil, errors.New("meta.collections format invalid, need a string or list")
+	}
+
+	//meta.milvus.collection only work for single collection, can't as default target collection name
+	baseMilvusCfg := copyMilvusCfg(defMilvusCfg)
+	if baseMilvusCfg != nil {
+		baseMilvusCfg.Collection = ""
+	}
+	collCfgs := make([]*milvus2xtype.CollectionCfg, 0, len(items))
+	for _, item := range items {
+		collCfg := &milvus2xtype.CollectionCfg{
+			Fields:    copyMilvus2xFields(defFields),
+			MilvusCfg: copyMilvusCfg(baseMilvusCfg),
+			Filter:    defFilter,
+		}
+		switch it := item.(type) {
+		case string:
+			collCfg.Collection = it
+		case map[string]interface{}:
+			name, _ := it["name"].(string)
+			collCfg.Collection = name
+			if filter, ok := it["filter"].(string); ok {
+				collCfg.Filter = filter
+			}
+			if ymlFields, ok := it["fields"].([]interface{}); ok {
+				fields, err := resolveMilvus2xFieldNames(ymlFields)
+				if err != nil {
+					return nil, err
+				}
+				collCfg.Fields = fields
+			}
+			if milvusMap, ok := it["milvus"].(map[string]interface{}); ok {
+				collCfg.MilvusCfg = resolveMilvusCfgMap(milvusMap, baseMilvusCfg)
+			}
+		default:
+			return nil, errors.New("meta.collections item format invalid, need a string or map")
+		}
+		if collCfg.Collection == "" {
+			return nil, errors.New("meta.collections item name is empty")
+		}
+		collCfgs = append(collCfgs, collCfg)
+	}
+	return collCfgs, nil
+}
+
+func copyMilvus2xFields(fields []milvus2xtype.FieldCfg) []milvus2xtype.FieldCfg {
+	if fields == nil {
+		return nil
+	}
+	return append(make([]milvus2xtype.FieldCfg, 0, len(fields)), fields...)
+}
+
+func copyMilvusCfg(milvusCfg *milvustype.MilvusCfg) *milvustype.MilvusCfg {
+	if milvusCfg == nil {
+		return nil
+	}
+	cfg := *milvusCfg
+	return &cfg
+}
+
 func resolveMilvus2xFields(v *viper.Viper) ([]milvus2xtype.FieldCfg, error) {
 
 	var ymlFields []interface{}
@@ -90,6 +176,10 @@ func resolveMilvus2xFieldsSimple(v *viper.Viper) ([]milvus2xtype.FieldCfg, error
 		//return nil, errors.New("meta.fields format invalid")
 	}
 
+	return resolveMilvus2xFieldNames(ymlFields)
+}
+
+func resolveMilvus2xFieldNames(ymlFields []interface{}) ([]milvus2xtype.FieldCfg, error) {
 	milvus2xFields := make([]milvus2xtype.FieldCfg, 0)
 	for _, yf := range ymlFields {
 		yamlMap, ok := yf.(map[string]interface{})
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/core/type/pgvectortype"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// assertPgvectorMode : postgres table rows read by keyset pagination and batch insert to target milvus directly,
+// not need target.mode and object storage
+func assertPgvectorMode(v *viper.Viper) (*MigrationConfig, error) {
+	sourceCfg, err := getSourcePgvectorConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	dumpWorkCfg, err := resolveMilvus2xDumpWorkConfig(v, common.Pgvector)
+	if err != nil {
+		return nil, err
+	}
+	//concurrent batch insert writers of each table
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	metaCfg, err := resolveMetaConfig(v, common.Pgvector)
+	if err != nil {
+		return nil, err
+	}
+	cfg := MigrationConfig{
+		SourcePgvectorConfig: sourceCfg,
+		TargetMilvus2xCfg:    resolveTargetMilvus2xConfig(v),
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWorkCfg.Limit,
+		//loader
+		LoaderWorkCfg: &LoaderWorkConfig{
+			WorkMode:   dumpWorkCfg.WorkMode,
+			InsertMode: string(common.BatchInsert),
+		},
+		LoaderWorkLimit: loadWrkLimit,
+		MetaConfig:      metaCfg,
+	}
+	return &cfg, nil
+}
+
+// getSourcePgvectorConfig : use source.pgvector.dsn first, otherwise build dsn by host, port, username, password and database
+func getSourcePgvectorConfig(v *viper.Viper) (*PgvectorConfig, error) {
+	dsn := strings.TrimSpace(v.GetString("source.pgvector.dsn"))
+	if dsn != "" {
+		return &PgvectorConfig{DSN: dsn}, nil
+	}
+	host := strings.TrimSpace(v.GetString("source.pgvector.host"))
+	if host == "" {
+		return nil, errors.New("[source.pgvector.dsn] and [source.pgvector.host] cannot both be empty")
+	}
+	database := v.GetString("source.pgvector.database")
+	if database == "" {
+		return nil, errors.New("[source.pgvector.database] can not empty")
+	}
+	port := v.GetString("source.pgvector.port")
+	if port == "" {
+		port = "5432"
+	}
+	sslMode := v.GetString("source.pgvector.sslmode")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		Host:     net.JoinHostPort(host, port),
+		Path:     "/" + database,
+		RawQuery: url.Values{"sslmode": []string{sslMode}}.Encode(),
+	}
+	if username := v.GetString("source.pgvector.username"); username != "" {
+		u.User = url.UserPassword(username, v.GetString("source.pgvector.password"))
+	}
+	return &PgvectorConfig{DSN: u.String()}, nil
+}
+
+// resolvePgvectorMeta : meta.table for single table, or meta.tables list like meta.collections of milvus2x,
+// meta.pk, meta.filter, meta.fields and meta.milvus as the default value of each table
+func resolvePgvectorMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
+	fields, err := resolvePgvectorFields(v.Get("meta.fields"))
+	if err != nil {
+		return nil, err
+	}
+	base := &pgvectortype.TableCfg{
+		Table:     v.GetString("meta.table"),
+		PK:        v.GetString("meta.pk"),
+		Filter:    strings.TrimSpace(v.GetString("meta.filter")),
+		Fields:    fields,
+		MilvusCfg: resolveMilvusCfg(v),
+	}
+	if base.MilvusCfg == nil {
+		base.MilvusCfg = &milvustype.MilvusCfg{}
+	}
+	var tableCfgs []*pgvectortype.TableCfg
+	if v.IsSet("meta.tables") {
+		tableCfgs, err = resolvePgvectorTables(v.Get("meta.tables"), base)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if base.Table == "" {
+			return nil, errors.New("[meta.table] and [meta.tables] cannot both be empty")
+		}
+		tableCfgs = []*pgvectortype.TableCfg{base}
+	}
+	return &MetaConfig{
+		MetaMode:     metaMode,
+		PgvectorMeta: &pgvectortype.MetaJSON{TableCfgs: tableCfgs},
+	}, nil
+}
+
+func resolvePgvectorTables(ymlTables interface{}, base *pgvectortype.TableCfg) ([]*pgvectortype.TableCfg, error) {
+	items, ok := ymlTables.([]interface{})
+	if !ok {
+		return nil, errors.New("meta.tables format invalid, need a list")
+	}
+	//meta.milvus.collection only work for single table, can't as default target collection name
+	baseMilvusCfg := copyMilvusCfg(base.MilvusCfg)
+	baseMilvusCfg.Collection = ""
+	tableCfgs := make([]*pgvectortype.TableCfg, 0, len(items))
+	for _, item := range items {
+		tableCfg := &pgvectortype.TableCfg{
+			PK:        base.PK,
+			Filter:    base.Filter,
+			Fields:    base.Fields,
+			MilvusCfg: copyMilvusCfg(baseMilvusCfg),
+		}
+		switch it := item.(type) {
+		case string:
+			tableCfg.Table = it
+		case map[string]interface{}:
+			tableCfg.Table, _ = it["name"].(string)
+			if pk, ok := it["pk"].(string); ok {
+				tableCfg.PK = pk
+			}
+			if filter, ok := it["filter"].(string); ok {
+				tableCfg.Filter = strings.TrimSpace(filter)
+			}
+			if ymlFields, ok := it["fields"]; ok {
+				fields, err := resolvePgvectorFields(ymlFields)
+				if err != nil {
+					return nil, err
+				}
+				tableCfg.Fields = fields
+			}
+			if milvusMap, ok := it["milvus"].(map[string]interface{}); ok {
+				tableCfg.MilvusCfg = resolveMilvusCfgMap(milvusMap, baseMilvusCfg)
+			}
+		default:
+			return nil, errors.New("meta.tables item format invalid, need a string or map")
+		}
+		if tableCfg.Table == "" {
+			return nil, errors.New("meta.tables item name is empty")
+		}
+		tableCfgs = append(tableCfgs, tableCfg)
+	}
+	return tableCfgs, nil
+}
+
+// resolvePgvectorFields : field item is a column name string or a map like: {name: xx, maxLen: xx, maxCapacity: xx, index: {...}}
+func resolvePgvectorFields(ymlFields interface{}) ([]pgvectortype.FieldCfg, error) {
+	if ymlFields == nil {
+		return nil, nil
+	}
+	items, ok := ymlFields.([]interface{})
+	if !ok {
+		return nil, errors.New("pgvector meta.fields format invalid, need a list")
+	}
+	fields := make([]pgvectortype.FieldCfg, 0, len(items))
+	for _, item := range items {
+		var field pgvectortype.FieldCfg
+		switch it := item.(type) {
+		case string:
+			field.Name = it
+		case map[string]interface{}:
+			//注意：v.Get()会把key全部转成小写, 如： maxLen -> maxlen
+			field.Name, _ = it["name"].(string)
+			field.MaxLen, _ = it["maxlen"].(int)
+			field.MaxCapacity, _ = it["maxcapacity"].(int)
+			indexCfg, err := resolveIndexCfg(it)
+			if err != nil {
+				return nil, err
+			}
+			field.Index = indexCfg
+		default:
+			return nil, errors.New("pgvector meta.fields item format invalid, need a string or map")
+		}
+		if field.Name == "" {
+			return nil, fmt.Errorf("pgvector meta.fields item name is empty")
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
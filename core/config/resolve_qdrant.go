@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"strings"
+	"time"
+)
+
+// assertQdrantMode : qdrant points read by the scroll api and batch insert to target milvus directly,
+// not need target.mode and object storage
+func assertQdrantMode(v *viper.Viper) (*MigrationConfig, error) {
+	sourceCfg, err := getSourceQdrantConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	dumpWorkCfg, err := resolveMilvus2xDumpWorkConfig(v, common.Qdrant)
+	if err != nil {
+		return nil, err
+	}
+	//concurrent batch insert writers of each collection
+	loadWrkLimit := v.GetInt("loader.worker.limit")
+	if loadWrkLimit <= 0 {
+		loadWrkLimit = 1
+	}
+	metaCfg, err := resolveMetaConfig(v, common.Qdrant)
+	if err != nil {
+		return nil, err
+	}
+	cfg := MigrationConfig{
+		SourceQdrantConfig: sourceCfg,
+		TargetMilvus2xCfg:  resolveTargetMilvus2xConfig(v),
+		// dumper
+		DumperWorkCfg:   dumpWorkCfg,
+		DumperWorkLimit: dumpWorkCfg.Limit,
+		//loader
+		LoaderWorkCfg: &LoaderWorkConfig{
+			WorkMode:   dumpWorkCfg.WorkMode,
+			InsertMode: string(common.BatchInsert),
+		},
+		LoaderWorkLimit: loadWrkLimit,
+		MetaConfig:      metaCfg,
+	}
+	return &cfg, nil
+}
+
+func getSourceQdrantConfig(v *viper.Viper) (*QdrantConfig, error) {
+	url := strings.TrimRight(strings.TrimSpace(v.GetString("source.qdrant.url")), "/")
+	if url == "" {
+		return nil, errors.New("[source.qdrant.url] can not empty")
+	}
+	timeout := v.GetDuration("source.qdrant.timeout")
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &QdrantConfig{
+		Url:     url,
+		ApiKey:  v.GetString("source.qdrant.apiKey"),
+		Timeout: timeout,
+	}, nil
+}
+
+// resolveQdrantMeta : meta.collection for single collection, or meta.collections list,
+// meta.pkName, meta.pkType, meta.filter, meta.fields and meta.milvus as the default value of each collection
+func resolveQdrantMeta(v *viper.Viper, metaMode string) (*MetaConfig, error) {
+	fields, err := resolveQdrantFields(v.Get("meta.fields"))
+	if err != nil {
+		return nil, err
+	}
+	base := &qdranttype.CollectionCfg{
+		Collection: v.GetString("meta.collection"),
+		PkName:     v.GetString("meta.pkName"),
+		PkType:     v.GetString("meta.pkType"),
+		Filter:     strings.TrimSpace(v.GetString("meta.filter")),
+		Fields:     fields,
+		MilvusCfg:  resolveMilvusCfg(v),
+	}
+	if base.MilvusCfg == nil {
+		base.MilvusCfg = &milvustype.MilvusCfg{}
+	}
+	var collCfgs []*qdranttype.CollectionCfg
+	if v.IsSet("meta.collections") {
+		collCfgs, err = resolveQdrantCollections(v.Get("meta.collections"), base)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if base.Collection == "" {
+			return nil, errors.New("[meta.collection] and [meta.collections] cannot both be empty")
+		}
+		collCfgs = []*qdranttype.CollectionCfg{base}
+	}
+	for _, collCfg := range collCfgs {
+		if collCfg.PkName == "" {
+			collCfg.PkName = "id"
+		}
+		switch collCfg.PkType {
+		case "", qdranttype.PkTypeInt64, qdranttype.PkTypeVarChar:
+		default:
+			return nil, fmt.Errorf("qdrant collection %s pkType only support int64 or varchar", collCfg.Collection)
+		}
+	}
+	return &MetaConfig{
+		MetaMode:   metaMode,
+		QdrantMeta: &qdranttype.MetaJSON{CollectionCfgs: collCfgs},
+	}, nil
+}
+
+func resolveQdrantCollections(ymlColls interface{}, base *qdranttype.CollectionCfg) ([]*qdranttype.CollectionCfg, error) {
+	items, ok := ymlColls.([]interface{})
+	if !ok {
+		return nil, errors.New("meta.collections format invalid, need a list")
+	}
+	//meta.milvus.collection only work for single collection, can't as default target collection name
+	baseMilvusCfg := copyMilvusCfg(base.MilvusCfg)
+	baseMilvusCfg.Collection = ""
+	collCfgs := make([]*qdranttype.CollectionCfg, 0, len(items))
+	for _, item := range items {
+		collCfg := &qdranttype.CollectionCfg{
+			PkName:    base.PkName,
+			PkType:    base.PkType,
+			Filter:    base.Filter,
+			Fields:    base.Fields,
+			MilvusCfg: copyMilvusCfg(baseMilvusCfg),
+		}
+		switch it := item.(type) {
+		case string:
+			collCfg.Collection = it
+		case map[string]interface{}:
+			//注意：v.Get()会把key全部转成小写, 如： pkName -> pkname
+			collCfg.Collection, _ = it["name"].(string)
+			if pkName, ok := it["pkname"].(string); ok {
+				collCfg.PkName = pkName
+			}
+			if pkType, ok := it["pktype"].(string); ok {
+				collCfg.PkType = pkType
+			}
+			if filter, ok := it["filter"].(string); ok {
+				collCfg.Filter = strings.TrimSpace(filter)
+			}
+			if ymlFields, ok := it["fields"]; ok {
+				fields, err := resolveQdrantFields(ymlFields)
+				if err != nil {
+					return nil, err
+				}
+				collCfg.Fields = fields
+			}
+			if milvusMap, ok := it["milvus"].(map[string]interface{}); ok {
+				collCfg.MilvusCfg = resolveMilvusCfgMap(milvusMap, baseMilvusCfg)
+			}
+		default:
+			return nil, errors.New("meta.collections item format invalid, need a string or map")
+		}
+		if collCfg.Collection == "" {
+			return nil, errors.New("meta.collections item name is empty")
+		}
+		collCfgs = append(collCfgs, collCfg)
+	}
+	return collCfgs, nil
+}
+
+// resolveQdrantFields : field item is a map like: {name: xx, type: xx, elementType: xx, maxLen: xx, maxCapacity: xx, index: {...}},
+// payload field need the type, vector field only config the index
+func resolveQdrantFields(ymlFields interface{}) ([]qdranttype.FieldCfg, error) {
+	if ymlFields == nil {
+		return nil, nil
+	}
+	items, ok := ymlFields.([]interface{})
+	if !ok {
+		return nil, errors.New("qdrant meta.fields format invalid, need a list")
+	}
+	fields := make([]qdranttype.FieldCfg, 0, len(items))
+	for _, item := range items {
+		it, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("qdrant meta.fields item format invalid, need a map")
+		}
+		var field qdranttype.FieldCfg
+		field.Name, _ = it["name"].(string)
+		field.Type, _ = it["type"].(string)
+		field.ElementType, _ = it["elementtype"].(string)
+		field.MaxLen, _ = it["maxlen"].(int)
+		field.MaxCapacity, _ = it["maxcapacity"].(int)
+		indexCfg, err := resolveIndexCfg(it)
+		if err != nil {
+			return nil, err
+		}
+		field.Index = indexCfg
+		if field.Name == "" {
+			return nil, fmt.Errorf("qdrant meta.fields item name is empty")
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
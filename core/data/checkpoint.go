@@ -0,0 +1,190 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"strconv"
+	"sync"
+)
+
+// Checkpoint : milvus2x job migration checkpoint, key is source collection name
+type Checkpoint struct {
+	JobId       string                     `json:"jobId"`
+	Collections map[string]*CollCheckpoint `json:"collections"`
+
+	lock sync.RWMutex
+}
+
+// CollCheckpoint : record the last pk of the last successfully written batch,
+// partitions are iterated one by one, so the partitions before current partition are finished.
+// pk range parallel read record the ranges of current partition and the last pk of every range.
+// es index read by pit record the search_after sort values of the last written hit of every slice
+type CollCheckpoint struct {
+	Finished           bool              `json:"finished"`
+	FinishedPartitions []string          `json:"finishedPartitions"`
+	Partition          string            `json:"partition"`
+	PkName             string            `json:"pkName"`
+	PkType             entity.FieldType  `json:"pkType"`
+	LastPK             string            `json:"lastPK"`
+	PKRanges           []string          `json:"pkRanges,omitempty"`
+	RangeLastPK        map[string]string `json:"rangeLastPK,omitempty"`
+	SliceMax           int               `json:"sliceMax,omitempty"`
+	SliceSearchAfter   map[int]string    `json:"sliceSearchAfter,omitempty"`
+}
+
+func NewCheckpoint(jobId string) *Checkpoint {
+	return &Checkpoint{
+		JobId:       jobId,
+		Collections: make(map[string]*CollCheckpoint),
+	}
+}
+
+func (c *Checkpoint) getOrNew(collection string) *CollCheckpoint {
+	collCkpt, ok := c.Collections[collection]
+	if !ok {
+		collCkpt = &CollCheckpoint{FinishedPartitions: make([]string, 0)}
+		c.Collections[collection] = collCkpt
+	}
+	return collCkpt
+}
+
+// Update : record the batch written, when partition changed mean the last partition finished
+func (c *Checkpoint) Update(collection string, partition string, pkName string, lastPK any) error {
+	return c.UpdateRange(collection, partition, nil, "", pkName, lastPK)
+}
+
+// UpdateRange : record the batch of the pk range written, pkRanges are all the ranges of the partition,
+// empty pkRanges mean the partition is iterated by a single iterator
+func (c *Checkpoint) UpdateRange(collection string, partition string, pkRanges []string, pkRange string,
+	pkName string, lastPK any) error {
+	pkType, pk, err := formatPK(lastPK)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	collCkpt := c.getOrNew(collection)
+	if collCkpt.Partition != partition && (collCkpt.LastPK != "" || len(collCkpt.RangeLastPK) > 0) {
+		collCkpt.FinishedPartitions = append(collCkpt.FinishedPartitions, collCkpt.Partition)
+		collCkpt.PKRanges = nil
+		collCkpt.RangeLastPK = nil
+	}
+	collCkpt.Partition = partition
+	collCkpt.PkName = pkName
+	collCkpt.PkType = pkType
+	if len(pkRanges) == 0 {
+		collCkpt.LastPK = pk
+		return nil
+	}
+	collCkpt.LastPK = ""
+	collCkpt.PKRanges = pkRanges
+	if collCkpt.RangeLastPK == nil {
+		collCkpt.RangeLastPK = make(map[string]string, len(pkRanges))
+	}
+	collCkpt.RangeLastPK[pkRange] = pk
+	return nil
+}
+
+// UpdateSlice : record the search_after sort values of the last written hit of the es pit slice
+func (c *Checkpoint) UpdateSlice(collection string, sliceMax int, sliceId int, searchAfter string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	collCkpt := c.getOrNew(collection)
+	collCkpt.SliceMax = sliceMax
+	if collCkpt.SliceSearchAfter == nil {
+		collCkpt.SliceSearchAfter = make(map[int]string, sliceMax)
+	}
+	collCkpt.SliceSearchAfter[sliceId] = searchAfter
+}
+
+func formatPK(lastPK any) (entity.FieldType, string, error) {
+	switch pk := lastPK.(type) {
+	case int64:
+		return entity.FieldTypeInt64, strconv.FormatInt(pk, 10), nil
+	case string:
+		return entity.FieldTypeVarChar, pk, nil
+	default:
+		return entity.FieldTypeNone, "", fmt.Errorf("checkpoint not support pk type %T", lastPK)
+	}
+}
+
+func (c *Checkpoint) FinishCollection(collection string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.getOrNew(collection).Finished = true
+}
+
+// GetCollection : return a copy of the collection checkpoint, nil if not exist
+func (c *Checkpoint) GetCollection(collection string) *CollCheckpoint {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	collCkpt, ok := c.Collections[collection]
+	if !ok {
+		return nil
+	}
+	cp := *collCkpt
+	cp.FinishedPartitions = append([]string{}, collCkpt.FinishedPartitions...)
+	cp.PKRanges = append([]string{}, collCkpt.PKRanges...)
+	cp.RangeLastPK = make(map[string]string, len(collCkpt.RangeLastPK))
+	for pkRange, pk := range collCkpt.RangeLastPK {
+		cp.RangeLastPK[pkRange] = pk
+	}
+	cp.SliceSearchAfter = make(map[int]string, len(collCkpt.SliceSearchAfter))
+	for sliceId, searchAfter := range collCkpt.SliceSearchAfter {
+		cp.SliceSearchAfter[sliceId] = searchAfter
+	}
+	return &cp
+}
+
+func (c *Checkpoint) Marshal() ([]byte, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return json.Marshal(c)
+}
+
+func (cc *CollCheckpoint) IsPartitionFinished(partition string) bool {
+	for _, p := range cc.FinishedPartitions {
+		if p == partition {
+			return true
+		}
+	}
+	return false
+}
+
+// ResumeExpr : the expr of continue to iterate the partition, empty if partition not started
+func (cc *CollCheckpoint) ResumeExpr(partition string) string {
+	if cc.Partition != partition || cc.LastPK == "" {
+		return ""
+	}
+	return cc.greaterThanExpr(cc.LastPK)
+}
+
+// ResumeRanges : the exprs of continue to read the pk ranges of the partition, the range without written batch
+// read from its start, nil if partition not read by pk ranges
+func (cc *CollCheckpoint) ResumeRanges(partition string) []string {
+	if cc.Partition != partition || len(cc.PKRanges) == 0 {
+		return nil
+	}
+	exprs := make([]string, len(cc.PKRanges))
+	for i, pkRange := range cc.PKRanges {
+		exprs[i] = pkRange
+		lastPK, ok := cc.RangeLastPK[pkRange]
+		if !ok {
+			continue
+		}
+		if pkRange == "" {
+			exprs[i] = cc.greaterThanExpr(lastPK)
+		} else {
+			exprs[i] = "(" + pkRange + ") and " + cc.greaterThanExpr(lastPK)
+		}
+	}
+	return exprs
+}
+
+func (cc *CollCheckpoint) greaterThanExpr(pk string) string {
+	if cc.PkType == entity.FieldTypeVarChar {
+		return fmt.Sprintf("%s > %s", cc.PkName, strconv.Quote(pk))
+	}
+	return fmt.Sprintf("%s > %s", cc.PkName, pk)
+}
@@ -0,0 +1,72 @@
+package data
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckpointResumeExpr(t *testing.T) {
+	ckpt := NewCheckpoint("job1")
+	assert.NoError(t, ckpt.Update("coll1", "p1", "id", int64(10)))
+	assert.NoError(t, ckpt.Update("coll1", "p1", "id", int64(20)))
+	assert.NoError(t, ckpt.Update("coll1", "p2", "id", int64(5)))
+	assert.NoError(t, ckpt.Update("coll2", "", "name", "a\"b"))
+
+	val, err := ckpt.Marshal()
+	assert.NoError(t, err)
+	loaded := NewCheckpoint("job1")
+	assert.NoError(t, json.Unmarshal(val, loaded))
+
+	collCkpt := loaded.GetCollection("coll1")
+	assert.True(t, collCkpt.IsPartitionFinished("p1"))
+	assert.False(t, collCkpt.IsPartitionFinished("p2"))
+	assert.Equal(t, "id > 5", collCkpt.ResumeExpr("p2"))
+	assert.Equal(t, "", collCkpt.ResumeExpr("p3"))
+
+	assert.Equal(t, `name > "a\"b"`, loaded.GetCollection("coll2").ResumeExpr(""))
+	assert.Nil(t, loaded.GetCollection("coll3"))
+
+	assert.Error(t, ckpt.Update("coll1", "p2", "id", 1.5))
+}
+
+func TestCheckpointResumeRanges(t *testing.T) {
+	ckpt := NewCheckpoint("job1")
+	ranges := []string{"id < 100", "id >= 100 and id < 200", "id >= 200"}
+	assert.NoError(t, ckpt.UpdateRange("coll1", "p1", ranges, ranges[0], "id", int64(10)))
+	assert.NoError(t, ckpt.UpdateRange("coll1", "p1", ranges, ranges[2], "id", int64(250)))
+	assert.NoError(t, ckpt.UpdateRange("coll1", "p1", ranges, ranges[0], "id", int64(20)))
+
+	val, err := ckpt.Marshal()
+	assert.NoError(t, err)
+	loaded := NewCheckpoint("job1")
+	assert.NoError(t, json.Unmarshal(val, loaded))
+
+	collCkpt := loaded.GetCollection("coll1")
+	assert.Equal(t, []string{"(id < 100) and id > 20", "id >= 100 and id < 200", "(id >= 200) and id > 250"},
+		collCkpt.ResumeRanges("p1"))
+	assert.Equal(t, "", collCkpt.ResumeExpr("p1"))
+	assert.Nil(t, collCkpt.ResumeRanges("p2"))
+
+	assert.NoError(t, ckpt.UpdateRange("coll1", "p2", []string{""}, "", "id", int64(5)))
+	collCkpt = ckpt.GetCollection("coll1")
+	assert.True(t, collCkpt.IsPartitionFinished("p1"))
+	assert.Equal(t, []string{"id > 5"}, collCkpt.ResumeRanges("p2"))
+}
+
+func TestCheckpointSliceSearchAfter(t *testing.T) {
+	ckpt := NewCheckpoint("job1")
+	ckpt.UpdateSlice("idx1", 2, 0, `[10]`)
+	ckpt.UpdateSlice("idx1", 2, 1, `[3]`)
+	ckpt.UpdateSlice("idx1", 2, 0, `[25]`)
+
+	val, err := ckpt.Marshal()
+	assert.NoError(t, err)
+	loaded := NewCheckpoint("job1")
+	assert.NoError(t, json.Unmarshal(val, loaded))
+
+	collCkpt := loaded.GetCollection("idx1")
+	assert.Equal(t, 2, collCkpt.SliceMax)
+	assert.Equal(t, map[int]string{0: `[25]`, 1: `[3]`}, collCkpt.SliceSearchAfter)
+	assert.False(t, collCkpt.Finished)
+}
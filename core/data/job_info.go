@@ -3,6 +3,7 @@ package data
 import (
 	"github.com/shopspring/decimal"
 	"go.uber.org/atomic"
+	"time"
 )
 
 type JobStatus string
@@ -12,15 +13,20 @@ const (
 	JobStatusRunning JobStatus = "running"
 	JobStatusSuccess JobStatus = "success"
 	JobStatusFail    JobStatus = "fail"
+	//the job not finished when the server process restart
+	JobStatusInterrupted JobStatus = "interrupted"
 )
 
 type JobInfo struct {
-	JobId       string        `json:"jobId"`
-	JobStatus   JobStatus     `json:"jobStatus"`
-	JobProcess  int           `json:"jobProcess"`
-	Msg         string        `json:"msg"`
-	TotalTasks  int           `json:"totalTasks"`
-	FinishTasks *atomic.Int64 `json:"finishTasks"`
+	JobId       string         `json:"jobId"`
+	JobStatus   JobStatus      `json:"jobStatus"`
+	JobProcess  int            `json:"jobProcess"`
+	Msg         string         `json:"msg"`
+	TotalTasks  int            `json:"totalTasks"`
+	FinishTasks *atomic.Int64  `json:"finishTasks"`
+	CollProcess map[string]int `json:"collProcess,omitempty"` //milvus2x multi collections process
+	StartTime   time.Time      `json:"startTime"`
+	EndTime     *time.Time     `json:"endTime,omitempty"`
 }
 
 func NewJobInfo(jobId string) *JobInfo {
@@ -29,6 +35,7 @@ func NewJobInfo(jobId string) *JobInfo {
 		JobStatus:   JobStatusInit,
 		TotalTasks:  0,
 		FinishTasks: atomic.NewInt64(0),
+		StartTime:   time.Now(),
 	}
 }
 
@@ -37,6 +44,15 @@ func (this *JobInfo) SetJobStatus(status JobStatus, err error) {
 	if err != nil {
 		this.Msg = err.Error()
 	}
+	if this.IsFinished() {
+		now := time.Now()
+		this.EndTime = &now
+	}
+}
+
+// IsFinished : success, fail and interrupted job will not change any more
+func (this *JobInfo) IsFinished() bool {
+	return this.JobStatus == JobStatusSuccess || this.JobStatus == JobStatusFail || this.JobStatus == JobStatusInterrupted
 }
 
 func (this *JobInfo) SetTotalTasks(totalTasks int) {
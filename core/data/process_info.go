@@ -7,6 +7,7 @@ import (
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"sync"
 )
 
 const One_Percent = 1
@@ -26,8 +27,13 @@ type ProcessHandler struct {
 	LoadTotalSize  int64
 	LoadFinishSize *atomic.Int64
 
+	//milvus2x multi collections: key collection name, val collection process, collection process will add to job process
+	Collections map[string]*ProcessHandler `json:"Collections,omitempty"`
+
 	lastPercent int
 	mode        string
+	parent      *ProcessHandler
+	lock        sync.RWMutex
 }
 
 func NewProcessHandler(mode string) *ProcessHandler {
@@ -39,15 +45,64 @@ func NewProcessHandler(mode string) *ProcessHandler {
 	}
 }
 
+// AddCollection : new a collection process handler, its dump&load size will also add to this job process handler
+func (p *ProcessHandler) AddCollection(collection string) *ProcessHandler {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.Collections == nil {
+		p.Collections = make(map[string]*ProcessHandler)
+	}
+	collPh := NewProcessHandler(p.mode)
+	collPh.parent = p
+	p.Collections[collection] = collPh
+	return collPh
+}
+
+func (p *ProcessHandler) GetCollection(collection string) *ProcessHandler {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.Collections[collection]
+}
+
+// CalcCollectionsProcess : key collection name, val collection process percent
+func (p *ProcessHandler) CalcCollectionsProcess() map[string]int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if len(p.Collections) == 0 {
+		return nil
+	}
+	collProcess := make(map[string]int, len(p.Collections))
+	for collection, collPh := range p.Collections {
+		collProcess[collection] = collPh.CalcProcess()
+	}
+	return collProcess
+}
+
 func (p *ProcessHandler) SetLoadFinished() {
 	p.LoadFinish = true
 }
 func (p *ProcessHandler) SetDumpTotalSize(totalSize int64) {
+	p.lock.Lock()
+	increment := totalSize - p.DumpTotalSize
 	p.DumpTotalSize = totalSize
+	p.lock.Unlock()
+	//not hold the collection lock when lock the parent, CalcCollectionsProcess lock the parent first
+	if p.parent != nil {
+		p.parent.addDumpTotalSize(increment)
+	}
+}
+func (p *ProcessHandler) addDumpTotalSize(increment int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.DumpTotalSize += increment
 }
 func (p *ProcessHandler) AddDumpedSize(increment int, ctx context.Context) {
 	p.DumpFinishSize.Add(int64(increment))
 	p.LoadTotalFiles.Inc()
+	if p.parent != nil {
+		p.parent.AddDumpedSize(increment, ctx)
+		return
+	}
 	log.LL(ctx).Info("=================>JobProcess!", zap.Int("Percent", p.CalcProcess()))
 }
 
@@ -62,10 +117,26 @@ func (p *ProcessHandler) SetUnLoadSize(processingCount int32, ctx context.Contex
 }
 
 func (p *ProcessHandler) SetLoadTotalSize(totalSize int64) {
+	p.lock.Lock()
+	increment := totalSize - p.LoadTotalSize
 	p.LoadTotalSize = totalSize
+	p.lock.Unlock()
+	//not hold the collection lock when lock the parent, CalcCollectionsProcess lock the parent first
+	if p.parent != nil {
+		p.parent.addLoadTotalSize(increment)
+	}
+}
+func (p *ProcessHandler) addLoadTotalSize(increment int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.LoadTotalSize += increment
 }
 func (p *ProcessHandler) AddLoadSize(increment int, ctx context.Context) {
 	p.LoadFinishSize.Add(int64(increment))
+	if p.parent != nil {
+		p.parent.AddLoadSize(increment, ctx)
+		return
+	}
 	log.LL(ctx).Info("=================>JobProcess!!", zap.Int("Percent", p.CalcProcess()))
 }
 
@@ -80,6 +151,9 @@ func (p *ProcessHandler) calcProcess0() int {
 	if p.LoadFinish {
 		return Hundred_Percent
 	}
+	if collProcess := p.CalcCollectionsProcess(); len(collProcess) > 0 {
+		return calcByCollectionsProc(collProcess)
+	}
 	if p.DumpFinish {
 		if common.DumpMode(p.mode) == common.Elasticsearch {
 			return calcByLoadFilesProc(p)
@@ -127,6 +201,15 @@ func calcByInsertDataProc(p *ProcessHandler) int {
 	return percent
 }
 
+// calcByCollectionsProc : multi collections job, every collection has the same weight
+func calcByCollectionsProc(collProcess map[string]int) int {
+	total := 0
+	for _, percent := range collProcess {
+		total += percent
+	}
+	return total / len(collProcess)
+}
+
 func calcByLoadFilesProc(p *ProcessHandler) int {
 	var unLoad = p.LoadUnFinishFiles
 	if unLoad == 0 {
@@ -17,17 +17,19 @@ func newSubTask() *SubFileTask {
 		FileSort:      atomic.NewInt32(0),
 		NoFinishFiles: make(map[string]bool),
 		FinishFiles:   make(map[string]bool),
+		TaskIds:       make(map[string]int64),
 	}
 }
 
 type SubFileTask struct {
 	//主要是一个collection对应的Json文件处理情况
 	//Finish        bool            `json:"Finish"`
-	Total         int             `json:"Total"`
-	TotalFinish   int             `json:"TotalFinish"`
-	NoFinishFiles map[string]bool `json:"NoFinishFiles"` //key: fileName, val: dont care
-	FinishFiles   map[string]bool `json:"FinishFiles"`   //key: fileName, val: dont care
-	FileSort      *atomic.Int32   `json:"FileSort"`
+	Total         int              `json:"Total"`
+	TotalFinish   int              `json:"TotalFinish"`
+	NoFinishFiles map[string]bool  `json:"NoFinishFiles"` //key: fileName, val: dont care
+	FinishFiles   map[string]bool  `json:"FinishFiles"`   //key: fileName, val: dont care
+	FileSort      *atomic.Int32    `json:"FileSort"`
+	TaskIds       map[string]int64 `json:"TaskIds"` //key: fileName, val: milvus bulk insert taskId
 }
 
 func (all *FileTask) GetFileSort(collection string) int32 {
@@ -61,3 +63,24 @@ func (all *FileTask) FinishFileTask(collection string, fileName string) {
 	subTask.FinishFiles[fileName] = true
 	delete(subTask.NoFinishFiles, fileName)
 }
+
+func (all *FileTask) SetFileTaskId(collection string, fileName string, taskId int64) {
+	lockTask.Lock()
+	defer lockTask.Unlock()
+	val := all.TaskMap[collection]
+	if val == nil {
+		val = newSubTask()
+		all.TaskMap[collection] = val
+	}
+	val.TaskIds[fileName] = taskId
+}
+
+func (all *FileTask) GetFileTaskId(collection string, fileName string) int64 {
+	lockTask.RLock()
+	defer lockTask.RUnlock()
+	val := all.TaskMap[collection]
+	if val == nil {
+		return 0
+	}
+	return val.TaskIds[fileName]
+}
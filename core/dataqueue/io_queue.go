@@ -52,3 +52,8 @@ func (this *IOQueue) Close() error {
 
 	return this.writer.Close()
 }
+
+// CloseReader : the consumer stop read, writer will get the error
+func (this *IOQueue) CloseReader(err error) error {
+	return this.reader.CloseWithError(err)
+}
@@ -29,10 +29,15 @@ func (cus *CustomFieldMilvus2x) CreateCollection(ctx context.Context, collection
 		return err
 	}
 	if exist {
-		return nil
-	} else {
-		return cus.createCollection(ctx, collectionInfo)
+		needCreate, err := cus.Milvus2x.handleExistCollection(ctx, ToSchema(collectionInfo))
+		if err != nil {
+			return err
+		}
+		if !needCreate {
+			return cus.createMissPartitions(ctx, collectionInfo)
+		}
 	}
+	return cus.createCollection(ctx, collectionInfo)
 }
 
 func (cus *CustomFieldMilvus2x) hasCollection(ctx context.Context, collection string) (bool, error) {
@@ -42,8 +47,7 @@ func (cus *CustomFieldMilvus2x) hasCollection(ctx context.Context, collection st
 		return false, err
 	}
 	if exist {
-		log.Warn("collection already exist,no need to create",
-			zap.String("collectionName", collection))
+		log.Warn("collection already exist", zap.String("collectionName", collection))
 		return true, nil
 	} else {
 		return false, nil
@@ -59,14 +63,7 @@ func (cus *CustomFieldMilvus2x) createCollection(ctx context.Context, collection
 		zap.String("partitionKey", collectionInfo.PartitionKey),
 		zap.Any("partitions", collectionInfo.Partitions),
 		zap.String("description", collectionInfo.Param.Description))
-	// schema
-	schema := &entity.Schema{
-		CollectionName:     collectionInfo.Param.CollectionName,
-		Description:        collectionInfo.Param.Description,
-		AutoID:             collectionInfo.Param.AutoId,
-		Fields:             collectionInfo.Fields,
-		EnableDynamicField: collectionInfo.Param.EnableDynamicField,
-	}
+	schema := ToSchema(collectionInfo)
 	var err error
 	if collectionInfo.Param.ConsistencyLevel == nil {
 		err = cus.Milvus2x.milvus.CreateCollection(ctx, schema, int32(collectionInfo.Param.ShardsNum))
@@ -95,6 +92,29 @@ func (cus *CustomFieldMilvus2x) createCollection(ctx context.Context, collection
 	return nil
 }
 
+// createMissPartitions : append into the exist collection, create the source partitions it not has
+func (cus *CustomFieldMilvus2x) createMissPartitions(ctx context.Context, collectionInfo *common.CollectionInfo) error {
+	if collectionInfo.PartitionKey != "" || collectionInfo.Partitions == nil {
+		return nil
+	}
+	partitions := make([]string, 0, len(collectionInfo.Partitions))
+	for _, partition := range collectionInfo.Partitions {
+		partitions = append(partitions, partition.Name)
+	}
+	return cus.Milvus2x.createMissPartitions(ctx, collectionInfo.Param.CollectionName, partitions)
+}
+
+// ToSchema : the target collection schema of the custom field collection
+func ToSchema(collectionInfo *common.CollectionInfo) *entity.Schema {
+	return &entity.Schema{
+		CollectionName:     collectionInfo.Param.CollectionName,
+		Description:        collectionInfo.Param.Description,
+		AutoID:             collectionInfo.Param.AutoId,
+		Fields:             collectionInfo.Fields,
+		EnableDynamicField: collectionInfo.Param.EnableDynamicField,
+	}
+}
+
 func (cus *CustomFieldMilvus2x) StartBulkLoad(ctx context.Context, colName string, fullFilePaths []string) (int64, error) {
 	return cus.Milvus2x.StartBulkLoad(ctx, colName, fullFilePaths)
 }
@@ -123,8 +143,18 @@ func (cus *CustomFieldMilvus2x) DropCollection(ctx context.Context, collectionNa
 	return cus.Milvus2x.milvus.DropCollection(ctx, collectionName)
 }
 
+func (cus *CustomFieldMilvus2x) CreateIndexes(ctx context.Context, collectionInfo *common.CollectionInfo) error {
+	for fieldName, index := range collectionInfo.Indexes {
+		err := cus.Milvus2x.CreateIndexIfNotExist(ctx, collectionInfo.Param.CollectionName, fieldName, index)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (cus *CustomFieldMilvus2x) LoadCollection(ctx context.Context, collectionName string, async bool) error {
-	err := cus.Milvus2x.milvus.LoadCollection(ctx, collectionName, async)
+	err := cus.Milvus2x.LoadCollection(ctx, collectionName, async)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,302 @@
+package dbclient
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/internal/util/retry"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// handleExistCollection : apply the target.onExists policy on the exist collection,
+// return true when the collection is dropped and need to create with the source schema
+func (this *Milvus2x) handleExistCollection(ctx context.Context, source *entity.Schema) (bool, error) {
+	collection := source.CollectionName
+	log.LL(ctx).Info("[Milvus2x] target collection already exist", zap.String("collection", collection),
+		zap.String("onExists", this.onExists))
+	switch common.OnExists(this.onExists) {
+	case common.OnExistsFail:
+		return false, retry.Unrecoverable(fmt.Errorf("collection %s already exist in target, target.onExists=fail", collection))
+	case common.OnExistsDrop:
+		log.LL(ctx).Warn("[Milvus2x] drop the exist collection", zap.String("collection", collection))
+		err := this.milvus.DropCollection(ctx, collection)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	// append and truncate both insert source data into the exist schema
+	exist, err := this.milvus.DescribeCollection(ctx, collection)
+	if err != nil {
+		log.Error("call milvus2x DescribeCollection error,", zap.String("collection", collection), zap.Error(err))
+		return false, err
+	}
+	diffs := diffSchema(source, exist.Schema)
+	if len(diffs) > 0 {
+		report := fmt.Sprintf("collection %s already exist in target and the schema not compatible with source (target.onExists=%s):\n  - %s",
+			collection, this.onExists, strings.Join(diffs, "\n  - "))
+		log.LL(ctx).Error("[Milvus2x] " + report)
+		return false, retry.Unrecoverable(fmt.Errorf("%s", report))
+	}
+	if common.OnExists(this.onExists) == common.OnExistsTruncate {
+		return false, this.truncateCollection(ctx, exist)
+	}
+	return false, nil
+}
+
+// DiffExistCollection : exist is false when the collection not exist in target,
+// otherwise return the field by field differences of the source schema and the exist collection schema
+func (this *Milvus2x) DiffExistCollection(ctx context.Context, source *entity.Schema) (bool, []string, error) {
+	exist, err := this.milvus.HasCollection(ctx, source.CollectionName)
+	if err != nil || !exist {
+		return false, nil, err
+	}
+	coll, err := this.milvus.DescribeCollection(ctx, source.CollectionName)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, diffSchema(source, coll.Schema), nil
+}
+
+// capturedCollection : the exist collection schema, partitions and indexes captured before truncate drop it
+type capturedCollection struct {
+	schema           *entity.Schema
+	shardNum         int32
+	consistencyLevel entity.ConsistencyLevel
+	partitions       []string
+	indexes          map[string][]entity.Index
+}
+
+// truncateCollection : milvus has no truncate, drop and create it again with its own schema, partitions and indexes.
+// the exist collection is captured once before drop, the recreate retry by it and never fall back to the source schema
+func (this *Milvus2x) truncateCollection(ctx context.Context, exist *entity.Collection) error {
+	collection := exist.Name
+	captured, err := this.captureCollection(ctx, exist)
+	if err != nil {
+		return err
+	}
+	log.LL(ctx).Warn("[Milvus2x] truncate the exist collection", zap.String("collection", collection),
+		zap.Int("partitions", len(captured.partitions)), zap.Int("indexedFields", len(captured.indexes)))
+	err = this.milvus.DropCollection(ctx, collection)
+	if err != nil {
+		//drop may success but response fail, only retry when the collection still exist
+		has, hasErr := this.milvus.HasCollection(ctx, collection)
+		if hasErr != nil || has {
+			return err
+		}
+	}
+	err = retry.Do(ctx, func() error {
+		return this.recreateCollection(ctx, captured)
+	}, retry.Attempts(5), retry.Sleep(2*time.Second))
+	if err != nil {
+		return retry.Unrecoverable(fmt.Errorf("collection %s dropped by target.onExists=truncate but recreate fail, "+
+			"need create it manually: %w", collection, err))
+	}
+	return nil
+}
+
+func (this *Milvus2x) captureCollection(ctx context.Context, exist *entity.Collection) (*capturedCollection, error) {
+	collection := exist.Name
+	captured := &capturedCollection{
+		schema: &entity.Schema{
+			CollectionName:     collection,
+			Description:        exist.Schema.Description,
+			AutoID:             exist.Schema.AutoID,
+			EnableDynamicField: exist.Schema.EnableDynamicField,
+		},
+		shardNum:         exist.ShardNum,
+		consistencyLevel: exist.ConsistencyLevel,
+		indexes:          make(map[string][]entity.Index),
+	}
+	hasPartitionKey := false
+	for _, field := range exist.Schema.Fields {
+		if field.IsDynamic {
+			continue
+		}
+		hasPartitionKey = hasPartitionKey || field.IsPartitionKey
+		captured.schema.Fields = append(captured.schema.Fields, field)
+		fieldIndexes, err := this.milvus.DescribeIndex(ctx, collection, field.Name)
+		if err != nil {
+			if isIndexNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		captured.indexes[field.Name] = fieldIndexes
+	}
+	if !hasPartitionKey {
+		partitions, err := this.milvus.ShowPartitions(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+		for _, partition := range partitions {
+			captured.partitions = append(captured.partitions, partition.Name)
+		}
+	}
+	return captured, nil
+}
+
+// recreateCollection : create the captured collection, skip the collection, partitions and indexes already created
+func (this *Milvus2x) recreateCollection(ctx context.Context, captured *capturedCollection) error {
+	collection := captured.schema.CollectionName
+	has, err := this.milvus.HasCollection(ctx, collection)
+	if err != nil {
+		return err
+	}
+	if !has {
+		err = this.milvus.CreateCollection(ctx, captured.schema, captured.shardNum, client.WithConsistencyLevel(captured.consistencyLevel))
+		if err != nil {
+			log.Error("call milvus2x CreateCollection error", zap.String("collection", collection), zap.Error(err))
+			return err
+		}
+	}
+	err = this.createMissPartitions(ctx, collection, captured.partitions)
+	if err != nil {
+		return err
+	}
+	for fieldName, fieldIndexes := range captured.indexes {
+		created, err := this.milvus.DescribeIndex(ctx, collection, fieldName)
+		if err != nil && !isIndexNotFound(err) {
+			return err
+		}
+		if len(created) > 0 {
+			continue
+		}
+		for _, index := range fieldIndexes {
+			err = this.CreateIndex(ctx, collection, fieldName, index)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createMissPartitions : the source partitions not in the exist collection, insert into them will fail
+func (this *Milvus2x) createMissPartitions(ctx context.Context, collection string, partitions []string) error {
+	if len(partitions) == 0 {
+		return nil
+	}
+	exists, err := this.milvus.ShowPartitions(ctx, collection)
+	if err != nil {
+		return err
+	}
+	existSet := make(map[string]bool, len(exists))
+	for _, partition := range exists {
+		existSet[partition.Name] = true
+	}
+	for _, partition := range partitions {
+		if partition == common.DEFAULT_PARTITION_NAME || existSet[partition] {
+			continue
+		}
+		log.LL(ctx).Info("[Milvus2x] create partition in the exist collection", zap.String("collection", collection),
+			zap.String("partition", partition))
+		err = this.milvus.CreatePartition(ctx, collection, partition)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isIndexNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "index not found") || strings.Contains(msg, "index doesn't exist") ||
+		strings.Contains(msg, "index not exist")
+}
+
+// diffSchema : field by field differences of the source schema and the exist target schema, empty means compatible
+func diffSchema(source *entity.Schema, target *entity.Schema) []string {
+	var diffs []string
+	targetFields := make(map[string]*entity.Field, len(target.Fields))
+	for _, field := range target.Fields {
+		if !field.IsDynamic {
+			targetFields[field.Name] = field
+		}
+	}
+	sourceFields := make(map[string]bool, len(source.Fields))
+	for _, sf := range source.Fields {
+		sourceFields[sf.Name] = true
+		tf, ok := targetFields[sf.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("field %s (%s) not exist in target", sf.Name, sf.DataType.Name()))
+			continue
+		}
+		diffs = append(diffs, diffField(source, sf, target, tf)...)
+	}
+	for _, tf := range target.Fields {
+		if tf.IsDynamic || sourceFields[tf.Name] {
+			continue
+		}
+		if tf.PrimaryKey && isAutoID(target, tf) {
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("field %s (%s) only exist in target, source data not contain it", tf.Name, tf.DataType.Name()))
+	}
+	if source.EnableDynamicField && !target.EnableDynamicField {
+		diffs = append(diffs, "dynamic field enabled in source but disabled in target")
+	}
+	return diffs
+}
+
+func diffField(source *entity.Schema, sf *entity.Field, target *entity.Schema, tf *entity.Field) []string {
+	if sf.DataType != tf.DataType {
+		return []string{fmt.Sprintf("field %s data type source=%s target=%s", sf.Name, sf.DataType.Name(), tf.DataType.Name())}
+	}
+	var diffs []string
+	if sf.PrimaryKey != tf.PrimaryKey {
+		diffs = append(diffs, fmt.Sprintf("field %s primary key source=%t target=%t", sf.Name, sf.PrimaryKey, tf.PrimaryKey))
+	} else if sf.PrimaryKey && isAutoID(source, sf) != isAutoID(target, tf) {
+		diffs = append(diffs, fmt.Sprintf("field %s autoId source=%t target=%t", sf.Name, isAutoID(source, sf), isAutoID(target, tf)))
+	}
+	switch sf.DataType {
+	case entity.FieldTypeFloatVector, entity.FieldTypeBinaryVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		if diff := diffTypeParam(sf, tf, entity.TypeParamDim, false); diff != "" {
+			diffs = append(diffs, diff)
+		}
+	case entity.FieldTypeVarChar:
+		if diff := diffTypeParam(sf, tf, entity.TypeParamMaxLength, true); diff != "" {
+			diffs = append(diffs, diff)
+		}
+	case entity.FieldTypeArray:
+		if sf.ElementType != tf.ElementType {
+			diffs = append(diffs, fmt.Sprintf("field %s element type source=%s target=%s", sf.Name, sf.ElementType.Name(), tf.ElementType.Name()))
+		}
+		if diff := diffTypeParam(sf, tf, entity.TypeParamMaxCapacity, true); diff != "" {
+			diffs = append(diffs, diff)
+		}
+		if sf.ElementType == entity.FieldTypeVarChar {
+			if diff := diffTypeParam(sf, tf, entity.TypeParamMaxLength, true); diff != "" {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+	return diffs
+}
+
+// diffTypeParam : atMost means the target value only need not less than source, eg: max_length
+func diffTypeParam(sf *entity.Field, tf *entity.Field, key string, atMost bool) string {
+	sv, tv := sf.TypeParams[key], tf.TypeParams[key]
+	if sv == tv || sv == "" {
+		return ""
+	}
+	if atMost {
+		var sn, tn int64
+		_, serr := fmt.Sscan(sv, &sn)
+		_, terr := fmt.Sscan(tv, &tn)
+		if serr == nil && terr == nil && sn <= tn {
+			return ""
+		}
+		return fmt.Sprintf("field %s %s source=%s larger than target=%s", sf.Name, key, sv, tv)
+	}
+	return fmt.Sprintf("field %s %s source=%s target=%s", sf.Name, key, sv, tv)
+}
+
+func isAutoID(schema *entity.Schema, field *entity.Field) bool {
+	return field.AutoID || schema.AutoID
+}
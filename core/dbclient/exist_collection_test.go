@@ -0,0 +1,175 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"testing"
+)
+
+func testSchema() *entity.Schema {
+	return &entity.Schema{
+		CollectionName:     "test",
+		EnableDynamicField: true,
+		Fields: []*entity.Field{
+			entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true),
+			entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(256),
+			entity.NewField().WithName("vec").WithDataType(entity.FieldTypeFloatVector).WithDim(128),
+		},
+	}
+}
+
+func TestDiffSchemaCompatible(t *testing.T) {
+	target := testSchema()
+	target.Fields[1].WithMaxLength(1024)
+	target.Fields = append(target.Fields, entity.NewField().WithName("$meta").WithDataType(entity.FieldTypeJSON).WithIsDynamic(true))
+	assert.Empty(t, diffSchema(testSchema(), target))
+}
+
+func TestDiffSchema(t *testing.T) {
+	target := testSchema()
+	target.EnableDynamicField = false
+	target.Fields = []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar).WithIsPrimaryKey(true).WithMaxLength(64),
+		entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(100),
+		entity.NewField().WithName("vec").WithDataType(entity.FieldTypeFloatVector).WithDim(256),
+		entity.NewField().WithName("extra").WithDataType(entity.FieldTypeBool),
+	}
+	diffs := diffSchema(testSchema(), target)
+	assert.Equal(t, []string{
+		"field id data type source=Int64 target=VarChar",
+		"field title max_length source=256 larger than target=100",
+		"field vec dim source=128 target=256",
+		"field extra (Bool) only exist in target, source data not contain it",
+		"dynamic field enabled in source but disabled in target",
+	}, diffs)
+}
+
+func TestDiffSchemaMissAndAutoID(t *testing.T) {
+	target := testSchema()
+	target.Fields = []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true),
+		entity.NewField().WithName("vec").WithDataType(entity.FieldTypeFloatVector).WithDim(128),
+	}
+	diffs := diffSchema(testSchema(), target)
+	assert.Equal(t, []string{
+		"field id autoId source=false target=true",
+		"field title (VarChar) not exist in target",
+	}, diffs)
+}
+
+// fakeMilvus : the target milvus only keep the collections in memory, create fail createFails times
+type fakeMilvus struct {
+	client.Client
+	collections map[string]*entity.Collection
+	dropped     int
+	createFails int
+	indexes     map[string][]entity.Index
+}
+
+func newFakeMilvus(schema *entity.Schema) *fakeMilvus {
+	return &fakeMilvus{collections: map[string]*entity.Collection{
+		schema.CollectionName: {Name: schema.CollectionName, Schema: schema, ShardNum: 2},
+	}}
+}
+
+func (f *fakeMilvus) HasCollection(_ context.Context, collName string) (bool, error) {
+	_, ok := f.collections[collName]
+	return ok, nil
+}
+
+func (f *fakeMilvus) DescribeCollection(_ context.Context, collName string) (*entity.Collection, error) {
+	return f.collections[collName], nil
+}
+
+func (f *fakeMilvus) DropCollection(_ context.Context, collName string, _ ...client.DropCollectionOption) error {
+	f.dropped++
+	delete(f.collections, collName)
+	return nil
+}
+
+func (f *fakeMilvus) CreateCollection(_ context.Context, schema *entity.Schema, shardsNum int32, _ ...client.CreateCollectionOption) error {
+	if f.createFails > 0 {
+		f.createFails--
+		return errors.New("create collection timeout")
+	}
+	f.collections[schema.CollectionName] = &entity.Collection{Name: schema.CollectionName, Schema: schema, ShardNum: shardsNum}
+	return nil
+}
+
+func (f *fakeMilvus) ShowPartitions(_ context.Context, _ string) ([]*entity.Partition, error) {
+	return []*entity.Partition{{Name: common.DEFAULT_PARTITION_NAME}}, nil
+}
+
+func (f *fakeMilvus) DescribeIndex(_ context.Context, _ string, fieldName string, _ ...client.IndexOption) ([]entity.Index, error) {
+	if len(f.indexes[fieldName]) == 0 {
+		return nil, errors.New("index not found")
+	}
+	return f.indexes[fieldName], nil
+}
+
+func (f *fakeMilvus) CreateIndex(_ context.Context, _ string, fieldName string, idx entity.Index, _ bool, _ ...client.IndexOption) error {
+	if len(f.indexes[fieldName]) > 0 {
+		return errors.New("at most one distinct index is allowed per field")
+	}
+	if f.indexes == nil {
+		f.indexes = make(map[string][]entity.Index)
+	}
+	f.indexes[fieldName] = append(f.indexes[fieldName], idx)
+	return nil
+}
+
+func TestResumeAppendOnExists(t *testing.T) {
+	for _, onExists := range []common.OnExists{common.OnExistsFail, common.OnExistsAppend, common.OnExistsDrop, common.OnExistsTruncate} {
+		cfg := &config.MigrationConfig{TargetMilvus2xCfg: &config.Milvus2xConfig{OnExists: string(onExists)}}
+		config.ForceResumeAppend(cfg)
+		assert.Equal(t, string(common.OnExistsAppend), cfg.TargetMilvus2xCfg.OnExists)
+
+		milvus := newFakeMilvus(testSchema())
+		needCreate, err := (&Milvus2x{milvus: milvus, onExists: cfg.TargetMilvus2xCfg.OnExists}).handleExistCollection(context.Background(), testSchema())
+		assert.NoError(t, err, onExists)
+		assert.False(t, needCreate, onExists)
+		assert.Equal(t, 0, milvus.dropped, onExists)
+	}
+}
+
+func TestTruncateRecreateWithExistSchema(t *testing.T) {
+	exist := testSchema()
+	exist.Fields[1].WithMaxLength(1024)
+	milvus := newFakeMilvus(exist)
+	milvus.createFails = 1
+	needCreate, err := (&Milvus2x{milvus: milvus, onExists: string(common.OnExistsTruncate)}).handleExistCollection(context.Background(), testSchema())
+	assert.NoError(t, err)
+	assert.False(t, needCreate)
+	assert.Equal(t, 1, milvus.dropped)
+	recreated := milvus.collections["test"]
+	assert.Equal(t, int32(2), recreated.ShardNum)
+	assert.Equal(t, "1024", recreated.Schema.Fields[1].TypeParams[entity.TypeParamMaxLength])
+}
+
+func TestCreateIndexIfNotExist(t *testing.T) {
+	milvus := newFakeMilvus(testSchema())
+	milvus.indexes = map[string][]entity.Index{
+		"vec": {entity.NewGenericIndex("vec", entity.HNSW, map[string]string{
+			"metric_type": "L2", "params": `{"M":"16","efConstruction":"200"}`})},
+	}
+	cli := &Milvus2x{milvus: milvus}
+	ctx := context.Background()
+
+	same, _ := entity.NewIndexHNSW(entity.L2, 16, 200)
+	assert.True(t, sameIndex(milvus.indexes["vec"][0], same))
+	assert.NoError(t, cli.CreateIndexIfNotExist(ctx, "test", "vec", same))
+
+	//different index on the indexed field only warn, not fail the migrated job
+	diff, _ := entity.NewIndexIvfFlat(entity.IP, 128)
+	assert.False(t, sameIndex(milvus.indexes["vec"][0], diff))
+	assert.NoError(t, cli.CreateIndexIfNotExist(ctx, "test", "vec", diff))
+	assert.Len(t, milvus.indexes["vec"], 1)
+
+	assert.NoError(t, cli.CreateIndexIfNotExist(ctx, "test", "title", entity.NewScalarIndexWithType(entity.Trie)))
+	assert.Len(t, milvus.indexes["title"], 1)
+}
@@ -2,7 +2,9 @@ package dbclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/zilliztech/milvus-migration/core/common"
@@ -11,6 +13,7 @@ import (
 	"github.com/zilliztech/milvus-migration/storage/milvus2x"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"reflect"
 	"strconv"
 	"time"
 )
@@ -21,7 +24,8 @@ var (
 )
 
 type Milvus2x struct {
-	milvus client.Client
+	milvus   client.Client
+	onExists string //target.onExists policy
 }
 
 func (this *Milvus2x) GetMilvus() client.Client {
@@ -88,7 +92,8 @@ func NewMilvus2xClient(cfg *config.Milvus2xConfig) (*Milvus2x, error) {
 	}
 
 	c := &Milvus2x{
-		milvus: milvus,
+		milvus:   milvus,
+		onExists: cfg.OnExists,
 	}
 
 	return c, nil
@@ -127,18 +132,20 @@ func (this *Milvus2x) CheckNeedCreateCollection(ctx context.Context, createParam
 		return err
 	}
 
+	schema := NumpySchema(createParam)
 	if exist {
-		log.Warn("find collection already exist, no need to create collection", zap.String("collectionName", createParam.CollectionName))
-		return nil
+		needCreate, err := this.handleExistCollection(ctx, schema)
+		if err != nil || !needCreate {
+			return err
+		}
 	}
 
-	return this.createCollection(ctx, createParam)
+	return this.createCollection(ctx, createParam.ShardsNum, schema)
 }
 
-func (this *Milvus2x) createCollection(ctx context.Context, createParam *common.CollectionParam) error {
-
-	// schema
-	schema := &entity.Schema{
+// NumpySchema : the fixed schema of milvus1x and faiss migration
+func NumpySchema(createParam *common.CollectionParam) *entity.Schema {
+	return &entity.Schema{
 		CollectionName: createParam.CollectionName,
 		Description:    "Migration from Milvus1.x",
 		AutoID:         false,
@@ -158,8 +165,11 @@ func (this *Milvus2x) createCollection(ctx context.Context, createParam *common.
 			},
 		},
 	}
+}
+
+func (this *Milvus2x) createCollection(ctx context.Context, shardsNum int, schema *entity.Schema) error {
 
-	err := this.milvus.CreateCollection(ctx, schema, int32(createParam.ShardsNum), client.WithConsistencyLevel(entity.ClBounded))
+	err := this.milvus.CreateCollection(ctx, schema, int32(shardsNum), client.WithConsistencyLevel(entity.ClBounded))
 	if err != nil {
 		log.Error("call milvus2x CreateCollection error", zap.Error(err))
 		return err
@@ -291,3 +301,69 @@ func (this *Milvus2x) StartBatchUpsert(ctx context.Context, collection string, d
 	log.LL(ctx).Info("[Loader] success to BatchUpsert to Milvus", zap.String("col", collection), zap.String("partition", data.Partition))
 	return nil
 }
+
+func (this *Milvus2x) CreateIndex(ctx context.Context, collection string, fieldName string, index entity.Index) error {
+	log.LL(ctx).Info("[Milvus2x] begin to create index", zap.String("collection", collection),
+		zap.String("field", fieldName), zap.Any("params", index.Params()))
+	var opts []client.IndexOption
+	if index.Name() != common.EMPTY {
+		opts = append(opts, client.WithIndexName(index.Name()))
+	}
+	//sync mode, will wait index build finish
+	err := this.milvus.CreateIndex(ctx, collection, fieldName, index, false, opts...)
+	if err != nil {
+		log.LL(ctx).Error("[Milvus2x] create index error", zap.String("collection", collection),
+			zap.String("field", fieldName), zap.Error(err))
+		return err
+	}
+	log.LL(ctx).Info("[Milvus2x] success to create index", zap.String("collection", collection), zap.String("field", fieldName))
+	return nil
+}
+
+// CreateIndexIfNotExist : the field of an exist collection (append, truncate) may already has index,
+// create index on it again will fail after all data written, so skip it, and warn if the exist index is different
+func (this *Milvus2x) CreateIndexIfNotExist(ctx context.Context, collection string, fieldName string, index entity.Index) error {
+	exists, err := this.milvus.DescribeIndex(ctx, collection, fieldName)
+	if err != nil && !isIndexNotFound(err) {
+		return err
+	}
+	if len(exists) == 0 {
+		return this.CreateIndex(ctx, collection, fieldName, index)
+	}
+	if !sameIndex(exists[0], index) {
+		log.LL(ctx).Warn("[Milvus2x] field already has a different index, skip create index", zap.String("collection", collection),
+			zap.String("field", fieldName), zap.Any("exist", exists[0].Params()), zap.Any("params", index.Params()))
+		return nil
+	}
+	log.LL(ctx).Info("[Milvus2x] field already has index, skip create index", zap.String("collection", collection),
+		zap.String("field", fieldName))
+	return nil
+}
+
+// sameIndex : compare index type, metric type and build params, the build params may be a json string in "params" or flat
+func sameIndex(exist entity.Index, index entity.Index) bool {
+	return reflect.DeepEqual(flatIndexParams(exist.Params()), flatIndexParams(index.Params()))
+}
+
+func flatIndexParams(params map[string]string) map[string]string {
+	flat := make(map[string]string, len(params))
+	for k, v := range params {
+		if k != "params" {
+			flat[k] = v
+			continue
+		}
+		var build map[string]any
+		if json.Unmarshal([]byte(v), &build) != nil {
+			flat[k] = v
+			continue
+		}
+		for bk, bv := range build {
+			flat[bk] = fmt.Sprint(bv)
+		}
+	}
+	return flat
+}
+
+func (this *Milvus2x) LoadCollection(ctx context.Context, collection string, async bool) error {
+	return this.milvus.LoadCollection(ctx, collection, async)
+}
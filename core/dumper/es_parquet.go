@@ -0,0 +1,86 @@
+package dumper
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/gstore"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
+	esparser "github.com/zilliztech/milvus-migration/core/transform/es/parser"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/core/worker"
+	"github.com/zilliztech/milvus-migration/core/writer"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+)
+
+// SubParquetDataInES : convert es hits to milvus columns and write to parquet files,
+// a new file will be created when file size reach SUB_FILE_SIZE
+func (dp *Dumper) SubParquetDataInES(ctx context.Context, idxCfg *estype.IdxCfg, number int, esSource *source.ESSource) error {
+	fields, err := esconvert.ToMilvusFields(idxCfg)
+	if err != nil {
+		return err
+	}
+	enableDynamic := !idxCfg.MilvusCfg.CloseDynamicField
+	wokCfg := CloneWorkConfig(dp.cfg, idxCfg, nil)
+	collection := esconvert.ToMilvusCollectionName(idxCfg)
+
+	sort := 1
+	var pqWriter *writer.ParquetWriter
+	var targetFileName string
+	finishFile := func() error {
+		err := pqWriter.Close()
+		if err != nil {
+			return err
+		}
+		gstore.GetProcessHandler(dp.jobId).AddDumpedSize(pqWriter.Rows(), ctx)
+		gstore.AddFileSubTask(dp.jobId, collection, targetFileName)
+		dp.Submitter.Commit(targetFileName, collection)
+		log.LL(ctx).Info("End to dump sub ES task data to parquet", zap.String("Index", idxCfg.Index),
+			zap.Int("SubTaskNumber", number), zap.Int("Sort", sort), zap.String("Target", targetFileName))
+		pqWriter = nil
+		sort++
+		return nil
+	}
+	for data := range esSource.DataChannel {
+		if data.IsEmpty {
+			continue
+		}
+		columns, err := esparser.ToMilvusColumns(&data.Hits, idxCfg, fields, enableDynamic)
+		if err != nil {
+			log.Error("[Dumper] parse es data error", zap.String("index", idxCfg.Index), zap.Error(err))
+			if pqWriter != nil {
+				pqWriter.Abort(err)
+			}
+			return err
+		}
+		if pqWriter == nil {
+			targetFileName = util.GenerateESDataSubFileNameWithExt(wokCfg.InnerWriteCfg.FileParam.FileDir, number, sort,
+				string(common.FormatParquet))
+			wokCfg.InnerWriteCfg.FileParam.FileFullName = targetFileName
+			pqWriter, err = worker.NewParquetWriter(ctx, wokCfg.InnerWriteCfg, fields, enableDynamic)
+			if err != nil {
+				return err
+			}
+			log.LL(ctx).Info("Begin to dump sub ES task data to parquet", zap.String("Index", idxCfg.Index),
+				zap.Int("SubTaskNumber", number), zap.Int("Sort", sort), zap.String("Target", targetFileName),
+				zap.String("TargetMode", wokCfg.InnerWriteCfg.WriteMode))
+		}
+		err = pqWriter.Write(columns)
+		if err != nil {
+			pqWriter.Abort(err)
+			return err
+		}
+		if pqWriter.Size() >= common.SUB_FILE_SIZE {
+			err = finishFile()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if pqWriter != nil {
+		return finishFile()
+	}
+	return nil
+}
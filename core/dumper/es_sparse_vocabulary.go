@@ -0,0 +1,39 @@
+package dumper
+
+import (
+	"bytes"
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/core/writer"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+)
+
+// SaveSparseVocabularies : persist the sparse field token vocabulary of the job, query need it to encode the sparse vector
+func (dp *Dumper) SaveSparseVocabularies(ctx context.Context, idxCfg *estype.IdxCfg) error {
+	for fieldName, vocabulary := range idxCfg.InnerVocabularies {
+		b, err := vocabulary.Marshal()
+		if err != nil {
+			return err
+		}
+		fileDir, fileName := util.GenerateESVocabularyFilePath(dp.cfg.SparseVocabularyDir, dp.jobId, idxCfg.Index, fieldName)
+		fileParam := common.FileParam{FileDir: fileDir, FileFullName: fileName}
+		var fileWriter writer.Receiver
+		if dp.cfg.TargetMode == "remote" {
+			fileParam.BucketName = dp.cfg.TargetRemote.BucketName
+			fileWriter = writer.NewRemoteWriter(dp.cfg.TargetRemote, &fileParam)
+		} else {
+			fileWriter = writer.NewDefaultFileWriter(fileParam)
+		}
+		err = fileWriter.Execute(ctx, bytes.NewReader(b))
+		if err != nil {
+			log.LL(ctx).Error("[Dumper] save es sparse vocabulary error", zap.String("file", fileName), zap.Error(err))
+			return err
+		}
+		log.LL(ctx).Info("[Dumper] save es sparse vocabulary", zap.String("index", idxCfg.Index),
+			zap.String("field", fieldName), zap.Int("tokens", vocabulary.Size()), zap.String("file", fileName))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package dumper
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"math"
+	"strconv"
+)
+
+const pkSampleBatchSize = 10000
+
+// splitPKRanges : split the partition pk space into n range exprs, return nil when partition data is empty.
+// int64 pk: use min/max pk split evenly, varchar pk: use the pk every partitionRows/n of a full pk scan as boundaries
+func splitPKRanges(ctx context.Context, src *source.Milvus2xSource, collCfg *milvus2xtype.CollectionCfg,
+	partition string, baseExpr string, n int) ([]string, error) {
+
+	first, err := src.Cli.VerCli.QueryPK(ctx, collCfg, partition, baseExpr, 1)
+	if err != nil {
+		return nil, err
+	}
+	if first == nil || first.Len() == 0 {
+		return nil, nil
+	}
+	pkName := collCfg.MilvusCfg.PkName
+	var ranges []string
+	switch pkCol := first.(type) {
+	case *entity.ColumnInt64:
+		minPK := pkCol.Data()[0]
+		maxPK, err := findMaxInt64PK(ctx, src, collCfg, partition, baseExpr, minPK)
+		if err != nil {
+			return nil, err
+		}
+		ranges = toRangeExprs(pkName, splitInt64Boundaries(minPK, maxPK, n), func(pk int64) string {
+			return strconv.FormatInt(pk, 10)
+		})
+	case *entity.ColumnVarChar:
+		boundaries, err := sampleVarCharBoundaries(ctx, src, collCfg, partition, baseExpr, n)
+		if err != nil {
+			return nil, err
+		}
+		ranges = toRangeExprs(pkName, boundaries, strconv.Quote)
+	default:
+		return nil, fmt.Errorf("split pk range not support pk type %T", first)
+	}
+	log.Info("[Dumper] split collection pk ranges", zap.String("collection", collCfg.Collection),
+		zap.String("partition", partition), zap.Strings("ranges", ranges))
+	return ranges, nil
+}
+
+// findMaxInt64PK : milvus query not support max(), binary search by 'pk >= x' exists query
+func findMaxInt64PK(ctx context.Context, src *source.Milvus2xSource, collCfg *milvus2xtype.CollectionCfg,
+	partition string, baseExpr string, minPK int64) (int64, error) {
+	pkName := collCfg.MilvusCfg.PkName
+	lo, hi := minPK, int64(math.MaxInt64)
+	for lo < hi {
+		mid := lo + int64((uint64(hi)-uint64(lo)+1)/2)
+		expr := combineExpr(baseExpr, fmt.Sprintf("%s >= %d", pkName, mid))
+		col, err := src.Cli.VerCli.QueryPK(ctx, collCfg, partition, expr, 1)
+		if err != nil {
+			return 0, err
+		}
+		if col != nil && col.Len() > 0 {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, nil
+}
+
+// splitInt64Boundaries : return the inner boundaries of n ranges in [minPK, maxPK]
+func splitInt64Boundaries(minPK int64, maxPK int64, n int) []int64 {
+	step := (uint64(maxPK)-uint64(minPK))/uint64(n) + 1
+	boundaries := make([]int64, 0, n-1)
+	for i := 1; i < n; i++ {
+		offset := step * uint64(i)
+		if offset > uint64(maxPK)-uint64(minPK) {
+			break
+		}
+		boundaries = append(boundaries, minPK+int64(offset))
+	}
+	return boundaries
+}
+
+// sampleVarCharBoundaries : full pk scan of the partition by iterate only the pk field,
+// take pk every partitionRows/n as boundaries, the partition rows counted by the same filter
+func sampleVarCharBoundaries(ctx context.Context, src *source.Milvus2xSource, collCfg *milvus2xtype.CollectionCfg,
+	partition string, baseExpr string, n int) ([]string, error) {
+	countCfg := *collCfg
+	countCfg.Filter = baseExpr
+	partitionRows, err := src.Cli.VerCli.CountPartition(ctx, &countCfg, partition)
+	if err != nil {
+		return nil, err
+	}
+	stride := partitionRows / int64(n)
+	if stride <= 0 {
+		return nil, nil
+	}
+	err = src.Cli.VerCli.InitIterator(ctx, collCfg, pkSampleBatchSize, partition, baseExpr, []string{collCfg.MilvusCfg.PkName})
+	if err != nil {
+		return nil, err
+	}
+	boundaries := make([]string, 0, n-1)
+	var read int64
+	for len(boundaries) < n-1 {
+		data, err := src.Cli.VerCli.IterateNext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if data.IsEmpty {
+			break
+		}
+		for _, col := range data.Columns {
+			pkCol, ok := col.(*entity.ColumnVarChar)
+			if !ok || col.Name() != collCfg.MilvusCfg.PkName {
+				continue
+			}
+			for _, pk := range pkCol.Data() {
+				read++
+				if read%stride == 0 && len(boundaries) < n-1 {
+					boundaries = append(boundaries, pk)
+				}
+			}
+		}
+	}
+	return boundaries, nil
+}
+
+// toRangeExprs : boundaries [b1, b2] => ["pk < b1", "pk >= b1 and pk < b2", "pk >= b2"]
+func toRangeExprs[T any](pkName string, boundaries []T, format func(T) string) []string {
+	ranges := make([]string, 0, len(boundaries)+1)
+	if len(boundaries) == 0 {
+		return append(ranges, "")
+	}
+	for i, b := range boundaries {
+		if i == 0 {
+			ranges = append(ranges, fmt.Sprintf("%s < %s", pkName, format(b)))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%s >= %s and %s < %s", pkName, format(boundaries[i-1]), pkName, format(b)))
+		}
+	}
+	return append(ranges, fmt.Sprintf("%s >= %s", pkName, format(boundaries[len(boundaries)-1])))
+}
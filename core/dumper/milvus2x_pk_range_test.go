@@ -0,0 +1,25 @@
+package dumper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestSplitInt64Boundaries(t *testing.T) {
+	assert.Equal(t, []int64{26, 51, 76}, splitInt64Boundaries(1, 100, 4))
+	assert.Equal(t, []int64{}, splitInt64Boundaries(5, 5, 4))
+	assert.Equal(t, []int64{6, 7}, splitInt64Boundaries(5, 7, 4))
+
+	boundaries := splitInt64Boundaries(math.MinInt64, math.MaxInt64, 2)
+	assert.Equal(t, []int64{0}, boundaries)
+}
+
+func TestToRangeExprs(t *testing.T) {
+	format := func(pk int64) string { return strconv.FormatInt(pk, 10) }
+	assert.Equal(t, []string{""}, toRangeExprs("id", []int64{}, format))
+	assert.Equal(t, []string{"id < 10", "id >= 10 and id < 20", "id >= 20"}, toRangeExprs("id", []int64{10, 20}, format))
+	assert.Equal(t, []string{`name < "b"`, `name >= "b"`}, toRangeExprs("name", []string{"b"}, strconv.Quote))
+	assert.Equal(t, "(age > 1) and id < 10", combineExpr("age > 1", "id < 10"))
+}
@@ -15,12 +15,18 @@ import (
 func (this *Dumper) doDumpInFaissMode(ctx context.Context) error {
 	var g errgroup.Group
 
-	g.Go(func() error {
-		return faissId2numpy(ctx, this.cfg)
-	})
-	g.Go(func() error {
-		return faissData2numpy(ctx, this.cfg)
-	})
+	if this.isParquetFormat() {
+		g.Go(func() error {
+			return faiss2parquet(ctx, this.cfg)
+		})
+	} else {
+		g.Go(func() error {
+			return faissId2numpy(ctx, this.cfg)
+		})
+		g.Go(func() error {
+			return faissData2numpy(ctx, this.cfg)
+		})
+	}
 
 	err := g.Wait()
 	if err != nil {
@@ -59,12 +59,18 @@ func (this *Dumper) workBatch(ctx context.Context, segColInfos []milvustype.SegC
 func (this *Dumper) workInMilvus1xMode(ctx context.Context, segColInfo milvustype.SegColInfo) error {
 	var g errgroup.Group
 
-	g.Go(func() error {
-		return rv2numpy(ctx, this.cfg, segColInfo)
-	})
-	g.Go(func() error {
-		return uid2numpy(ctx, this.cfg, segColInfo)
-	})
+	if this.isParquetFormat() {
+		g.Go(func() error {
+			return milvus1x2parquet(ctx, this.cfg, segColInfo)
+		})
+	} else {
+		g.Go(func() error {
+			return rv2numpy(ctx, this.cfg, segColInfo)
+		})
+		g.Go(func() error {
+			return uid2numpy(ctx, this.cfg, segColInfo)
+		})
+	}
 
 	err := g.Wait()
 	if err != nil {
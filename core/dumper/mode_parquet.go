@@ -0,0 +1,117 @@
+package dumper
+
+import (
+	"context"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/core/worker"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"path/filepath"
+)
+
+func (this *Dumper) isParquetFormat() bool {
+	return this.cfg.DumperWorkCfg.FileFormat == string(common.FormatParquet)
+}
+
+// milvus1x2parquet : combine segment uid and rv file to parquet files, deleted docs will be skipped
+func milvus1x2parquet(ctx context.Context, insCfg *config.MigrationConfig, segColInfo milvustype.SegColInfo) error {
+	targetDir, _ := util.GetOutputRVFilePath(insCfg.TargetOutputDir, &segColInfo)
+	idReadCfg, dataReadCfg := newMilvus1xReadConfigs(insCfg, segColInfo)
+	return numpy2parquet(ctx, insCfg, idReadCfg, dataReadCfg, targetDir)
+}
+
+// ReadMilvus1xColumns : read the segment ids and vectors as milvus columns batches, deleted docs will be skipped
+func ReadMilvus1xColumns(ctx context.Context, insCfg *config.MigrationConfig, segColInfo milvustype.SegColInfo,
+	handle func(columns []entity.Column) error) error {
+	idReadCfg, dataReadCfg := newMilvus1xReadConfigs(insCfg, segColInfo)
+	return readNumpyColumns(ctx, idReadCfg, dataReadCfg, handle)
+}
+
+func newMilvus1xReadConfigs(insCfg *config.MigrationConfig, segColInfo milvustype.SegColInfo) (*config.ReadConfig, *config.ReadConfig) {
+	sourceUIDPath := util.GetSourceUIDFilePath(insCfg.SourceTablesDir, &segColInfo)
+	sourceRVPath := util.GetSourceRVFilePath(insCfg.SourceTablesDir, &segColInfo)
+	deleteFilePath := util.GetSourceDeletedDocsFilePath(insCfg.SourceTablesDir, &segColInfo)
+
+	idReadCfg := newParquetSourceReadConfig(insCfg, sourceUIDPath, common.UID)
+	idReadCfg.DeleteFile = &common.FileParam{FileFullName: deleteFilePath, BucketName: insCfg.SourceRemote.BucketName}
+	dataReadCfg := newParquetSourceReadConfig(insCfg, sourceRVPath, common.RV)
+	dataReadCfg.DeleteFile = &common.FileParam{FileFullName: deleteFilePath, BucketName: insCfg.SourceRemote.BucketName}
+	dataReadCfg.Dim = segColInfo.Dim
+	return idReadCfg, dataReadCfg
+}
+
+// faiss2parquet : combine faiss ids and vectors to parquet files
+func faiss2parquet(ctx context.Context, insCfg *config.MigrationConfig) error {
+	targetDir, _ := util.GenerateFaissDataFilePath(insCfg.TargetOutputDir, insCfg.LoaderWorkCfg.CreateColCfg.CollectionName)
+	idReadCfg := newParquetSourceReadConfig(insCfg, insCfg.SourceFaissFile, common.FAISS_ID)
+	dataReadCfg := newParquetSourceReadConfig(insCfg, insCfg.SourceFaissFile, common.FAISS_DATA)
+	return numpy2parquet(ctx, insCfg, idReadCfg, dataReadCfg, targetDir)
+}
+
+// ReadFaissColumns : read the faiss ids and vectors as milvus columns batches
+func ReadFaissColumns(ctx context.Context, insCfg *config.MigrationConfig, handle func(columns []entity.Column) error) error {
+	idReadCfg := newParquetSourceReadConfig(insCfg, insCfg.SourceFaissFile, common.FAISS_ID)
+	dataReadCfg := newParquetSourceReadConfig(insCfg, insCfg.SourceFaissFile, common.FAISS_DATA)
+	return readNumpyColumns(ctx, idReadCfg, dataReadCfg, handle)
+}
+
+// ReadFaissHead : read the vector dim and total rows of the faiss file, not read the vectors
+func ReadFaissHead(insCfg *config.MigrationConfig) (int, int, error) {
+	return worker.ReadFaissHead(newParquetSourceReadConfig(insCfg, insCfg.SourceFaissFile, common.FAISS_ID))
+}
+
+func readNumpyColumns(ctx context.Context, idReadCfg *config.ReadConfig, dataReadCfg *config.ReadConfig,
+	handle func(columns []entity.Column) error) error {
+	wrk, err := worker.NewNumpyColumnWorker(idReadCfg, dataReadCfg)
+	if err != nil {
+		return err
+	}
+	return wrk.Work(ctx, func(columns []entity.Column, _ bool) error {
+		return handle(columns)
+	})
+}
+
+func numpy2parquet(ctx context.Context, insCfg *config.MigrationConfig, idReadCfg *config.ReadConfig,
+	dataReadCfg *config.ReadConfig, targetDir string) error {
+	writeCfg := &config.WriteConfig{
+		WriteMode: insCfg.TargetMode,
+		FileParam: &common.FileParam{
+			FileDir:    targetDir,
+			BucketName: insCfg.TargetRemote.BucketName,
+		},
+		BufSize:      insCfg.DumperWorkCfg.WriterBufferSize,
+		RemoteConfig: insCfg.TargetRemote,
+	}
+	wrk, err := worker.NewNumpyParquetWorker(idReadCfg, dataReadCfg, writeCfg)
+	if err != nil {
+		return err
+	}
+
+	log.LL(ctx).Info("Begin to dump data to parquet",
+		zap.String("Source", dataReadCfg.FileParam.FileFullName), zap.String("Target", filepath.Join(targetDir, "*.parquet")),
+		zap.String("readMode", insCfg.SourceMode), zap.String("writeMode", insCfg.TargetMode))
+	files, err := wrk.Work(ctx)
+	if err != nil {
+		return err
+	}
+	log.LL(ctx).Info("End to dump data to parquet",
+		zap.String("Source", dataReadCfg.FileParam.FileFullName), zap.Strings("Target", files))
+	return nil
+}
+
+func newParquetSourceReadConfig(insCfg *config.MigrationConfig, sourceFilePath string, readerType string) *config.ReadConfig {
+	return &config.ReadConfig{
+		ReadMode: insCfg.SourceMode,
+		FileParam: &common.FileParam{
+			FileFullName: sourceFilePath,
+			BucketName:   insCfg.SourceRemote.BucketName,
+		},
+		ReaderType:   readerType,
+		BufSize:      insCfg.DumperWorkCfg.ReaderBufferSize,
+		RemoteConfig: insCfg.SourceRemote,
+	}
+}
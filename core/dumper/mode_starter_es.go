@@ -40,7 +40,7 @@ func (dp *Dumper) InitDumpInEsMode(ctx context.Context) ([][]*estype.IdxCfg, err
 
 func (dp *Dumper) WorkBatchInES(ctx context.Context, idxCfgs []*estype.IdxCfg) error {
 	var g errgroup.Group
-	for i, _ := range idxCfgs {
+	for i := range idxCfgs {
 		finalI := i
 		g.Go(func() error {
 			return dp.WorkOneInES(ctx, idxCfgs[finalI])
@@ -54,6 +54,10 @@ func (dp *Dumper) WorkOneInES(ctx context.Context, idxCfg *estype.IdxCfg) error
 	if err != nil {
 		return err
 	}
+	err = dp.SaveSparseVocabularies(ctx, idxCfg)
+	if err != nil {
+		return err
+	}
 	gstore.AddFinishTasks(dp.jobId, 1)
 	return nil
 }
@@ -61,7 +65,12 @@ func (dp *Dumper) WorkOneInES(ctx context.Context, idxCfg *estype.IdxCfg) error
 func (dp *Dumper) StreamDataInES(ctx context.Context, idxCfg *estype.IdxCfg) error {
 
 	esSource := source.NewESSource(idxCfg, dp.cfg)
-	_, err := esSource.ReadFirst()
+	var err error
+	if esSource.IsPITMode() {
+		err = esSource.Count()
+	} else {
+		_, err = esSource.ReadFirst()
+	}
 	if err != nil {
 		return err
 	}
@@ -70,16 +79,32 @@ func (dp *Dumper) StreamDataInES(ctx context.Context, idxCfg *estype.IdxCfg) err
 	gstore.GetProcessHandler(dp.jobId).SetDumpTotalSize(idxCfg.Rows)
 
 	wokReadCfg := CloneWorkReadConfig(dp.cfg)
-	var g errgroup.Group
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	g, subCtx := errgroup.WithContext(cancelCtx)
 	//for number := 1; number <= common.DUMP_SUB_TASK_NUM; number++ {
 	for number := 1; number <= dp.concurLimit; number++ {
 		subNum := number
 		g.Go(func() error {
-			return dp.SubJsonDataInES(idxCfg, subNum, wokReadCfg, ctx, channel)
+			if dp.isParquetFormat() {
+				return dp.SubParquetDataInES(subCtx, idxCfg, subNum, esSource)
+			}
+			return dp.SubJsonDataInES(idxCfg, subNum, wokReadCfg, subCtx, channel)
 		})
 	}
-	err = dp.LoopReadESStreamData(esSource)
+	if esSource.IsPITMode() {
+		//pit slices read by parallel, sub tasks stop when DataChannel closed
+		err = esSource.ReadByPIT(subCtx)
+	} else {
+		err = dp.LoopReadESStreamData(esSource)
+		if err != nil {
+			esSource.Close()
+		}
+	}
 	if err != nil {
+		//stop the sub tasks and wait them exit, the DataChannel already closed
+		cancel()
+		g.Wait()
 		return err
 	}
 
@@ -3,16 +3,19 @@ package dumper
 import (
 	"context"
 	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/data"
 	"github.com/zilliztech/milvus-migration/core/gstore"
 	"github.com/zilliztech/milvus-migration/core/meta"
 	"github.com/zilliztech/milvus-migration/core/reader/source"
 	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/core/util"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"github.com/zilliztech/milvus-migration/storage/milvus2x"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
-func (dp *Dumper) InitDumpInMilvus2xMode(ctx context.Context) (*milvus2xtype.CollectionCfg, error) {
+func (dp *Dumper) InitDumpInMilvus2xMode(ctx context.Context) ([][]*milvus2xtype.CollectionCfg, error) {
 	// new meta helper
 	metaHelper := meta.NewMetaHelperForDumper(dp.cfg)
 	// read meta
@@ -21,13 +24,20 @@ func (dp *Dumper) InitDumpInMilvus2xMode(ctx context.Context) (*milvus2xtype.Col
 		return nil, err
 	}
 
+	splitArray := util.SplitArray(milvus2xMetaJson.CollCfgs, dp.concurLimit)
+
 	log.LL(ctx).Info("dump Milvus2x split collections for concurrent work",
 		zap.Int("CollectionSize", len(milvus2xMetaJson.CollCfgs)),
 		zap.Int("ConcurLimit", dp.concurLimit),
+		zap.Int("QueueSize", len(splitArray)),
 	)
 	dp.cfg.SourceMilvus2xConfig.Version = milvus2xMetaJson.Version
-	gstore.SetTotalTasks(dp.jobId, 1)
-	return milvus2xMetaJson.CollCfgs[0], err //先只考虑单collection
+	gstore.SetTotalTasks(dp.jobId, len(milvus2xMetaJson.CollCfgs))
+	//每个collection单独记录进度, 同时累加到job进度
+	for _, collCfg := range milvus2xMetaJson.CollCfgs {
+		gstore.InitCollProcessHandler(dp.jobId, collCfg.Collection)
+	}
+	return splitArray, err
 }
 
 func (dp *Dumper) WorkInMilvus2x(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, dataChannel chan *milvus2x.Milvus2xData) error {
@@ -35,14 +45,17 @@ func (dp *Dumper) WorkInMilvus2x(ctx context.Context, collCfg *milvus2xtype.Coll
 	if err != nil {
 		return err
 	}
-	gstore.GetProcessHandler(dp.jobId).SetDumpFinished()
-	gstore.AddFinishTasks(dp.jobId, 1)
+	gstore.GetCollProcessHandler(dp.jobId, collCfg.Collection).SetDumpFinished()
 	return nil
 }
 
 func (dp *Dumper) ReadData2Channel(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, dataChannel chan *milvus2x.Milvus2xData) error {
 
-	source := source.NewMilvus2xSource(collCfg, dp.cfg, dataChannel)
+	source, err := source.NewMilvus2xSource(collCfg, dp.cfg, dataChannel)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
 
 	count, err := source.Count(ctx, collCfg)
 	if err != nil {
@@ -50,8 +63,9 @@ func (dp *Dumper) ReadData2Channel(ctx context.Context, collCfg *milvus2xtype.Co
 	}
 	collCfg.Rows = count
 	//设置进度相关信息：dump & load 总数量
-	gstore.GetProcessHandler(dp.jobId).SetDumpTotalSize(collCfg.Rows)
-	gstore.GetProcessHandler(dp.jobId).SetLoadTotalSize(collCfg.Rows)
+	ph := gstore.GetCollProcessHandler(dp.jobId, collCfg.Collection)
+	ph.SetDumpTotalSize(collCfg.Rows)
+	ph.SetLoadTotalSize(collCfg.Rows)
 
 	partitionNames := getPartitionNames(collCfg)
 	fieldNames := getIteratorFields(collCfg)
@@ -62,21 +76,106 @@ func (dp *Dumper) ReadData2Channel(ctx context.Context, collCfg *milvus2xtype.Co
 	log.Info("start iterator milvus collection", zap.Any("migration milvusCfg", collCfg.MilvusCfg))
 	log.Info("start iterator milvus collection", zap.Any("migration fields", collCfg.Fields))
 
+	collCkpt := dp.getCollCheckpoint(collCfg.Collection)
 	for _, partition := range partitionNames {
 		source.CurrPartition = partition
-		dataIsEmpty, err := dp.readFirstData(ctx, source)
+		source.CurrExpr = collCfg.Filter
+		if collCkpt != nil {
+			if collCkpt.IsPartitionFinished(partition) {
+				log.Info("[Dumper] resume job, skip finished partition", zap.String("collection", collCfg.Collection),
+					zap.String("partition", partition))
+				continue
+			}
+			//partition read by pk ranges before, continue every range from its checkpoint
+			if resumeRanges := collCkpt.ResumeRanges(partition); resumeRanges != nil {
+				log.Info("[Dumper] resume job, continue pk ranges of partition", zap.String("collection", collCfg.Collection),
+					zap.String("partition", partition), zap.Strings("ranges", resumeRanges))
+				err = dp.readPKRanges(ctx, collCfg, source, ph, dataChannel, collCkpt.PKRanges, resumeRanges)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			source.CurrExpr = combineExpr(collCfg.Filter, collCkpt.ResumeExpr(partition))
+		}
+		if dp.cfg.DumperWorkCfg.ReaderParallel > 1 {
+			err = dp.readPartitionByPKRanges(ctx, collCfg, source, ph, dataChannel)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		dataIsEmpty, err := dp.readFirstData(ctx, source, ph)
 		if err != nil {
 			return err
 		}
 		if dataIsEmpty {
 			continue
 		}
-		err = dp.LoopReadData(ctx, source)
+		err = dp.LoopReadData(ctx, source, ph)
 		if err != nil {
 			return err
 		}
 	}
-	return source.Close()
+	return nil
+}
+
+// readPartitionByPKRanges : split the partition into pk ranges, each range read by its own source iterator
+func (dp *Dumper) readPartitionByPKRanges(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partSource *source.Milvus2xSource,
+	ph *data.ProcessHandler, dataChannel chan *milvus2x.Milvus2xData) error {
+
+	ranges, err := splitPKRanges(ctx, partSource, collCfg, partSource.CurrPartition, partSource.CurrExpr, dp.cfg.DumperWorkCfg.ReaderParallel)
+	if err != nil {
+		return err
+	}
+	return dp.readPKRanges(ctx, collCfg, partSource, ph, dataChannel, ranges, ranges)
+}
+
+// readPKRanges : read exprs in parallel, exprs[i] is ranges[i] or its resume expr, the batch record ranges[i] for checkpoint
+func (dp *Dumper) readPKRanges(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partSource *source.Milvus2xSource,
+	ph *data.ProcessHandler, dataChannel chan *milvus2x.Milvus2xData, ranges []string, exprs []string) error {
+
+	g, subCtx := errgroup.WithContext(ctx)
+	for i, rangeExpr := range exprs {
+		pkRange := ranges[i]
+		finalExpr := combineExpr(partSource.CurrExpr, rangeExpr)
+		g.Go(func() error {
+			rangeSource, err := source.NewMilvus2xSource(collCfg, dp.cfg, dataChannel)
+			if err != nil {
+				return err
+			}
+			defer rangeSource.Close()
+			rangeSource.FieldNames = partSource.FieldNames
+			rangeSource.CurrPartition = partSource.CurrPartition
+			rangeSource.CurrExpr = finalExpr
+			rangeSource.PKRanges = ranges
+			rangeSource.PKRange = pkRange
+			dataIsEmpty, err := dp.readFirstData(subCtx, rangeSource, ph)
+			if err != nil || dataIsEmpty {
+				return err
+			}
+			return dp.LoopReadData(subCtx, rangeSource, ph)
+		})
+	}
+	return g.Wait()
+}
+
+func (dp *Dumper) getCollCheckpoint(collection string) *data.CollCheckpoint {
+	ckpt := gstore.GetCheckpoint(dp.jobId)
+	if ckpt == nil {
+		return nil
+	}
+	return ckpt.GetCollection(collection)
+}
+
+func combineExpr(filter string, resumeExpr string) string {
+	if filter == "" {
+		return resumeExpr
+	}
+	if resumeExpr == "" {
+		return filter
+	}
+	return "(" + filter + ") and " + resumeExpr
 }
 
 func getIteratorFields(collCfg *milvus2xtype.CollectionCfg) []string {
@@ -105,7 +204,7 @@ func getPartitionNames(collCfg *milvus2xtype.CollectionCfg) []string {
 	return partitionNames
 }
 
-func (dp *Dumper) readFirstData(ctx context.Context, source *source.Milvus2xSource) (bool, error) {
+func (dp *Dumper) readFirstData(ctx context.Context, source *source.Milvus2xSource, ph *data.ProcessHandler) (bool, error) {
 	data, err := source.ReadFirst(ctx)
 	if err != nil {
 		return false, err
@@ -115,18 +214,18 @@ func (dp *Dumper) readFirstData(ctx context.Context, source *source.Milvus2xSour
 		return true, nil
 	}
 	//已完成dump数量
-	gstore.GetProcessHandler(dp.jobId).AddDumpedSize(data.Columns[0].Len(), ctx)
+	ph.AddDumpedSize(data.Columns[0].Len(), ctx)
 	return false, nil
 }
 
-func (dp *Dumper) LoopReadData(ctx context.Context, source *source.Milvus2xSource) error {
+func (dp *Dumper) LoopReadData(ctx context.Context, source *source.Milvus2xSource, ph *data.ProcessHandler) error {
 	data, err := source.ReadNext(ctx)
 	if err != nil {
 		return err
 	}
 	for !data.IsEmpty {
 
-		gstore.GetProcessHandler(dp.jobId).AddDumpedSize(data.Columns[0].Len(), ctx)
+		ph.AddDumpedSize(data.Columns[0].Len(), ctx)
 		data, err = source.ReadNext(ctx)
 		if err != nil {
 			return err
@@ -0,0 +1,148 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	manifestconvert "github.com/zilliztech/milvus-migration/core/transform/manifest/convert"
+	"github.com/zilliztech/milvus-migration/core/type/manifesttype"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/core/writer"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// CollectionExporter : write the collection data to the files of each partition,
+// a new file will be created when the file size reach SUB_FILE_SIZE, Write can be called concurrently
+type CollectionExporter struct {
+	exporter   *Exporter
+	collCfg    *manifesttype.CollectionCfg
+	fields     []*entity.Field
+	partitions map[string]*partitionFile
+	mu         sync.Mutex
+}
+
+type partitionFile struct {
+	partCfg  *manifesttype.PartitionCfg
+	sort     int
+	fileCfg  *manifesttype.FileCfg
+	writer   writer.ColumnWriter
+	checksum *checksumReceiver
+}
+
+// NewCollectionExporter : source is the source collection or es index, dynamicField means the data have $meta column
+func (e *Exporter) NewCollectionExporter(info *common.CollectionInfo, source string, dynamicField bool) *CollectionExporter {
+	return &CollectionExporter{
+		exporter:   e,
+		collCfg:    manifestconvert.ToManifestCollection(info, source, dynamicField),
+		fields:     manifestconvert.DataFields(info.Fields),
+		partitions: make(map[string]*partitionFile),
+	}
+}
+
+func (ce *CollectionExporter) Write(ctx context.Context, partition string, columns []entity.Column) error {
+	if len(columns) == 0 || columns[0].Len() == 0 {
+		return nil
+	}
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	if partition == common.EMPTY {
+		partition = common.DEFAULT_PARTITION_NAME
+	}
+	pf, ok := ce.partitions[partition]
+	if !ok {
+		pf = &partitionFile{partCfg: &manifesttype.PartitionCfg{Name: partition, Files: make([]*manifesttype.FileCfg, 0)}}
+		ce.partitions[partition] = pf
+		ce.collCfg.Partitions = append(ce.collCfg.Partitions, pf.partCfg)
+	}
+	if pf.writer == nil {
+		err := ce.openFile(ctx, pf)
+		if err != nil {
+			return err
+		}
+	}
+	err := pf.writer.Write(columns)
+	if err != nil {
+		pf.writer.Abort(err)
+		pf.writer = nil
+		return err
+	}
+	if pf.writer.Size() >= common.SUB_FILE_SIZE {
+		return ce.closeFile(ctx, pf)
+	}
+	return nil
+}
+
+func (ce *CollectionExporter) openFile(ctx context.Context, pf *partitionFile) error {
+	pf.sort++
+	path := util.GenerateExportFilePath(ce.collCfg.Collection, pf.partCfg.Name, pf.sort, ce.exporter.cfg.TargetFilesFormat)
+	pf.checksum = newChecksumReceiver(ce.exporter.newReceiver(path))
+	var err error
+	switch common.FileFormat(ce.exporter.cfg.TargetFilesFormat) {
+	case common.FormatParquet:
+		pf.writer, err = writer.NewParquetWriter(ctx, pf.checksum, ce.fields, ce.collCfg.DynamicField)
+	case common.FormatJSONL:
+		pf.writer, err = writer.NewJSONLWriter(ctx, pf.checksum, ce.fields, ce.collCfg.DynamicField)
+	default:
+		err = fmt.Errorf("files target not support format %s", ce.exporter.cfg.TargetFilesFormat)
+	}
+	if err != nil {
+		pf.writer = nil
+		return err
+	}
+	pf.fileCfg = &manifesttype.FileCfg{Path: path}
+	log.LL(ctx).Info("[Exporter] begin to export collection file", zap.String("collection", ce.collCfg.Collection),
+		zap.String("partition", pf.partCfg.Name), zap.String("file", path))
+	return nil
+}
+
+func (ce *CollectionExporter) closeFile(ctx context.Context, pf *partitionFile) error {
+	err := pf.writer.Close()
+	if err != nil {
+		pf.writer = nil
+		return err
+	}
+	pf.fileCfg.Rows = int64(pf.writer.Rows())
+	pf.fileCfg.Size = pf.checksum.size
+	pf.fileCfg.SHA256 = pf.checksum.sum()
+	pf.partCfg.Files = append(pf.partCfg.Files, pf.fileCfg)
+	pf.partCfg.Rows += pf.fileCfg.Rows
+	pf.writer = nil
+	log.LL(ctx).Info("[Exporter] end to export collection file", zap.String("collection", ce.collCfg.Collection),
+		zap.String("file", pf.fileCfg.Path), zap.Int64("rows", pf.fileCfg.Rows), zap.Int64("size", pf.fileCfg.Size))
+	return nil
+}
+
+// Close : close the opened files and add the collection to manifest
+func (ce *CollectionExporter) Close(ctx context.Context) error {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	for _, partCfg := range ce.collCfg.Partitions {
+		pf := ce.partitions[partCfg.Name]
+		if pf.writer != nil {
+			err := ce.closeFile(ctx, pf)
+			if err != nil {
+				return err
+			}
+		}
+		ce.collCfg.Rows += pf.partCfg.Rows
+	}
+	ce.exporter.addCollection(ce.collCfg)
+	log.LL(ctx).Info("[Exporter] export collection finish", zap.String("collection", ce.collCfg.Collection),
+		zap.Int64("rows", ce.collCfg.Rows), zap.Int("partitions", len(ce.collCfg.Partitions)))
+	return nil
+}
+
+// Abort : stop write the opened files, the collection will not add to manifest
+func (ce *CollectionExporter) Abort(err error) {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	for _, pf := range ce.partitions {
+		if pf.writer != nil {
+			pf.writer.Abort(err)
+			pf.writer = nil
+		}
+	}
+}
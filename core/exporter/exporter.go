@@ -0,0 +1,104 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/manifesttype"
+	"github.com/zilliztech/milvus-migration/core/writer"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"hash"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Exporter : export collections data to parquet/jsonl files of the files target,
+// all exported collections are recorded in the manifest.json of output dir
+type Exporter struct {
+	cfg      *config.MigrationConfig
+	manifest *manifesttype.Manifest
+	mu       sync.Mutex
+}
+
+func NewExporter(cfg *config.MigrationConfig) *Exporter {
+	return &Exporter{
+		cfg: cfg,
+		manifest: &manifesttype.Manifest{
+			Version:     manifesttype.ManifestVersion,
+			Format:      cfg.TargetFilesFormat,
+			SourceType:  cfg.DumperWorkCfg.WorkMode,
+			CreateTime:  time.Now().Format(time.RFC3339),
+			Collections: make([]*manifesttype.CollectionCfg, 0),
+		},
+	}
+}
+
+func (e *Exporter) addCollection(collCfg *manifesttype.CollectionCfg) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.manifest.Collections = append(e.manifest.Collections, collCfg)
+}
+
+// SaveManifest : write manifest.json after all collections exported
+func (e *Exporter) SaveManifest(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sort.Slice(e.manifest.Collections, func(i, j int) bool {
+		return e.manifest.Collections[i].Collection < e.manifest.Collections[j].Collection
+	})
+	b, err := json.MarshalIndent(e.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = e.newReceiver(manifesttype.ManifestFileName).Execute(ctx, bytes.NewReader(b))
+	if err != nil {
+		log.LL(ctx).Error("[Exporter] save manifest error", zap.Error(err))
+		return err
+	}
+	log.LL(ctx).Info("[Exporter] save manifest", zap.String("outputDir", e.cfg.TargetOutputDir),
+		zap.Int("collections", len(e.manifest.Collections)))
+	return nil
+}
+
+// newReceiver : path is relative to the output dir
+func (e *Exporter) newReceiver(path string) writer.Receiver {
+	fullName := filepath.Join(e.cfg.TargetOutputDir, path)
+	fileParam := common.FileParam{FileDir: filepath.Dir(fullName), FileFullName: fullName}
+	if common.TargetMode(e.cfg.TargetMode) == common.T_REMOTE {
+		fileParam.BucketName = e.cfg.TargetRemote.BucketName
+		return writer.NewRemoteWriter(e.cfg.TargetRemote, &fileParam)
+	}
+	return writer.NewDefaultFileWriter(fileParam)
+}
+
+// checksumReceiver : calc the sha256 and size of the file bytes stream to the Receiver
+type checksumReceiver struct {
+	receiver writer.Receiver
+	hash     hash.Hash
+	size     int64
+}
+
+func newChecksumReceiver(receiver writer.Receiver) *checksumReceiver {
+	return &checksumReceiver{receiver: receiver, hash: sha256.New()}
+}
+
+func (r *checksumReceiver) Execute(ctx context.Context, rd io.Reader) error {
+	return r.receiver.Execute(ctx, io.TeeReader(rd, r))
+}
+
+func (r *checksumReceiver) Write(p []byte) (int, error) {
+	r.size += int64(len(p))
+	return r.hash.Write(p)
+}
+
+func (r *checksumReceiver) sum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
@@ -63,3 +63,12 @@ func Put(key string, value any) {
 
 	g.store.Set(key, value, cache.NoExpiration)
 }
+
+func Delete(keys ...string) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, key := range keys {
+		g.store.Delete(key)
+	}
+}
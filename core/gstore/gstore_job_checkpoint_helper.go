@@ -0,0 +1,21 @@
+package gstore
+
+import (
+	"github.com/zilliztech/milvus-migration/core/data"
+)
+
+func SetCheckpoint(jobId string, ckpt *data.Checkpoint) {
+	Put(getCheckpointKey(jobId), ckpt)
+}
+
+func GetCheckpoint(jobId string) *data.Checkpoint {
+	val, err := Get(getCheckpointKey(jobId))
+	if err != nil {
+		return nil
+	}
+	return val.(*data.Checkpoint)
+}
+
+func getCheckpointKey(jobId string) string {
+	return jobId + "_ckpt"
+}
@@ -25,7 +25,18 @@ func GetFileSort(jobId string, collection string) int32 {
 }
 func AddFileSubTask(jobId string, collection string, fileName string) {
 	GetFileTask(jobId).AddFileTask(collection, fileName)
+	saveFileTask(jobId, collection, fileName, 0, FileTaskStatePending)
+}
+
+// SetFileTaskId : record the milvus bulk insert taskId of the file
+func SetFileTaskId(jobId string, collection string, fileName string, taskId int64) {
+	if fileTask := GetFileTask(jobId); fileTask != nil {
+		fileTask.SetFileTaskId(collection, fileName, taskId)
+	}
+	saveFileTask(jobId, collection, fileName, taskId, FileTaskStateImporting)
 }
 func FinishFileSubTask(jobId string, collection string, fileName string) {
-	GetFileTask(jobId).FinishFileTask(collection, fileName)
+	fileTask := GetFileTask(jobId)
+	fileTask.FinishFileTask(collection, fileName)
+	saveFileTask(jobId, collection, fileName, fileTask.GetFileTaskId(collection, fileName), FileTaskStateFinished)
 }
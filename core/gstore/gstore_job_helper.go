@@ -31,25 +31,30 @@ func NewJobInfo(jobId string) error {
 	if err != nil {
 		return err
 	}
+	saveJob(jobId, jobInfo)
 	return nil
 }
 
 func RecordJobError(jobId string, err error) {
 	jobInfo := mustGetJobInfo(jobId)
 	jobInfo.SetJobStatus(data.JobStatusFail, err)
+	saveJob(jobId, jobInfo)
 }
 
 func RecordJobSuccess(jobId string) {
 	jobInfo := mustGetJobInfo(jobId)
 	jobInfo.SetJobStatus(data.JobStatusSuccess, nil)
+	saveJob(jobId, jobInfo)
 }
 
 func SetTotalTasks(jobId string, totalTasks int) {
 	jobInfo := mustGetJobInfo(jobId)
 	jobInfo.SetTotalTasks(totalTasks)
+	saveJob(jobId, jobInfo)
 }
 
 func AddFinishTasks(jobId string, increment int) {
 	jobInfo := mustGetJobInfo(jobId)
 	jobInfo.AddFinishTasks(increment)
+	saveJob(jobId, jobInfo)
 }
@@ -20,6 +20,15 @@ func GetProcessHandler(jobId string) *data.ProcessHandler {
 	return val.(*data.ProcessHandler)
 }
 
+// InitCollProcessHandler : collection process handler, its process will add to job process handler
+func InitCollProcessHandler(jobId string, collection string) *data.ProcessHandler {
+	return GetProcessHandler(jobId).AddCollection(collection)
+}
+
+func GetCollProcessHandler(jobId string, collection string) *data.ProcessHandler {
+	return GetProcessHandler(jobId).GetCollection(collection)
+}
+
 func getProcKey(jobId string) string {
 	return jobId + "_proc"
 }
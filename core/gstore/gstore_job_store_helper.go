@@ -0,0 +1,186 @@
+package gstore
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/data"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const interruptedMsg = "job interrupted by the migration server restart"
+
+var jobStore JobStore = NewMemoryJobStore()
+
+var storeLock = sync.RWMutex{}
+
+func getStore() JobStore {
+	storeLock.RLock()
+	defer storeLock.RUnlock()
+	return jobStore
+}
+
+// UseStore : replace the default memory job store, the not finished jobs in the store are interrupted by the last process exit
+func UseStore(store JobStore) error {
+	records, err := store.ListJobs()
+	if err != nil {
+		return err
+	}
+	interrupted := 0
+	for _, record := range records {
+		if isFinishedStatus(record.JobStatus) {
+			continue
+		}
+		record.JobStatus = string(data.JobStatusInterrupted)
+		record.Msg = interruptedMsg
+		record.UpdatedAt = time.Now()
+		err = store.SaveJob(record)
+		if err != nil {
+			return err
+		}
+		interrupted++
+	}
+	log.Info("[JobStore] load job history", zap.Int("jobs", len(records)), zap.Int("interrupted", interrupted))
+
+	storeLock.Lock()
+	defer storeLock.Unlock()
+	jobStore = store
+	return nil
+}
+
+// CloseStore : close the job store, the running jobs process flushed first
+func CloseStore() error {
+	flushRunningJobs()
+	return getStore().Close()
+}
+
+// GetJobHistory : the job not in current process, read from the job store
+func GetJobHistory(jobId string) (*data.JobInfo, error) {
+	record, err := getStore().GetJob(jobId)
+	if err != nil {
+		return nil, err
+	}
+	return toJobInfo(record), nil
+}
+
+// ListFileTaskRecords : the bulk insert taskId and state of each dumped file
+func ListFileTaskRecords(jobId string) ([]*FileTaskRecord, error) {
+	return getStore().ListFileTasks(jobId)
+}
+
+// StartRetention : flush the running jobs process to the store periodically,
+// and remove the finished jobs older than retention from the store and memory, retention <= 0 means keep forever
+func StartRetention(ctx context.Context, retention time.Duration) {
+	go func() {
+		cleanExpiredJobs(retention)
+		ticker := time.NewTicker(common.JOB_STORE_FLUSH_INTERVAL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				flushRunningJobs()
+				cleanExpiredJobs(retention)
+			}
+		}
+	}()
+}
+
+func cleanExpiredJobs(retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	jobIds, err := getStore().DeleteFinishedBefore(time.Now().Add(-retention))
+	if err != nil {
+		log.Warn("[JobStore] clean expired jobs error", zap.Error(err))
+		return
+	}
+	for _, jobId := range jobIds {
+		Delete(jobId, getProcKey(jobId), getFileTaskKey(jobId), getCheckpointKey(jobId))
+	}
+	if len(jobIds) > 0 {
+		log.Info("[JobStore] clean expired jobs", zap.Int("count", len(jobIds)), zap.Duration("retention", retention))
+	}
+}
+
+func flushRunningJobs() {
+	if g == nil {
+		return
+	}
+	lock.RLock()
+	items := g.store.Items()
+	lock.RUnlock()
+	for key, item := range items {
+		jobInfo, ok := item.Object.(*data.JobInfo)
+		if ok && jobInfo.JobStatus == data.JobStatusRunning {
+			saveJob(key, jobInfo)
+		}
+	}
+}
+
+// saveJob : persist failure only log, not break the migration job
+func saveJob(jobId string, jobInfo *data.JobInfo) {
+	record := &JobRecord{
+		JobId:       jobId,
+		JobStatus:   string(jobInfo.JobStatus),
+		Msg:         jobInfo.Msg,
+		TotalTasks:  jobInfo.TotalTasks,
+		FinishTasks: jobInfo.FinishTasks.Load(),
+		CreatedAt:   jobInfo.StartTime,
+		UpdatedAt:   time.Now(),
+	}
+	ph := GetProcessHandler(jobId)
+	if ph != nil {
+		record.JobProcess = ph.CalcProcess()
+		if collProcess := ph.CalcCollectionsProcess(); len(collProcess) > 0 {
+			bytes, _ := json.Marshal(collProcess)
+			record.CollProcess = string(bytes)
+		}
+	} else {
+		jobInfo.CalculateJobProcess()
+		record.JobProcess = jobInfo.JobProcess
+	}
+	err := getStore().SaveJob(record)
+	if err != nil {
+		log.Warn("[JobStore] save job error", zap.String("jobId", jobId), zap.Error(err))
+	}
+}
+
+func saveFileTask(jobId string, collection string, fileName string, taskId int64, state string) {
+	err := getStore().SaveFileTask(&FileTaskRecord{
+		JobId:      jobId,
+		Collection: collection,
+		FileName:   fileName,
+		TaskId:     taskId,
+		State:      state,
+		UpdatedAt:  time.Now(),
+	})
+	if err != nil {
+		log.Warn("[JobStore] save file task error", zap.String("jobId", jobId), zap.String("fileName", fileName), zap.Error(err))
+	}
+}
+
+func toJobInfo(record *JobRecord) *data.JobInfo {
+	jobInfo := &data.JobInfo{
+		JobId:       record.JobId,
+		JobStatus:   data.JobStatus(record.JobStatus),
+		JobProcess:  record.JobProcess,
+		Msg:         record.Msg,
+		TotalTasks:  record.TotalTasks,
+		FinishTasks: atomic.NewInt64(record.FinishTasks),
+		StartTime:   record.CreatedAt,
+	}
+	if record.CollProcess != "" {
+		_ = json.Unmarshal([]byte(record.CollProcess), &jobInfo.CollProcess)
+	}
+	if jobInfo.IsFinished() {
+		endTime := record.UpdatedAt
+		jobInfo.EndTime = &endTime
+	}
+	return jobInfo
+}
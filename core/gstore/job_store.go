@@ -0,0 +1,164 @@
+package gstore
+
+import (
+	"errors"
+	"github.com/zilliztech/milvus-migration/core/data"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StoreMemory = "memory"
+	StoreSqlite = "sqlite"
+
+	FileTaskStatePending   = "pending"   //file dumped, wait to bulk insert
+	FileTaskStateImporting = "importing" //bulk insert task committed
+	FileTaskStateFinished  = "finished"
+)
+
+var ErrJobNotFound = errors.New("job not found in job store")
+
+// JobStore : the job state behind the gstore helpers, the running job objects still kept in the memory cache,
+// the store keep the job history after finished or server restart
+type JobStore interface {
+	SaveJob(job *JobRecord) error
+	GetJob(jobId string) (*JobRecord, error)
+	ListJobs() ([]*JobRecord, error)
+	SaveFileTask(task *FileTaskRecord) error
+	ListFileTasks(jobId string) ([]*FileTaskRecord, error)
+	// DeleteFinishedBefore : delete the finished jobs and their file tasks updated before the time, return the deleted jobIds
+	DeleteFinishedBefore(t time.Time) ([]string, error)
+	Close() error
+}
+
+type JobRecord struct {
+	JobId       string `gorm:"primaryKey"`
+	JobStatus   string `gorm:"index"`
+	JobProcess  int
+	Msg         string
+	TotalTasks  int
+	FinishTasks int64
+	CollProcess string //json of the collections process
+	CreatedAt   time.Time
+	UpdatedAt   time.Time `gorm:"index"`
+}
+
+func (JobRecord) TableName() string {
+	return "migration_job"
+}
+
+type FileTaskRecord struct {
+	JobId      string    `gorm:"primaryKey" json:"jobId"`
+	Collection string    `gorm:"primaryKey" json:"collection"`
+	FileName   string    `gorm:"primaryKey" json:"fileName"`
+	TaskId     int64     `json:"taskId"` //milvus bulk insert taskId
+	State      string    `json:"state"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func (FileTaskRecord) TableName() string {
+	return "migration_file_task"
+}
+
+var finishedStatuses = []string{string(data.JobStatusSuccess), string(data.JobStatusFail), string(data.JobStatusInterrupted)}
+
+func isFinishedStatus(status string) bool {
+	return slices.Contains(finishedStatuses, status)
+}
+
+// MemoryJobStore : default store, the job history lost when the process exit
+type MemoryJobStore struct {
+	mu    sync.RWMutex
+	jobs  map[string]*JobRecord
+	files map[string]map[string]*FileTaskRecord //key: jobId, val: key collection/fileName
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs:  make(map[string]*JobRecord),
+		files: make(map[string]map[string]*FileTaskRecord),
+	}
+}
+
+func (s *MemoryJobStore) SaveJob(job *JobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := *job
+	record.UpdatedAt = time.Now()
+	s.jobs[job.JobId] = &record
+	return nil
+}
+
+func (s *MemoryJobStore) GetJob(jobId string) (*JobRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.jobs[jobId]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (s *MemoryJobStore) ListJobs() ([]*JobRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make([]*JobRecord, 0, len(s.jobs))
+	for _, record := range s.jobs {
+		copied := *record
+		records = append(records, &copied)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	return records, nil
+}
+
+func (s *MemoryJobStore) SaveFileTask(task *FileTaskRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := s.files[task.JobId]
+	if files == nil {
+		files = make(map[string]*FileTaskRecord)
+		s.files[task.JobId] = files
+	}
+	record := *task
+	record.UpdatedAt = time.Now()
+	files[task.Collection+"/"+task.FileName] = &record
+	return nil
+}
+
+func (s *MemoryJobStore) ListFileTasks(jobId string) ([]*FileTaskRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make([]*FileTaskRecord, 0, len(s.files[jobId]))
+	for _, record := range s.files[jobId] {
+		copied := *record
+		records = append(records, &copied)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Collection != records[j].Collection {
+			return records[i].Collection < records[j].Collection
+		}
+		return records[i].FileName < records[j].FileName
+	})
+	return records, nil
+}
+
+func (s *MemoryJobStore) DeleteFinishedBefore(t time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var jobIds []string
+	for jobId, record := range s.jobs {
+		if isFinishedStatus(record.JobStatus) && record.UpdatedAt.Before(t) {
+			jobIds = append(jobIds, jobId)
+			delete(s.jobs, jobId)
+			delete(s.files, jobId)
+		}
+	}
+	return jobIds, nil
+}
+
+func (s *MemoryJobStore) Close() error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package gstore
+
+import (
+	"errors"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SqliteJobStore : embedded sqlite file store, the job history kept after the server restart
+type SqliteJobStore struct {
+	db *gorm.DB
+}
+
+func NewSqliteJobStore(file string) (*SqliteJobStore, error) {
+	if dir := filepath.Dir(file); dir != "" {
+		err := os.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.Info("[JobStore] open sqlite job store", zap.String("file", file))
+	db, err := gorm.Open(sqlite.Open(file), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	//sqlite only one writer, avoid the 'database is locked' error of concurrent job goroutines
+	sqlDB.SetMaxOpenConns(1)
+	err = db.AutoMigrate(&JobRecord{}, &FileTaskRecord{})
+	if err != nil {
+		return nil, err
+	}
+	return &SqliteJobStore{db: db}, nil
+}
+
+func (s *SqliteJobStore) SaveJob(job *JobRecord) error {
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(job).Error
+}
+
+func (s *SqliteJobStore) GetJob(jobId string) (*JobRecord, error) {
+	var record JobRecord
+	err := s.db.Where("job_id = ?", jobId).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *SqliteJobStore) ListJobs() ([]*JobRecord, error) {
+	var records []*JobRecord
+	err := s.db.Order("created_at").Find(&records).Error
+	return records, err
+}
+
+func (s *SqliteJobStore) SaveFileTask(task *FileTaskRecord) error {
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(task).Error
+}
+
+func (s *SqliteJobStore) ListFileTasks(jobId string) ([]*FileTaskRecord, error) {
+	var records []*FileTaskRecord
+	err := s.db.Where("job_id = ?", jobId).Order("collection, file_name").Find(&records).Error
+	return records, err
+}
+
+func (s *SqliteJobStore) DeleteFinishedBefore(t time.Time) ([]string, error) {
+	var jobIds []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&JobRecord{}).Where("job_status IN ? AND updated_at < ?", finishedStatuses, t).
+			Pluck("job_id", &jobIds).Error
+		if err != nil || len(jobIds) == 0 {
+			return err
+		}
+		err = tx.Where("job_id IN ?", jobIds).Delete(&FileTaskRecord{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("job_id IN ?", jobIds).Delete(&JobRecord{}).Error
+	})
+	return jobIds, err
+}
+
+func (s *SqliteJobStore) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
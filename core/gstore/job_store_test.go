@@ -0,0 +1,60 @@
+package gstore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/data"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSqliteJobStore(t *testing.T) {
+	store, err := NewSqliteJobStore(filepath.Join(t.TempDir(), "jobs.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	assert.NoError(t, store.SaveJob(&JobRecord{JobId: "running", JobStatus: "running", CreatedAt: now, UpdatedAt: now}))
+	assert.NoError(t, store.SaveJob(&JobRecord{JobId: "old", JobStatus: "success", JobProcess: 100,
+		CreatedAt: now.Add(-48 * time.Hour), UpdatedAt: now.Add(-48 * time.Hour)}))
+	assert.NoError(t, store.SaveFileTask(&FileTaskRecord{JobId: "old", Collection: "coll", FileName: "1.json",
+		State: FileTaskStatePending, UpdatedAt: now}))
+	assert.NoError(t, store.SaveFileTask(&FileTaskRecord{JobId: "old", Collection: "coll", FileName: "1.json",
+		TaskId: 10, State: FileTaskStateFinished, UpdatedAt: now}))
+
+	files, err := store.ListFileTasks("old")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, int64(10), files[0].TaskId)
+	assert.Equal(t, FileTaskStateFinished, files[0].State)
+
+	_, err = store.GetJob("none")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	jobIds, err := store.DeleteFinishedBefore(now.Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"old"}, jobIds)
+	files, err = store.ListFileTasks("old")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(files))
+
+	jobs, err := store.ListJobs()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(jobs))
+	assert.Equal(t, "running", jobs[0].JobId)
+}
+
+func TestUseStoreInterrupted(t *testing.T) {
+	store := NewMemoryJobStore()
+	now := time.Now()
+	assert.NoError(t, store.SaveJob(&JobRecord{JobId: "job", JobStatus: "running", FinishTasks: 2, TotalTasks: 5, CreatedAt: now}))
+	assert.NoError(t, UseStore(store))
+	defer UseStore(NewMemoryJobStore())
+
+	info, err := GetJobHistory("job")
+	assert.NoError(t, err)
+	assert.Equal(t, data.JobStatusInterrupted, info.JobStatus)
+	assert.Equal(t, interruptedMsg, info.Msg)
+	assert.Equal(t, int64(2), info.FinishTasks.Load())
+	assert.NotNil(t, info.EndTime)
+}
@@ -2,6 +2,7 @@ package loader
 
 import (
 	"context"
+	"errors"
 	"github.com/zilliztech/milvus-migration/core/common"
 	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/dbclient"
@@ -41,6 +42,27 @@ func NewCusFieldMilvus2xLoader(cfg *config.MigrationConfig) (*CustomMilvus2xLoad
 	}, nil
 }
 
+// Clone : new a loader share the target milvus client, but not share runtime collection info,
+// used for migrate multi collections concurrently
+func (this *CustomMilvus2xLoader) Clone() *CustomMilvus2xLoader {
+	return &CustomMilvus2xLoader{
+		CusMilvus2x: this.CusMilvus2x,
+		cfg:         this.cfg,
+		concurLimit: this.concurLimit,
+		workMode:    this.workMode,
+	}
+}
+
+// InitCollectionInfo : the target collection of the batch insert source, converted by the source collection config
+func (this *CustomMilvus2xLoader) InitCollectionInfo(collectionInfo *common.CollectionInfo) error {
+	if collectionInfo == nil {
+		return errors.New("collectionInfo is empty, cannot init the target collection")
+	}
+	this.runtimeCusCollectionInfos = []*common.CollectionInfo{collectionInfo}
+	this.runtimeCollectionNames = []string{collectionInfo.Param.CollectionName}
+	return nil
+}
+
 func (this *CustomMilvus2xLoader) Before(ctx context.Context) error {
 	err := this.createTable(ctx)
 	if err != nil {
@@ -64,7 +86,37 @@ func (cus *CustomMilvus2xLoader) CheckMilvusState(ctx context.Context, taskId in
 }
 
 func (cus *CustomMilvus2xLoader) After(ctx context.Context) error {
-	return cus.compareResult(ctx)
+	err := cus.compareResult(ctx)
+	if err != nil {
+		return err
+	}
+	return cus.createIndexAndLoad(ctx)
+}
+
+func (cus *CustomMilvus2xLoader) createIndexAndLoad(ctx context.Context) error {
+	for _, collectionInfo := range cus.runtimeCusCollectionInfos {
+		collection := collectionInfo.Param.CollectionName
+		if collectionInfo.Param.CreateIndex {
+			log.LL(ctx).Info("[Loader] Begin to create collection index", zap.String("collection", collection))
+			err := cus.CusMilvus2x.CreateIndexes(ctx, collectionInfo)
+			if err != nil {
+				return err
+			}
+		}
+		if collectionInfo.Param.LoadData {
+			log.LL(ctx).Info("[Loader] Begin to load collection", zap.String("collection", collection))
+			err := cus.CusMilvus2x.LoadCollection(ctx, collection, true)
+			if err != nil {
+				return err
+			}
+			err = cus.CusMilvus2x.CheckLoadStatus(ctx, collection)
+			if err != nil {
+				return err
+			}
+			log.LL(ctx).Info("[Loader] Load collection finish", zap.String("collection", collection))
+		}
+	}
+	return nil
 }
 
 func (cus *CustomMilvus2xLoader) createTable(ctx context.Context) error {
@@ -125,7 +177,7 @@ func (this *CustomMilvus2xLoader) BatchWrite(ctx context.Context, data *milvus2x
 
 	log.LL(ctx).Info("[Loader] Begin to batchWrite data to milvus", zap.String("collection",
 		this.runtimeCollectionNames[0]), zap.String("partition", data.Partition))
-	
+
 	if this.cfg.TargetMilvus2xCfg.WriteMode == common.UPSERT {
 		return this.CusMilvus2x.StartBatchUpsert(ctx, this.runtimeCollectionNames[0], data)
 	} else {
@@ -3,11 +3,14 @@ package loader
 import (
 	"context"
 	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	cmap "github.com/orcaman/concurrent-map/v2"
 	"github.com/zilliztech/milvus-migration/core/common"
 	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/dbclient"
 	"github.com/zilliztech/milvus-migration/core/gstore"
+	convert "github.com/zilliztech/milvus-migration/core/transform/common"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
 	"github.com/zilliztech/milvus-migration/core/util"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"github.com/zilliztech/milvus-migration/internal/util/retry"
@@ -119,7 +122,12 @@ func (this *Milvus2xLoader) loadAll(ctx context.Context) error {
 		return err
 	}
 
-	return this.compareResult(ctx)
+	err = this.compareResult(ctx)
+	if err != nil {
+		return err
+	}
+
+	return this.createIndexAndLoad(ctx)
 }
 
 func (this *Milvus2xLoader) createTable(ctx context.Context) error {
@@ -190,7 +198,21 @@ func (this *Milvus2xLoader) loadDataOne(ctx context.Context, col common.Collecti
 
 	files, _ := this.runtimeFiles.Get(fileMapKey)
 
-	taskId, err := this.milvus.StartBulkLoad(ctx, col.CollectionName, files)
+	if this.isParquetFormat() {
+		//every parquet file is a bulkInsert task
+		for _, file := range files {
+			err := this.bulkLoad(ctx, col.CollectionName, []string{file})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return this.bulkLoad(ctx, col.CollectionName, files)
+}
+
+func (this *Milvus2xLoader) bulkLoad(ctx context.Context, collection string, files []string) error {
+	taskId, err := this.milvus.StartBulkLoad(ctx, collection, files)
 	if err != nil {
 		return err
 	}
@@ -225,3 +247,79 @@ func (this *Milvus2xLoader) compareResult(ctx context.Context) error {
 
 	return nil
 }
+
+// createIndexAndLoad : faiss/milvus1x collection fields is fixed: id(Int64), data(FloatVector)
+func (this *Milvus2xLoader) createIndexAndLoad(ctx context.Context) error {
+	milvusCfg := this.cfg.LoaderWorkCfg.MilvusCfg
+	if milvusCfg == nil || (!milvusCfg.CreateIndex && !milvusCfg.LoadData) {
+		return nil
+	}
+	metricTypes := make(map[string]string)
+	for _, col := range this.runtimeCollections {
+		metricTypes[col.CollectionName] = col.MetricType
+	}
+	cusMilvus2x := dbclient.NewCusFieldMilvus2xClient(this.milvus)
+	for _, colName := range this.runtimeCollectionNames {
+		if milvusCfg.CreateIndex {
+			indexes, err := this.toMilvusIndexes(metricTypes[colName])
+			if err != nil {
+				return err
+			}
+			for fieldName, index := range indexes {
+				err = this.milvus.CreateIndexIfNotExist(ctx, colName, fieldName, index)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if milvusCfg.LoadData {
+			log.LL(ctx).Info("[Loader] Begin to load collection", zap.String("collection", colName))
+			err := this.milvus.LoadCollection(ctx, colName, true)
+			if err != nil {
+				return err
+			}
+			err = cusMilvus2x.CheckLoadStatus(ctx, colName)
+			if err != nil {
+				return err
+			}
+			log.LL(ctx).Info("[Loader] Load collection finish", zap.String("collection", colName))
+		}
+	}
+	return nil
+}
+
+func (this *Milvus2xLoader) toMilvusIndexes(metricType string) (map[string]entity.Index, error) {
+	return ToNumpyIndexes(this.cfg.LoaderWorkCfg.FieldIndexes, metricType)
+}
+
+// ToNumpyIndexes : faiss and milvus1x target collection indexes, vector field metric type default same as source collection
+func ToNumpyIndexes(fieldIndexes map[string]*milvustype.IndexCfg, metricType string) (map[string]entity.Index, error) {
+	fields := []*entity.Field{
+		{Name: "id", DataType: entity.FieldTypeInt64},
+		{Name: "data", DataType: entity.FieldTypeFloatVector},
+	}
+	indexes := make(map[string]entity.Index)
+	for _, field := range fields {
+		idxCfg, ok := fieldIndexes[field.Name]
+		if !ok && !convert.IsVectorField(field) {
+			continue
+		}
+		if convert.IsVectorField(field) {
+			//vector field metric type default same as source collection
+			vecIdxCfg := milvustype.IndexCfg{MetricType: metricType}
+			if idxCfg != nil {
+				vecIdxCfg = *idxCfg
+				if vecIdxCfg.MetricType == "" {
+					vecIdxCfg.MetricType = metricType
+				}
+			}
+			idxCfg = &vecIdxCfg
+		}
+		index, err := convert.ToMilvusIndex(field, idxCfg)
+		if err != nil {
+			return nil, err
+		}
+		indexes[field.Name] = index
+	}
+	return indexes, nil
+}
@@ -26,6 +26,15 @@ func (this *Milvus2xLoader) loadRuntimeMetaInFaissMode(ctx context.Context) erro
 	filesMap := cmap.New[[]string]()
 	var targetDir = this.cfg.TargetOutputDir
 	for _, val := range this.runtimeCollections {
+		if this.isParquetFormat() {
+			collDir, _ := util.GenerateFaissDataFilePath(targetDir, val.CollectionName)
+			parquetFiles, err := this.listParquetFiles(ctx, collDir)
+			if err != nil {
+				return err
+			}
+			filesMap.Set(val.FileMapKey, parquetFiles)
+			continue
+		}
 		_, idFiles := util.GenerateFaissIdFilePath(targetDir, val.CollectionName)
 		_, dataFiles := util.GenerateFaissDataFilePath(targetDir, val.CollectionName)
 		// key is collection + segment
@@ -21,7 +21,7 @@ func (this *Milvus2xLoader) loadRuntimeMetaInMilvus1xMode(ctx context.Context) e
 		return err
 	}
 
-	err = this.getFileNames(metaJSON)
+	err = this.getFileNames(ctx, metaJSON)
 	if err != nil {
 		return err
 	}
@@ -30,7 +30,7 @@ func (this *Milvus2xLoader) loadRuntimeMetaInMilvus1xMode(ctx context.Context) e
 	return nil
 }
 
-func (this *Milvus2xLoader) getFileNames(metaJSON *milvustype.MetaJSON) error {
+func (this *Milvus2xLoader) getFileNames(ctx context.Context, metaJSON *milvustype.MetaJSON) error {
 
 	colInfos := metaJSON.Collections
 
@@ -43,6 +43,15 @@ func (this *Milvus2xLoader) getFileNames(metaJSON *milvustype.MetaJSON) error {
 	var targetDir = this.cfg.TargetOutputDir
 	for _, col := range colInfos {
 		for _, segment := range col.Segments {
+			if this.isParquetFormat() {
+				segDir, _ := util.GetOutputRVFilePath(targetDir, &segment)
+				parquetFiles, err := this.listParquetFiles(ctx, segDir)
+				if err != nil {
+					return err
+				}
+				filesMap.Set(getFileMapKey(&segment), parquetFiles)
+				continue
+			}
 			_, uidFile := util.GetOutputUIDFilePath(targetDir, &segment)
 			_, rvFile := util.GetOutputRVFilePath(targetDir, &segment)
 			filesMap.Set(getFileMapKey(&segment), []string{uidFile, rvFile})
@@ -62,7 +71,7 @@ func (this *Milvus2xLoader) getFileNames(metaJSON *milvustype.MetaJSON) error {
 }
 
 func convertSegColInfoList2CollectionParams(segCols []milvustype.SegColInfo, colInfo *milvustype.ColInfo) ([]common.CollectionParam, error) {
-	metricType, err := convertMetricTypeFrom1xTo2x(colInfo.MetricType)
+	metricType, err := ConvertMetricTypeFrom1xTo2x(colInfo.MetricType)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +97,8 @@ func getFileMapKey(segCol *milvustype.SegColInfo) string {
 	return segCol.CollectionName + segCol.SegmentName
 }
 
-func convertMetricTypeFrom1xTo2x(metricType int) (string, error) {
+// ConvertMetricTypeFrom1xTo2x : milvus1x metric 1 is L2, 2 is IP
+func ConvertMetricTypeFrom1xTo2x(metricType int) (string, error) {
 	switch metricType {
 	case 1:
 		return "L2", nil
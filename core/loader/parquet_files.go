@@ -0,0 +1,54 @@
+package loader
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/factory"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/storage"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func (this *Milvus2xLoader) isParquetFormat() bool {
+	return this.cfg.DumperWorkCfg.FileFormat == string(common.FormatParquet)
+}
+
+// listParquetFiles : milvus1x segment or faiss collection parquet files in target dir, order by file sort
+func (this *Milvus2xLoader) listParquetFiles(ctx context.Context, targetDir string) ([]string, error) {
+	var files []string
+	if this.cfg.TargetMode == string(common.T_REMOTE) {
+		cli := factory.GetStorageCli(this.cfg.TargetRemote)
+		prefix := strings.TrimSuffix(targetDir, "/") + "/"
+		paginator := cli.ListObjectsPage(ctx, storage.ListObjectPageInput{Bucket: this.cfg.TargetRemote.BucketName, Prefix: prefix})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, obj := range page.Contents {
+				files = append(files, obj.Key)
+			}
+		}
+	} else {
+		entries, err := os.ReadDir(targetDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			files = append(files, filepath.Join(targetDir, entry.Name()))
+		}
+	}
+	parquetFiles := make([]string, 0, len(files))
+	for _, file := range files {
+		if filepath.Dir(file) == filepath.Clean(targetDir) && util.GetParquetFileSort(file) > 0 {
+			parquetFiles = append(parquetFiles, file)
+		}
+	}
+	sort.Slice(parquetFiles, func(i, j int) bool {
+		return util.GetParquetFileSort(parquetFiles[i]) < util.GetParquetFileSort(parquetFiles[j])
+	})
+	return parquetFiles, nil
+}
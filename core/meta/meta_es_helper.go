@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"github.com/zilliztech/milvus-migration/core/check"
+	"github.com/zilliztech/milvus-migration/core/config"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
 	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
 	"github.com/zilliztech/milvus-migration/core/util"
 	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/es"
 	"go.uber.org/zap"
 	"os"
 )
@@ -21,6 +25,15 @@ func (this *MetaHelper) ReadESMeta(ctx context.Context) (*estype.MetaJSON, error
 		return nil, errors.New("read es meta index is empty")
 	}
 
+	err = this.expandESIndices(metaJson)
+	if err != nil {
+		return nil, err
+	}
+	err = this.discoverESFields(metaJson)
+	if err != nil {
+		return nil, err
+	}
+
 	err = check.VerifyESMetaCfg(metaJson)
 	if err != nil {
 		return nil, err
@@ -84,3 +97,123 @@ func (this *MetaHelper) getMockESMeta() (*estype.MetaJSON, error) {
 func (this *MetaHelper) getConfigESMeta() (*estype.MetaJSON, error) {
 	return this.metaCfg.EsMeta, nil
 }
+
+// discoverESFields : read the index fields from es _mapping, config fields as override
+func (this *MetaHelper) discoverESFields(metaJson *estype.MetaJSON) error {
+	for _, idx := range metaJson.IdxCfgs {
+		if !idx.AutoMapping && len(idx.Fields) > 0 {
+			continue
+		}
+		esCli, err := this.getSourceESClient(metaJson.Version)
+		if err != nil {
+			return err
+		}
+		properties, err := esCli.Cli.GetMapping(idx.Index)
+		if err != nil {
+			return err
+		}
+		mappingFields, unsupported := esconvert.ToFieldCfgs(properties)
+		idx.Fields = esconvert.MergeFieldCfgs(mappingFields, idx.Fields)
+		if idx.UnsupportedFieldPolicy == estype.UnsupportedFieldDynamic {
+			idx.DynamicFields = unsupported
+		}
+		//fields已确定, loader读取dump后的meta不需要再次读取mapping
+		idx.AutoMapping = false
+		log.Info("[MetaESHelper] auto mapping es index fields", zap.String("index", idx.Index),
+			zap.Any("fields", idx.Fields), zap.Strings("unsupportedFields", unsupported),
+			zap.String("unsupportedFieldPolicy", idx.UnsupportedFieldPolicy))
+	}
+	return nil
+}
+
+// expandESIndices : split mode index pattern or alias will split to one IdxCfg per concrete index
+func (this *MetaHelper) expandESIndices(metaJson *estype.MetaJSON) error {
+	idxCfgs := make([]*estype.IdxCfg, 0, len(metaJson.IdxCfgs))
+	for _, idx := range metaJson.IdxCfgs {
+		if idx.MultiIndexMode != estype.MultiIndexSplit {
+			idxCfgs = append(idxCfgs, idx)
+			continue
+		}
+		if idx.MilvusCfg != nil && idx.MilvusCfg.Collection != "" {
+			return errors.New("es multiIndexMode=split can not set milvus.collection, index: " + idx.Index)
+		}
+		esCli, err := this.getSourceESClient(metaJson.Version)
+		if err != nil {
+			return err
+		}
+		indices, err := esCli.Cli.ListIndices(idx.Index)
+		if err != nil {
+			return err
+		}
+		if len(indices) == 0 {
+			return errors.New("es index pattern not match any index: " + idx.Index)
+		}
+		log.Info("[MetaESHelper] split es index pattern", zap.String("index", idx.Index), zap.Strings("indices", indices))
+		for _, index := range indices {
+			idxCfgs = append(idxCfgs, copyESIdxCfg(idx, index))
+		}
+	}
+	metaJson.IdxCfgs = idxCfgs
+	return nil
+}
+
+// copyESIdxCfg : concrete index use its name as collection name, the invalid char will replace by '_'
+func copyESIdxCfg(idx *estype.IdxCfg, index string) *estype.IdxCfg {
+	cp := *idx
+	cp.Index = index
+	cp.MultiIndexMode = estype.MultiIndexMerge
+	cp.Fields = append([]estype.FieldCfg{}, idx.Fields...)
+	milvusCfg := milvustype.MilvusCfg{}
+	if idx.MilvusCfg != nil {
+		milvusCfg = *idx.MilvusCfg
+	}
+	milvusCfg.Collection = toCollectionName(index)
+	cp.MilvusCfg = &milvusCfg
+	return &cp
+}
+
+func toCollectionName(index string) string {
+	name := []rune(index)
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		return "_" + string(name)
+	}
+	return string(name)
+}
+
+// getSourceESClient : new a temp client, avoid the factory cache the client before version confirmed
+func (this *MetaHelper) getSourceESClient(version string) (*es.ESClient, error) {
+	if this.sourceESCli != nil {
+		return this.sourceESCli, nil
+	}
+	if this.cfg.SourceESConfig == nil {
+		return nil, errors.New("es auto mapping or index pattern need source.es config")
+	}
+	sourceCfg := &config.ESConfig{
+		Urls:            this.cfg.SourceESConfig.Urls,
+		Username:        this.cfg.SourceESConfig.Username,
+		Password:        this.cfg.SourceESConfig.Password,
+		Cert:            this.cfg.SourceESConfig.Cert,
+		FingerPrint:     this.cfg.SourceESConfig.FingerPrint,
+		CloudId:         this.cfg.SourceESConfig.CloudId,
+		ApiKey:          this.cfg.SourceESConfig.ApiKey,
+		ServiceToken:    this.cfg.SourceESConfig.ServiceToken,
+		Distribution:    this.cfg.SourceESConfig.Distribution,
+		AwsRegion:       this.cfg.SourceESConfig.AwsRegion,
+		AwsService:      this.cfg.SourceESConfig.AwsService,
+		AwsAccessKey:    this.cfg.SourceESConfig.AwsAccessKey,
+		AwsSecretKey:    this.cfg.SourceESConfig.AwsSecretKey,
+		AwsSessionToken: this.cfg.SourceESConfig.AwsSessionToken,
+		Version:         version,
+	}
+	esCli, err := es.CreateESClient(sourceCfg)
+	if err != nil {
+		return nil, err
+	}
+	this.sourceESCli = esCli
+	return esCli, nil
+}
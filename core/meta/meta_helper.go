@@ -7,6 +7,7 @@ import (
 	"github.com/zilliztech/milvus-migration/core/type/milvustype"
 	"github.com/zilliztech/milvus-migration/core/util"
 	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/es"
 	"go.uber.org/zap"
 	"os"
 )
@@ -15,6 +16,8 @@ type MetaHelper struct {
 	cfg           *config.MigrationConfig
 	readRemoteCfg *config.RemoteConfig
 	metaCfg       *config.MetaConfig
+
+	sourceESCli *es.ESClient //es source meta client, lazy created
 }
 
 func NewMetaHelperForDumper(config *config.MigrationConfig) *MetaHelper {
@@ -3,10 +3,14 @@ package meta
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/zilliztech/milvus-migration/core/check"
+	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
 	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
 	"go.uber.org/zap"
+	"path"
 )
 
 func (this *MetaHelper) ReadMilvus2xMeta(ctx context.Context) (*milvus2xtype.MetaJSON, error) {
@@ -15,6 +19,10 @@ func (this *MetaHelper) ReadMilvus2xMeta(ctx context.Context) (*milvus2xtype.Met
 	if err != nil {
 		return nil, err
 	}
+	err = this.expandMilvus2xCollections(ctx, metaJson)
+	if err != nil {
+		return nil, err
+	}
 	if metaJson.CollCfgs == nil || len(metaJson.CollCfgs) == 0 {
 		return nil, errors.New("read milvus2x meta collection is empty")
 	}
@@ -44,3 +52,92 @@ func (this *MetaHelper) getMilvus2xMeta(ctx context.Context) (*milvus2xtype.Meta
 func (this *MetaHelper) getConfigMilvus2xMeta() (*milvus2xtype.MetaJSON, error) {
 	return this.metaCfg.Milvus2xMeta, nil
 }
+
+// expandMilvus2xCollections : replace the glob pattern collection cfg by the matched source collections,
+// explicit collection cfg has higher priority than pattern matched
+func (this *MetaHelper) expandMilvus2xCollections(ctx context.Context, metaJson *milvus2xtype.MetaJSON) error {
+	hasPattern := false
+	explicitColls := make(map[string]bool)
+	for _, collCfg := range metaJson.CollCfgs {
+		if collCfg.IsPattern() {
+			hasPattern = true
+		} else {
+			explicitColls[collCfg.Collection] = true
+		}
+	}
+	if !hasPattern {
+		return nil
+	}
+
+	sourceColls, err := this.listSourceMilvus2xCollections(ctx, metaJson.Version)
+	if err != nil {
+		return err
+	}
+	log.Info("[MetaMilvus2xHelper] list source collections", zap.String("database", this.cfg.SourceMilvus2xConfig.Database),
+		zap.Strings("collections", sourceColls))
+
+	matchedColls := make(map[string]bool)
+	collCfgs := make([]*milvus2xtype.CollectionCfg, 0, len(metaJson.CollCfgs))
+	for _, collCfg := range metaJson.CollCfgs {
+		if !collCfg.IsPattern() {
+			collCfgs = append(collCfgs, collCfg)
+			continue
+		}
+		if collCfg.MilvusCfg != nil && collCfg.MilvusCfg.Collection != "" {
+			return fmt.Errorf("collection pattern %s can not set milvus.collection", collCfg.Collection)
+		}
+		matchCount := 0
+		for _, sourceColl := range sourceColls {
+			matched, err := path.Match(collCfg.Collection, sourceColl)
+			if err != nil {
+				return fmt.Errorf("collection pattern %s invalid: %w", collCfg.Collection, err)
+			}
+			if !matched || explicitColls[sourceColl] || matchedColls[sourceColl] {
+				continue
+			}
+			matchedColls[sourceColl] = true
+			matchCount++
+			collCfgs = append(collCfgs, copyMilvus2xCollectionCfg(collCfg, sourceColl))
+		}
+		if matchCount == 0 {
+			log.Warn("[MetaMilvus2xHelper] collection pattern not match any source collection",
+				zap.String("pattern", collCfg.Collection))
+		}
+	}
+	metaJson.CollCfgs = collCfgs
+	return nil
+}
+
+func (this *MetaHelper) listSourceMilvus2xCollections(ctx context.Context, version string) ([]string, error) {
+	//new a temp client, avoid the factory cache the client before version confirmed
+	sourceCfg := &config.Milvus2xConfig{
+		Endpoint:           this.cfg.SourceMilvus2xConfig.Endpoint,
+		UserName:           this.cfg.SourceMilvus2xConfig.UserName,
+		Password:           this.cfg.SourceMilvus2xConfig.Password,
+		GrpcMaxRecvMsgSize: this.cfg.SourceMilvus2xConfig.GrpcMaxRecvMsgSize,
+		GrpcMaxSendMsgSize: this.cfg.SourceMilvus2xConfig.GrpcMaxSendMsgSize,
+		Database:           this.cfg.SourceMilvus2xConfig.Database,
+		Version:            version,
+	}
+	cli, err := milvus2x.CreateMilvus2xClient(sourceCfg)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.VerCli.Close()
+	return cli.VerCli.ListCollections(ctx)
+}
+
+func copyMilvus2xCollectionCfg(collCfg *milvus2xtype.CollectionCfg, collection string) *milvus2xtype.CollectionCfg {
+	newCollCfg := &milvus2xtype.CollectionCfg{
+		Collection: collection,
+		Filter:     collCfg.Filter,
+	}
+	if collCfg.Fields != nil {
+		newCollCfg.Fields = append(make([]milvus2xtype.FieldCfg, 0, len(collCfg.Fields)), collCfg.Fields...)
+	}
+	if collCfg.MilvusCfg != nil {
+		milvusCfg := *collCfg.MilvusCfg
+		newCollCfg.MilvusCfg = &milvusCfg
+	}
+	return newCollCfg
+}
@@ -0,0 +1,369 @@
+package parity
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/dumper"
+	"github.com/zilliztech/milvus-migration/core/factory/es_factory"
+	"github.com/zilliztech/milvus-migration/core/meta"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
+	milvus2xconvert "github.com/zilliztech/milvus-migration/core/transform/milvus2x/convert"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/es"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// numpyPKField, numpyVectorField : field names of the faiss target collection
+	numpyPKField     = "id"
+	numpyVectorField = "data"
+	esIdField        = "_id"
+	esDenseVector    = "dense_vector"
+	esKnnVector      = "knn_vector"
+	// searchBatch : query vectors of one milvus search request
+	searchBatch = 16
+	// sampleBatchSize : iterator batch size when sample the milvus2x source vectors
+	sampleBatchSize = 1000
+)
+
+// Checker : search the random source vectors on both source and target, compare the top-k results
+type Checker struct {
+	cfg       *config.MigrationConfig
+	jobId     string
+	targetCli *milvus2x.Milvus2xClient
+}
+
+func NewChecker(cfg *config.MigrationConfig, jobId string) *Checker {
+	return &Checker{cfg: cfg, jobId: jobId}
+}
+
+// Run : check all collections of the config, the report returned even some collections not passed
+func (c *Checker) Run(ctx context.Context) (*Report, error) {
+	if c.cfg.TargetType == string(common.T_FILES) {
+		return nil, fmt.Errorf("parity not support files target")
+	}
+	start := time.Now()
+	targetCli, err := milvus2x.CreateMilvus2xClient(c.cfg.TargetMilvus2xCfg)
+	if err != nil {
+		return nil, err
+	}
+	defer targetCli.VerCli.Close()
+	c.targetCli = targetCli
+
+	parityCfg := c.cfg.ParityCfg
+	report := &Report{JobId: c.jobId, SourceType: c.cfg.DumperWorkCfg.WorkMode, TopK: parityCfg.TopK,
+		MinRecall: parityCfg.MinRecall, Passed: true}
+	switch common.DumpMode(c.cfg.DumperWorkCfg.WorkMode) {
+	case common.Milvus2x:
+		err = c.checkMilvus2x(ctx, report)
+	case common.Elasticsearch:
+		err = c.checkES(ctx, report)
+	case common.Faiss:
+		err = c.checkFaiss(ctx, report)
+	default:
+		err = fmt.Errorf("parity not support WorkMode %s", c.cfg.DumperWorkCfg.WorkMode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.LL(ctx).Info("[Parity] parity check finish", zap.String("jobId", c.jobId), zap.Bool("passed", report.Passed),
+		zap.Int("collections", len(report.Collections)), zap.Float64("Cost", time.Since(start).Seconds()))
+	return report, nil
+}
+
+func (c *Checker) checkMilvus2x(ctx context.Context, report *Report) error {
+	metaJson, err := meta.NewMetaHelperForDumper(c.cfg).ReadMilvus2xMeta(ctx)
+	if err != nil {
+		return err
+	}
+	c.cfg.SourceMilvus2xConfig.Version = metaJson.Version
+	sourceCli, err := milvus2x.CreateMilvus2xClient(c.cfg.SourceMilvus2xConfig)
+	if err != nil {
+		return err
+	}
+	defer sourceCli.VerCli.Close()
+	for _, collCfg := range metaJson.CollCfgs {
+		cr, err := c.checkMilvus2xCollection(ctx, sourceCli, collCfg)
+		if err != nil {
+			log.Error("[Parity] check Milvus2x collection err", zap.String("collection", collCfg.Collection), zap.Error(err))
+			return err
+		}
+		report.addCollection(cr)
+	}
+	return nil
+}
+
+func (c *Checker) checkMilvus2xCollection(ctx context.Context, sourceCli *milvus2x.Milvus2xClient,
+	collCfg *milvus2xtype.CollectionCfg) (*CollectionReport, error) {
+	cr := &CollectionReport{Source: collCfg.Collection, Collection: milvus2xconvert.ToMilvusCollectionName(collCfg)}
+	if collCfg.MilvusCfg.AutoId == "true" {
+		cr.Notes = append(cr.Notes, "target autoId, the target pks not comparable with source")
+		return cr, nil
+	}
+	collEntity, err := sourceCli.VerCli.DescCollection(ctx, collCfg.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var pkName string
+	for _, field := range collEntity.Schema.Fields {
+		if field.PrimaryKey {
+			pkName = field.Name
+		}
+		if cr.VectorField == "" && field.DataType == entity.FieldTypeFloatVector &&
+			(c.cfg.ParityCfg.VectorField == "" || c.cfg.ParityCfg.VectorField == field.Name) {
+			cr.VectorField = field.Name
+		}
+	}
+	if cr.VectorField == "" {
+		return nil, fmt.Errorf("collection %s not found float vector field %s", collCfg.Collection, c.cfg.ParityCfg.VectorField)
+	}
+
+	//sample the source vectors by a streaming pass of pk and vector field
+	samples := newReservoir(c.cfg.ParityCfg.Queries)
+	err = sourceCli.VerCli.InitIterator(ctx, collCfg, sampleBatchSize, common.EMPTY, collCfg.Filter, []string{pkName, cr.VectorField})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		data, err := sourceCli.VerCli.IterateNext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if data.IsEmpty {
+			break
+		}
+		var pkCol entity.Column
+		var vectorCol *entity.ColumnFloatVector
+		for _, col := range data.Columns {
+			switch col.Name() {
+			case pkName:
+				pkCol = col
+			case cr.VectorField:
+				vectorCol, _ = col.(*entity.ColumnFloatVector)
+			}
+		}
+		if pkCol == nil || vectorCol == nil {
+			return nil, fmt.Errorf("collection %s iterate result not contain pk or vector field", collCfg.Collection)
+		}
+		for i := 0; i < pkCol.Len(); i++ {
+			pk, err := pkCol.Get(i)
+			if err != nil {
+				return nil, err
+			}
+			samples.add(fmt.Sprint(pk), vectorCol.Data()[i])
+		}
+	}
+
+	cr.SourceMetric = indexMetricType(ctx, sourceCli, collCfg.Collection, cr.VectorField)
+	sourceResults, err := c.search(ctx, sourceCli, collCfg.Collection, collCfg.Filter, cr.VectorField, cr.SourceMetric, samples.items)
+	if err != nil {
+		return nil, err
+	}
+	return c.compareTarget(ctx, cr, samples.items, sourceResults)
+}
+
+func (c *Checker) checkES(ctx context.Context, report *Report) error {
+	metaJson, err := meta.NewMetaHelperForDumper(c.cfg).ReadESMeta(ctx)
+	if err != nil {
+		return err
+	}
+	c.cfg.SourceESConfig.Version = metaJson.Version
+	esCli := es_factory.GetESCli(c.cfg.SourceESConfig)
+	for _, idxCfg := range metaJson.IdxCfgs {
+		cr, err := c.checkESIndex(ctx, esCli, idxCfg)
+		if err != nil {
+			log.Error("[Parity] check ES index err", zap.String("index", idxCfg.Index), zap.Error(err))
+			return err
+		}
+		report.addCollection(cr)
+	}
+	return nil
+}
+
+func (c *Checker) checkESIndex(ctx context.Context, esCli *es.ESClient, idxCfg *estype.IdxCfg) (*CollectionReport, error) {
+	cr := &CollectionReport{Source: idxCfg.Index, Collection: esconvert.ToMilvusCollectionName(idxCfg)}
+	for _, f := range idxCfg.Fields {
+		isFloatVector := f.Type == esDenseVector || f.Type == esKnnVector && f.VectorDataType != estype.KnnDataBinary
+		if cr.VectorField == "" && isFloatVector && (c.cfg.ParityCfg.VectorField == "" || c.cfg.ParityCfg.VectorField == f.Name) {
+			cr.VectorField = f.Name
+		}
+	}
+	if cr.VectorField == "" {
+		return nil, fmt.Errorf("es index %s not found dense_vector or knn_vector field %s", idxCfg.Index, c.cfg.ParityCfg.VectorField)
+	}
+	//the pk is es _id or a source field
+	var pkFields []string
+	if idxCfg.InnerPkField != nil && idxCfg.InnerPkField.Name != esIdField {
+		pkFields = []string{idxCfg.InnerPkField.Name}
+	}
+	hitPK := func(hit gjson.Result) string {
+		if len(pkFields) == 0 {
+			return hit.Get(esIdField).String()
+		}
+		return hit.Get("_source").Get(gjson.Escape(pkFields[0])).String()
+	}
+
+	res, err := esCli.Cli.RandomDocs(idxCfg, c.cfg.ParityCfg.Queries, append([]string{cr.VectorField}, pkFields...))
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]*queryVector, 0, c.cfg.ParityCfg.Queries)
+	for _, hit := range res.Hits.Array() {
+		values := hit.Get("_source").Get(gjson.Escape(cr.VectorField)).Array()
+		vector := make([]float32, 0, len(values))
+		for _, v := range values {
+			vector = append(vector, float32(v.Float()))
+		}
+		queries = append(queries, &queryVector{pk: hitPK(hit), vector: vector})
+	}
+
+	//es8 and opensearch knn use the similarity of the field mapping, es7 exact script_score search by the target metric
+	cr.TargetMetric = indexMetricType(ctx, c.targetCli, cr.Collection, cr.VectorField)
+	cr.SourceMetric = "knn"
+	if esCli.Version == es.VER7 {
+		cr.SourceMetric = "exact:" + cr.TargetMetric
+	}
+	sourceResults := make([][]string, 0, len(queries))
+	for _, q := range queries {
+		res, err := esCli.Cli.KnnSearch(idxCfg, &es.KnnRequest{Field: cr.VectorField, Vector: q.vector,
+			K: c.cfg.ParityCfg.TopK, MetricType: cr.TargetMetric, Fields: pkFields})
+		if err != nil {
+			return nil, err
+		}
+		pks := make([]string, 0, c.cfg.ParityCfg.TopK)
+		for _, hit := range res.Hits.Array() {
+			pks = append(pks, hitPK(hit))
+		}
+		sourceResults = append(sourceResults, pks)
+	}
+	return c.compareTarget(ctx, cr, queries, sourceResults)
+}
+
+// checkFaiss : faiss file not have search service, the source results are the exact top-k of the file vectors
+func (c *Checker) checkFaiss(ctx context.Context, report *Report) error {
+	colCfg := c.cfg.LoaderWorkCfg.CreateColCfg
+	cr := &CollectionReport{Source: c.cfg.SourceFaissFile, Collection: colCfg.CollectionName, VectorField: numpyVectorField,
+		SourceMetric: "exact:" + colCfg.MetricType}
+	samples := newReservoir(c.cfg.ParityCfg.Queries)
+	err := dumper.ReadFaissColumns(ctx, c.cfg, func(columns []entity.Column) error {
+		return forEachVector(columns, samples.add)
+	})
+	if err != nil {
+		return err
+	}
+	searchers := make([]*exactSearcher, 0, len(samples.items))
+	for _, q := range samples.items {
+		searchers = append(searchers, newExactSearcher(q.vector, c.cfg.ParityCfg.TopK, colCfg.MetricType))
+	}
+	err = dumper.ReadFaissColumns(ctx, c.cfg, func(columns []entity.Column) error {
+		return forEachVector(columns, func(pk string, vector []float32) {
+			for _, searcher := range searchers {
+				searcher.add(pk, vector)
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+	sourceResults := make([][]string, 0, len(searchers))
+	for _, searcher := range searchers {
+		sourceResults = append(sourceResults, searcher.result())
+	}
+	cr, err = c.compareTarget(ctx, cr, samples.items, sourceResults)
+	if err != nil {
+		return err
+	}
+	report.addCollection(cr)
+	return nil
+}
+
+func forEachVector(columns []entity.Column, handle func(pk string, vector []float32)) error {
+	var ids []int64
+	var vectors [][]float32
+	for _, col := range columns {
+		switch c := col.(type) {
+		case *entity.ColumnInt64:
+			if c.Name() == numpyPKField {
+				ids = c.Data()
+			}
+		case *entity.ColumnFloatVector:
+			vectors = c.Data()
+		}
+	}
+	if len(ids) != len(vectors) {
+		return fmt.Errorf("faiss ids size %d not match vectors size %d", len(ids), len(vectors))
+	}
+	for i := range ids {
+		handle(fmt.Sprint(ids[i]), vectors[i])
+	}
+	return nil
+}
+
+// compareTarget : search the same queries on target collection, compare with the source results
+func (c *Checker) compareTarget(ctx context.Context, cr *CollectionReport, queries []*queryVector, sourceResults [][]string) (*CollectionReport, error) {
+	if cr.TargetMetric == "" {
+		cr.TargetMetric = indexMetricType(ctx, c.targetCli, cr.Collection, cr.VectorField)
+	}
+	targetResults, err := c.search(ctx, c.targetCli, cr.Collection, common.EMPTY, cr.VectorField, cr.TargetMetric, queries)
+	if err != nil {
+		return nil, err
+	}
+	for i, q := range queries {
+		cr.addQuery(q.pk, sourceResults[i], targetResults[i])
+	}
+	cr.summarize(c.cfg.ParityCfg.MinRecall)
+	log.LL(ctx).Info("[Parity] check collection finish", zap.String("collection", cr.Collection), zap.Bool("passed", cr.Passed),
+		zap.Float64("meanRecall", cr.MeanRecall), zap.Float64("meanRankOverlap", cr.MeanRankOverlap))
+	return cr, nil
+}
+
+// search : milvus top-k search in batches, the result pks convert to string for compare with other source
+func (c *Checker) search(ctx context.Context, cli *milvus2x.Milvus2xClient, collection string, expr string, vectorField string,
+	metricType string, queries []*queryVector) ([][]string, error) {
+	results := make([][]string, 0, len(queries))
+	for start := 0; start < len(queries); start += searchBatch {
+		end := min(start+searchBatch, len(queries))
+		vectors := make([][]float32, 0, end-start)
+		for _, q := range queries[start:end] {
+			vectors = append(vectors, q.vector)
+		}
+		rs, err := cli.VerCli.Search(ctx, collection, expr, vectorField, metricType, vectors, c.cfg.ParityCfg.TopK,
+			c.cfg.ParityCfg.SearchParams)
+		if err != nil {
+			log.Error("[Parity] milvus search error", zap.String("collection", collection), zap.Error(err))
+			return nil, err
+		}
+		for _, pks := range rs {
+			strPKs := make([]string, 0, len(pks))
+			for _, pk := range pks {
+				strPKs = append(strPKs, fmt.Sprint(pk))
+			}
+			results = append(results, strPKs)
+		}
+	}
+	return results, nil
+}
+
+// indexMetricType : metric type of the vector field index, empty will use the server default
+func indexMetricType(ctx context.Context, cli *milvus2x.Milvus2xClient, collection string, field string) string {
+	indexes, err := cli.VerCli.DescIndex(ctx, collection, field)
+	if err != nil {
+		log.Warn("[Parity] describe index error, use the default metric type", zap.String("collection", collection),
+			zap.String("field", field), zap.Error(err))
+		return common.EMPTY
+	}
+	for _, index := range indexes {
+		if metricType, ok := index.Params()["metric_type"]; ok {
+			return metricType
+		}
+	}
+	return common.EMPTY
+}
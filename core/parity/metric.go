@@ -0,0 +1,175 @@
+package parity
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// queryVector : one random source vector used as search query, pk is the string value of the source pk
+type queryVector struct {
+	pk     string
+	vector []float32
+}
+
+// reservoir : uniform random sample of the streaming source vectors
+type reservoir struct {
+	size   int
+	seen   int64
+	items  []*queryVector
+	random *rand.Rand
+}
+
+func newReservoir(size int) *reservoir {
+	return &reservoir{size: size, random: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+func (r *reservoir) add(pk string, vector []float32) {
+	r.seen++
+	if len(r.items) < r.size {
+		r.items = append(r.items, &queryVector{pk: pk, vector: vector})
+		return
+	}
+	slot := r.random.Int63n(r.seen)
+	if slot < int64(r.size) {
+		r.items[slot] = &queryVector{pk: pk, vector: vector}
+	}
+}
+
+// recallAtK : the ratio of source top-k pks found in target top-k
+func recallAtK(source []string, target []string) float64 {
+	if len(source) == 0 {
+		if len(target) == 0 {
+			return 1
+		}
+		return 0
+	}
+	targetSet := make(map[string]bool, len(target))
+	for _, pk := range target {
+		targetSet[pk] = true
+	}
+	hit := 0
+	for _, pk := range source {
+		if targetSet[pk] {
+			hit++
+		}
+	}
+	return float64(hit) / float64(len(source))
+}
+
+// rankOverlap : average overlap of the two rankings, mean of |S[:d] ∩ T[:d]| / d for depth d in 1..k,
+// 1 means the same pks in the same order
+func rankOverlap(source []string, target []string) float64 {
+	k := max(len(source), len(target))
+	if k == 0 {
+		return 1
+	}
+	sourceSeen := make(map[string]bool, k)
+	targetSeen := make(map[string]bool, k)
+	common := 0
+	var sum float64
+	for d := 0; d < k; d++ {
+		if d < len(source) {
+			if targetSeen[source[d]] {
+				common++
+			}
+			sourceSeen[source[d]] = true
+		}
+		if d < len(target) {
+			if sourceSeen[target[d]] {
+				common++
+			}
+			targetSeen[target[d]] = true
+		}
+		sum += float64(common) / float64(d+1)
+	}
+	return sum / float64(k)
+}
+
+// vectorScore : the larger the closer, L2 use the negative squared distance
+func vectorScore(metricType string, a []float32, b []float32) float64 {
+	switch strings.ToUpper(metricType) {
+	case "IP":
+		var dot float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+		}
+		return dot
+	case "COSINE":
+		var dot, normA, normB float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+			normA += float64(a[i]) * float64(a[i])
+			normB += float64(b[i]) * float64(b[i])
+		}
+		if normA == 0 || normB == 0 {
+			return 0
+		}
+		return dot / math.Sqrt(normA*normB)
+	default:
+		var dist float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			dist += d * d
+		}
+		return -dist
+	}
+}
+
+type scoredPK struct {
+	pk    string
+	score float64
+}
+
+// topKHeap : min heap by score, the root is the worst of the current top-k
+type topKHeap []scoredPK
+
+func (h topKHeap) Len() int           { return len(h) }
+func (h topKHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h topKHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *topKHeap) Push(x any)        { *h = append(*h, x.(scoredPK)) }
+func (h *topKHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// exactSearcher : brute-force exact top-k search of one query, the ground truth of the source without search service
+type exactSearcher struct {
+	query      []float32
+	k          int
+	metricType string
+	h          topKHeap
+}
+
+func newExactSearcher(query []float32, k int, metricType string) *exactSearcher {
+	return &exactSearcher{query: query, k: k, metricType: metricType}
+}
+
+func (es *exactSearcher) add(pk string, vector []float32) {
+	score := vectorScore(es.metricType, es.query, vector)
+	if len(es.h) < es.k {
+		heap.Push(&es.h, scoredPK{pk: pk, score: score})
+		return
+	}
+	if score > es.h[0].score {
+		es.h[0] = scoredPK{pk: pk, score: score}
+		heap.Fix(&es.h, 0)
+	}
+}
+
+// result : the top-k pks in rank order
+func (es *exactSearcher) result() []string {
+	items := make([]scoredPK, len(es.h))
+	copy(items, es.h)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].score > items[j].score })
+	pks := make([]string, 0, len(items))
+	for _, item := range items {
+		pks = append(pks, item.pk)
+	}
+	return pks
+}
@@ -0,0 +1,56 @@
+package parity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecallAndRankOverlap(t *testing.T) {
+	assert.Equal(t, 1.0, recallAtK([]string{"1", "2", "3"}, []string{"3", "2", "1"}))
+	assert.InDelta(t, 2.0/3, recallAtK([]string{"1", "2", "3"}, []string{"1", "2", "9"}), 1e-9)
+	assert.Equal(t, 1.0, recallAtK(nil, nil))
+	assert.Equal(t, 0.0, recallAtK(nil, []string{"1"}))
+
+	assert.Equal(t, 1.0, rankOverlap([]string{"1", "2", "3"}, []string{"1", "2", "3"}))
+	// depth1: 0/1, depth2: 2/2, depth3: 3/3
+	assert.InDelta(t, 2.0/3, rankOverlap([]string{"1", "2", "3"}, []string{"2", "1", "3"}), 1e-9)
+	assert.Equal(t, 0.0, rankOverlap([]string{"1", "2"}, []string{"3", "4"}))
+}
+
+func TestExactSearcher(t *testing.T) {
+	vectors := map[string][]float32{"a": {0, 0}, "b": {1, 0}, "c": {3, 0}, "d": {0, 2}}
+	searcher := newExactSearcher([]float32{0.9, 0}, 2, "L2")
+	for _, pk := range []string{"a", "b", "c", "d"} {
+		searcher.add(pk, vectors[pk])
+	}
+	assert.Equal(t, []string{"b", "a"}, searcher.result())
+
+	searcher = newExactSearcher([]float32{1, 0}, 2, "IP")
+	for _, pk := range []string{"a", "b", "c", "d"} {
+		searcher.add(pk, vectors[pk])
+	}
+	assert.Equal(t, []string{"c", "b"}, searcher.result())
+}
+
+func TestReservoir(t *testing.T) {
+	r := newReservoir(3)
+	for i := 0; i < 100; i++ {
+		r.add(string(rune('a'+i%26)), []float32{float32(i)})
+	}
+	assert.Len(t, r.items, 3)
+	assert.Equal(t, int64(100), r.seen)
+}
+
+func TestCollectionSummarize(t *testing.T) {
+	cr := &CollectionReport{}
+	cr.addQuery("1", []string{"1", "2"}, []string{"1", "2"})
+	cr.addQuery("2", []string{"2", "3"}, []string{"2", "9"})
+	cr.summarize(0.9)
+	assert.InDelta(t, 0.75, cr.MeanRecall, 1e-9)
+	assert.Equal(t, 0.5, cr.MinRecall)
+	assert.False(t, cr.Passed)
+
+	cr = &CollectionReport{}
+	cr.summarize(0.9)
+	assert.False(t, cr.Passed)
+}
@@ -0,0 +1,99 @@
+package parity
+
+import (
+	"fmt"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"strings"
+)
+
+// Report : search parity result of all collections, Passed only when all collections passed
+type Report struct {
+	JobId       string              `json:"jobId"`
+	SourceType  string              `json:"sourceType"`
+	TopK        int                 `json:"topK"`
+	MinRecall   float64             `json:"minRecall"`
+	Passed      bool                `json:"passed"`
+	Collections []*CollectionReport `json:"collections"`
+}
+
+type CollectionReport struct {
+	Source          string         `json:"source"` //source collection, es index or faiss file
+	Collection      string         `json:"collection"`
+	VectorField     string         `json:"vectorField"`
+	SourceMetric    string         `json:"sourceMetric"`
+	TargetMetric    string         `json:"targetMetric"`
+	Passed          bool           `json:"passed"`
+	MeanRecall      float64        `json:"meanRecall"`
+	MinRecall       float64        `json:"minRecall"`
+	MeanRankOverlap float64        `json:"meanRankOverlap"`
+	Queries         []*QueryReport `json:"queries"`
+	Notes           []string       `json:"notes,omitempty"`
+}
+
+type QueryReport struct {
+	PK          string   `json:"pk"` //pk of the source vector used as query
+	Recall      float64  `json:"recall"`
+	RankOverlap float64  `json:"rankOverlap"`
+	SourceTopK  []string `json:"sourceTopK"`
+	TargetTopK  []string `json:"targetTopK"`
+}
+
+// addQuery : compare the source and target top-k pks of one query
+func (cr *CollectionReport) addQuery(pk string, sourceTopK []string, targetTopK []string) {
+	cr.Queries = append(cr.Queries, &QueryReport{
+		PK:          pk,
+		Recall:      recallAtK(sourceTopK, targetTopK),
+		RankOverlap: rankOverlap(sourceTopK, targetTopK),
+		SourceTopK:  sourceTopK,
+		TargetTopK:  targetTopK,
+	})
+}
+
+// summarize : collection passed when has queries and the mean recall reach minRecall
+func (cr *CollectionReport) summarize(minRecall float64) {
+	if len(cr.Queries) == 0 {
+		cr.Passed = false
+		cr.Notes = append(cr.Notes, "no source vector sampled")
+		return
+	}
+	cr.MinRecall = 1
+	var recallSum, overlapSum float64
+	for _, q := range cr.Queries {
+		recallSum += q.Recall
+		overlapSum += q.RankOverlap
+		cr.MinRecall = min(cr.MinRecall, q.Recall)
+	}
+	cr.MeanRecall = recallSum / float64(len(cr.Queries))
+	cr.MeanRankOverlap = overlapSum / float64(len(cr.Queries))
+	cr.Passed = cr.MeanRecall >= minRecall
+}
+
+func (r *Report) addCollection(cr *CollectionReport) {
+	r.Collections = append(r.Collections, cr)
+	r.Passed = r.Passed && cr.Passed
+}
+
+// String : the readable report text
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Parity Job %s sourceType=%s topK=%d minRecall=%.3f result=%s\n", r.JobId, r.SourceType, r.TopK,
+		r.MinRecall, util.PassText(r.Passed))
+	for _, cr := range r.Collections {
+		fmt.Fprintf(&sb, "  collection %s (source %s) field=%s metric=%s->%s: %s\n", cr.Collection, cr.Source,
+			cr.VectorField, cr.SourceMetric, cr.TargetMetric, util.PassText(cr.Passed))
+		fmt.Fprintf(&sb, "    queries=%d meanRecall=%.4f minRecall=%.4f meanRankOverlap=%.4f\n", len(cr.Queries),
+			cr.MeanRecall, cr.MinRecall, cr.MeanRankOverlap)
+		for _, q := range cr.Queries {
+			fmt.Fprintf(&sb, "    query pk=%s recall=%.4f rankOverlap=%.4f\n", q.PK, q.Recall, q.RankOverlap)
+		}
+		for _, note := range cr.Notes {
+			fmt.Fprintf(&sb, "    note: %s\n", note)
+		}
+	}
+	return sb.String()
+}
+
+// Save : write the report json to local file
+func (r *Report) Save(file string) error {
+	return util.SaveJSONReport(file, r)
+}
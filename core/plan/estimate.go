@@ -0,0 +1,112 @@
+package plan
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"strconv"
+)
+
+const (
+	// the real length of varchar, json, sparse vector and dynamic data unknown before read, use the average guess
+	guessVarcharLen   = 64
+	guessJSONLen      = 128
+	guessSparseNNZ    = 64
+	guessArrayLen     = 16
+	textNumberLen     = 12 //text float or int value like "-0.12345678,"
+	textInt64Len      = 20
+	textFieldOverhead = 4 //"name": quotes, colon and comma of the json key
+)
+
+// estimateRowBytes : rough bytes of one row in the intermediate file, json and jsonl are text format,
+// numpy and parquet are binary format (parquet compression not considered)
+func estimateRowBytes(fields []*entity.Field, dynamic bool, format common.FileFormat) int64 {
+	text := format == common.FormatJSON || format == common.FormatJSONL
+	var size int64
+	for _, field := range fields {
+		if field.PrimaryKey && field.AutoID {
+			continue
+		}
+		if text {
+			size += int64(len(field.Name)+textFieldOverhead) + textFieldBytes(field)
+		} else {
+			size += binaryFieldBytes(field)
+		}
+	}
+	if dynamic {
+		size += guessJSONLen
+	}
+	if text {
+		size += 3 //braces and line break
+	}
+	return size
+}
+
+func binaryFieldBytes(field *entity.Field) int64 {
+	switch field.DataType {
+	case entity.FieldTypeBool, entity.FieldTypeInt8:
+		return 1
+	case entity.FieldTypeInt16:
+		return 2
+	case entity.FieldTypeInt32, entity.FieldTypeFloat:
+		return 4
+	case entity.FieldTypeInt64, entity.FieldTypeDouble:
+		return 8
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return 4 + varcharLen(field)
+	case entity.FieldTypeJSON:
+		return 4 + guessJSONLen
+	case entity.FieldTypeArray:
+		elem := &entity.Field{DataType: field.ElementType, TypeParams: field.TypeParams}
+		return 4 + arrayLen(field)*binaryFieldBytes(elem)
+	case entity.FieldTypeFloatVector:
+		return typeParam(field, entity.TypeParamDim, 0) * 4
+	case entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		return typeParam(field, entity.TypeParamDim, 0) * 2
+	case entity.FieldTypeBinaryVector:
+		return typeParam(field, entity.TypeParamDim, 0) / 8
+	case entity.FieldTypeSparseVector:
+		return guessSparseNNZ * 8 //uint32 index + float32 value
+	default:
+		return 8
+	}
+}
+
+func textFieldBytes(field *entity.Field) int64 {
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		return 5
+	case entity.FieldTypeInt64:
+		return textInt64Len
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return 2 + varcharLen(field)
+	case entity.FieldTypeJSON:
+		return guessJSONLen
+	case entity.FieldTypeArray:
+		elem := &entity.Field{DataType: field.ElementType, TypeParams: field.TypeParams}
+		return 2 + arrayLen(field)*(textFieldBytes(elem)+1)
+	case entity.FieldTypeFloatVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		return 2 + typeParam(field, entity.TypeParamDim, 0)*textNumberLen
+	case entity.FieldTypeBinaryVector:
+		return 2 + typeParam(field, entity.TypeParamDim, 0)/8*4
+	case entity.FieldTypeSparseVector:
+		return 2 + guessSparseNNZ*2*textNumberLen
+	default:
+		return textNumberLen
+	}
+}
+
+func varcharLen(field *entity.Field) int64 {
+	return min(typeParam(field, entity.TypeParamMaxLength, guessVarcharLen), guessVarcharLen)
+}
+
+func arrayLen(field *entity.Field) int64 {
+	return min(typeParam(field, entity.TypeParamMaxCapacity, guessArrayLen), guessArrayLen)
+}
+
+func typeParam(field *entity.Field, key string, def int64) int64 {
+	v, err := strconv.ParseInt(field.TypeParams[key], 10, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
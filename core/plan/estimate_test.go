@@ -0,0 +1,57 @@
+package plan
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"strings"
+	"testing"
+)
+
+func TestEstimateRowBytesNumpy(t *testing.T) {
+	fields := []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true),
+		entity.NewField().WithName("data").WithDataType(entity.FieldTypeFloatVector).WithDim(128),
+	}
+	assert.Equal(t, int64(8+128*4), estimateRowBytes(fields, false, common.FormatNumpy))
+}
+
+func TestEstimateRowBytesText(t *testing.T) {
+	fields := []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar).WithIsPrimaryKey(true).WithMaxLength(16),
+		entity.NewField().WithName("vec").WithDataType(entity.FieldTypeFloatVector).WithDim(4),
+	}
+	expect := int64(len("id")+textFieldOverhead+2+16) + int64(len("vec")+textFieldOverhead+2+4*textNumberLen) + 3
+	assert.Equal(t, expect, estimateRowBytes(fields, false, common.FormatJSON))
+	assert.Equal(t, expect+guessJSONLen, estimateRowBytes(fields, true, common.FormatJSONL))
+}
+
+func TestEstimateRowBytesSkipAutoId(t *testing.T) {
+	fields := []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true),
+		entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(1024),
+	}
+	assert.Equal(t, int64(4+guessVarcharLen), estimateRowBytes(fields, false, common.FormatParquet))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512B", formatBytes(512))
+	assert.Equal(t, "1.5KB", formatBytes(1536))
+	assert.Equal(t, "2.0GB", formatBytes(2*1024*1024*1024))
+}
+
+func TestPlanString(t *testing.T) {
+	p := &Plan{JobId: "job", SourceType: "milvus2x", TargetType: "milvus2x", InsertMode: "batch", OnExists: "append"}
+	cp := &CollectionPlan{Source: "src", Collection: "dst", Rows: 10, ShardsNum: 0, ConsistencyLevel: "Bounded",
+		Fields: []*FieldPlan{{Name: "id", DataType: "Int64", PrimaryKey: true}, {Name: "vec", DataType: "FloatVector",
+			Params: map[string]string{"dim": "8"}}}}
+	cp.warn("collection already exist in target with %d rows, source rows will be appended", 5)
+	p.addCollection(cp)
+	text := p.String()
+	assert.True(t, strings.Contains(text, "collection dst (source src) rows=10\n"))
+	assert.True(t, strings.Contains(text, "shards=default consistency=Bounded"))
+	assert.True(t, strings.Contains(text, "field vec FloatVector dim=8"))
+	assert.True(t, strings.Contains(text, "warning: collection already exist in target with 5 rows"))
+	assert.Equal(t, 1, p.WarningCount())
+	assert.Equal(t, int64(10), p.TotalRows)
+}
@@ -0,0 +1,171 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Plan : the dry-run migration plan, nothing is written to the target
+type Plan struct {
+	JobId          string            `json:"jobId"`
+	SourceType     string            `json:"sourceType"`
+	TargetType     string            `json:"targetType"`
+	InsertMode     string            `json:"insertMode"` //bulk, batch or files
+	FileFormat     string            `json:"fileFormat,omitempty"`
+	OnExists       string            `json:"onExists,omitempty"`
+	TotalRows      int64             `json:"totalRows"`
+	TotalFileBytes int64             `json:"totalFileBytes"`
+	Collections    []*CollectionPlan `json:"collections"`
+	Warnings       []string          `json:"warnings,omitempty"`
+}
+
+type CollectionPlan struct {
+	Source             string            `json:"source"` //source collection, es index or faiss file
+	Collection         string            `json:"collection"`
+	Rows               int64             `json:"rows"`
+	EstimatedFileBytes int64             `json:"estimatedFileBytes"` //0 when batch insert, no intermediate files
+	ShardsNum          int               `json:"shardsNum"`
+	ConsistencyLevel   string            `json:"consistencyLevel"`
+	AutoId             bool              `json:"autoId"`
+	EnableDynamicField bool              `json:"enableDynamicField"`
+	PartitionKey       string            `json:"partitionKey,omitempty"`
+	Partitions         []string          `json:"partitions,omitempty"`
+	Fields             []*FieldPlan      `json:"fields"`
+	Indexes            map[string]string `json:"indexes,omitempty"` //key: field name, val: index type and metric
+	TargetExists       bool              `json:"targetExists"`
+	TargetRows         int64             `json:"targetRows"`
+	Warnings           []string          `json:"warnings,omitempty"`
+}
+
+type FieldPlan struct {
+	Name         string            `json:"name"`
+	DataType     string            `json:"dataType"`
+	PrimaryKey   bool              `json:"primaryKey,omitempty"`
+	AutoId       bool              `json:"autoId,omitempty"`
+	PartitionKey bool              `json:"partitionKey,omitempty"`
+	ElementType  string            `json:"elementType,omitempty"`
+	Params       map[string]string `json:"params,omitempty"`
+}
+
+func (cp *CollectionPlan) warn(format string, args ...any) {
+	cp.Warnings = append(cp.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *Plan) addCollection(cp *CollectionPlan) {
+	p.Collections = append(p.Collections, cp)
+	p.TotalRows += cp.Rows
+	p.TotalFileBytes += cp.EstimatedFileBytes
+}
+
+// WarningCount : all warnings of the job and collections
+func (p *Plan) WarningCount() int {
+	count := len(p.Warnings)
+	for _, cp := range p.Collections {
+		count += len(cp.Warnings)
+	}
+	return count
+}
+
+// String : the readable plan text
+func (p *Plan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Migration Plan Job %s source=%s target=%s insertMode=%s", p.JobId, p.SourceType, p.TargetType, p.InsertMode)
+	if p.FileFormat != "" {
+		fmt.Fprintf(&sb, " fileFormat=%s", p.FileFormat)
+	}
+	if p.OnExists != "" {
+		fmt.Fprintf(&sb, " onExists=%s", p.OnExists)
+	}
+	sb.WriteString("\n")
+	for _, cp := range p.Collections {
+		fmt.Fprintf(&sb, "  collection %s (source %s) rows=%d", cp.Collection, cp.Source, cp.Rows)
+		if p.InsertMode != "batch" {
+			fmt.Fprintf(&sb, " estimatedFileSize=%s", formatBytes(cp.EstimatedFileBytes))
+		}
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "    shards=%s consistency=%s autoId=%t dynamicField=%t", shardsText(cp.ShardsNum), cp.ConsistencyLevel,
+			cp.AutoId, cp.EnableDynamicField)
+		if cp.PartitionKey != "" {
+			fmt.Fprintf(&sb, " partitionKey=%s", cp.PartitionKey)
+		}
+		if len(cp.Partitions) > 0 {
+			fmt.Fprintf(&sb, " partitions=[%s]", strings.Join(cp.Partitions, ","))
+		}
+		sb.WriteString("\n")
+		for _, f := range cp.Fields {
+			fmt.Fprintf(&sb, "    field %s %s%s\n", f.Name, f.DataType, f.attrText())
+		}
+		for _, name := range sortedKeys(cp.Indexes) {
+			fmt.Fprintf(&sb, "    index %s: %s\n", name, cp.Indexes[name])
+		}
+		if cp.TargetExists {
+			fmt.Fprintf(&sb, "    target collection exists rows=%d\n", cp.TargetRows)
+		}
+		for _, w := range cp.Warnings {
+			fmt.Fprintf(&sb, "    warning: %s\n", w)
+		}
+	}
+	fmt.Fprintf(&sb, "  total collections=%d rows=%d", len(p.Collections), p.TotalRows)
+	if p.InsertMode != "batch" {
+		fmt.Fprintf(&sb, " estimatedFileSize=%s", formatBytes(p.TotalFileBytes))
+	}
+	sb.WriteString("\n")
+	for _, w := range p.Warnings {
+		fmt.Fprintf(&sb, "  warning: %s\n", w)
+	}
+	return sb.String()
+}
+
+func (f *FieldPlan) attrText() string {
+	var attrs []string
+	if f.PrimaryKey {
+		attrs = append(attrs, "pk")
+	}
+	if f.AutoId {
+		attrs = append(attrs, "autoId")
+	}
+	if f.PartitionKey {
+		attrs = append(attrs, "partitionKey")
+	}
+	if f.ElementType != "" {
+		attrs = append(attrs, "element_type="+f.ElementType)
+	}
+	for _, key := range sortedKeys(f.Params) {
+		attrs = append(attrs, key+"="+f.Params[key])
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " " + strings.Join(attrs, " ")
+}
+
+func shardsText(shardsNum int) string {
+	if shardsNum <= 0 {
+		return "default"
+	}
+	return fmt.Sprint(shardsNum)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatBytes : human readable size, eg: 1.5GB
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,360 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/dbclient"
+	"github.com/zilliztech/milvus-migration/core/dumper"
+	"github.com/zilliztech/milvus-migration/core/factory/es_factory"
+	"github.com/zilliztech/milvus-migration/core/loader"
+	"github.com/zilliztech/milvus-migration/core/meta"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
+	milvus2xconvert "github.com/zilliztech/milvus-migration/core/transform/milvus2x/convert"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/es"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Planner : resolve the whole migration plan by the source meta and the target collections, not write anything
+type Planner struct {
+	cfg       *config.MigrationConfig
+	jobId     string
+	targetCli *dbclient.Milvus2x //nil when the target is files
+}
+
+func NewPlanner(cfg *config.MigrationConfig, jobId string) *Planner {
+	return &Planner{cfg: cfg, jobId: jobId}
+}
+
+// Run : connect to source and target, build the plan of all collections of the config
+func (p *Planner) Run(ctx context.Context) (*Plan, error) {
+	start := time.Now()
+	plan := p.newPlan()
+	if p.cfg.TargetType != string(common.T_FILES) {
+		targetCli, err := dbclient.NewMilvus2xClient(p.cfg.TargetMilvus2xCfg)
+		if err != nil {
+			return nil, err
+		}
+		defer targetCli.GetMilvus().Close()
+		p.targetCli = targetCli
+	}
+
+	var err error
+	switch common.DumpMode(p.cfg.DumperWorkCfg.WorkMode) {
+	case common.Milvus2x:
+		err = p.planMilvus2x(ctx, plan)
+	case common.Elasticsearch:
+		err = p.planES(ctx, plan)
+	case common.Milvus1x:
+		err = p.planMilvus1x(ctx, plan)
+	case common.Faiss:
+		err = p.planFaiss(ctx, plan)
+	default:
+		err = fmt.Errorf("plan not support WorkMode %s", p.cfg.DumperWorkCfg.WorkMode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.LL(ctx).Info("[Plan] plan finish", zap.String("jobId", p.jobId), zap.Int("collections", len(plan.Collections)),
+		zap.Int("warnings", plan.WarningCount()), zap.Float64("Cost", time.Since(start).Seconds()))
+	return plan, nil
+}
+
+func (p *Planner) newPlan() *Plan {
+	plan := &Plan{JobId: p.jobId, SourceType: p.cfg.DumperWorkCfg.WorkMode, TargetType: string(common.T_MILVUS2X)}
+	switch {
+	case p.cfg.TargetType == string(common.T_FILES):
+		plan.TargetType = string(common.T_FILES)
+		plan.InsertMode = string(common.T_FILES)
+		plan.FileFormat = p.cfg.TargetFilesFormat
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("files target, the schema only exported to the manifest in %s",
+			p.cfg.TargetOutputDir))
+	case common.DumpMode(p.cfg.DumperWorkCfg.WorkMode) == common.Milvus2x ||
+		p.cfg.LoaderWorkCfg.InsertMode == string(common.BatchInsert):
+		plan.InsertMode = string(common.BatchInsert)
+	default:
+		plan.InsertMode = string(common.BulkInsert)
+		plan.FileFormat = p.cfg.DumperWorkCfg.FileFormat
+	}
+	if p.cfg.TargetMilvus2xCfg != nil && plan.TargetType == string(common.T_MILVUS2X) {
+		plan.OnExists = p.cfg.TargetMilvus2xCfg.OnExists
+	}
+	return plan
+}
+
+func (p *Planner) planMilvus2x(ctx context.Context, plan *Plan) error {
+	metaJson, err := meta.NewMetaHelperForDumper(p.cfg).ReadMilvus2xMeta(ctx)
+	if err != nil {
+		return err
+	}
+	p.cfg.SourceMilvus2xConfig.Version = metaJson.Version
+	sourceCli, err := milvus2x.CreateMilvus2xClient(p.cfg.SourceMilvus2xConfig)
+	if err != nil {
+		return err
+	}
+	defer sourceCli.VerCli.Close()
+	if milvus2x.IsBeforeVer23(sourceCli.Version) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("source milvus %s not support query iterator, will read by query pagination",
+			sourceCli.Version))
+	}
+	for _, collCfg := range metaJson.CollCfgs {
+		cp, err := p.planMilvus2xCollection(ctx, sourceCli, collCfg)
+		if err != nil {
+			log.Error("[Plan] plan Milvus2x collection err", zap.String("collection", collCfg.Collection), zap.Error(err))
+			return err
+		}
+		plan.addCollection(cp)
+	}
+	return nil
+}
+
+func (p *Planner) planMilvus2xCollection(ctx context.Context, sourceCli *milvus2x.Milvus2xClient,
+	collCfg *milvus2xtype.CollectionCfg) (*CollectionPlan, error) {
+	srcColl, err := sourceCli.VerCli.DescCollection(ctx, collCfg.Collection)
+	if err != nil {
+		return nil, err
+	}
+	collectionInfo, err := milvus2xconvert.ToMilvusParam(ctx, collCfg, sourceCli)
+	if err != nil {
+		return nil, err
+	}
+	cp := p.newCollectionPlan(collCfg.Collection, collectionInfo)
+	if srcColl.Schema.EnableDynamicField && collCfg.MilvusCfg.CloseDynamicField {
+		cp.warn("source dynamic field data will not be migrated by closeDynamicField")
+	}
+	cp.Rows, err = sourceCli.VerCli.CountPartition(ctx, collCfg, common.EMPTY)
+	if err != nil {
+		return nil, err
+	}
+	return p.finishCollectionPlan(ctx, cp, collectionInfo)
+}
+
+func (p *Planner) planES(ctx context.Context, plan *Plan) error {
+	metaJson, err := meta.NewMetaHelperForDumper(p.cfg).ReadESMeta(ctx)
+	if err != nil {
+		return err
+	}
+	p.cfg.SourceESConfig.Version = metaJson.Version
+	esCli := es_factory.GetESCli(p.cfg.SourceESConfig)
+	for _, idxCfg := range metaJson.IdxCfgs {
+		cp, err := p.planESIndex(ctx, esCli, idxCfg)
+		if err != nil {
+			log.Error("[Plan] plan ES index err", zap.String("index", idxCfg.Index), zap.Error(err))
+			return err
+		}
+		plan.addCollection(cp)
+	}
+	return nil
+}
+
+func (p *Planner) planESIndex(ctx context.Context, esCli *es.ESClient, idxCfg *estype.IdxCfg) (*CollectionPlan, error) {
+	collectionInfo, err := esconvert.ToMilvusParam(idxCfg)
+	if err != nil {
+		return nil, err
+	}
+	cp := p.newCollectionPlan(idxCfg.Index, collectionInfo)
+	//count return error when the index is empty, the real run will fail by the same error
+	err = esCli.Cli.Count(idxCfg)
+	if err != nil {
+		cp.warn("count es index error: %s", err.Error())
+	}
+	cp.Rows = idxCfg.Rows
+	for _, field := range collectionInfo.Fields {
+		if field.DataType == entity.FieldTypeSparseVector {
+			cp.warn("sparse vector field %s indices are encoded by the job vocabulary", field.Name)
+		}
+	}
+	return p.finishCollectionPlan(ctx, cp, collectionInfo)
+}
+
+func (p *Planner) planMilvus1x(ctx context.Context, plan *Plan) error {
+	metaJson, err := meta.NewMetaHelperForDumper(p.cfg).ReadMeta(ctx)
+	if err != nil {
+		return err
+	}
+	for _, colInfo := range metaJson.Collections {
+		metricType, err := loader.ConvertMetricTypeFrom1xTo2x(colInfo.MetricType)
+		if err != nil {
+			return err
+		}
+		param := &common.CollectionParam{
+			CollectionName: colInfo.Collection,
+			MetricType:     metricType,
+			Dim:            colInfo.Dim,
+		}
+		cp, err := p.planNumpyCollection(ctx, colInfo.Collection, param, int64(colInfo.Rows))
+		if err != nil {
+			return err
+		}
+		plan.addCollection(cp)
+	}
+	return nil
+}
+
+func (p *Planner) planFaiss(ctx context.Context, plan *Plan) error {
+	dim, rows, err := dumper.ReadFaissHead(p.cfg)
+	if err != nil {
+		return err
+	}
+	colCfg := p.cfg.LoaderWorkCfg.CreateColCfg
+	param := &common.CollectionParam{
+		CollectionName: colCfg.CollectionName,
+		MetricType:     colCfg.MetricType,
+		ShardsNum:      colCfg.ShardsNum,
+		Dim:            colCfg.Dim,
+	}
+	cp, err := p.planNumpyCollection(ctx, p.cfg.SourceFaissFile, param, int64(rows))
+	if err != nil {
+		return err
+	}
+	if dim != colCfg.Dim {
+		cp.warn("faiss file vector dim %d not equal [target.create.collection.dim] %d", dim, colCfg.Dim)
+	}
+	plan.addCollection(cp)
+	return nil
+}
+
+// planNumpyCollection : milvus1x and faiss target collection has the fixed schema
+func (p *Planner) planNumpyCollection(ctx context.Context, source string, param *common.CollectionParam, rows int64) (*CollectionPlan, error) {
+	milvusCfg := p.cfg.LoaderWorkCfg.MilvusCfg
+	level := entity.ClBounded
+	param.ConsistencyLevel = &level
+	if milvusCfg != nil {
+		param.CreateIndex = milvusCfg.CreateIndex
+		param.LoadData = milvusCfg.LoadData
+	}
+	collectionInfo := &common.CollectionInfo{Param: param, Fields: dbclient.NumpySchema(param).Fields}
+	if param.CreateIndex {
+		indexes, err := loader.ToNumpyIndexes(p.cfg.LoaderWorkCfg.FieldIndexes, param.MetricType)
+		if err != nil {
+			return nil, err
+		}
+		collectionInfo.Indexes = indexes
+	}
+	cp := p.newCollectionPlan(source, collectionInfo)
+	cp.Rows = rows
+	return p.finishCollectionPlan(ctx, cp, collectionInfo)
+}
+
+func (p *Planner) newCollectionPlan(source string, collectionInfo *common.CollectionInfo) *CollectionPlan {
+	param := collectionInfo.Param
+	level := entity.DefaultConsistencyLevel
+	if param.ConsistencyLevel != nil {
+		level = *param.ConsistencyLevel
+	}
+	cp := &CollectionPlan{
+		Source:             source,
+		Collection:         param.CollectionName,
+		ShardsNum:          param.ShardsNum,
+		ConsistencyLevel:   level.CommonConsistencyLevel().String(),
+		AutoId:             param.AutoId,
+		EnableDynamicField: param.EnableDynamicField,
+		PartitionKey:       collectionInfo.PartitionKey,
+	}
+	for _, partition := range collectionInfo.Partitions {
+		cp.Partitions = append(cp.Partitions, partition.Name)
+	}
+	for _, field := range collectionInfo.Fields {
+		fp := &FieldPlan{
+			Name:         field.Name,
+			DataType:     field.DataType.Name(),
+			PrimaryKey:   field.PrimaryKey,
+			AutoId:       field.PrimaryKey && (field.AutoID || param.AutoId),
+			PartitionKey: field.IsPartitionKey,
+			Params:       field.TypeParams,
+		}
+		if field.DataType == entity.FieldTypeArray {
+			fp.ElementType = field.ElementType.Name()
+		}
+		cp.Fields = append(cp.Fields, fp)
+	}
+	if param.CreateIndex && len(collectionInfo.Indexes) > 0 {
+		cp.Indexes = make(map[string]string, len(collectionInfo.Indexes))
+		for name, index := range collectionInfo.Indexes {
+			text := string(index.IndexType())
+			if metricType := index.Params()["metric_type"]; metricType != "" {
+				text += " metric=" + metricType
+			}
+			cp.Indexes[name] = text
+		}
+	}
+	return cp
+}
+
+// finishCollectionPlan : the estimated file size and the warnings a real run would hit
+func (p *Planner) finishCollectionPlan(ctx context.Context, cp *CollectionPlan, collectionInfo *common.CollectionInfo) (*CollectionPlan, error) {
+	if format := p.fileFormat(); format != "" {
+		cp.EstimatedFileBytes = cp.Rows * estimateRowBytes(collectionInfo.Fields, cp.EnableDynamicField, format)
+	}
+	if cp.Rows == 0 {
+		cp.warn("source has no rows")
+	}
+	for _, f := range cp.Fields {
+		if f.AutoId {
+			cp.warn("pk field %s is autoId, the source pk values will not be kept in target", f.Name)
+		}
+	}
+	if collectionInfo.Param.LoadData && !collectionInfo.Param.CreateIndex {
+		cp.warn("loadData without createIndex, load will fail if the target vector fields have no index")
+	}
+	if p.targetCli == nil {
+		return cp, nil
+	}
+	return cp, p.checkTarget(ctx, cp, collectionInfo)
+}
+
+func (p *Planner) checkTarget(ctx context.Context, cp *CollectionPlan, collectionInfo *common.CollectionInfo) error {
+	exist, diffs, err := p.targetCli.DiffExistCollection(ctx, dbclient.ToSchema(collectionInfo))
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	cp.TargetExists = true
+	targetRows, err := p.targetCli.GetCollectionRowCount(ctx, cp.Collection)
+	if err != nil {
+		return err
+	}
+	cp.TargetRows = int64(targetRows)
+	onExists := p.cfg.TargetMilvus2xCfg.OnExists
+	switch common.OnExists(onExists) {
+	case common.OnExistsFail:
+		cp.warn("collection already exist in target, the run will fail by target.onExists=fail")
+		return nil
+	case common.OnExistsDrop:
+		cp.warn("collection already exist in target, will be dropped with its %d rows by target.onExists=drop", cp.TargetRows)
+		return nil
+	}
+	for _, diff := range diffs {
+		cp.warn("schema not compatible with the exist collection, the run will fail: %s", diff)
+	}
+	if len(diffs) > 0 {
+		return nil
+	}
+	if common.OnExists(onExists) == common.OnExistsTruncate {
+		cp.warn("collection already exist in target, its %d rows will be removed by target.onExists=truncate", cp.TargetRows)
+	} else if cp.TargetRows > 0 {
+		cp.warn("collection already exist in target with %d rows, source rows will be appended", cp.TargetRows)
+	}
+	return nil
+}
+
+// fileFormat : the intermediate or exported file format, empty when batch insert directly
+func (p *Planner) fileFormat() common.FileFormat {
+	if p.cfg.TargetType == string(common.T_FILES) {
+		return common.FileFormat(p.cfg.TargetFilesFormat)
+	}
+	if common.DumpMode(p.cfg.DumperWorkCfg.WorkMode) == common.Milvus2x ||
+		p.cfg.LoaderWorkCfg.InsertMode == string(common.BatchInsert) {
+		return ""
+	}
+	return common.FileFormat(p.cfg.DumperWorkCfg.FileFormat)
+}
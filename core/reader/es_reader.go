@@ -77,11 +77,16 @@ func (esr *ESReader) writeAll(writer io.Writer) (error, *PublishResponse) {
 			startParseDataTime = time.Now()
 		}
 		var b []byte
+		var err error
 		if noData {
-			b = esparser.First2JsonData(&data.Hits, esr.ESSource.IdxCfg)
+			b, err = esparser.First2JsonData(&data.Hits, esr.ESSource.IdxCfg)
 			noData = false
 		} else {
-			b = esparser.Next2JsonData(&data.Hits, esr.ESSource.IdxCfg)
+			b, err = esparser.Next2JsonData(&data.Hits, esr.ESSource.IdxCfg)
+		}
+		if err != nil {
+			log.Error("[ESReader] parse es data error", zap.String("index", esr.ESSource.IdxCfg.Index), zap.Error(err))
+			return err, nil
 		}
 		writer.Write(b)
 		fileSize += len(b)
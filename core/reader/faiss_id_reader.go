@@ -92,6 +92,20 @@ func (this *FaissIdReader) pushIdList(w io.Writer) error {
 	return nil
 }
 
+// ReadHeadInfo : only read the index header, return the vector dim and total rows
+func (this *FaissIdReader) ReadHeadInfo() (int, int, error) {
+	err := this.BeforePublish()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer this.AfterPublish()
+	err = this.readAutoIndexHeader(true)
+	if err != nil {
+		return 0, 0, err
+	}
+	return this.dataDim, this.head.Row, nil
+}
+
 func (this *FaissIdReader) SetReadSources(source ReadSource) {
 	this.setFileSource(source)
 }
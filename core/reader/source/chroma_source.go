@@ -0,0 +1,142 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/factory"
+	chromaconvert "github.com/zilliztech/milvus-migration/core/transform/chroma/convert"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage"
+	"github.com/zilliztech/milvus-migration/storage/chroma"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FetchChromaDir : local persist dir use directly, remote persist dir prefix download to a temp dir,
+// sqlite and hnsw files need random access. return the local dir and the clean func
+func FetchChromaDir(ctx context.Context, cfg *config.MigrationConfig) (string, func(), error) {
+	if common.SourceMode(cfg.SourceMode) != common.S_Remote {
+		return cfg.SourceChromaDir, func() {}, nil
+	}
+	tempDir, err := os.MkdirTemp("", "chroma-*")
+	if err != nil {
+		return "", nil, err
+	}
+	clean := func() { os.RemoveAll(tempDir) }
+	prefix := strings.TrimSuffix(cfg.SourceChromaDir, "/") + "/"
+	cli := factory.GetStorageCli(cfg.SourceRemote)
+	paginator := cli.ListObjectsPage(ctx, storage.ListObjectPageInput{Bucket: cfg.SourceRemote.BucketName, Prefix: prefix})
+	var files int
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			clean()
+			return "", nil, err
+		}
+		for _, obj := range page.Contents {
+			rel := strings.TrimPrefix(obj.Key, prefix)
+			if rel == "" || strings.HasSuffix(rel, "/") {
+				continue
+			}
+			err = downloadObject(ctx, cli, cfg.SourceRemote.BucketName, obj.Key, filepath.Join(tempDir, filepath.FromSlash(rel)))
+			if err != nil {
+				clean()
+				return "", nil, err
+			}
+			files++
+		}
+	}
+	log.LL(ctx).Info("[Chroma Source] download persist dir", zap.String("bucket", cfg.SourceRemote.BucketName),
+		zap.String("prefix", prefix), zap.Int("files", files))
+	return tempDir, clean, nil
+}
+
+func downloadObject(ctx context.Context, cli storage.Client, bucket string, key string, file string) error {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	object, err := cli.GetObject(ctx, storage.GetObjectInput{Bucket: bucket, Key: key})
+	if err != nil {
+		log.Error("[Chroma Source] get remote object error", zap.String("bucket", bucket), zap.String("key", key), zap.Error(err))
+		return err
+	}
+	defer object.Body.Close()
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, object.Body)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ChromaSource : read the collection records in the metadata segment order
+type ChromaSource struct {
+	Cli         *chroma.Client
+	CollCfg     *chromatype.CollectionCfg
+	BatchSize   int
+	DataChannel chan *milvus2x.Milvus2xData
+}
+
+func NewChromaSource(cli *chroma.Client, collCfg *chromatype.CollectionCfg, cfg *config.MigrationConfig,
+	dataChannel chan *milvus2x.Milvus2xData) *ChromaSource {
+	cs := &ChromaSource{
+		Cli:         cli,
+		CollCfg:     collCfg,
+		BatchSize:   DefaultSize,
+		DataChannel: dataChannel,
+	}
+	if cfg.DumperWorkCfg.ReaderBufferSize > 0 {
+		cs.BatchSize = cfg.DumperWorkCfg.ReaderBufferSize
+	}
+	return cs
+}
+
+// ReadAll : read all pages, return the read rows, not close the DataChannel
+func (cs *ChromaSource) ReadAll(ctx context.Context) (int64, error) {
+	reader, err := cs.Cli.NewCollectionReader(ctx, cs.CollCfg)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	var lastKey int64
+	var total int64
+	for {
+		records, nextKey, err := reader.ReadPage(ctx, lastKey, cs.BatchSize)
+		if err != nil {
+			log.Error("[Chroma Source] read page error", zap.String("collection", cs.CollCfg.Collection),
+				zap.Int64("lastKey", lastKey), zap.Error(err))
+			return total, err
+		}
+		if len(records) == 0 {
+			break
+		}
+		columns, err := chromaconvert.ToMilvusColumns(records, cs.CollCfg.InnerDim)
+		if err != nil {
+			return total, fmt.Errorf("chroma collection %s: %w", cs.CollCfg.Collection, err)
+		}
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case cs.DataChannel <- &milvus2x.Milvus2xData{Columns: columns}:
+		}
+		total += int64(len(records))
+		lastKey = nextKey
+		if len(records) < cs.BatchSize {
+			break
+		}
+	}
+	log.LL(ctx).Info("[Chroma Source] read collection finish", zap.String("collection", cs.CollCfg.Collection), zap.Int64("rows", total))
+	return total, nil
+}
@@ -1,11 +1,16 @@
 package source
 
 import (
+	"context"
 	"errors"
 	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/factory/es_factory"
 	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/internal/util/retry"
 	"github.com/zilliztech/milvus-migration/storage/es"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 var DEFAULT_BATCH_SIZE = 200
@@ -17,6 +22,10 @@ type ESSource struct {
 	ScrollId    string
 	BatchSize   int
 	DataChannel chan *es.SearchRes
+
+	pitMode bool //readMode is pit and the es server support it
+	//pit mode: key slice id, val sort values of the last written hit recorded by checkpoint, resume slice continue from it
+	SliceSearchAfter map[int]string
 }
 
 func NewESSource(idxInfo *estype.IdxCfg, dpCfg *config.MigrationConfig) *ESSource {
@@ -26,12 +35,21 @@ func NewESSource(idxInfo *estype.IdxCfg, dpCfg *config.MigrationConfig) *ESSourc
 	if dpCfg.DumperWorkCfg.ReaderBufferSize > 0 {
 		batchSize = dpCfg.DumperWorkCfg.ReaderBufferSize
 	}
+	pitMode := esCfg.ReadMode == config.ESReadModePIT
+	if pitMode && !esCli.SupportPIT() {
+		log.Warn("[ESSource] es server before 7.12 not support pit _shard_doc sort, will read by scroll",
+			zap.String("index", idxInfo.Index))
+		pitMode = false
+	}
 	esr := &ESSource{
 		Cli:         esCli,
 		Cfg:         esCfg,
 		IdxCfg:      idxInfo,
 		BatchSize:   batchSize,
 		DataChannel: make(chan *es.SearchRes, 100),
+		pitMode:     pitMode,
+
+		SliceSearchAfter: make(map[int]string),
 	}
 	return esr
 }
@@ -68,6 +86,114 @@ func (ess *ESSource) Close() error {
 	return nil
 }
 
+func (ess *ESSource) IsPITMode() bool {
+	return ess.pitMode
+}
+
+// Count : count index rows to IdxCfg.Rows, scroll mode will count in ReadFirst
+func (ess *ESSource) Count() error {
+	return ess.Cli.Cli.Count(ess.IdxCfg)
+}
+
+// ReadByScroll : read all index data by scroll and send to DataChannel, DataChannel will be closed when read finished
+func (ess *ESSource) ReadByScroll(ctx context.Context) error {
+	defer close(ess.DataChannel)
+	data, err := ess.Cli.Cli.InitScroll(ess.IdxCfg, ess.BatchSize)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ess.Cli.Cli.Close(ess.ScrollId)
+	}()
+	for {
+		ess.ScrollId = data.ScrollId
+		if data.IsEmpty {
+			log.Info("[ESSource] finished scroll search", zap.String("index", ess.IdxCfg.Index))
+			return nil
+		}
+		select {
+		case ess.DataChannel <- data:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		data, err = ess.Cli.Cli.NextScroll(ess.ScrollId)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// SliceMax : pit mode slice number
+func (ess *ESSource) SliceMax() int {
+	if ess.Cfg.Slices <= 0 {
+		return 1
+	}
+	return ess.Cfg.Slices
+}
+
+// ReadByPIT : open a point in time, every slice read by search_after in a goroutine and send to DataChannel,
+// DataChannel will be closed when all slices read finished. the slice in SliceSearchAfter continue from its sort values
+func (ess *ESSource) ReadByPIT(ctx context.Context) error {
+	defer close(ess.DataChannel)
+	pitId, err := ess.Cli.Cli.OpenPIT(ess.IdxCfg.Index)
+	if err != nil {
+		return err
+	}
+	defer ess.Cli.Cli.ClosePIT(pitId)
+
+	slices := ess.SliceMax()
+	g, subCtx := errgroup.WithContext(ctx)
+	for i := 0; i < slices; i++ {
+		sliceId := i
+		g.Go(func() error {
+			return ess.readPITSlice(subCtx, pitId, sliceId, slices)
+		})
+	}
+	return g.Wait()
+}
+
+func (ess *ESSource) readPITSlice(ctx context.Context, pitId string, sliceId int, sliceMax int) error {
+	req := &es.PITRequest{
+		PitId:       pitId,
+		BatchSize:   ess.BatchSize,
+		SliceId:     sliceId,
+		SliceMax:    sliceMax,
+		SearchAfter: ess.SliceSearchAfter[sliceId],
+	}
+	if req.SearchAfter != "" {
+		log.Info("[ESSource] resume pit search slice", zap.String("index", ess.IdxCfg.Index), zap.Int("slice", sliceId),
+			zap.String("searchAfter", req.SearchAfter))
+	}
+	for {
+		var data *es.SearchRes
+		err := retry.Do(ctx, func() error {
+			var err error
+			data, err = ess.Cli.Cli.SearchAfter(ess.IdxCfg, req)
+			return err
+		}, retry.Attempts(3))
+		if err != nil {
+			log.Error("[ESSource] pit search slice failed", zap.String("index", ess.IdxCfg.Index), zap.Int("slice", sliceId),
+				zap.String("lastSearchAfter", req.SearchAfter), zap.Error(err))
+			return err
+		}
+		if data.IsEmpty {
+			log.Info("[ESSource] finished pit search slice", zap.String("index", ess.IdxCfg.Index), zap.Int("slice", sliceId))
+			return nil
+		}
+		if data.PitId != "" {
+			req.PitId = data.PitId
+		}
+		//the failed search retry from the sort values of the last hit, checkpoint record it after the hits written
+		req.SearchAfter = data.SearchAfter
+		data.SliceId = sliceId
+		select {
+		case ess.DataChannel <- data:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // GetReader : will invoke by initFileSource() method, in ES need do nothing
 //func (ess *ESSource) GetReader() (io.Reader, error) {
 //	return nil, nil
@@ -0,0 +1,247 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	filesconvert "github.com/zilliztech/milvus-migration/core/transform/files/convert"
+	"io"
+	"os"
+	"strings"
+)
+
+// rowReader : read at most n rows, return io.EOF with the last rows when the file end
+type rowReader interface {
+	Read(n int) ([]filesconvert.Row, error)
+	Close() error
+}
+
+// jsonlRowReader : one json object each line, numbers keep as json.Number
+type jsonlRowReader struct {
+	dec *json.Decoder
+}
+
+func newJSONLRowReader(r io.Reader) *jsonlRowReader {
+	dec := json.NewDecoder(bufio.NewReaderSize(r, 1<<20))
+	dec.UseNumber()
+	return &jsonlRowReader{dec: dec}
+}
+
+func (jr *jsonlRowReader) Read(n int) ([]filesconvert.Row, error) {
+	rows := make([]filesconvert.Row, 0, n)
+	for len(rows) < n {
+		var row filesconvert.Row
+		err := jr.dec.Decode(&row)
+		if err == io.EOF {
+			return rows, io.EOF
+		}
+		if err != nil {
+			return rows, fmt.Errorf("invalid jsonl row: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (jr *jsonlRowReader) Close() error {
+	return nil
+}
+
+// csvRowReader : the first line is the header of column names, all values are string
+type csvRowReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	cr := csv.NewReader(bufio.NewReaderSize(r, 1<<20))
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	//excel utf-8 csv file start with the BOM
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	return &csvRowReader{r: cr, header: header}, nil
+}
+
+func (cr *csvRowReader) Read(n int) ([]filesconvert.Row, error) {
+	rows := make([]filesconvert.Row, 0, n)
+	for len(rows) < n {
+		record, err := cr.r.Read()
+		if err == io.EOF {
+			return rows, io.EOF
+		}
+		if err != nil {
+			return rows, err
+		}
+		row := make(filesconvert.Row, len(cr.header))
+		for i, name := range cr.header {
+			row[name] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (cr *csvRowReader) Close() error {
+	return nil
+}
+
+// parquetRowReader : read the parquet by record batch, the batch size is the rows of each Read
+type parquetRowReader struct {
+	pf   *file.Reader
+	rr   pqarrow.RecordReader
+	temp *os.File
+}
+
+func newParquetRowReader(ctx context.Context, r io.Reader, batchSize int) (*parquetRowReader, error) {
+	pr := &parquetRowReader{}
+	//parquet reader need random access, remote object download to a temp file
+	ras, ok := r.(parquet.ReaderAtSeeker)
+	if !ok {
+		f, err := os.CreateTemp("", "files-*.parquet")
+		if err != nil {
+			return nil, err
+		}
+		//the temp file removed after open, released when the file closed
+		os.Remove(f.Name())
+		pr.temp = f
+		_, err = io.Copy(f, r)
+		if err != nil {
+			pr.Close()
+			return nil, err
+		}
+		ras = f
+	}
+	var err error
+	pr.pf, err = file.NewParquetReader(ras)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	fr, err := pqarrow.NewFileReader(pr.pf, pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}, memory.DefaultAllocator)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	pr.rr, err = fr.GetRecordReader(ctx, nil, nil)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (pr *parquetRowReader) Read(n int) ([]filesconvert.Row, error) {
+	if !pr.rr.Next() {
+		if pr.rr.Err() != nil && pr.rr.Err() != io.EOF {
+			return nil, pr.rr.Err()
+		}
+		return nil, io.EOF
+	}
+	rec := pr.rr.Record()
+	rows := make([]filesconvert.Row, rec.NumRows())
+	for i := range rows {
+		rows[i] = make(filesconvert.Row, rec.NumCols())
+	}
+	for c, col := range rec.Columns() {
+		name := rec.ColumnName(c)
+		for i := range rows {
+			v, err := arrowValue(col, i)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+			rows[i][name] = v
+		}
+	}
+	return rows, nil
+}
+
+func (pr *parquetRowReader) Close() error {
+	if pr.rr != nil {
+		pr.rr.Release()
+	}
+	if pr.pf != nil {
+		pr.pf.Close()
+	}
+	if pr.temp != nil {
+		return pr.temp.Close()
+	}
+	return nil
+}
+
+// arrowValue : the go value of the row, int and float widen to int64 and float64, float list keep the element type
+func arrowValue(arr arrow.Array, i int) (any, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+	switch typed := arr.(type) {
+	case *array.Boolean:
+		return typed.Value(i), nil
+	case *array.Int8:
+		return int64(typed.Value(i)), nil
+	case *array.Int16:
+		return int64(typed.Value(i)), nil
+	case *array.Int32:
+		return int64(typed.Value(i)), nil
+	case *array.Int64:
+		return typed.Value(i), nil
+	case *array.Uint8:
+		return int64(typed.Value(i)), nil
+	case *array.Uint16:
+		return int64(typed.Value(i)), nil
+	case *array.Uint32:
+		return int64(typed.Value(i)), nil
+	case *array.Float32:
+		return float64(typed.Value(i)), nil
+	case *array.Float64:
+		return typed.Value(i), nil
+	case *array.String:
+		return typed.Value(i), nil
+	case *array.LargeString:
+		return typed.Value(i), nil
+	case *array.Binary:
+		return append([]byte{}, typed.Value(i)...), nil
+	case *array.LargeBinary:
+		return append([]byte{}, typed.Value(i)...), nil
+	case *array.FixedSizeBinary:
+		return append([]byte{}, typed.Value(i)...), nil
+	case *array.List:
+		start, end := typed.ValueOffsets(i)
+		return arrowListValue(typed.ListValues(), int(start), int(end))
+	case *array.LargeList:
+		start, end := typed.ValueOffsets(i)
+		return arrowListValue(typed.ListValues(), int(start), int(end))
+	case *array.FixedSizeList:
+		size := int(typed.DataType().(*arrow.FixedSizeListType).Len())
+		start := (typed.Offset() + i) * size
+		return arrowListValue(typed.ListValues(), start, start+size)
+	}
+	return nil, fmt.Errorf("parquet type %s not support", arr.DataType())
+}
+
+func arrowListValue(values arrow.Array, start int, end int) (any, error) {
+	switch typed := values.(type) {
+	case *array.Float32:
+		return append([]float32{}, typed.Float32Values()[start:end]...), nil
+	case *array.Float64:
+		return append([]float64{}, typed.Float64Values()[start:end]...), nil
+	}
+	list := make([]any, 0, end-start)
+	for i := start; i < end; i++ {
+		v, err := arrowValue(values, i)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
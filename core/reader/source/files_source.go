@@ -0,0 +1,216 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/factory"
+	filesconvert "github.com/zilliztech/milvus-migration/core/transform/files/convert"
+	"github.com/zilliztech/milvus-migration/core/type/filestype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileReadSource : the same as reader.ReadSource, implemented by LocalFileSource and RemoteSource
+type fileReadSource interface {
+	GetReader() (io.Reader, error)
+	Close() error
+}
+
+// ListDataFiles : the data files of the collection relative to the source dir, a path end with / or a local dir
+// means all data files in it recursively, empty files means the source dir
+func ListDataFiles(ctx context.Context, cfg *config.MigrationConfig, collCfg *filestype.CollectionCfg) ([]string, error) {
+	entries := collCfg.Files
+	if len(entries) == 0 {
+		entries = []string{""}
+	}
+	var files []string
+	for _, entry := range entries {
+		var dirFiles []string
+		var err error
+		if common.SourceMode(cfg.SourceMode) == common.S_Remote {
+			key := strings.TrimPrefix(path.Join(cfg.SourceFilesDir, entry), "/")
+			if entry != "" && !strings.HasSuffix(entry, "/") {
+				files = append(files, key)
+				continue
+			}
+			dirFiles, err = listRemoteDataFiles(ctx, cfg, collCfg, key)
+		} else {
+			file := entry
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(cfg.SourceFilesDir, file)
+			}
+			var stat os.FileInfo
+			stat, err = os.Stat(file)
+			if err != nil {
+				return nil, err
+			}
+			if !stat.IsDir() {
+				files = append(files, file)
+				continue
+			}
+			dirFiles, err = listLocalDataFiles(collCfg, file)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(dirFiles) == 0 {
+			return nil, fmt.Errorf("files collection %s not found data files in %s", collCfg.Collection, entry)
+		}
+		files = append(files, dirFiles...)
+	}
+	log.LL(ctx).Info("[Files Source] list data files", zap.String("collection", collCfg.Collection), zap.Strings("files", files))
+	return files, nil
+}
+
+func listLocalDataFiles(collCfg *filestype.CollectionCfg, dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, ok := filesconvert.FileFormat(collCfg, file); ok && !d.IsDir() {
+			files = append(files, file)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func listRemoteDataFiles(ctx context.Context, cfg *config.MigrationConfig, collCfg *filestype.CollectionCfg, dir string) ([]string, error) {
+	prefix := strings.TrimSuffix(dir, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	cli := factory.GetStorageCli(cfg.SourceRemote)
+	paginator := cli.ListObjectsPage(ctx, storage.ListObjectPageInput{Bucket: cfg.SourceRemote.BucketName, Prefix: prefix})
+	var files []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			if _, ok := filesconvert.FileFormat(collCfg, obj.Key); ok {
+				files = append(files, obj.Key)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// FilesSource : read the data files of a collection in order
+type FilesSource struct {
+	cfg         *config.MigrationConfig
+	CollCfg     *filestype.CollectionCfg
+	Files       []string
+	fields      []*entity.Field
+	dynamic     bool
+	BatchSize   int
+	DataChannel chan *milvus2x.Milvus2xData
+}
+
+func NewFilesSource(collCfg *filestype.CollectionCfg, files []string, cfg *config.MigrationConfig,
+	dataChannel chan *milvus2x.Milvus2xData) (*FilesSource, error) {
+	fields, err := filesconvert.ToMilvusFields(collCfg)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := DefaultSize
+	if cfg.DumperWorkCfg.ReaderBufferSize > 0 {
+		batchSize = cfg.DumperWorkCfg.ReaderBufferSize
+	}
+	return &FilesSource{
+		cfg:         cfg,
+		CollCfg:     collCfg,
+		Files:       files,
+		fields:      fields,
+		dynamic:     !collCfg.MilvusCfg.CloseDynamicField,
+		BatchSize:   batchSize,
+		DataChannel: dataChannel,
+	}, nil
+}
+
+// ReadAll : read all files in order, return the read rows, not close the DataChannel
+func (src *FilesSource) ReadAll(ctx context.Context) (int64, error) {
+	var total int64
+	for _, file := range src.Files {
+		rows, err := src.readFile(ctx, file)
+		total += rows
+		if err != nil {
+			log.Error("[Files Source] read file error", zap.String("collection", src.CollCfg.Collection),
+				zap.String("file", file), zap.Int64("rows", rows), zap.Error(err))
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (src *FilesSource) readFile(ctx context.Context, file string) (int64, error) {
+	format, _ := filesconvert.FileFormat(src.CollCfg, file)
+	var rs fileReadSource
+	if common.SourceMode(src.cfg.SourceMode) == common.S_Remote {
+		rs = NewRemoteSource(&common.FileParam{BucketName: src.cfg.SourceRemote.BucketName, FileFullName: file}, src.cfg.SourceRemote)
+	} else {
+		rs = NewLocalFileSource(&common.FileParam{FileFullName: file})
+	}
+	r, err := rs.GetReader()
+	if err != nil {
+		return 0, err
+	}
+	defer rs.Close()
+	var rr rowReader
+	switch format {
+	case common.FormatJSONL:
+		rr = newJSONLRowReader(r)
+	case common.FormatCSV:
+		rr, err = newCSVRowReader(r)
+	case common.FormatParquet:
+		rr, err = newParquetRowReader(ctx, r, src.BatchSize)
+	default:
+		err = fmt.Errorf("file %s format unknown, config the meta format", file)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer rr.Close()
+
+	var rows int64
+	for {
+		batch, err := rr.Read(src.BatchSize)
+		if len(batch) > 0 {
+			columns, convErr := filesconvert.ToMilvusColumns(batch, src.fields, src.dynamic)
+			if convErr != nil {
+				return rows, fmt.Errorf("file %s after row %d: %w", file, rows, convErr)
+			}
+			select {
+			case <-ctx.Done():
+				return rows, ctx.Err()
+			case src.DataChannel <- &milvus2x.Milvus2xData{Columns: columns}:
+			}
+			rows += int64(len(batch))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, fmt.Errorf("file %s after row %d: %w", file, rows, err)
+		}
+	}
+	log.LL(ctx).Info("[Files Source] read file finish", zap.String("collection", src.CollCfg.Collection),
+		zap.String("file", file), zap.Int64("rows", rows))
+	return rows, nil
+}
@@ -0,0 +1,113 @@
+package source
+
+import (
+	"context"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/filestype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// base64 of the little endian float32 [1, 2]
+const vectorBase64 = "AACAPwAAAEA="
+
+func writeParquet(t *testing.T, file string) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "vec", Type: arrow.ListOf(arrow.PrimitiveTypes.Float32)},
+		{Name: "title", Type: arrow.BinaryTypes.String},
+	}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).Append(5)
+	lb := b.Field(1).(*array.ListBuilder)
+	lb.Append(true)
+	lb.ValueBuilder().(*array.Float32Builder).AppendValues([]float32{5, 6}, nil)
+	b.Field(2).(*array.StringBuilder).Append("e")
+	rec := b.NewRecord()
+	defer rec.Release()
+	f, err := os.Create(file)
+	assert.NoError(t, err)
+	w, err := pqarrow.NewFileWriter(schema, f, nil, pqarrow.DefaultWriterProps())
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(rec))
+	assert.NoError(t, w.Close())
+}
+
+func TestFilesSource(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(
+		`{"id":1,"vec":[0.5,1],"title":"a","extra":true}`+"\n"+`{"id":2,"vec":"`+vectorBase64+`","title":"b"}`+"\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.csv"), []byte(
+		"\ufeffid,vec,title\n3,\"[3,4]\",c\n4,"+vectorBase64+",d\n"), 0644))
+	writeParquet(t, filepath.Join(dir, "c.parquet"))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("not data"), 0644))
+
+	ctx := context.Background()
+	cfg := &config.MigrationConfig{SourceMode: "local", SourceFilesDir: dir,
+		DumperWorkCfg: &config.DumperWorkConfig{ReaderBufferSize: 2}}
+	collCfg := &filestype.CollectionCfg{
+		Collection: "items",
+		Fields: []filestype.FieldCfg{
+			{Name: "id", Type: "Int64", PK: true},
+			{Name: "vec", Type: "FloatVector", Dims: 2},
+			{Name: "title", Type: "VarChar", MaxLen: 16},
+		},
+		MilvusCfg: &milvustype.MilvusCfg{},
+	}
+	files, err := ListDataFiles(ctx, cfg, collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.jsonl"), filepath.Join(dir, "b.csv"), filepath.Join(dir, "c.parquet")}, files)
+
+	dataChannel := make(chan *milvus2x.Milvus2xData, 10)
+	fs, err := NewFilesSource(collCfg, files, cfg, dataChannel)
+	assert.NoError(t, err)
+	rows, err := fs.ReadAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), rows)
+	close(dataChannel)
+
+	var ids []int64
+	var vectors [][]float32
+	var titles []string
+	var dynamics []string
+	for data := range dataChannel {
+		assert.Len(t, data.Columns, 4)
+		ids = append(ids, data.Columns[0].(*entity.ColumnInt64).Data()...)
+		vectors = append(vectors, data.Columns[1].(*entity.ColumnFloatVector).Data()...)
+		titles = append(titles, data.Columns[2].(*entity.ColumnVarChar).Data()...)
+		for _, v := range data.Columns[3].(*entity.ColumnJSONBytes).Data() {
+			dynamics = append(dynamics, string(v))
+		}
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, [][]float32{{0.5, 1}, {1, 2}, {3, 4}, {1, 2}, {5, 6}}, vectors)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, titles)
+	assert.Equal(t, `{"extra":true}`, dynamics[0])
+	assert.Equal(t, "{}", dynamics[2])
+}
+
+func TestFilesSourceInvalidRow(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.jsonl")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"id":1,"vec":[1,2,3]}`), 0644))
+	cfg := &config.MigrationConfig{SourceMode: "local", DumperWorkCfg: &config.DumperWorkConfig{}}
+	collCfg := &filestype.CollectionCfg{
+		Collection: "items",
+		Fields:     []filestype.FieldCfg{{Name: "id", Type: "int64", PK: true}, {Name: "vec", Type: "floatvector", Dims: 2}},
+		MilvusCfg:  &milvustype.MilvusCfg{CloseDynamicField: true},
+	}
+	fs, err := NewFilesSource(collCfg, []string{file}, cfg, make(chan *milvus2x.Milvus2xData, 1))
+	assert.NoError(t, err)
+	_, err = fs.ReadAll(context.Background())
+	assert.ErrorContains(t, err, "vector dim 3 not match 2")
+}
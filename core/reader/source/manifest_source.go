@@ -0,0 +1,234 @@
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/factory"
+	manifestconvert "github.com/zilliztech/milvus-migration/core/transform/manifest/convert"
+	"github.com/zilliztech/milvus-migration/core/transform/parquet"
+	"github.com/zilliztech/milvus-migration/core/type/manifesttype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// LoadManifest : read the manifest.json of the files target output dir
+func LoadManifest(ctx context.Context, cfg *config.MigrationConfig) (*manifesttype.Manifest, error) {
+	rd, err := openManifestObject(ctx, cfg, cfg.SourceManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	manifest := &manifesttype.Manifest{}
+	err = json.NewDecoder(rd).Decode(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest file %s: %w", cfg.SourceManifestFile, err)
+	}
+	switch common.FileFormat(manifest.Format) {
+	case common.FormatParquet, common.FormatJSONL:
+	default:
+		return nil, fmt.Errorf("manifest file %s not support format %s", cfg.SourceManifestFile, manifest.Format)
+	}
+	log.LL(ctx).Info("[Manifest Source] load manifest", zap.String("file", cfg.SourceManifestFile),
+		zap.String("sourceType", manifest.SourceType), zap.Int("collections", len(manifest.Collections)))
+	return manifest, nil
+}
+
+// ManifestSource : read the data files of one manifest collection, the file size and sha256 are verified before read
+type ManifestSource struct {
+	cfg         *config.MigrationConfig
+	format      string
+	CollCfg     *manifesttype.CollectionCfg
+	fields      []*entity.Field
+	BatchSize   int
+	DataChannel chan *milvus2x.Milvus2xData
+}
+
+func NewManifestSource(manifest *manifesttype.Manifest, collCfg *manifesttype.CollectionCfg, cfg *config.MigrationConfig,
+	dataChannel chan *milvus2x.Milvus2xData) (*ManifestSource, error) {
+	fields, err := manifestconvert.ToMilvusFields(collCfg.Fields)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := DefaultSize
+	if cfg.DumperWorkCfg.ReaderBufferSize > 0 {
+		batchSize = cfg.DumperWorkCfg.ReaderBufferSize
+	}
+	return &ManifestSource{
+		cfg:         cfg,
+		format:      manifest.Format,
+		CollCfg:     collCfg,
+		fields:      manifestconvert.DataFields(fields),
+		BatchSize:   batchSize,
+		DataChannel: dataChannel,
+	}, nil
+}
+
+// ReadAll : read all partition files in order, return the read rows, not close the DataChannel
+func (ms *ManifestSource) ReadAll(ctx context.Context) (int64, error) {
+	var total int64
+	for _, partCfg := range ms.CollCfg.Partitions {
+		//partition key collection not have physical partition, insert without partition name
+		partition := partCfg.Name
+		if ms.CollCfg.PartitionKey != "" {
+			partition = common.EMPTY
+		}
+		for _, fileCfg := range partCfg.Files {
+			rows, err := ms.readFile(ctx, partition, fileCfg)
+			total += rows
+			if err != nil {
+				log.Error("[Manifest Source] read file error", zap.String("collection", ms.CollCfg.Collection),
+					zap.String("file", fileCfg.Path), zap.Error(err))
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+func (ms *ManifestSource) readFile(ctx context.Context, partition string, fileCfg *manifesttype.FileCfg) (int64, error) {
+	var rows int64
+	f, err := ms.fetchFile(ctx, fileCfg)
+	if err != nil {
+		return rows, err
+	}
+	defer f.Close()
+	var reader array.RecordReader
+	switch common.FileFormat(ms.format) {
+	case common.FormatParquet:
+		pf, err := file.NewParquetReader(f)
+		if err != nil {
+			return rows, err
+		}
+		defer pf.Close()
+		fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: int64(ms.BatchSize)}, memory.DefaultAllocator)
+		if err != nil {
+			return rows, err
+		}
+		rr, err := fr.GetRecordReader(ctx, nil, nil)
+		if err != nil {
+			return rows, err
+		}
+		reader = rr
+	default:
+		schema, err := pqconvert.ToArrowSchema(ms.fields, ms.CollCfg.DynamicField)
+		if err != nil {
+			return rows, err
+		}
+		reader = array.NewJSONReader(f, schema, array.WithChunk(ms.BatchSize))
+	}
+	defer reader.Release()
+
+	for reader.Next() {
+		columns, err := pqconvert.ToMilvusColumns(reader.Record(), ms.fields, ms.CollCfg.DynamicField)
+		if err != nil {
+			return rows, fmt.Errorf("file %s: %w", fileCfg.Path, err)
+		}
+		rows += int64(columns[0].Len())
+		select {
+		case <-ctx.Done():
+			return rows, ctx.Err()
+		case ms.DataChannel <- &milvus2x.Milvus2xData{Columns: columns, Partition: partition}:
+		}
+	}
+	if reader.Err() != nil && reader.Err() != io.EOF {
+		return rows, reader.Err()
+	}
+	if rows != fileCfg.Rows {
+		return rows, fmt.Errorf("file %s rows %d not match manifest rows %d", fileCfg.Path, rows, fileCfg.Rows)
+	}
+	log.LL(ctx).Info("[Manifest Source] read file finish", zap.String("collection", ms.CollCfg.Collection),
+		zap.String("file", fileCfg.Path), zap.Int64("rows", rows))
+	return rows, nil
+}
+
+// fetchFile : local file open directly, remote file download to a temp file, parquet reader need random access
+func (ms *ManifestSource) fetchFile(ctx context.Context, fileCfg *manifesttype.FileCfg) (*os.File, error) {
+	//the data file path in manifest is relative to the manifest.json dir
+	var f *os.File
+	var err error
+	if common.SourceMode(ms.cfg.SourceMode) == common.S_Remote {
+		f, err = downloadTempFile(ctx, ms.cfg, path.Join(path.Dir(ms.cfg.SourceManifestFile), fileCfg.Path))
+	} else {
+		f, err = os.Open(filepath.Join(filepath.Dir(ms.cfg.SourceManifestFile), filepath.FromSlash(fileCfg.Path)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = verifyFile(f, fileCfg)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func downloadTempFile(ctx context.Context, cfg *config.MigrationConfig, key string) (*os.File, error) {
+	rd, err := openManifestObject(ctx, cfg, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	f, err := os.CreateTemp("", "manifest-*")
+	if err != nil {
+		return nil, err
+	}
+	//the temp file removed after open, released when the file closed
+	os.Remove(f.Name())
+	_, err = io.Copy(f, rd)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// verifyFile : check the file size and sha256 with manifest, seek to the file begin after check
+func verifyFile(f *os.File, fileCfg *manifesttype.FileCfg) error {
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	if size != fileCfg.Size {
+		return fmt.Errorf("file %s size %d not match manifest size %d", fileCfg.Path, size, fileCfg.Size)
+	}
+	if fileCfg.SHA256 != "" && hex.EncodeToString(h.Sum(nil)) != fileCfg.SHA256 {
+		return fmt.Errorf("file %s sha256 not match manifest", fileCfg.Path)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
+
+func openManifestObject(ctx context.Context, cfg *config.MigrationConfig, name string) (io.ReadCloser, error) {
+	if common.SourceMode(cfg.SourceMode) != common.S_Remote {
+		return os.Open(name)
+	}
+	cli := factory.GetStorageCli(cfg.SourceRemote)
+	object, err := cli.GetObject(ctx, storage.GetObjectInput{Bucket: cfg.SourceRemote.BucketName, Key: name})
+	if err != nil {
+		log.Error("[Manifest Source] get remote object error", zap.String("bucket", cfg.SourceRemote.BucketName),
+			zap.String("key", name), zap.Error(err))
+		return nil, err
+	}
+	return object.Body, nil
+}
@@ -2,12 +2,13 @@ package source
 
 import (
 	"context"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/zilliztech/milvus-migration/core/config"
-	"github.com/zilliztech/milvus-migration/core/factory/milvus2x_factory"
 	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"github.com/zilliztech/milvus-migration/storage/milvus2x"
 	"go.uber.org/zap"
+	"slices"
 )
 
 var DefaultSize = 100
@@ -20,11 +21,18 @@ type Milvus2xSource struct {
 	BatchSize     int
 	DataChannel   chan *milvus2x.Milvus2xData
 	CurrPartition string
+	CurrExpr      string
 	FieldNames    []string
+	PKRanges      []string //pk range parallel read: all ranges of the partition
+	PKRange       string   //pk range parallel read: the range of this source read
 }
 
-func NewMilvus2xSource(collCfg *milvus2xtype.CollectionCfg, dpCfg *config.MigrationConfig, dataChannel chan *milvus2x.Milvus2xData) *Milvus2xSource {
-	mlv2xCli := milvus2x_factory.GetMilvus2xCli(dpCfg.SourceMilvus2xConfig)
+func NewMilvus2xSource(collCfg *milvus2xtype.CollectionCfg, dpCfg *config.MigrationConfig, dataChannel chan *milvus2x.Milvus2xData) (*Milvus2xSource, error) {
+	//每个source单独创建client, iterator状态保存在client中, 多collection并发迁移时不能共用
+	mlv2xCli, err := milvus2x.CreateMilvus2xClient(dpCfg.SourceMilvus2xConfig)
+	if err != nil {
+		return nil, err
+	}
 	batchSize := DefaultSize
 	if dpCfg.DumperWorkCfg.ReaderBufferSize > 0 {
 		batchSize = dpCfg.DumperWorkCfg.ReaderBufferSize
@@ -36,12 +44,12 @@ func NewMilvus2xSource(collCfg *milvus2xtype.CollectionCfg, dpCfg *config.Migrat
 		BatchSize:   batchSize,
 		DataChannel: dataChannel,
 	}
-	return mlv2xSource
+	return mlv2xSource, nil
 }
 
 func (milvus2xSource *Milvus2xSource) ReadFirst(ctx context.Context) (*milvus2x.Milvus2xData, error) {
 	err := milvus2xSource.Cli.VerCli.InitIterator(ctx, milvus2xSource.CollCfg,
-		milvus2xSource.BatchSize, milvus2xSource.CurrPartition, milvus2xSource.FieldNames)
+		milvus2xSource.BatchSize, milvus2xSource.CurrPartition, milvus2xSource.CurrExpr, milvus2xSource.FieldNames)
 	if err != nil {
 		return nil, err
 	}
@@ -56,12 +64,39 @@ func (milvus2xSource *Milvus2xSource) ReadFirst(ctx context.Context) (*milvus2x.
 	log.Info("milvus2x dumpMilvusData", zap.Any("columnCount", len(data.Columns)),
 		zap.Any("Partition", milvus2xSource.CurrPartition))
 
+	milvus2xSource.setLastPK(data)
 	milvus2xSource.removePKColIfOpenAutoId(data)
-	data.Partition = milvus2xSource.CurrPartition
-	milvus2xSource.DataChannel <- data
+	milvus2xSource.setPosition(data)
+	err = milvus2xSource.sendData(ctx, data)
+	if err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
+// setPosition : the partition and pk range of the data, checkpoint record the last pk in them
+func (milvus2xSource *Milvus2xSource) setPosition(data *milvus2x.Milvus2xData) {
+	data.Partition = milvus2xSource.CurrPartition
+	data.PKRanges = milvus2xSource.PKRanges
+	data.PKRange = milvus2xSource.PKRange
+}
+
+// setLastPK : record the max pk before remove pk column, milvus 2.2 window page rows not order by pk
+func (milvus2xSource *Milvus2xSource) setLastPK(data *milvus2x.Milvus2xData) {
+	for _, dataColumn := range data.Columns {
+		if dataColumn.Name() != milvus2xSource.CollCfg.MilvusCfg.PkName || dataColumn.Len() == 0 {
+			continue
+		}
+		switch pkCol := dataColumn.(type) {
+		case *entity.ColumnInt64:
+			data.LastPK = slices.Max(pkCol.Data())
+		case *entity.ColumnVarChar:
+			data.LastPK = slices.Max(pkCol.Data())
+		}
+		return
+	}
+}
+
 func (milvus2xSource *Milvus2xSource) removePKColIfOpenAutoId(data *milvus2x.Milvus2xData) {
 	if milvus2xSource.CollCfg.MilvusCfg.AutoId == "true" {
 		for idx, dataColumn := range data.Columns {
@@ -79,13 +114,27 @@ func (milvus2xSource *Milvus2xSource) ReadNext(ctx context.Context) (*milvus2x.M
 		return nil, err
 	}
 	if !data.IsEmpty {
+		milvus2xSource.setLastPK(data)
 		milvus2xSource.removePKColIfOpenAutoId(data)
-		data.Partition = milvus2xSource.CurrPartition
-		milvus2xSource.DataChannel <- data
+		milvus2xSource.setPosition(data)
+		err = milvus2xSource.sendData(ctx, data)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return data, nil
 }
 
+// sendData : loader may stop by error, avoid block forever when channel is full
+func (milvus2xSource *Milvus2xSource) sendData(ctx context.Context, data *milvus2x.Milvus2xData) error {
+	select {
+	case milvus2xSource.DataChannel <- data:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (milvus2xSource *Milvus2xSource) Close() error {
 	cli := milvus2xSource.Cli
 	if cli != nil {
@@ -0,0 +1,92 @@
+package source
+
+import (
+	"context"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/config"
+	pgvectorconvert "github.com/zilliztech/milvus-migration/core/transform/pgvector/convert"
+	"github.com/zilliztech/milvus-migration/core/type/pgvectortype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/internal/util/retry"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"github.com/zilliztech/milvus-migration/storage/pgvector"
+	"go.uber.org/zap"
+)
+
+// PgvectorSource : read the table rows by keyset pagination on the pk column
+type PgvectorSource struct {
+	Cli         *pgvector.Client
+	TableCfg    *pgvectortype.TableCfg
+	BatchSize   int
+	DataChannel chan *milvus2x.Milvus2xData
+
+	//the select columns and their milvus fields, autoId pk column not in it
+	columns []*pgvectortype.Column
+	fields  []*entity.Field
+}
+
+func NewPgvectorSource(cli *pgvector.Client, tableCfg *pgvectortype.TableCfg, cfg *config.MigrationConfig,
+	dataChannel chan *milvus2x.Milvus2xData) (*PgvectorSource, error) {
+	fields, err := pgvectorconvert.ToMilvusFields(tableCfg)
+	if err != nil {
+		return nil, err
+	}
+	ps := &PgvectorSource{
+		Cli:         cli,
+		TableCfg:    tableCfg,
+		BatchSize:   DefaultSize,
+		DataChannel: dataChannel,
+	}
+	if cfg.DumperWorkCfg.ReaderBufferSize > 0 {
+		ps.BatchSize = cfg.DumperWorkCfg.ReaderBufferSize
+	}
+	for i, field := range fields {
+		if field.AutoID {
+			continue
+		}
+		ps.columns = append(ps.columns, tableCfg.InnerColumns[i])
+		ps.fields = append(ps.fields, field)
+	}
+	return ps, nil
+}
+
+// ReadAll : read all pages in pk order, return the read rows, not close the DataChannel
+func (ps *PgvectorSource) ReadAll(ctx context.Context) (int64, error) {
+	var lastPK any
+	var total int64
+	for {
+		var rows [][]any
+		var nextPK any
+		err := retry.Do(ctx, func() error {
+			var err error
+			rows, nextPK, err = ps.Cli.ReadPage(ctx, ps.TableCfg, ps.columns, lastPK, ps.BatchSize)
+			return err
+		}, retry.Attempts(3))
+		if err != nil {
+			log.Error("[Pgvector Source] read page error", zap.String("table", ps.TableCfg.Table),
+				zap.Any("lastPK", lastPK), zap.Error(err))
+			return total, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		columns, err := pgvectorconvert.ToMilvusColumns(rows, ps.fields)
+		if err != nil {
+			log.Error("[Pgvector Source] convert rows error", zap.String("table", ps.TableCfg.Table),
+				zap.Any("lastPK", lastPK), zap.Error(err))
+			return total, err
+		}
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case ps.DataChannel <- &milvus2x.Milvus2xData{Columns: columns}:
+		}
+		total += int64(len(rows))
+		lastPK = nextPK
+		if len(rows) < ps.BatchSize {
+			break
+		}
+	}
+	log.LL(ctx).Info("[Pgvector Source] read table finish", zap.String("table", ps.TableCfg.Table), zap.Int64("rows", total))
+	return total, nil
+}
@@ -0,0 +1,91 @@
+package source
+
+import (
+	"context"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/config"
+	qdrantconvert "github.com/zilliztech/milvus-migration/core/transform/qdrant/convert"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/internal/util/retry"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"github.com/zilliztech/milvus-migration/storage/qdrant"
+	"go.uber.org/zap"
+)
+
+// QdrantSource : read the collection points by the scroll api
+type QdrantSource struct {
+	Cli         *qdrant.Client
+	CollCfg     *qdranttype.CollectionCfg
+	BatchSize   int
+	DataChannel chan *milvus2x.Milvus2xData
+
+	//milvus fields of the point data, autoId pk field not in it
+	fields  []*entity.Field
+	dynamic bool
+}
+
+func NewQdrantSource(cli *qdrant.Client, collCfg *qdranttype.CollectionCfg, cfg *config.MigrationConfig,
+	dataChannel chan *milvus2x.Milvus2xData) (*QdrantSource, error) {
+	fields, err := qdrantconvert.ToMilvusFields(collCfg)
+	if err != nil {
+		return nil, err
+	}
+	qs := &QdrantSource{
+		Cli:         cli,
+		CollCfg:     collCfg,
+		BatchSize:   DefaultSize,
+		DataChannel: dataChannel,
+		dynamic:     !collCfg.MilvusCfg.CloseDynamicField,
+	}
+	if cfg.DumperWorkCfg.ReaderBufferSize > 0 {
+		qs.BatchSize = cfg.DumperWorkCfg.ReaderBufferSize
+	}
+	for _, field := range fields {
+		if !field.AutoID {
+			qs.fields = append(qs.fields, field)
+		}
+	}
+	return qs, nil
+}
+
+// ReadAll : scroll all pages by the next page offset, return the read rows, not close the DataChannel
+func (qs *QdrantSource) ReadAll(ctx context.Context) (int64, error) {
+	var offset string
+	var total int64
+	for {
+		var points []gjson.Result
+		var next string
+		err := retry.Do(ctx, func() error {
+			var err error
+			points, next, err = qs.Cli.Scroll(ctx, qs.CollCfg, offset, qs.BatchSize)
+			return err
+		}, retry.Attempts(3))
+		if err != nil {
+			log.Error("[Qdrant Source] scroll points error", zap.String("collection", qs.CollCfg.Collection),
+				zap.String("offset", offset), zap.Error(err))
+			return total, err
+		}
+		if len(points) > 0 {
+			columns, err := qdrantconvert.ToMilvusColumns(points, qs.fields, qs.CollCfg, qs.dynamic)
+			if err != nil {
+				log.Error("[Qdrant Source] convert points error", zap.String("collection", qs.CollCfg.Collection),
+					zap.String("offset", offset), zap.Error(err))
+				return total, err
+			}
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case qs.DataChannel <- &milvus2x.Milvus2xData{Columns: columns}:
+			}
+			total += int64(len(points))
+		}
+		if next == "" {
+			break
+		}
+		offset = next
+	}
+	log.LL(ctx).Info("[Qdrant Source] read collection finish", zap.String("collection", qs.CollCfg.Collection), zap.Int64("rows", total))
+	return total, nil
+}
@@ -0,0 +1,26 @@
+package task
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/loader"
+)
+
+// CollectionInitTasker : create the target collection of a batch insert source by its converted collection info
+type CollectionInitTasker struct {
+	CollectionInfo *common.CollectionInfo
+}
+
+func NewCollectionInitTasker(collectionInfo *common.CollectionInfo) *CollectionInitTasker {
+	return &CollectionInitTasker{
+		CollectionInfo: collectionInfo,
+	}
+}
+
+func (initer CollectionInitTasker) Init(ctx context.Context, loader *loader.CustomMilvus2xLoader) error {
+	err := loader.InitCollectionInfo(initer.CollectionInfo)
+	if err != nil {
+		return err
+	}
+	return loader.Before(ctx)
+}
@@ -52,6 +52,7 @@ func (tasker BaseLoadTasker) CommitData(fileInfo *FileInfo) {
 
 func (tasker BaseLoadTasker) CommitCheck(task *FileInfo, taskId int64) {
 	task.taskId = taskId
+	gstore.SetFileTaskId(tasker.JobId, task.cn, task.fn, taskId)
 	tasker.CheckChannel <- task
 }
 
@@ -76,7 +77,10 @@ func (tasker BaseLoadTasker) Check(ctx context.Context) error {
 		log.Info("[LoadTasker] Progress Task --------------->",
 			zap.String("fileName", task.fn), zap.Int64("taskId", task.taskId))
 	}
-	tasker.CusFieldLoader.After(ctx)
+	err := tasker.CusFieldLoader.After(ctx)
+	if err != nil {
+		return err
+	}
 	gstore.GetProcessHandler(tasker.JobId).SetLoadFinished()
 	return nil
 }
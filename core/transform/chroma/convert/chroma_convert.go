@@ -0,0 +1,128 @@
+package chromaconvert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	convert "github.com/zilliztech/milvus-migration/core/transform/common"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"strconv"
+)
+
+// SpaceMetricMap : chroma hnsw:space to milvus metric type, default space is l2
+var SpaceMetricMap = map[string]entity.MetricType{
+	"":       entity.L2,
+	"l2":     entity.L2,
+	"ip":     entity.IP,
+	"cosine": entity.COSINE,
+}
+
+func ToCollectionName(collCfg *chromatype.CollectionCfg) string {
+	if collCfg.MilvusCfg != nil && collCfg.MilvusCfg.Collection != "" {
+		return collCfg.MilvusCfg.Collection
+	}
+	return collCfg.Collection
+}
+
+// ToCollectionInfo : the target collection have id, document, embedding and metadata fields,
+// the embedding index metric type is the chroma hnsw:space
+func ToCollectionInfo(collCfg *chromatype.CollectionCfg) (*common.CollectionInfo, error) {
+	fields, err := ToMilvusFields(collCfg)
+	if err != nil {
+		return nil, err
+	}
+	milvusCfg := collCfg.MilvusCfg
+	param := &common.CollectionParam{
+		CollectionName:     ToCollectionName(collCfg),
+		ShardsNum:          milvusCfg.ShardNum,
+		EnableDynamicField: !milvusCfg.CloseDynamicField,
+		AutoId:             false,
+		Description:        "Migration from chroma collection " + collCfg.Collection,
+		CreateIndex:        milvusCfg.CreateIndex,
+		LoadData:           milvusCfg.LoadData,
+	}
+	if param.ShardsNum <= 0 {
+		param.ShardsNum = common.DEF_SHARD_NUM
+	}
+	if len(milvusCfg.ConsistencyLevel) > 0 {
+		level, ok := convert.ConsistencyLevelMap[milvusCfg.ConsistencyLevel]
+		if !ok {
+			return nil, errors.New("chroma transform to milvus consistencyLevel value invalid: " + milvusCfg.ConsistencyLevel)
+		}
+		param.ConsistencyLevel = &level
+	}
+	metricType, ok := SpaceMetricMap[collCfg.InnerSpace]
+	if !ok {
+		return nil, fmt.Errorf("chroma collection %s hnsw:space %s not support", collCfg.Collection, collCfg.InnerSpace)
+	}
+	index, err := convert.ToMilvusIndex(fields[2], &milvustype.IndexCfg{MetricType: string(metricType)})
+	if err != nil {
+		return nil, err
+	}
+	indexes := map[string]entity.Index{chromatype.FieldEmbedding: index}
+	return &common.CollectionInfo{Param: param, Fields: fields, Indexes: indexes}, nil
+}
+
+// ToMilvusFields : id(varchar pk), document(varchar), embedding(float vector), metadata(json)
+func ToMilvusFields(collCfg *chromatype.CollectionCfg) ([]*entity.Field, error) {
+	if collCfg.InnerDim <= 0 {
+		return nil, fmt.Errorf("chroma collection %s dimension unknown, the collection may be empty", collCfg.Collection)
+	}
+	return []*entity.Field{
+		{
+			Name:       chromatype.FieldId,
+			DataType:   entity.FieldTypeVarChar,
+			PrimaryKey: true,
+			TypeParams: map[string]string{entity.TypeParamMaxLength: maxLen(collCfg.IdMaxLen)},
+		},
+		{
+			Name:       chromatype.FieldDocument,
+			DataType:   entity.FieldTypeVarChar,
+			TypeParams: map[string]string{entity.TypeParamMaxLength: maxLen(collCfg.DocumentMaxLen)},
+		},
+		{
+			Name:       chromatype.FieldEmbedding,
+			DataType:   entity.FieldTypeFloatVector,
+			TypeParams: map[string]string{entity.TypeParamDim: strconv.Itoa(collCfg.InnerDim)},
+		},
+		{
+			Name:     chromatype.FieldMetadata,
+			DataType: entity.FieldTypeJSON,
+		},
+	}, nil
+}
+
+func maxLen(n int) string {
+	if n > 0 {
+		return strconv.Itoa(n)
+	}
+	return convert.VarcharMaxLen
+}
+
+// ToMilvusColumns : columns of the records in the ToMilvusFields order
+func ToMilvusColumns(records []*chromatype.Record, dim int) ([]entity.Column, error) {
+	ids := make([]string, len(records))
+	documents := make([]string, len(records))
+	embeddings := make([][]float32, len(records))
+	metadatas := make([][]byte, len(records))
+	for i, record := range records {
+		if len(record.Embedding) != dim {
+			return nil, fmt.Errorf("chroma id %s embedding dim %d not match %d", record.Id, len(record.Embedding), dim)
+		}
+		ids[i] = record.Id
+		documents[i] = record.Document
+		embeddings[i] = record.Embedding
+		metadatas[i] = record.Metadata
+		if len(metadatas[i]) == 0 {
+			metadatas[i] = []byte("{}")
+		}
+	}
+	return []entity.Column{
+		entity.NewColumnVarChar(chromatype.FieldId, ids),
+		entity.NewColumnVarChar(chromatype.FieldDocument, documents),
+		entity.NewColumnFloatVector(chromatype.FieldEmbedding, dim, embeddings),
+		entity.NewColumnJSONBytes(chromatype.FieldMetadata, metadatas),
+	}, nil
+}
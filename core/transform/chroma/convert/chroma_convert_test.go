@@ -0,0 +1,45 @@
+package chromaconvert
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"testing"
+)
+
+func TestToCollectionInfo(t *testing.T) {
+	collCfg := &chromatype.CollectionCfg{Collection: "docs", IdMaxLen: 64, InnerDim: 3, InnerSpace: "ip",
+		MilvusCfg: &milvustype.MilvusCfg{Collection: "target"}}
+	info, err := ToCollectionInfo(collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "target", info.Param.CollectionName)
+	assert.Len(t, info.Fields, 4)
+	assert.True(t, info.Fields[0].PrimaryKey)
+	assert.Equal(t, "64", info.Fields[0].TypeParams[entity.TypeParamMaxLength])
+	assert.Equal(t, "3", info.Fields[2].TypeParams[entity.TypeParamDim])
+	assert.Equal(t, string(entity.IP), info.Indexes[chromatype.FieldEmbedding].Params()["metric_type"])
+
+	collCfg.InnerSpace = "hamming"
+	_, err = ToCollectionInfo(collCfg)
+	assert.Error(t, err)
+	collCfg.InnerDim = 0
+	_, err = ToCollectionInfo(collCfg)
+	assert.Error(t, err)
+}
+
+func TestToMilvusColumns(t *testing.T) {
+	records := []*chromatype.Record{
+		{Id: "a", Document: "doc", Embedding: []float32{1, 2}, Metadata: []byte(`{"k":1}`)},
+		{Id: "b", Embedding: []float32{3, 4}},
+	}
+	columns, err := ToMilvusColumns(records, 2)
+	assert.NoError(t, err)
+	assert.Len(t, columns, 4)
+	assert.Equal(t, 2, columns[0].Len())
+	metadata, _ := columns[3].Get(1)
+	assert.Equal(t, []byte("{}"), metadata)
+
+	_, err = ToMilvusColumns(records, 3)
+	assert.Error(t, err)
+}
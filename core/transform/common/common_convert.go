@@ -1,7 +1,10 @@
 package convert
 
 import (
+	"encoding/json"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
 	"strconv"
 )
 
@@ -16,6 +19,13 @@ var ConsistencyLevelMap = map[string]entity.ConsistencyLevel{
 	"Customized": entity.ClCustomized,
 }
 
+const IndexTypeKey = "index_type"
+const MetricTypeKey = "metric_type"
+const IndexParamsKey = "params"
+
+// MaxArrayCapacity : milvus array field max_capacity limit
+const MaxArrayCapacity = 4096
+
 func IsVectorField(srcField *entity.Field) bool {
 	return srcField.DataType == entity.FieldTypeFloatVector ||
 		srcField.DataType == entity.FieldTypeBinaryVector ||
@@ -23,3 +33,40 @@ func IsVectorField(srcField *entity.Field) bool {
 		srcField.DataType == entity.FieldTypeBFloat16Vector ||
 		srcField.DataType == entity.FieldTypeFloat16Vector
 }
+
+// DefaultMetricType : vector field default metric type when index config not specify it
+func DefaultMetricType(field *entity.Field) entity.MetricType {
+	switch field.DataType {
+	case entity.FieldTypeBinaryVector:
+		return entity.HAMMING
+	case entity.FieldTypeSparseVector:
+		return entity.IP
+	default:
+		return entity.L2
+	}
+}
+
+// ToMilvusIndex : convert index config to milvus index, if idxCfg is nil will use AUTOINDEX
+func ToMilvusIndex(field *entity.Field, idxCfg *milvustype.IndexCfg) (entity.Index, error) {
+	if idxCfg == nil {
+		idxCfg = &milvustype.IndexCfg{}
+	}
+	indexType := entity.AUTOINDEX
+	if len(idxCfg.IndexType) > 0 {
+		indexType = entity.IndexType(idxCfg.IndexType)
+	}
+	params := make(map[string]string)
+	if len(idxCfg.MetricType) > 0 {
+		params[MetricTypeKey] = idxCfg.MetricType
+	} else if IsVectorField(field) {
+		params[MetricTypeKey] = string(DefaultMetricType(field))
+	}
+	if len(idxCfg.Params) > 0 {
+		b, err := json.Marshal(idxCfg.Params)
+		if err != nil {
+			return nil, err
+		}
+		params[IndexParamsKey] = string(b)
+	}
+	return entity.NewGenericIndex(common.EMPTY, indexType, params), nil
+}
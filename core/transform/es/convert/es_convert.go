@@ -7,6 +7,7 @@ import (
 	convert "github.com/zilliztech/milvus-migration/core/transform/common"
 	"github.com/zilliztech/milvus-migration/core/transform/es/parser"
 	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"go.uber.org/zap"
 	"strconv"
@@ -25,41 +26,118 @@ es type:
 	complex type : array, object, nested,
 	geo-point, geo-shape
 	dense_vector
+	knn_vector (opensearch)
 */
 type ESType string
 
 const (
-	Text        ESType = "text"
-	String      ESType = "string"
-	Keyword     ESType = "keyword"
-	Long        ESType = "long"
-	Integer     ESType = "integer"
-	Short       ESType = "short"
-	Byte        ESType = "byte"
-	Boolean     ESType = "boolean"
-	DenseVector ESType = "dense_vector"
-	Double      ESType = "double"
-	Float       ESType = "float"
-	HalfFloat   ESType = "half_float"
-	ScaledFloat ESType = "scaled_float"
-	Object      ESType = "object"
+	Text         ESType = "text"
+	String       ESType = "string"
+	Keyword      ESType = "keyword"
+	Long         ESType = "long"
+	Integer      ESType = "integer"
+	Short        ESType = "short"
+	Byte         ESType = "byte"
+	Boolean      ESType = "boolean"
+	DenseVector  ESType = "dense_vector"
+	Double       ESType = "double"
+	Float        ESType = "float"
+	HalfFloat    ESType = "half_float"
+	ScaledFloat  ESType = "scaled_float"
+	Object       ESType = "object"
+	Date         ESType = "date"
+	DateNanos    ESType = "date_nanos"
+	GeoPoint     ESType = "geo_point"
+	Nested       ESType = "nested"
+	IP           ESType = "ip"
+	Binary       ESType = "binary"
+	SparseVector ESType = "sparse_vector"
+	RankFeatures ESType = "rank_features"
+	KnnVector    ESType = "knn_vector"
 )
 
 var SupportESTypeMap = map[string]entity.FieldType{
-	string(Text):        entity.FieldTypeVarChar,
-	string(String):      entity.FieldTypeVarChar,
-	string(Keyword):     entity.FieldTypeVarChar,
-	string(Long):        entity.FieldTypeInt64,
-	string(Integer):     entity.FieldTypeInt32,
-	string(Short):       entity.FieldTypeInt16,
-	string(Byte):        entity.FieldTypeInt8,
-	string(Boolean):     entity.FieldTypeBool,
-	string(DenseVector): entity.FieldTypeFloatVector,
-	string(Double):      entity.FieldTypeDouble,
-	string(Float):       entity.FieldTypeFloat,
-	string(HalfFloat):   entity.FieldTypeFloat,
-	string(ScaledFloat): entity.FieldTypeFloat,
-	string(Object):      entity.FieldTypeJSON,
+	string(Text):         entity.FieldTypeVarChar,
+	string(String):       entity.FieldTypeVarChar,
+	string(Keyword):      entity.FieldTypeVarChar,
+	string(Long):         entity.FieldTypeInt64,
+	string(Integer):      entity.FieldTypeInt32,
+	string(Short):        entity.FieldTypeInt16,
+	string(Byte):         entity.FieldTypeInt8,
+	string(Boolean):      entity.FieldTypeBool,
+	string(DenseVector):  entity.FieldTypeFloatVector,
+	string(Double):       entity.FieldTypeDouble,
+	string(Float):        entity.FieldTypeFloat,
+	string(HalfFloat):    entity.FieldTypeFloat,
+	string(ScaledFloat):  entity.FieldTypeFloat,
+	string(Object):       entity.FieldTypeJSON,
+	string(Date):         entity.FieldTypeInt64, //default epoch, target=varchar as ISO string
+	string(DateNanos):    entity.FieldTypeInt64, //default epoch nanos, target=varchar as ISO string
+	string(GeoPoint):     entity.FieldTypeJSON,  //default {"lat":x,"lon":y}, target=array as [lon, lat]
+	string(Nested):       entity.FieldTypeJSON,
+	string(IP):           entity.FieldTypeVarChar,
+	string(Binary):       entity.FieldTypeVarChar,      //es _source binary value is base64 string
+	string(SparseVector): entity.FieldTypeSparseVector, //token:weight object, token use vocabulary index
+	string(RankFeatures): entity.FieldTypeSparseVector,
+	string(KnnVector):    entity.FieldTypeFloatVector, //opensearch, data_type binary as BinaryVector
+}
+
+// SpaceTypeMetricMap : opensearch knn_vector method space_type to milvus metric type, l1 and linf not supported
+var SpaceTypeMetricMap = map[string]entity.MetricType{
+	"l2":           entity.L2,
+	"innerproduct": entity.IP,
+	"cosinesimil":  entity.COSINE,
+	"hamming":      entity.HAMMING,
+	"hammingbit":   entity.HAMMING,
+}
+
+// ToMilvusType : return milvus field type and array element type, field type is array when maxCapacity > 0
+func ToMilvusType(field *estype.FieldCfg) (entity.FieldType, entity.FieldType, error) {
+	milvusType, ok := SupportESTypeMap[field.Type]
+	if !ok {
+		return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("not support es field type " + field.Type)
+	}
+	switch field.Type {
+	case string(Date), string(DateNanos):
+		switch field.Target {
+		case "", estype.TargetInt64:
+		case estype.TargetVarChar:
+			milvusType = entity.FieldTypeVarChar
+		default:
+			return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("es date field target only support int64 or varchar, field: " + field.Name)
+		}
+	case string(KnnVector):
+		switch field.VectorDataType {
+		case "", estype.KnnDataFloat, estype.KnnDataByte:
+		case estype.KnnDataBinary:
+			milvusType = entity.FieldTypeBinaryVector
+		default:
+			return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("es knn_vector data_type only support float, byte or binary, field: " + field.Name)
+		}
+		if field.Target != "" {
+			return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("es field target not support type " + field.Type + ", field: " + field.Name)
+		}
+	case string(GeoPoint):
+		switch field.Target {
+		case "", estype.TargetJSON:
+		case estype.TargetArray:
+			return entity.FieldTypeArray, entity.FieldTypeDouble, nil
+		default:
+			return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("es geo_point field target only support json or array, field: " + field.Name)
+		}
+	default:
+		if field.Target != "" {
+			return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("es field target not support type " + field.Type + ", field: " + field.Name)
+		}
+	}
+	if field.MaxCapacity <= 0 {
+		return milvusType, entity.FieldTypeNone, nil
+	}
+	switch milvusType {
+	case entity.FieldTypeFloatVector, entity.FieldTypeBinaryVector, entity.FieldTypeSparseVector, entity.FieldTypeJSON:
+		return entity.FieldTypeNone, entity.FieldTypeNone, errors.New("es field type " + field.Type + " not support maxCapacity, field: " + field.Name)
+	}
+	return entity.FieldTypeArray, milvusType, nil
 }
 
 func ToMilvusParam(idxCfg *estype.IdxCfg) (*common.CollectionInfo, error) {
@@ -75,6 +153,8 @@ func ToMilvusParam(idxCfg *estype.IdxCfg) (*common.CollectionInfo, error) {
 		EnableDynamicField: !idxCfg.MilvusCfg.CloseDynamicField,
 		AutoId:             false,
 		Description:        "Migration from Elasticsearch",
+		CreateIndex:        idxCfg.MilvusCfg.CreateIndex,
+		LoadData:           idxCfg.MilvusCfg.LoadData,
 	}
 	if len(idxCfg.MilvusCfg.ConsistencyLevel) > 0 {
 		val, ok := convert.ConsistencyLevelMap[idxCfg.MilvusCfg.ConsistencyLevel]
@@ -84,7 +164,50 @@ func ToMilvusParam(idxCfg *estype.IdxCfg) (*common.CollectionInfo, error) {
 		}
 		param.ConsistencyLevel = &val
 	}
-	return &common.CollectionInfo{Param: param, Fields: fields}, err
+	indexes, err := ToMilvusIndexes(idxCfg, fields)
+	if err != nil {
+		return nil, err
+	}
+	return &common.CollectionInfo{Param: param, Fields: fields, Indexes: indexes}, err
+}
+
+// ToMilvusIndexes : vector field will use AUTOINDEX if not config index, scalar field only create index when config it.
+// opensearch knn_vector space_type is the metric type when the index config not specify it
+func ToMilvusIndexes(idxCfg *estype.IdxCfg, fields []*entity.Field) (map[string]entity.Index, error) {
+	idxCfgMap := make(map[string]*milvustype.IndexCfg)
+	for _, f := range idxCfg.Fields {
+		if f.Index != nil {
+			idxCfgMap[f.Name] = f.Index
+		}
+		if f.SpaceType == "" || (f.Index != nil && f.Index.MetricType != "") {
+			continue
+		}
+		metricType, ok := SpaceTypeMetricMap[f.SpaceType]
+		if !ok {
+			log.Warn("opensearch knn_vector space_type not support, will use the default metric type",
+				zap.String("field", f.Name), zap.String("spaceType", f.SpaceType))
+			continue
+		}
+		fieldIdxCfg := milvustype.IndexCfg{}
+		if f.Index != nil {
+			fieldIdxCfg = *f.Index
+		}
+		fieldIdxCfg.MetricType = string(metricType)
+		idxCfgMap[f.Name] = &fieldIdxCfg
+	}
+	indexes := make(map[string]entity.Index)
+	for _, field := range fields {
+		fieldIdxCfg, ok := idxCfgMap[field.Name]
+		if !ok && !convert.IsVectorField(field) {
+			continue
+		}
+		index, err := convert.ToMilvusIndex(field, fieldIdxCfg)
+		if err != nil {
+			return nil, err
+		}
+		indexes[field.Name] = index
+	}
+	return indexes, nil
 }
 
 func ToMilvusFields(idxCfg *estype.IdxCfg) ([]*entity.Field, error) {
@@ -101,9 +224,9 @@ func ToMilvusFields(idxCfg *estype.IdxCfg) ([]*entity.Field, error) {
 		}
 		_fields = append(_fields, milvusField)
 
-		milvusType, ok := SupportESTypeMap[field.Type]
-		if !ok {
-			return nil, errors.New("not support es field type " + field.Type)
+		milvusType, elementType, err := ToMilvusType(&field)
+		if err != nil {
+			return nil, err
 		}
 		milvusField.DataType = milvusType
 
@@ -113,22 +236,33 @@ func ToMilvusFields(idxCfg *estype.IdxCfg) ([]*entity.Field, error) {
 		}
 
 		//field specify config
-		switch field.Type {
-		case string(Text), string(Keyword), string(String):
-			var maxLen = convert.VarcharMaxLen
-			if field.MaxLen > 0 {
-				maxLen = strconv.Itoa(field.MaxLen)
+		switch milvusType {
+		case entity.FieldTypeFloatVector, entity.FieldTypeBinaryVector:
+			milvusField.TypeParams = map[string]string{entity.TypeParamDim: strconv.Itoa(field.Dims)}
+		case entity.FieldTypeArray:
+			milvusField.ElementType = elementType
+			maxCapacity := field.MaxCapacity
+			if field.Type == string(GeoPoint) {
+				maxCapacity = 2
 			}
-			milvusField.TypeParams = map[string]string{
-				entity.TypeParamMaxLength: maxLen,
+			milvusField.TypeParams = map[string]string{entity.TypeParamMaxCapacity: strconv.Itoa(maxCapacity)}
+			if elementType == entity.FieldTypeVarChar {
+				milvusField.TypeParams[entity.TypeParamMaxLength] = toMaxLen(field.MaxLen)
 			}
-		case string(DenseVector):
-			milvusField.TypeParams = map[string]string{entity.TypeParamDim: strconv.Itoa(field.Dims)}
+		case entity.FieldTypeVarChar:
+			milvusField.TypeParams = map[string]string{entity.TypeParamMaxLength: toMaxLen(field.MaxLen)}
 		}
 	}
 	return _fields, nil
 }
 
+func toMaxLen(maxLen int) string {
+	if maxLen > 0 {
+		return strconv.Itoa(maxLen)
+	}
+	return convert.VarcharMaxLen
+}
+
 func DefaultPKField() *entity.Field {
 	return &entity.Field{
 		Name:       esparser.MILVUS_ID,
@@ -0,0 +1,90 @@
+package esconvert
+
+import (
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+)
+
+// ToFieldCfgs : convert es index mapping properties to field configs, return the unsupported type field names
+func ToFieldCfgs(properties gjson.Result) ([]estype.FieldCfg, []string) {
+	fields := make([]estype.FieldCfg, 0)
+	unsupported := make([]string, 0)
+	properties.ForEach(func(name, property gjson.Result) bool {
+		_type := property.Get("type").String()
+		//object field mapping only have properties, no type
+		if _type == "" && property.Get("properties").Exists() {
+			_type = string(Object)
+		}
+		if _, ok := SupportESTypeMap[_type]; !ok {
+			unsupported = append(unsupported, name.String())
+			return true
+		}
+		field := estype.FieldCfg{Name: name.String(), Type: _type}
+		switch _type {
+		case string(DenseVector):
+			field.Dims = int(property.Get("dims").Int())
+		case string(KnnVector):
+			//model_id knn_vector not have dimension in mapping, need config dims
+			field.Dims = int(property.Get("dimension").Int())
+			field.VectorDataType = property.Get("data_type").String()
+			field.SpaceType = property.Get("method.space_type").String()
+			if field.SpaceType == "" {
+				field.SpaceType = property.Get("space_type").String()
+			}
+		}
+		fields = append(fields, field)
+		return true
+	})
+	return fields, unsupported
+}
+
+// MergeFieldCfgs : config field override the same name mapping field, the config field not in mapping will append at last
+func MergeFieldCfgs(mappingFields []estype.FieldCfg, cfgFields []estype.FieldCfg) []estype.FieldCfg {
+	cfgFieldMap := make(map[string]estype.FieldCfg, len(cfgFields))
+	for _, f := range cfgFields {
+		cfgFieldMap[f.Name] = f
+	}
+	merged := make([]estype.FieldCfg, 0, len(mappingFields)+len(cfgFields))
+	for _, f := range mappingFields {
+		if cfgField, ok := cfgFieldMap[f.Name]; ok {
+			f = overrideFieldCfg(f, cfgField)
+			delete(cfgFieldMap, f.Name)
+		}
+		merged = append(merged, f)
+	}
+	for _, f := range cfgFields {
+		if _, ok := cfgFieldMap[f.Name]; ok {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+func overrideFieldCfg(field estype.FieldCfg, cfgField estype.FieldCfg) estype.FieldCfg {
+	if cfgField.Type != "" {
+		field.Type = cfgField.Type
+	}
+	if cfgField.Dims > 0 {
+		field.Dims = cfgField.Dims
+	}
+	if cfgField.MaxLen > 0 {
+		field.MaxLen = cfgField.MaxLen
+	}
+	if cfgField.Target != "" {
+		field.Target = cfgField.Target
+	}
+	if cfgField.MaxCapacity > 0 {
+		field.MaxCapacity = cfgField.MaxCapacity
+	}
+	if cfgField.Index != nil {
+		field.Index = cfgField.Index
+	}
+	if cfgField.VectorDataType != "" {
+		field.VectorDataType = cfgField.VectorDataType
+	}
+	if cfgField.SpaceType != "" {
+		field.SpaceType = cfgField.SpaceType
+	}
+	field.PK = cfgField.PK
+	return field
+}
@@ -0,0 +1,61 @@
+package esconvert
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"testing"
+)
+
+func TestToFieldCfgs(t *testing.T) {
+	properties := gjson.Parse(`{
+		"id": {"type": "long"},
+		"title": {"type": "text", "fields": {"raw": {"type": "keyword"}}},
+		"vec": {"type": "dense_vector", "dims": 128},
+		"attrs": {"properties": {"color": {"type": "keyword"}}},
+		"area": {"type": "geo_shape"},
+		"knn": {"type": "knn_vector", "dimension": 4, "method": {"name": "hnsw", "space_type": "innerproduct", "engine": "faiss"}},
+		"bits": {"type": "knn_vector", "dimension": 16, "data_type": "binary", "space_type": "hamming"}
+	}`)
+	fields, unsupported := ToFieldCfgs(properties)
+	assert.Equal(t, []estype.FieldCfg{
+		{Name: "id", Type: "long"},
+		{Name: "title", Type: "text"},
+		{Name: "vec", Type: "dense_vector", Dims: 128},
+		{Name: "attrs", Type: "object"},
+		{Name: "knn", Type: "knn_vector", Dims: 4, SpaceType: "innerproduct"},
+		{Name: "bits", Type: "knn_vector", Dims: 16, VectorDataType: "binary", SpaceType: "hamming"},
+	}, fields)
+	assert.Equal(t, []string{"area"}, unsupported)
+
+	idxCfg := &estype.IdxCfg{Fields: fields[4:], InnerPkField: &estype.FieldCfg{}}
+	milvusFields, err := ToMilvusFields(idxCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.FieldTypeFloatVector, milvusFields[0].DataType)
+	assert.Equal(t, entity.FieldTypeBinaryVector, milvusFields[1].DataType)
+	assert.Equal(t, "16", milvusFields[1].TypeParams[entity.TypeParamDim])
+	indexes, err := ToMilvusIndexes(idxCfg, milvusFields)
+	assert.NoError(t, err)
+	assert.Equal(t, string(entity.IP), indexes["knn"].Params()["metric_type"])
+	assert.Equal(t, string(entity.HAMMING), indexes["bits"].Params()["metric_type"])
+}
+
+func TestMergeFieldCfgs(t *testing.T) {
+	mappingFields := []estype.FieldCfg{
+		{Name: "id", Type: "long"},
+		{Name: "title", Type: "text"},
+		{Name: "vec", Type: "dense_vector", Dims: 128},
+	}
+	cfgFields := []estype.FieldCfg{
+		{Name: "_id", Type: "keyword", MaxLen: 60, PK: true},
+		{Name: "title", MaxLen: 256},
+	}
+	merged := MergeFieldCfgs(mappingFields, cfgFields)
+	assert.Equal(t, []estype.FieldCfg{
+		{Name: "id", Type: "long"},
+		{Name: "title", Type: "text", MaxLen: 256},
+		{Name: "vec", Type: "dense_vector", Dims: 128},
+		{Name: "_id", Type: "keyword", MaxLen: 60, PK: true},
+	}, merged)
+}
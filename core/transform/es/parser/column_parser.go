@@ -0,0 +1,207 @@
+package esparser
+
+import (
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"strconv"
+	"strings"
+)
+
+// ToMilvusColumns : convert es hits to milvus columns of the collection fields, used by batch insert mode.
+// the field not in collection fields will be stored in dynamic field when enableDynamic
+func ToMilvusColumns(hits *gjson.Result, idx *estype.IdxCfg, fields []*entity.Field, enableDynamic bool) ([]entity.Column, error) {
+	b, err := ParseHits(hits, SquareL, idx)
+	if err != nil {
+		return nil, err
+	}
+	arr := gjson.Parse(string(b) + SquareR).Array()
+	rows := make([]map[string]gjson.Result, 0, len(arr))
+	for _, row := range arr {
+		rows = append(rows, row.Map())
+	}
+
+	columns := make([]entity.Column, 0, len(fields)+1)
+	fieldSet := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		fieldSet[field.Name] = true
+		values := make([]gjson.Result, 0, len(rows))
+		for _, row := range rows {
+			values = append(values, row[field.Name])
+		}
+		column, err := toColumn(field, values)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	if enableDynamic {
+		columns = append(columns, toDynamicColumn(rows, fieldSet))
+	}
+	return columns, nil
+}
+
+func toColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		return entity.NewColumnBool(field.Name, toSlice(values, gjson.Result.Bool)), nil
+	case entity.FieldTypeInt8:
+		return entity.NewColumnInt8(field.Name, toSlice(values, func(v gjson.Result) int8 { return int8(v.Int()) })), nil
+	case entity.FieldTypeInt16:
+		return entity.NewColumnInt16(field.Name, toSlice(values, func(v gjson.Result) int16 { return int16(v.Int()) })), nil
+	case entity.FieldTypeInt32:
+		return entity.NewColumnInt32(field.Name, toSlice(values, func(v gjson.Result) int32 { return int32(v.Int()) })), nil
+	case entity.FieldTypeInt64:
+		return entity.NewColumnInt64(field.Name, toSlice(values, gjson.Result.Int)), nil
+	case entity.FieldTypeFloat:
+		return entity.NewColumnFloat(field.Name, toSlice(values, func(v gjson.Result) float32 { return float32(v.Float()) })), nil
+	case entity.FieldTypeDouble:
+		return entity.NewColumnDouble(field.Name, toSlice(values, gjson.Result.Float)), nil
+	case entity.FieldTypeVarChar:
+		return entity.NewColumnVarChar(field.Name, toSlice(values, gjson.Result.String)), nil
+	case entity.FieldTypeJSON:
+		return entity.NewColumnJSONBytes(field.Name, toSlice(values, toJSONBytes)), nil
+	case entity.FieldTypeFloatVector:
+		return toFloatVectorColumn(field, values)
+	case entity.FieldTypeBinaryVector:
+		return toBinaryVectorColumn(field, values)
+	case entity.FieldTypeSparseVector:
+		return toSparseColumn(field, values)
+	case entity.FieldTypeArray:
+		return toArrayColumn(field, values)
+	default:
+		return nil, fmt.Errorf("es batch insert not support milvus field type %s, field: %s", field.DataType.Name(), field.Name)
+	}
+}
+
+func toSlice[T any](values []gjson.Result, convert func(gjson.Result) T) []T {
+	data := make([]T, 0, len(values))
+	for _, v := range values {
+		data = append(data, convert(v))
+	}
+	return data
+}
+
+func toJSONBytes(v gjson.Result) []byte {
+	if !v.Exists() || v.Type == gjson.Null {
+		return []byte("{}")
+	}
+	return []byte(v.Raw)
+}
+
+func toFloats(v gjson.Result) []float32 {
+	arr := v.Array()
+	floats := make([]float32, 0, len(arr))
+	for _, f := range arr {
+		floats = append(floats, float32(f.Float()))
+	}
+	return floats
+}
+
+func toFloatVectorColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	dim, err := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+	if err != nil {
+		return nil, fmt.Errorf("field %s dim invalid: %w", field.Name, err)
+	}
+	vectors := make([][]float32, 0, len(values))
+	for _, v := range values {
+		vector := toFloats(v)
+		if len(vector) != dim {
+			return nil, fmt.Errorf("field %s vector dim %d not equal %d", field.Name, len(vector), dim)
+		}
+		vectors = append(vectors, vector)
+	}
+	return entity.NewColumnFloatVector(field.Name, dim, vectors), nil
+}
+
+// toBinaryVectorColumn : value is converted to uint8 array by binaryVectorConverter, dim is the bits number
+func toBinaryVectorColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	dim, err := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+	if err != nil {
+		return nil, fmt.Errorf("field %s dim invalid: %w", field.Name, err)
+	}
+	vectors := make([][]byte, 0, len(values))
+	for _, v := range values {
+		vector := toSlice(v.Array(), func(b gjson.Result) byte { return byte(b.Int()) })
+		if len(vector)*8 != dim {
+			return nil, fmt.Errorf("field %s binary vector bytes %d not match dim %d", field.Name, len(vector), dim)
+		}
+		vectors = append(vectors, vector)
+	}
+	return entity.NewColumnBinaryVector(field.Name, dim, vectors), nil
+}
+
+// toSparseColumn : value is converted to {"indices":[...],"values":[...]} by sparseConverter
+func toSparseColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	embeddings := make([]entity.SparseEmbedding, 0, len(values))
+	for _, v := range values {
+		indices := v.Get("indices").Array()
+		positions := make([]uint32, 0, len(indices))
+		for _, idx := range indices {
+			positions = append(positions, uint32(idx.Uint()))
+		}
+		embedding, err := entity.NewSliceSparseEmbedding(positions, toFloats(v.Get("values")))
+		if err != nil {
+			return nil, fmt.Errorf("field %s sparse vector invalid: %w", field.Name, err)
+		}
+		embeddings = append(embeddings, embedding)
+	}
+	return entity.NewColumnSparseVectors(field.Name, embeddings), nil
+}
+
+// toArrayColumn : value is converted to json array by arrayConverter or geoPointConverter
+func toArrayColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	switch field.ElementType {
+	case entity.FieldTypeBool:
+		return entity.NewColumnBoolArray(field.Name, toArrays(values, gjson.Result.Bool)), nil
+	case entity.FieldTypeInt8:
+		return entity.NewColumnInt8Array(field.Name, toArrays(values, func(v gjson.Result) int8 { return int8(v.Int()) })), nil
+	case entity.FieldTypeInt16:
+		return entity.NewColumnInt16Array(field.Name, toArrays(values, func(v gjson.Result) int16 { return int16(v.Int()) })), nil
+	case entity.FieldTypeInt32:
+		return entity.NewColumnInt32Array(field.Name, toArrays(values, func(v gjson.Result) int32 { return int32(v.Int()) })), nil
+	case entity.FieldTypeInt64:
+		return entity.NewColumnInt64Array(field.Name, toArrays(values, gjson.Result.Int)), nil
+	case entity.FieldTypeFloat:
+		return entity.NewColumnFloatArray(field.Name, toArrays(values, func(v gjson.Result) float32 { return float32(v.Float()) })), nil
+	case entity.FieldTypeDouble:
+		return entity.NewColumnDoubleArray(field.Name, toArrays(values, gjson.Result.Float)), nil
+	case entity.FieldTypeVarChar:
+		return entity.NewColumnVarCharArray(field.Name, toArrays(values, func(v gjson.Result) []byte { return []byte(v.String()) })), nil
+	default:
+		return nil, fmt.Errorf("es batch insert not support array element type %s, field: %s", field.ElementType.Name(), field.Name)
+	}
+}
+
+func toArrays[T any](values []gjson.Result, convert func(gjson.Result) T) [][]T {
+	arrays := make([][]T, 0, len(values))
+	for _, v := range values {
+		arrays = append(arrays, toSlice(v.Array(), convert))
+	}
+	return arrays
+}
+
+// toDynamicColumn : the row fields not in collection fields as a json object
+func toDynamicColumn(rows []map[string]gjson.Result, fieldSet map[string]bool) entity.Column {
+	data := make([][]byte, 0, len(rows))
+	for _, row := range rows {
+		var sb strings.Builder
+		sb.WriteString(BraceL)
+		for key, val := range row {
+			if fieldSet[key] {
+				continue
+			}
+			if sb.Len() > 1 {
+				sb.WriteString(COMMA)
+			}
+			sb.WriteString(strconv.Quote(key))
+			sb.WriteString(":")
+			sb.WriteString(val.Raw)
+		}
+		sb.WriteString(BraceR)
+		data = append(data, []byte(sb.String()))
+	}
+	return entity.NewColumnJSONBytes(common.MILVUS_META_FD, data).WithIsDynamic(true)
+}
@@ -0,0 +1,38 @@
+package esparser
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"testing"
+)
+
+func TestToMilvusColumns(t *testing.T) {
+	idx := &estype.IdxCfg{
+		Fields: []estype.FieldCfg{
+			{Name: "vec", Type: "dense_vector", Dims: 2},
+			{Name: "tags", Type: "keyword", MaxCapacity: 4},
+		},
+	}
+	fields := []*entity.Field{
+		{Name: "_id", DataType: entity.FieldTypeVarChar, PrimaryKey: true},
+		{Name: "vec", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{entity.TypeParamDim: "2"}},
+		{Name: "tags", DataType: entity.FieldTypeArray, ElementType: entity.FieldTypeVarChar},
+	}
+	hits := gjson.Parse(`[{"_id":"1","_source":{"vec":[0.1,0.2],"tags":"a","title":"t1"}},` +
+		`{"_id":"2","_source":{"vec":[0.3,0.4],"tags":["b","c"]}}]`)
+	columns, err := ToMilvusColumns(&hits, idx, fields, true)
+	assert.NoError(t, err)
+	assert.Len(t, columns, 4)
+	assert.Equal(t, []string{"1", "2"}, columns[0].(*entity.ColumnVarChar).Data())
+	assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, columns[1].(*entity.ColumnFloatVector).Data())
+	assert.Equal(t, [][][]byte{{[]byte("a")}, {[]byte("b"), []byte("c")}}, columns[2].(*entity.ColumnVarCharArray).Data())
+	dynamic := columns[3].(*entity.ColumnJSONBytes)
+	assert.True(t, dynamic.FieldData().IsDynamic)
+	assert.Equal(t, [][]byte{[]byte(`{"title":"t1"}`), []byte(`{}`)}, dynamic.Data())
+
+	hits = gjson.Parse(`[{"_id":"3","_source":{"vec":[0.1]}}]`)
+	_, err = ToMilvusColumns(&hits, idx, fields, false)
+	assert.Error(t, err)
+}
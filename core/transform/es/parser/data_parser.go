@@ -14,19 +14,18 @@ const JSON_END = "]}"
 const SquareL = "["
 const SquareR = "]"
 const BraceL = "{"
-
-// var BRACE_R = "}"
+const BraceR = "}"
 
 const JsonIdKey = `"_id":`
 const _SOURCE = "_source"
 const _ID = "_id"
 const MILVUS_ID = _ID
 
-func Next2JsonData(hits *gjson.Result, idx *estype.IdxCfg) []byte {
+func Next2JsonData(hits *gjson.Result, idx *estype.IdxCfg) ([]byte, error) {
 	return ParseHits(hits, COMMA, idx)
 }
 
-func First2JsonData(hits *gjson.Result, idx *estype.IdxCfg) []byte {
+func First2JsonData(hits *gjson.Result, idx *estype.IdxCfg) ([]byte, error) {
 	//return ParseHits(hits, common.EMPTY)
 	return ParseHits(hits, JSON_START, idx)
 }
@@ -35,11 +34,12 @@ func StartCharacter() []byte {
 	return []byte(JSON_START)
 }
 
-func ParseHits(hits *gjson.Result, startStr string, idx *estype.IdxCfg) []byte {
+func ParseHits(hits *gjson.Result, startStr string, idx *estype.IdxCfg) ([]byte, error) {
 	var sb strings.Builder
 	if len(startStr) > 0 {
 		sb.WriteString(startStr)
 	}
+	converters := newValueConverters(idx)
 	arr := hits.Array()
 	for n, obj := range arr {
 		sb.WriteString(BraceL)
@@ -56,13 +56,48 @@ func ParseHits(hits *gjson.Result, startStr string, idx *estype.IdxCfg) []byte {
 				sb.WriteString(COMMA)
 			}
 		}
-		source := obj.Get(_SOURCE).String()[1:]
-		sb.WriteString(source)
+		if len(converters) == 0 {
+			source := obj.Get(_SOURCE).String()[1:]
+			sb.WriteString(source)
+		} else if err := writeConvertedSource(&sb, obj.Get(_SOURCE), converters); err != nil {
+			return nil, err
+		}
 		if n < len(arr)-1 {
 			sb.WriteString(COMMA)
 		}
 	}
-	return []byte(sb.String())
+	return []byte(sb.String()), nil
+}
+
+// writeConvertedSource : write _source fields without the first '{', the field has converter will write converted value
+func writeConvertedSource(sb *strings.Builder, source gjson.Result, converters map[string]valueConverter) error {
+	var err error
+	first := true
+	source.ForEach(func(key, val gjson.Result) bool {
+		if !first {
+			sb.WriteString(COMMA)
+		}
+		first = false
+		sb.WriteString(key.Raw)
+		sb.WriteString(":")
+		converter, ok := converters[key.String()]
+		if !ok {
+			sb.WriteString(val.Raw)
+			return true
+		}
+		var v string
+		v, err = converter(val)
+		if err != nil {
+			return false
+		}
+		sb.WriteString(v)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	sb.WriteString(BraceR)
+	return nil
 }
 
 func get_IDVal(val string, milvusType *entity.FieldType) string {
@@ -0,0 +1,275 @@
+package esparser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	esDate      = "date"
+	esDateNanos = "date_nanos"
+	esGeoPoint  = "geo_point"
+	esSparse    = "sparse_vector"
+	esRankFeats = "rank_features"
+	esKnnVector = "knn_vector"
+)
+
+// es date default format: strict_date_optional_time||epoch_millis
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// valueConverter : convert es _source field value to milvus json row value
+type valueConverter func(val gjson.Result) (string, error)
+
+// newValueConverters : only the fields need convert value have converter, others keep the _source raw value
+func newValueConverters(idx *estype.IdxCfg) map[string]valueConverter {
+	converters := make(map[string]valueConverter)
+	for _, f := range idx.Fields {
+		var converter valueConverter
+		switch f.Type {
+		case esDate, esDateNanos:
+			converter = dateConverter(f.Type == esDateNanos, f.Target)
+		case esGeoPoint:
+			converter = geoPointConverter(f.Target)
+		case esSparse, esRankFeats:
+			converter = sparseConverter(f.Name, idx.InnerVocabularies[f.Name])
+		case esKnnVector:
+			if f.VectorDataType == estype.KnnDataBinary {
+				converter = binaryVectorConverter(f.Name)
+			}
+		}
+		if f.MaxCapacity > 0 && f.Type != esGeoPoint {
+			converter = arrayConverter(f.Name, f.MaxCapacity, converter)
+		}
+		if converter != nil {
+			converters[f.Name] = converter
+		}
+	}
+	return converters
+}
+
+func dateConverter(nanos bool, target string) valueConverter {
+	return func(val gjson.Result) (string, error) {
+		if val.Type == gjson.Null {
+			return val.Raw, nil
+		}
+		if target == estype.TargetVarChar {
+			//date string keep the origin format
+			if val.Type == gjson.String {
+				return val.Raw, nil
+			}
+			return strconv.Quote(time.UnixMilli(val.Int()).UTC().Format(time.RFC3339Nano)), nil
+		}
+		t, err := parseDate(val)
+		if err != nil {
+			return "", err
+		}
+		if nanos {
+			return strconv.FormatInt(t.UnixNano(), 10), nil
+		}
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	}
+}
+
+func parseDate(val gjson.Result) (time.Time, error) {
+	if val.Type == gjson.Number {
+		return time.UnixMilli(val.Int()), nil
+	}
+	str := val.String()
+	if millis, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("es date value format not support: " + str + ", you can set field target=varchar")
+}
+
+// geoPointConverter : json target as {"lat":x,"lon":y}, array target as [lon, lat]
+func geoPointConverter(target string) valueConverter {
+	return func(val gjson.Result) (string, error) {
+		if val.Type == gjson.Null {
+			return val.Raw, nil
+		}
+		lat, lon, err := parseGeoPoint(val)
+		if err != nil {
+			return "", err
+		}
+		if target == estype.TargetArray {
+			return "[" + formatFloat(lon) + "," + formatFloat(lat) + "]", nil
+		}
+		return `{"lat":` + formatFloat(lat) + `,"lon":` + formatFloat(lon) + "}", nil
+	}
+}
+
+// parseGeoPoint : es geo_point value support object, geojson, [lon, lat], "lat,lon", "POINT (lon lat)" and geohash
+func parseGeoPoint(val gjson.Result) (float64, float64, error) {
+	switch {
+	case val.IsObject():
+		if coords := val.Get("coordinates"); coords.IsArray() {
+			return parseGeoPoint(coords)
+		}
+		return val.Get("lat").Float(), val.Get("lon").Float(), nil
+	case val.IsArray():
+		arr := val.Array()
+		if len(arr) < 2 || arr[0].Type != gjson.Number {
+			return 0, 0, errors.New("es geo_point value format not support: " + val.Raw)
+		}
+		return arr[1].Float(), arr[0].Float(), nil
+	case val.Type == gjson.String:
+		str := strings.TrimSpace(val.String())
+		if strings.HasPrefix(strings.ToUpper(str), "POINT") {
+			var lon, lat float64
+			_, err := fmt.Sscanf(strings.TrimSpace(str[len("POINT"):]), "(%g %g)", &lon, &lat)
+			if err != nil {
+				return 0, 0, errors.New("es geo_point wkt format not support: " + str)
+			}
+			return lat, lon, nil
+		}
+		if latStr, lonStr, ok := strings.Cut(str, ","); ok {
+			lat, err1 := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+			lon, err2 := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+			if err1 != nil || err2 != nil {
+				return 0, 0, errors.New("es geo_point value format not support: " + str)
+			}
+			return lat, lon, nil
+		}
+		return decodeGeohash(str)
+	default:
+		return 0, 0, errors.New("es geo_point value format not support: " + val.Raw)
+	}
+}
+
+// decodeGeohash : return the center point of the geohash cell
+func decodeGeohash(hash string) (float64, float64, error) {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	isLon := true
+	for _, c := range strings.ToLower(hash) {
+		idx := strings.IndexRune(geohashBase32, c)
+		if idx < 0 {
+			return 0, 0, errors.New("es geo_point geohash invalid: " + hash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			r := &latRange
+			if isLon {
+				r = &lonRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if idx&(1<<bit) != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			isLon = !isLon
+		}
+	}
+	return (latRange[0] + latRange[1]) / 2, (lonRange[0] + lonRange[1]) / 2, nil
+}
+
+// sparseConverter : es {"token":weight} convert to milvus sparse json {"indices":[...],"values":[...]}
+func sparseConverter(name string, vocabulary *estype.SparseVocabulary) valueConverter {
+	return func(val gjson.Result) (string, error) {
+		if vocabulary == nil {
+			return "", errors.New("es sparse field vocabulary not init, field: " + name)
+		}
+		if !val.IsObject() {
+			return "", errors.New("es sparse field value need token:weight object, field: " + name + ", value: " + val.Raw)
+		}
+		type pair struct {
+			index  uint32
+			weight string
+		}
+		pairs := make([]pair, 0)
+		val.ForEach(func(token, weight gjson.Result) bool {
+			pairs = append(pairs, pair{index: vocabulary.IndexOf(token.String()), weight: weight.Raw})
+			return true
+		})
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].index < pairs[j].index })
+		var indices, values strings.Builder
+		for i, p := range pairs {
+			if i > 0 {
+				indices.WriteString(COMMA)
+				values.WriteString(COMMA)
+			}
+			indices.WriteString(strconv.FormatUint(uint64(p.index), 10))
+			values.WriteString(p.weight)
+		}
+		return `{"indices":[` + indices.String() + `],"values":[` + values.String() + "]}", nil
+	}
+}
+
+// binaryVectorConverter : opensearch binary knn_vector value is int8 array, milvus binary vector json is uint8 array
+func binaryVectorConverter(name string) valueConverter {
+	return func(val gjson.Result) (string, error) {
+		if !val.IsArray() {
+			return "", errors.New("opensearch binary knn_vector value need int8 array, field: " + name + ", value: " + val.Raw)
+		}
+		var sb strings.Builder
+		sb.WriteString(SquareL)
+		for i, v := range val.Array() {
+			if i > 0 {
+				sb.WriteString(COMMA)
+			}
+			sb.WriteString(strconv.Itoa(int(uint8(int8(v.Int())))))
+		}
+		sb.WriteString(SquareR)
+		return sb.String(), nil
+	}
+}
+
+// arrayConverter : es field single value and multi values both convert to array
+func arrayConverter(name string, maxCapacity int, elemConverter valueConverter) valueConverter {
+	return func(val gjson.Result) (string, error) {
+		var elems []gjson.Result
+		switch {
+		case val.Type == gjson.Null:
+		case val.IsArray():
+			elems = val.Array()
+		default:
+			elems = []gjson.Result{val}
+		}
+		if len(elems) > maxCapacity {
+			return "", fmt.Errorf("es field %s values size %d > maxCapacity %d", name, len(elems), maxCapacity)
+		}
+		var sb strings.Builder
+		sb.WriteString(SquareL)
+		for i, elem := range elems {
+			if i > 0 {
+				sb.WriteString(COMMA)
+			}
+			if elemConverter == nil {
+				sb.WriteString(elem.Raw)
+				continue
+			}
+			v, err := elemConverter(elem)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(v)
+		}
+		sb.WriteString(SquareR)
+		return sb.String(), nil
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,65 @@
+package esparser
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"testing"
+)
+
+func TestParseHitsConvertValue(t *testing.T) {
+	idx := &estype.IdxCfg{
+		Fields: []estype.FieldCfg{
+			{Name: "created", Type: "date"},
+			{Name: "updated", Type: "date", Target: estype.TargetVarChar},
+			{Name: "loc", Type: "geo_point"},
+			{Name: "pos", Type: "geo_point", Target: estype.TargetArray},
+			{Name: "tags", Type: "keyword", MaxCapacity: 4},
+			{Name: "title", Type: "text"},
+		},
+	}
+	hits := gjson.Parse(`[{"_id":"1","_source":{"created":"2024-01-02T03:04:05Z","updated":1704164645000,` +
+		`"loc":"41.12,-71.34","pos":{"lat":41.12,"lon":-71.34},"tags":"a","title":"t"}}]`)
+	b, err := ParseHits(&hits, JSON_START, idx)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"rows":[{"_id":"1","created":1704164645000,"updated":"2024-01-02T03:04:05Z",`+
+		`"loc":{"lat":41.12,"lon":-71.34},"pos":[-71.34,41.12],"tags":["a"],"title":"t"}`, string(b))
+}
+
+func TestParseGeoPoint(t *testing.T) {
+	cases := []string{`{"lat":41.12,"lon":-71.34}`, `[-71.34,41.12]`, `"41.12,-71.34"`, `"POINT (-71.34 41.12)"`,
+		`{"type":"Point","coordinates":[-71.34,41.12]}`}
+	for _, c := range cases {
+		lat, lon, err := parseGeoPoint(gjson.Parse(c))
+		assert.NoError(t, err)
+		assert.Equal(t, 41.12, lat)
+		assert.Equal(t, -71.34, lon)
+	}
+	lat, lon, err := parseGeoPoint(gjson.Parse(`"drm3btev3e86"`))
+	assert.NoError(t, err)
+	assert.InDelta(t, 41.12, lat, 0.0001)
+	assert.InDelta(t, -71.34, lon, 0.0001)
+}
+
+func TestArrayConverterExceedCapacity(t *testing.T) {
+	_, err := arrayConverter("tags", 1, nil)(gjson.Parse(`["a","b"]`))
+	assert.Error(t, err)
+}
+
+func TestSparseConverter(t *testing.T) {
+	vocabulary := estype.NewSparseVocabulary()
+	converter := sparseConverter("tokens", vocabulary)
+	v, err := converter(gjson.Parse(`{"milvus":0.5,"vector":1.2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"indices":[0,1],"values":[0.5,1.2]}`, v)
+	v, err = converter(gjson.Parse(`{"search":0.3,"milvus":0.1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"indices":[0,2],"values":[0.1,0.3]}`, v)
+	assert.Equal(t, 3, vocabulary.Size())
+}
+
+func TestBinaryVectorConverter(t *testing.T) {
+	v, err := binaryVectorConverter("bits")(gjson.Parse(`[-126,7]`))
+	assert.NoError(t, err)
+	assert.Equal(t, `[130,7]`, v)
+}
@@ -0,0 +1,147 @@
+package filesconvert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	convert "github.com/zilliztech/milvus-migration/core/transform/common"
+	"github.com/zilliztech/milvus-migration/core/type/filestype"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// SupportFieldTypeMap : key is the lower case meta.fields type
+var SupportFieldTypeMap = map[string]entity.FieldType{
+	"bool":        entity.FieldTypeBool,
+	"int8":        entity.FieldTypeInt8,
+	"int16":       entity.FieldTypeInt16,
+	"int32":       entity.FieldTypeInt32,
+	"int64":       entity.FieldTypeInt64,
+	"float":       entity.FieldTypeFloat,
+	"double":      entity.FieldTypeDouble,
+	"varchar":     entity.FieldTypeVarChar,
+	"json":        entity.FieldTypeJSON,
+	"floatvector": entity.FieldTypeFloatVector,
+}
+
+// FormatExtensions : file extensions of each format, used to detect the file format and filter the dir files
+var FormatExtensions = map[common.FileFormat][]string{
+	common.FormatJSONL:   {".jsonl", ".ndjson", ".json"},
+	common.FormatCSV:     {".csv"},
+	common.FormatParquet: {".parquet"},
+}
+
+// FileFormat : the format of the file extension, the collection format limit the extensions if config.
+// false if the extension not match, a listed file can still read as the collection format
+func FileFormat(collCfg *filestype.CollectionCfg, file string) (common.FileFormat, bool) {
+	ext := strings.ToLower(path.Ext(file))
+	for format, exts := range FormatExtensions {
+		if collCfg.Format != "" && format != common.FileFormat(collCfg.Format) {
+			continue
+		}
+		for _, e := range exts {
+			if e == ext {
+				return format, true
+			}
+		}
+	}
+	return common.FileFormat(collCfg.Format), false
+}
+
+func ToCollectionName(collCfg *filestype.CollectionCfg) string {
+	if collCfg.MilvusCfg != nil && collCfg.MilvusCfg.Collection != "" {
+		return collCfg.MilvusCfg.Collection
+	}
+	return collCfg.Collection
+}
+
+// ToCollectionInfo : the target collection of the meta.fields schema, vector field will use AUTOINDEX if not config index
+func ToCollectionInfo(collCfg *filestype.CollectionCfg) (*common.CollectionInfo, error) {
+	fields, err := ToMilvusFields(collCfg)
+	if err != nil {
+		return nil, err
+	}
+	milvusCfg := collCfg.MilvusCfg
+	param := &common.CollectionParam{
+		CollectionName:     ToCollectionName(collCfg),
+		ShardsNum:          milvusCfg.ShardNum,
+		EnableDynamicField: !milvusCfg.CloseDynamicField,
+		AutoId:             milvusCfg.AutoId == "true",
+		Description:        "Migration from files",
+		CreateIndex:        milvusCfg.CreateIndex,
+		LoadData:           milvusCfg.LoadData,
+	}
+	if param.ShardsNum <= 0 {
+		param.ShardsNum = common.DEF_SHARD_NUM
+	}
+	if len(milvusCfg.ConsistencyLevel) > 0 {
+		level, ok := convert.ConsistencyLevelMap[milvusCfg.ConsistencyLevel]
+		if !ok {
+			return nil, errors.New("files transform to milvus consistencyLevel value invalid: " + milvusCfg.ConsistencyLevel)
+		}
+		param.ConsistencyLevel = &level
+	}
+	indexes := make(map[string]entity.Index)
+	for _, field := range fields {
+		idxCfg := collCfg.GetField(field.Name).Index
+		if idxCfg == nil && !convert.IsVectorField(field) {
+			continue
+		}
+		index, err := convert.ToMilvusIndex(field, idxCfg)
+		if err != nil {
+			return nil, err
+		}
+		indexes[field.Name] = index
+	}
+	return &common.CollectionInfo{Param: param, Fields: fields, Indexes: indexes}, nil
+}
+
+// ToMilvusFields : fields in the meta.fields order, need one Int64 or VarChar pk field and at least one vector field
+func ToMilvusFields(collCfg *filestype.CollectionCfg) ([]*entity.Field, error) {
+	fields := make([]*entity.Field, 0, len(collCfg.Fields))
+	names := make(map[string]bool, len(collCfg.Fields))
+	var pkNum, vectorNum int
+	for i := range collCfg.Fields {
+		fieldCfg := &collCfg.Fields[i]
+		dataType, ok := SupportFieldTypeMap[strings.ToLower(fieldCfg.Type)]
+		if !ok {
+			return nil, fmt.Errorf("files collection %s field %s type %s not support", collCfg.Collection, fieldCfg.Name, fieldCfg.Type)
+		}
+		if names[fieldCfg.Name] {
+			return nil, fmt.Errorf("files collection %s field name %s duplicated", collCfg.Collection, fieldCfg.Name)
+		}
+		names[fieldCfg.Name] = true
+		field := &entity.Field{Name: fieldCfg.Name, DataType: dataType, PrimaryKey: fieldCfg.PK, TypeParams: make(map[string]string)}
+		switch dataType {
+		case entity.FieldTypeVarChar:
+			maxLen := convert.VarcharMaxLenNum
+			if fieldCfg.MaxLen > 0 {
+				maxLen = fieldCfg.MaxLen
+			}
+			field.TypeParams[entity.TypeParamMaxLength] = strconv.Itoa(maxLen)
+		case entity.FieldTypeFloatVector:
+			if fieldCfg.Dims <= 0 {
+				return nil, fmt.Errorf("files collection %s vector field %s need dims", collCfg.Collection, fieldCfg.Name)
+			}
+			field.TypeParams[entity.TypeParamDim] = strconv.Itoa(fieldCfg.Dims)
+			vectorNum++
+		}
+		if field.PrimaryKey {
+			if dataType != entity.FieldTypeInt64 && dataType != entity.FieldTypeVarChar {
+				return nil, fmt.Errorf("files collection %s pk field %s type should be Int64 or VarChar", collCfg.Collection, field.Name)
+			}
+			field.AutoID = collCfg.MilvusCfg != nil && collCfg.MilvusCfg.AutoId == "true"
+			pkNum++
+		}
+		fields = append(fields, field)
+	}
+	if pkNum != 1 {
+		return nil, fmt.Errorf("files collection %s need exactly one pk field, but %d", collCfg.Collection, pkNum)
+	}
+	if vectorNum == 0 {
+		return nil, fmt.Errorf("files collection %s has no vector, milvus collection need a vector field", collCfg.Collection)
+	}
+	return fields, nil
+}
@@ -0,0 +1,111 @@
+package filesconvert
+
+import (
+	"encoding/json"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/filestype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"testing"
+)
+
+func TestToMilvusFields(t *testing.T) {
+	collCfg := &filestype.CollectionCfg{
+		Collection: "items",
+		Fields: []filestype.FieldCfg{
+			{Name: "id", Type: "VarChar", PK: true, MaxLen: 64},
+			{Name: "vec", Type: "FloatVector", Dims: 4},
+			{Name: "meta", Type: "json"},
+		},
+		MilvusCfg: &milvustype.MilvusCfg{},
+	}
+	fields, err := ToMilvusFields(collCfg)
+	assert.NoError(t, err)
+	assert.Len(t, fields, 3)
+	assert.Equal(t, entity.FieldTypeVarChar, fields[0].DataType)
+	assert.Equal(t, "64", fields[0].TypeParams[entity.TypeParamMaxLength])
+	assert.Equal(t, "4", fields[1].TypeParams[entity.TypeParamDim])
+
+	info, err := ToCollectionInfo(collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "items", info.Param.CollectionName)
+	assert.Len(t, info.Indexes, 1)
+
+	collCfg.Fields[1].Dims = 0
+	_, err = ToMilvusFields(collCfg)
+	assert.Error(t, err)
+	collCfg.Fields[1].Dims = 4
+	collCfg.Fields[0].PK = false
+	_, err = ToMilvusFields(collCfg)
+	assert.Error(t, err)
+	collCfg.Fields[0].PK = true
+	collCfg.Fields[2].Type = "timestamp"
+	_, err = ToMilvusFields(collCfg)
+	assert.Error(t, err)
+}
+
+func TestFileFormat(t *testing.T) {
+	collCfg := &filestype.CollectionCfg{}
+	format, ok := FileFormat(collCfg, "dir/part-0.NDJSON")
+	assert.True(t, ok)
+	assert.Equal(t, common.FormatJSONL, format)
+	_, ok = FileFormat(collCfg, "dir/readme.txt")
+	assert.False(t, ok)
+	collCfg.Format = string(common.FormatCSV)
+	_, ok = FileFormat(collCfg, "dir/part-0.parquet")
+	assert.False(t, ok)
+	format, ok = FileFormat(collCfg, "dir/data.tsv")
+	assert.False(t, ok)
+	assert.Equal(t, common.FormatCSV, format)
+}
+
+func TestToFloatVector(t *testing.T) {
+	for _, v := range []any{
+		[]any{json.Number("1"), json.Number("2")},
+		"[1, 2]",
+		"AACAPwAAAEA=",
+		"AACAPwAAAEA",
+		[]byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0x40},
+		[]float64{1, 2},
+	} {
+		vector, err := ToFloatVector(v, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{1, 2}, vector)
+	}
+	_, err := ToFloatVector("[1, 2, 3]", 2)
+	assert.Error(t, err)
+	_, err = ToFloatVector("not a vector", 2)
+	assert.Error(t, err)
+}
+
+func TestToMilvusColumns(t *testing.T) {
+	fields := []*entity.Field{
+		{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true, AutoID: true},
+		{Name: "age", DataType: entity.FieldTypeInt8},
+		{Name: "score", DataType: entity.FieldTypeFloat},
+		{Name: "ok", DataType: entity.FieldTypeBool},
+		{Name: "meta", DataType: entity.FieldTypeJSON},
+		{Name: "vec", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{entity.TypeParamDim: "2"}},
+	}
+	rows := []Row{
+		{"id": "1", "age": "7", "score": "0.5", "ok": "true", "meta": `{"a":1}`, "vec": "[1,2]"},
+		{"age": json.Number("8"), "vec": []any{json.Number("3"), json.Number("4")}, "meta": map[string]any{"b": json.Number("2")}},
+	}
+	columns, err := ToMilvusColumns(rows, fields, false)
+	assert.NoError(t, err)
+	//autoId pk is not inserted
+	assert.Len(t, columns, 5)
+	assert.Equal(t, []int8{7, 8}, columns[0].(*entity.ColumnInt8).Data())
+	assert.Equal(t, []float32{0.5, 0}, columns[1].(*entity.ColumnFloat).Data())
+	assert.Equal(t, []bool{true, false}, columns[2].(*entity.ColumnBool).Data())
+	assert.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, columns[3].(*entity.ColumnJSONBytes).Data())
+
+	rows[1]["age"] = json.Number("300")
+	_, err = ToMilvusColumns(rows, fields, false)
+	assert.ErrorContains(t, err, "out of range")
+	rows[1]["age"] = "8"
+	delete(rows[1], "vec")
+	_, err = ToMilvusColumns(rows, fields, false)
+	assert.ErrorContains(t, err, "empty")
+}
@@ -0,0 +1,311 @@
+package filesconvert
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Row : a record of the data file, value types are:
+// jsonl: nil, bool, json.Number, string, []any, map[string]any
+// csv: string
+// parquet: nil, bool, int64, float64, string, []byte, []float32, []float64, []any
+type Row map[string]any
+
+// ToMilvusColumns : convert the rows to the columns of fields, autoId pk field is skipped.
+// missing scalar value migrate as zero value (json: {}), the keys not in fields are the dynamic field value when dynamic is true
+func ToMilvusColumns(rows []Row, fields []*entity.Field, dynamic bool) ([]entity.Column, error) {
+	columns := make([]entity.Column, 0, len(fields)+1)
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field.Name] = true
+		if field.PrimaryKey && field.AutoID {
+			continue
+		}
+		column, err := toColumn(field, rows)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		columns = append(columns, column)
+	}
+	if dynamic {
+		column, err := toDynamicColumn(rows, names)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func toColumn(field *entity.Field, rows []Row) (entity.Column, error) {
+	values := make([]any, len(rows))
+	for i, row := range rows {
+		values[i] = row[field.Name]
+		if isEmpty(values[i]) && (field.PrimaryKey || field.DataType == entity.FieldTypeFloatVector) {
+			return nil, fmt.Errorf("row %d value is empty", i)
+		}
+	}
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		data, err := convertValues(values, toBool)
+		return entity.NewColumnBool(field.Name, data), err
+	case entity.FieldTypeInt8:
+		data, err := convertValues(values, toIntN[int8](math.MinInt8, math.MaxInt8))
+		return entity.NewColumnInt8(field.Name, data), err
+	case entity.FieldTypeInt16:
+		data, err := convertValues(values, toIntN[int16](math.MinInt16, math.MaxInt16))
+		return entity.NewColumnInt16(field.Name, data), err
+	case entity.FieldTypeInt32:
+		data, err := convertValues(values, toIntN[int32](math.MinInt32, math.MaxInt32))
+		return entity.NewColumnInt32(field.Name, data), err
+	case entity.FieldTypeInt64:
+		data, err := convertValues(values, toInt64)
+		return entity.NewColumnInt64(field.Name, data), err
+	case entity.FieldTypeFloat:
+		data, err := convertValues(values, func(v any) (float32, error) {
+			f, err := toFloat64(v)
+			return float32(f), err
+		})
+		return entity.NewColumnFloat(field.Name, data), err
+	case entity.FieldTypeDouble:
+		data, err := convertValues(values, toFloat64)
+		return entity.NewColumnDouble(field.Name, data), err
+	case entity.FieldTypeVarChar:
+		data, err := convertValues(values, toString)
+		return entity.NewColumnVarChar(field.Name, data), err
+	case entity.FieldTypeJSON:
+		data, err := convertValues(values, toJSON)
+		return entity.NewColumnJSONBytes(field.Name, data), err
+	case entity.FieldTypeFloatVector:
+		dim, _ := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+		data, err := convertValues(values, func(v any) ([]float32, error) {
+			return ToFloatVector(v, dim)
+		})
+		return entity.NewColumnFloatVector(field.Name, dim, data), err
+	default:
+		return nil, fmt.Errorf("not support milvus type %s", field.DataType.Name())
+	}
+}
+
+func convertValues[T any](values []any, convert func(v any) (T, error)) ([]T, error) {
+	data := make([]T, len(values))
+	for i, v := range values {
+		var err error
+		data[i], err = convert(v)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return data, nil
+}
+
+// isEmpty : nil value, or empty csv cell
+func isEmpty(v any) bool {
+	s, ok := v.(string)
+	return v == nil || (ok && strings.TrimSpace(s) == "")
+}
+
+func toBool(v any) (bool, error) {
+	switch val := v.(type) {
+	case bool:
+		return val, nil
+	case string:
+		if isEmpty(val) {
+			return false, nil
+		}
+		return strconv.ParseBool(strings.TrimSpace(val))
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("value %v is not bool", v)
+}
+
+func toInt64(v any) (int64, error) {
+	switch val := v.(type) {
+	case int64:
+		return val, nil
+	case json.Number:
+		return strconv.ParseInt(val.String(), 10, 64)
+	case float64:
+		if val != math.Trunc(val) {
+			return 0, fmt.Errorf("value %v is not integer", v)
+		}
+		return int64(val), nil
+	case string:
+		if isEmpty(val) {
+			return 0, nil
+		}
+		return strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+	case nil:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("value %v is not integer", v)
+}
+
+func toIntN[T int8 | int16 | int32](min int64, max int64) func(v any) (T, error) {
+	return func(v any) (T, error) {
+		n, err := toInt64(v)
+		if err != nil {
+			return 0, err
+		}
+		if n < min || n > max {
+			return 0, fmt.Errorf("value %d out of range", n)
+		}
+		return T(n), nil
+	}
+}
+
+func toFloat64(v any) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case json.Number:
+		return val.Float64()
+	case string:
+		if isEmpty(val) {
+			return 0, nil
+		}
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
+	case nil:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("value %v is not number", v)
+}
+
+func toString(v any) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case []byte:
+		return string(val), nil
+	case bool, int64, float64:
+		return fmt.Sprint(val), nil
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("value %v is not string", v)
+}
+
+// toJSON : json text of csv/parquet string, or the jsonl value
+func toJSON(v any) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return []byte("{}"), nil
+	case string:
+		return toJSONText([]byte(val))
+	case []byte:
+		return toJSONText(val)
+	}
+	return json.Marshal(v)
+}
+
+func toJSONText(text []byte) ([]byte, error) {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		return []byte("{}"), nil
+	}
+	if !json.Valid(text) {
+		return nil, fmt.Errorf("value %s is not valid json", text)
+	}
+	return text, nil
+}
+
+// ToFloatVector : vector is a json array, json array text, base64 text of the little endian float32 blob,
+// parquet float list or the binary float32 blob
+func ToFloatVector(v any, dim int) ([]float32, error) {
+	var vector []float32
+	var err error
+	switch val := v.(type) {
+	case []float32:
+		vector = val
+	case []float64:
+		vector = make([]float32, len(val))
+		for i, f := range val {
+			vector[i] = float32(f)
+		}
+	case []any:
+		vector, err = convertValues(val, func(v any) (float32, error) {
+			f, err := toFloat64(v)
+			return float32(f), err
+		})
+	case string:
+		vector, err = parseVectorText(strings.TrimSpace(val))
+	case []byte:
+		if len(val) == dim*4 {
+			vector, err = decodeFloat32Blob(val)
+		} else {
+			vector, err = parseVectorText(strings.TrimSpace(string(val)))
+		}
+	default:
+		err = fmt.Errorf("value %v is not vector", v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(vector) != dim {
+		return nil, fmt.Errorf("vector dim %d not match %d", len(vector), dim)
+	}
+	return vector, nil
+}
+
+func parseVectorText(text string) ([]float32, error) {
+	if strings.HasPrefix(text, "[") {
+		var vector []float32
+		err := json.Unmarshal([]byte(text), &vector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector json: %w", err)
+		}
+		return vector, nil
+	}
+	blob, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		blob, err = base64.RawStdEncoding.DecodeString(text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("vector is neither json array nor base64 text: %w", err)
+	}
+	return decodeFloat32Blob(blob)
+}
+
+// decodeFloat32Blob : little endian float32 array, the numpy float32 tobytes() format
+func decodeFloat32Blob(blob []byte) ([]float32, error) {
+	if len(blob)%4 != 0 {
+		return nil, errors.New("float32 vector blob length " + strconv.Itoa(len(blob)) + " is not multiple of 4")
+	}
+	vector := make([]float32, len(blob)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[i*4:]))
+	}
+	return vector, nil
+}
+
+func toDynamicColumn(rows []Row, names map[string]bool) (entity.Column, error) {
+	data := make([][]byte, len(rows))
+	for i, row := range rows {
+		dynamic := make(map[string]any)
+		for key, value := range row {
+			if !names[key] {
+				dynamic[key] = value
+			}
+		}
+		var err error
+		data[i], err = json.Marshal(dynamic)
+		if err != nil {
+			return nil, fmt.Errorf("row %d dynamic field: %w", i, err)
+		}
+	}
+	return entity.NewColumnJSONBytes(common.MILVUS_META_FD, data).WithIsDynamic(true), nil
+}
@@ -0,0 +1,166 @@
+package manifestconvert
+
+import (
+	"errors"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	convert "github.com/zilliztech/milvus-migration/core/transform/common"
+	"github.com/zilliztech/milvus-migration/core/type/manifesttype"
+)
+
+const indexTypeKey = "index_type"
+
+var fieldTypeNames = map[entity.FieldType]string{
+	entity.FieldTypeBool:           "Bool",
+	entity.FieldTypeInt8:           "Int8",
+	entity.FieldTypeInt16:          "Int16",
+	entity.FieldTypeInt32:          "Int32",
+	entity.FieldTypeInt64:          "Int64",
+	entity.FieldTypeFloat:          "Float",
+	entity.FieldTypeDouble:         "Double",
+	entity.FieldTypeString:         "String",
+	entity.FieldTypeVarChar:        "VarChar",
+	entity.FieldTypeArray:          "Array",
+	entity.FieldTypeJSON:           "JSON",
+	entity.FieldTypeBinaryVector:   "BinaryVector",
+	entity.FieldTypeFloatVector:    "FloatVector",
+	entity.FieldTypeFloat16Vector:  "Float16Vector",
+	entity.FieldTypeBFloat16Vector: "BFloat16Vector",
+	entity.FieldTypeSparseVector:   "SparseFloatVector",
+}
+
+// ToManifestCollection : record the target collection schema in manifest, dynamicField means data files have $meta column
+func ToManifestCollection(info *common.CollectionInfo, source string, dynamicField bool) *manifesttype.CollectionCfg {
+	collCfg := &manifesttype.CollectionCfg{
+		Collection:   info.Param.CollectionName,
+		Source:       source,
+		Description:  info.Param.Description,
+		ShardsNum:    info.Param.ShardsNum,
+		DynamicField: dynamicField,
+		PartitionKey: info.PartitionKey,
+		Fields:       make([]*manifesttype.FieldCfg, 0, len(info.Fields)),
+		Indexes:      make(map[string]*manifesttype.IndexCfg),
+		Partitions:   make([]*manifesttype.PartitionCfg, 0),
+	}
+	if info.Param.ConsistencyLevel != nil {
+		for name, level := range convert.ConsistencyLevelMap {
+			if level == *info.Param.ConsistencyLevel {
+				collCfg.ConsistencyLevel = name
+			}
+		}
+	}
+	for _, field := range info.Fields {
+		fieldCfg := &manifesttype.FieldCfg{
+			Name:         field.Name,
+			Type:         fieldTypeNames[field.DataType],
+			PK:           field.PrimaryKey,
+			AutoID:       field.AutoID,
+			PartitionKey: field.IsPartitionKey,
+			Nullable:     field.Nullable,
+			Description:  field.Description,
+			TypeParams:   field.TypeParams,
+		}
+		if field.DataType == entity.FieldTypeArray {
+			fieldCfg.ElementType = fieldTypeNames[field.ElementType]
+		}
+		collCfg.Fields = append(collCfg.Fields, fieldCfg)
+	}
+	for fieldName, index := range info.Indexes {
+		params := index.Params()
+		delete(params, indexTypeKey)
+		collCfg.Indexes[fieldName] = &manifesttype.IndexCfg{IndexType: string(index.IndexType()), Params: params}
+	}
+	return collCfg
+}
+
+// ToCollectionInfo : target collection create by manifest schema, create index and load collection if manifest have indexes
+func ToCollectionInfo(collCfg *manifesttype.CollectionCfg) (*common.CollectionInfo, error) {
+	fields, err := ToMilvusFields(collCfg.Fields)
+	if err != nil {
+		return nil, err
+	}
+	param := &common.CollectionParam{
+		CollectionName:     collCfg.Collection,
+		ShardsNum:          collCfg.ShardsNum,
+		EnableDynamicField: collCfg.DynamicField,
+		Description:        collCfg.Description,
+		CreateIndex:        len(collCfg.Indexes) > 0,
+		LoadData:           len(collCfg.Indexes) > 0,
+	}
+	if param.ShardsNum <= 0 {
+		param.ShardsNum = common.DEF_SHARD_NUM
+	}
+	for _, field := range fields {
+		if field.PrimaryKey {
+			param.AutoId = field.AutoID
+		}
+	}
+	if collCfg.ConsistencyLevel != "" {
+		level, ok := convert.ConsistencyLevelMap[collCfg.ConsistencyLevel]
+		if !ok {
+			return nil, errors.New("manifest consistencyLevel value invalid: " + collCfg.ConsistencyLevel)
+		}
+		param.ConsistencyLevel = &level
+	}
+	var partitions []*entity.Partition
+	if collCfg.PartitionKey == "" {
+		for _, partition := range collCfg.Partitions {
+			partitions = append(partitions, &entity.Partition{Name: partition.Name})
+		}
+	}
+	indexes := make(map[string]entity.Index)
+	for fieldName, index := range collCfg.Indexes {
+		indexes[fieldName] = entity.NewGenericIndex(common.EMPTY, entity.IndexType(index.IndexType), index.Params)
+	}
+	return &common.CollectionInfo{Param: param, Fields: fields, Partitions: partitions,
+		PartitionKey: collCfg.PartitionKey, Indexes: indexes}, nil
+}
+
+func ToMilvusFields(fieldCfgs []*manifesttype.FieldCfg) ([]*entity.Field, error) {
+	fields := make([]*entity.Field, 0, len(fieldCfgs))
+	for _, fieldCfg := range fieldCfgs {
+		dataType, err := toFieldType(fieldCfg.Type)
+		if err != nil {
+			return nil, err
+		}
+		field := &entity.Field{
+			Name:           fieldCfg.Name,
+			DataType:       dataType,
+			PrimaryKey:     fieldCfg.PK,
+			AutoID:         fieldCfg.AutoID,
+			IsPartitionKey: fieldCfg.PartitionKey,
+			Nullable:       fieldCfg.Nullable,
+			Description:    fieldCfg.Description,
+			TypeParams:     fieldCfg.TypeParams,
+		}
+		if dataType == entity.FieldTypeArray {
+			field.ElementType, err = toFieldType(fieldCfg.ElementType)
+			if err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func toFieldType(name string) (entity.FieldType, error) {
+	for fieldType, typeName := range fieldTypeNames {
+		if typeName == name {
+			return fieldType, nil
+		}
+	}
+	return entity.FieldTypeNone, errors.New("manifest not support field type " + name)
+}
+
+// DataFields : the fields exported to data files, autoId pk value generate by milvus, not exported
+func DataFields(fields []*entity.Field) []*entity.Field {
+	dataFields := make([]*entity.Field, 0, len(fields))
+	for _, field := range fields {
+		if field.PrimaryKey && field.AutoID {
+			continue
+		}
+		dataFields = append(dataFields, field)
+	}
+	return dataFields
+}
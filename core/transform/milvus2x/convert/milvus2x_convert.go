@@ -56,6 +56,8 @@ func ToMilvusParam(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, mil
 		//AutoId:             collCfg.MilvusCfg.AutoId,
 		//Description:        "Migration from Milvus2x",
 		Description: Description,
+		CreateIndex: collCfg.MilvusCfg.CreateIndex,
+		LoadData:    collCfg.MilvusCfg.LoadData,
 	}
 	if collCfg.MilvusCfg.AutoId == "true" {
 		param.AutoId = true
@@ -67,7 +69,42 @@ func ToMilvusParam(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, mil
 		return nil, err
 	}
 
-	return &common.CollectionInfo{Param: param, Fields: fields, Partitions: partitions, PartitionKey: partitionKey}, err
+	var indexes map[string]entity.Index
+	if collCfg.MilvusCfg.CreateIndex {
+		indexes, err = ToMilvusIndexes(ctx, collCfg, fields, milvus2xCli)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &common.CollectionInfo{Param: param, Fields: fields, Partitions: partitions, PartitionKey: partitionKey,
+		Indexes: indexes}, err
+}
+
+// ToMilvusIndexes : copy the source collection field index, if source vector field not have index will use AUTOINDEX
+func ToMilvusIndexes(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, fields []*entity.Field,
+	milvus2xCli *milvus2x.Milvus2xClient) (map[string]entity.Index, error) {
+
+	indexes := make(map[string]entity.Index)
+	for _, field := range fields {
+		srcIndexes, err := milvus2xCli.VerCli.DescIndex(ctx, collCfg.Collection, field.Name)
+		if err != nil {
+			//source field not have index will return err
+			log.Warn("milvus2x source collection field describe index fail", zap.String("Collection", collCfg.Collection),
+				zap.String("Field", field.Name), zap.Error(err))
+		}
+		if len(srcIndexes) > 0 {
+			indexes[field.Name] = srcIndexes[0]
+		} else if convert.IsVectorField(field) {
+			index, err := convert.ToMilvusIndex(field, nil)
+			if err != nil {
+				return nil, err
+			}
+			indexes[field.Name] = index
+		}
+	}
+	log.Info("milvus2x source collection indexes", zap.String("Collection", collCfg.Collection), zap.Any("Indexes", indexes))
+	return indexes, nil
 }
 
 func getPartitionKey(collEntity *entity.Collection) string {
@@ -9,6 +9,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -135,3 +137,56 @@ func shapeString(row int, dim int) string {
 	return fmt.Sprintf("(%s)", strings.Join(str, ", "))
 
 }
+
+var shapeRegexp = regexp.MustCompile(`'shape':\s*\(([^)]*)\)`)
+var descrRegexp = regexp.MustCompile(`'descr':\s*'([^']*)'`)
+
+// ReadNumpyHead : read the numpy head from stream, return the data type(like '<i8') and shape
+func ReadNumpyHead(r io.Reader) (string, []int, error) {
+	magic := make([]byte, len(numpy_magic_head)+2)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return "", nil, err
+	}
+	if !bytes.Equal(magic[:len(numpy_magic_head)], numpy_magic_head) {
+		return "", nil, errors.New("npy: invalid magic head")
+	}
+	var headLen int
+	switch magic[len(numpy_magic_head)] {
+	case 1:
+		var l uint16
+		if err := binary.Read(r, order, &l); err != nil {
+			return "", nil, err
+		}
+		headLen = int(l)
+	case 2:
+		var l uint32
+		if err := binary.Read(r, order, &l); err != nil {
+			return "", nil, err
+		}
+		headLen = int(l)
+	default:
+		return "", nil, fmt.Errorf("npy: invalid major version number (%d)", magic[len(numpy_magic_head)])
+	}
+	head := make([]byte, headLen)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return "", nil, err
+	}
+	descr := descrRegexp.FindSubmatch(head)
+	shape := shapeRegexp.FindSubmatch(head)
+	if descr == nil || shape == nil {
+		return "", nil, fmt.Errorf("npy: invalid head %s", string(head))
+	}
+	var dims []int
+	for _, s := range strings.Split(string(shape[1]), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		dim, err := strconv.Atoi(s)
+		if err != nil {
+			return "", nil, fmt.Errorf("npy: invalid shape %s", string(shape[1]))
+		}
+		dims = append(dims, dim)
+	}
+	return string(descr[1]), dims, nil
+}
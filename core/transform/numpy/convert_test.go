@@ -0,0 +1,24 @@
+package npconvert
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"testing"
+)
+
+func TestReadNumpyHead(t *testing.T) {
+	head, err := ConvertToNumpyHead(common.CMeta{Type: "float32", Row: 10, Dim: 4})
+	assert.NoError(t, err)
+	descr, shape, err := ReadNumpyHead(bytes.NewReader(head))
+	assert.NoError(t, err)
+	assert.Equal(t, "<f4", descr)
+	assert.Equal(t, []int{10, 4}, shape)
+
+	head, err = ConvertToNumpyHead(common.CMeta{Type: "int64", Row: 10})
+	assert.NoError(t, err)
+	descr, shape, err = ReadNumpyHead(bytes.NewReader(head))
+	assert.NoError(t, err)
+	assert.Equal(t, "<i8", descr)
+	assert.Equal(t, []int{10}, shape)
+}
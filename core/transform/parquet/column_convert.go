@@ -0,0 +1,235 @@
+package pqconvert
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"strconv"
+	"strings"
+)
+
+// ToMilvusColumns : parquet/jsonl record to milvus columns, the reverse of ToArrowRecord,
+// the values are copied, the record can be released after return
+func ToMilvusColumns(rec arrow.Record, fields []*entity.Field, enableDynamic bool) ([]entity.Column, error) {
+	columns := make([]entity.Column, 0, len(fields)+1)
+	for _, field := range fields {
+		arr, err := getArray(rec, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		col, err := toColumn(field, arr)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		columns = append(columns, col)
+	}
+	if enableDynamic {
+		arr, err := getArray(rec, common.MILVUS_META_FD)
+		if err != nil {
+			return nil, err
+		}
+		values, err := stringValues(arr, func(v string) []byte { return []byte(v) })
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, entity.NewColumnJSONBytes(common.MILVUS_META_FD, values).WithIsDynamic(true))
+	}
+	return columns, nil
+}
+
+func getArray(rec arrow.Record, name string) (arrow.Array, error) {
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("record miss column %s", name)
+	}
+	return rec.Column(indices[0]), nil
+}
+
+func toColumn(field *entity.Field, arr arrow.Array) (entity.Column, error) {
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		values, err := boolValues(arr)
+		return entity.NewColumnBool(field.Name, values), err
+	case entity.FieldTypeInt8:
+		values, err := primitiveValues[int8](arr)
+		return entity.NewColumnInt8(field.Name, values), err
+	case entity.FieldTypeInt16:
+		values, err := primitiveValues[int16](arr)
+		return entity.NewColumnInt16(field.Name, values), err
+	case entity.FieldTypeInt32:
+		values, err := primitiveValues[int32](arr)
+		return entity.NewColumnInt32(field.Name, values), err
+	case entity.FieldTypeInt64:
+		values, err := primitiveValues[int64](arr)
+		return entity.NewColumnInt64(field.Name, values), err
+	case entity.FieldTypeFloat:
+		values, err := primitiveValues[float32](arr)
+		return entity.NewColumnFloat(field.Name, values), err
+	case entity.FieldTypeDouble:
+		values, err := primitiveValues[float64](arr)
+		return entity.NewColumnDouble(field.Name, values), err
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		values, err := stringValues(arr, strings.Clone)
+		return entity.NewColumnVarChar(field.Name, values), err
+	case entity.FieldTypeJSON:
+		values, err := stringValues(arr, func(v string) []byte { return []byte(v) })
+		return entity.NewColumnJSONBytes(field.Name, values), err
+	case entity.FieldTypeSparseVector:
+		values, err := stringValues(arr, func(v string) string { return v })
+		if err != nil {
+			return nil, err
+		}
+		embeddings := make([]entity.SparseEmbedding, 0, len(values))
+		for _, v := range values {
+			embedding, err := parseSparseJSON(v)
+			if err != nil {
+				return nil, err
+			}
+			embeddings = append(embeddings, embedding)
+		}
+		return entity.NewColumnSparseVectors(field.Name, embeddings), nil
+	case entity.FieldTypeFloatVector:
+		values, err := listValues(arr, primitiveValues[float32])
+		return entity.NewColumnFloatVector(field.Name, vectorDim(field, values, 1, 1), values), err
+	case entity.FieldTypeBinaryVector:
+		values, err := listValues(arr, primitiveValues[uint8])
+		return entity.NewColumnBinaryVector(field.Name, vectorDim(field, values, 8, 1), values), err
+	case entity.FieldTypeFloat16Vector:
+		values, err := listValues(arr, primitiveValues[uint8])
+		return entity.NewColumnFloat16Vector(field.Name, vectorDim(field, values, 1, 2), values), err
+	case entity.FieldTypeBFloat16Vector:
+		values, err := listValues(arr, primitiveValues[uint8])
+		return entity.NewColumnBFloat16Vector(field.Name, vectorDim(field, values, 1, 2), values), err
+	case entity.FieldTypeArray:
+		return toArrayColumn(field, arr)
+	default:
+		return nil, fmt.Errorf("not support milvus field type %s", field.DataType.Name())
+	}
+}
+
+func toArrayColumn(field *entity.Field, arr arrow.Array) (entity.Column, error) {
+	switch field.ElementType {
+	case entity.FieldTypeBool:
+		values, err := listValues(arr, boolValues)
+		return entity.NewColumnBoolArray(field.Name, values), err
+	case entity.FieldTypeInt8:
+		values, err := listValues(arr, primitiveValues[int8])
+		return entity.NewColumnInt8Array(field.Name, values), err
+	case entity.FieldTypeInt16:
+		values, err := listValues(arr, primitiveValues[int16])
+		return entity.NewColumnInt16Array(field.Name, values), err
+	case entity.FieldTypeInt32:
+		values, err := listValues(arr, primitiveValues[int32])
+		return entity.NewColumnInt32Array(field.Name, values), err
+	case entity.FieldTypeInt64:
+		values, err := listValues(arr, primitiveValues[int64])
+		return entity.NewColumnInt64Array(field.Name, values), err
+	case entity.FieldTypeFloat:
+		values, err := listValues(arr, primitiveValues[float32])
+		return entity.NewColumnFloatArray(field.Name, values), err
+	case entity.FieldTypeDouble:
+		values, err := listValues(arr, primitiveValues[float64])
+		return entity.NewColumnDoubleArray(field.Name, values), err
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		values, err := listValues(arr, func(a arrow.Array) ([][]byte, error) {
+			return stringValues(a, func(v string) []byte { return []byte(v) })
+		})
+		return entity.NewColumnVarCharArray(field.Name, values), err
+	default:
+		return nil, fmt.Errorf("not support milvus array element type %s", field.ElementType.Name())
+	}
+}
+
+func boolValues(arr arrow.Array) ([]bool, error) {
+	typed, ok := arr.(*array.Boolean)
+	if !ok {
+		return nil, fmt.Errorf("expect bool but %s", arr.DataType())
+	}
+	values := make([]bool, typed.Len())
+	for i := range values {
+		values[i] = typed.Value(i)
+	}
+	return values, nil
+}
+
+func primitiveValues[T int8 | int16 | int32 | int64 | uint8 | float32 | float64](arr arrow.Array) ([]T, error) {
+	var src any
+	switch typed := arr.(type) {
+	case *array.Int8:
+		src = typed.Int8Values()
+	case *array.Int16:
+		src = typed.Int16Values()
+	case *array.Int32:
+		src = typed.Int32Values()
+	case *array.Int64:
+		src = typed.Int64Values()
+	case *array.Uint8:
+		src = typed.Uint8Values()
+	case *array.Float32:
+		src = typed.Float32Values()
+	case *array.Float64:
+		src = typed.Float64Values()
+	}
+	typedValues, ok := src.([]T)
+	if !ok {
+		return nil, fmt.Errorf("expect %T but %s", *new(T), arr.DataType())
+	}
+	values := make([]T, len(typedValues))
+	copy(values, typedValues)
+	return values, nil
+}
+
+func stringValues[T any](arr arrow.Array, convert func(v string) T) ([]T, error) {
+	typed, ok := arr.(*array.String)
+	if !ok {
+		return nil, fmt.Errorf("expect string but %s", arr.DataType())
+	}
+	values := make([]T, typed.Len())
+	for i := range values {
+		values[i] = convert(typed.Value(i))
+	}
+	return values, nil
+}
+
+func listValues[T any](arr arrow.Array, elemValues func(arr arrow.Array) ([]T, error)) ([][]T, error) {
+	typed, ok := arr.(*array.List)
+	if !ok {
+		return nil, fmt.Errorf("expect list but %s", arr.DataType())
+	}
+	elems, err := elemValues(typed.ListValues())
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]T, typed.Len())
+	for i := range values {
+		start, end := typed.ValueOffsets(i)
+		values[i] = elems[start:end]
+	}
+	return values, nil
+}
+
+// vectorDim : use the field dim param, if not set calc by the first vector length, binary vector is len*8, float16 is len/2
+func vectorDim[T any](field *entity.Field, values [][]T, mul int, div int) int {
+	if dim, err := strconv.Atoi(field.TypeParams[entity.TypeParamDim]); err == nil {
+		return dim
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	return len(values[0]) * mul / div
+}
+
+func parseSparseJSON(v string) (entity.SparseEmbedding, error) {
+	var sparse struct {
+		Indices []uint32  `json:"indices"`
+		Values  []float32 `json:"values"`
+	}
+	err := json.Unmarshal([]byte(v), &sparse)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sparse vector %s: %w", v, err)
+	}
+	return entity.NewSliceSparseEmbedding(sparse.Indices, sparse.Values)
+}
@@ -0,0 +1,179 @@
+package pqconvert
+
+import (
+	"fmt"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"strconv"
+	"strings"
+)
+
+// ToArrowSchema : milvus collection fields to parquet arrow schema, column type is the milvus bulkInsert parquet format:
+// json/sparse vector is json string, vector is list, binary/float16/bfloat16 vector is list of uint8 bytes
+func ToArrowSchema(fields []*entity.Field, enableDynamic bool) (*arrow.Schema, error) {
+	arrowFields := make([]arrow.Field, 0, len(fields)+1)
+	for _, field := range fields {
+		dataType, err := toArrowType(field.DataType, field.ElementType)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		arrowFields = append(arrowFields, arrow.Field{Name: field.Name, Type: dataType})
+	}
+	if enableDynamic {
+		arrowFields = append(arrowFields, arrow.Field{Name: common.MILVUS_META_FD, Type: arrow.BinaryTypes.String})
+	}
+	return arrow.NewSchema(arrowFields, nil), nil
+}
+
+func toArrowType(dataType entity.FieldType, elementType entity.FieldType) (arrow.DataType, error) {
+	switch dataType {
+	case entity.FieldTypeBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case entity.FieldTypeInt8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case entity.FieldTypeInt16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case entity.FieldTypeInt32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case entity.FieldTypeInt64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case entity.FieldTypeFloat:
+		return arrow.PrimitiveTypes.Float32, nil
+	case entity.FieldTypeDouble:
+		return arrow.PrimitiveTypes.Float64, nil
+	case entity.FieldTypeVarChar, entity.FieldTypeString, entity.FieldTypeJSON, entity.FieldTypeSparseVector:
+		return arrow.BinaryTypes.String, nil
+	case entity.FieldTypeFloatVector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Float32), nil
+	case entity.FieldTypeBinaryVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Uint8), nil
+	case entity.FieldTypeArray:
+		if elementType == entity.FieldTypeArray {
+			return nil, fmt.Errorf("parquet not support nested array")
+		}
+		elemType, err := toArrowType(elementType, entity.FieldTypeNone)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elemType), nil
+	default:
+		return nil, fmt.Errorf("parquet not support milvus field type %s", dataType.Name())
+	}
+}
+
+// ToArrowRecord : the columns order is the same as the schema fields, dynamic column match the $meta field.
+// caller need release the record
+func ToArrowRecord(schema *arrow.Schema, columns []entity.Column) (arrow.Record, error) {
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	colMap := make(map[string]entity.Column, len(columns))
+	for _, col := range columns {
+		if jsonCol, ok := col.(*entity.ColumnJSONBytes); ok && jsonCol.IsDynamic() {
+			colMap[common.MILVUS_META_FD] = col
+		} else {
+			colMap[col.Name()] = col
+		}
+	}
+	for i, field := range schema.Fields() {
+		col, ok := colMap[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("parquet record miss column %s", field.Name)
+		}
+		err := appendColumn(builder.Field(i), col)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+	}
+	return builder.NewRecord(), nil
+}
+
+func appendColumn(b array.Builder, column entity.Column) error {
+	switch col := column.(type) {
+	case *entity.ColumnBool:
+		b.(*array.BooleanBuilder).AppendValues(col.Data(), nil)
+	case *entity.ColumnInt8:
+		b.(*array.Int8Builder).AppendValues(col.Data(), nil)
+	case *entity.ColumnInt16:
+		b.(*array.Int16Builder).AppendValues(col.Data(), nil)
+	case *entity.ColumnInt32:
+		b.(*array.Int32Builder).AppendValues(col.Data(), nil)
+	case *entity.ColumnInt64:
+		b.(*array.Int64Builder).AppendValues(col.Data(), nil)
+	case *entity.ColumnFloat:
+		b.(*array.Float32Builder).AppendValues(col.Data(), nil)
+	case *entity.ColumnDouble:
+		b.(*array.Float64Builder).AppendValues(col.Data(), nil)
+	case *entity.ColumnVarChar:
+		b.(*array.StringBuilder).AppendValues(col.Data(), nil)
+	case *entity.ColumnString:
+		b.(*array.StringBuilder).AppendValues(col.Data(), nil)
+	case *entity.ColumnJSONBytes:
+		sb := b.(*array.StringBuilder)
+		for _, v := range col.Data() {
+			sb.Append(string(v))
+		}
+	case *entity.ColumnSparseFloatVector:
+		sb := b.(*array.StringBuilder)
+		for _, v := range col.Data() {
+			sb.Append(toSparseJSON(v))
+		}
+	case *entity.ColumnFloatVector:
+		appendList(b, col.Data(), func(vb array.Builder, v []float32) { vb.(*array.Float32Builder).AppendValues(v, nil) })
+	case *entity.ColumnBinaryVector:
+		appendList(b, col.Data(), appendBytes)
+	case *entity.ColumnFloat16Vector:
+		appendList(b, col.Data(), appendBytes)
+	case *entity.ColumnBFloat16Vector:
+		appendList(b, col.Data(), appendBytes)
+	case *entity.ColumnBoolArray:
+		appendList(b, col.Data(), func(vb array.Builder, v []bool) { vb.(*array.BooleanBuilder).AppendValues(v, nil) })
+	case *entity.ColumnInt8Array:
+		appendList(b, col.Data(), func(vb array.Builder, v []int8) { vb.(*array.Int8Builder).AppendValues(v, nil) })
+	case *entity.ColumnInt16Array:
+		appendList(b, col.Data(), func(vb array.Builder, v []int16) { vb.(*array.Int16Builder).AppendValues(v, nil) })
+	case *entity.ColumnInt32Array:
+		appendList(b, col.Data(), func(vb array.Builder, v []int32) { vb.(*array.Int32Builder).AppendValues(v, nil) })
+	case *entity.ColumnInt64Array:
+		appendList(b, col.Data(), func(vb array.Builder, v []int64) { vb.(*array.Int64Builder).AppendValues(v, nil) })
+	case *entity.ColumnFloatArray:
+		appendList(b, col.Data(), func(vb array.Builder, v []float32) { vb.(*array.Float32Builder).AppendValues(v, nil) })
+	case *entity.ColumnDoubleArray:
+		appendList(b, col.Data(), func(vb array.Builder, v []float64) { vb.(*array.Float64Builder).AppendValues(v, nil) })
+	case *entity.ColumnVarCharArray:
+		appendList(b, col.Data(), func(vb array.Builder, v [][]byte) {
+			for _, s := range v {
+				vb.(*array.StringBuilder).Append(string(s))
+			}
+		})
+	default:
+		return fmt.Errorf("parquet not support column type %T", column)
+	}
+	return nil
+}
+
+func appendList[T any](b array.Builder, data []T, appendValues func(vb array.Builder, v T)) {
+	lb := b.(*array.ListBuilder)
+	for _, v := range data {
+		lb.Append(true)
+		appendValues(lb.ValueBuilder(), v)
+	}
+}
+
+func appendBytes(vb array.Builder, v []byte) {
+	vb.(*array.Uint8Builder).AppendValues(v, nil)
+}
+
+// toSparseJSON : sparse vector to {"indices":[...],"values":[...]}
+func toSparseJSON(v entity.SparseEmbedding) string {
+	indices := make([]string, 0, v.Len())
+	values := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		idx, val, _ := v.Get(i)
+		indices = append(indices, strconv.FormatUint(uint64(idx), 10))
+		values = append(values, strconv.FormatFloat(float64(val), 'g', -1, 32))
+	}
+	return `{"indices":[` + strings.Join(indices, ",") + `],"values":[` + strings.Join(values, ",") + `]}`
+}
@@ -0,0 +1,105 @@
+package pqconvert
+
+import (
+	"bytes"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToArrowRecord(t *testing.T) {
+	fields := []*entity.Field{
+		{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true},
+		{Name: "title", DataType: entity.FieldTypeVarChar},
+		{Name: "meta", DataType: entity.FieldTypeJSON},
+		{Name: "tags", DataType: entity.FieldTypeArray, ElementType: entity.FieldTypeVarChar},
+		{Name: "vec", DataType: entity.FieldTypeFloatVector},
+		{Name: "fp16", DataType: entity.FieldTypeFloat16Vector},
+		{Name: "sparse", DataType: entity.FieldTypeSparseVector},
+	}
+	schema, err := ToArrowSchema(fields, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, len(schema.Fields()))
+	assert.Equal(t, "$meta", schema.Field(7).Name)
+	assert.Equal(t, arrow.LIST, schema.Field(5).Type.ID())
+
+	sparse, err := entity.NewSliceSparseEmbedding([]uint32{1, 5}, []float32{0.5, 1.5})
+	assert.NoError(t, err)
+	columns := []entity.Column{
+		entity.NewColumnInt64("id", []int64{1}),
+		entity.NewColumnVarChar("title", []string{"t"}),
+		entity.NewColumnJSONBytes("meta", [][]byte{[]byte(`{"a":1}`)}),
+		entity.NewColumnVarCharArray("tags", [][][]byte{{[]byte("x"), []byte("y")}}),
+		entity.NewColumnFloatVector("vec", 2, [][]float32{{0.1, 0.2}}),
+		entity.NewColumnFloat16Vector("fp16", 2, [][]byte{{1, 2, 3, 4}}),
+		entity.NewColumnSparseVectors("sparse", []entity.SparseEmbedding{sparse}),
+		entity.NewColumnJSONBytes("", [][]byte{[]byte(`{"extra":true}`)}).WithIsDynamic(true),
+	}
+	rec, err := ToArrowRecord(schema, columns)
+	assert.NoError(t, err)
+	defer rec.Release()
+	assert.Equal(t, int64(1), rec.NumRows())
+	assert.Equal(t, `{"indices":[1,5],"values":[0.5,1.5]}`, rec.Column(6).(*array.String).Value(0))
+	assert.Equal(t, `{"extra":true}`, rec.Column(7).(*array.String).Value(0))
+	assert.Equal(t, 4, rec.Column(5).(*array.List).ListValues().Len())
+
+	_, err = ToArrowRecord(schema, columns[:7])
+	assert.Error(t, err)
+}
+
+func TestToMilvusColumns(t *testing.T) {
+	fields := []*entity.Field{
+		{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true},
+		{Name: "title", DataType: entity.FieldTypeVarChar},
+		{Name: "tags", DataType: entity.FieldTypeArray, ElementType: entity.FieldTypeInt32},
+		{Name: "vec", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{entity.TypeParamDim: "2"}},
+		{Name: "fp16", DataType: entity.FieldTypeFloat16Vector},
+		{Name: "sparse", DataType: entity.FieldTypeSparseVector},
+	}
+	schema, err := ToArrowSchema(fields, true)
+	assert.NoError(t, err)
+	sparse, err := entity.NewSliceSparseEmbedding([]uint32{1, 5}, []float32{0.5, 1.5})
+	assert.NoError(t, err)
+	columns := []entity.Column{
+		entity.NewColumnInt64("id", []int64{1, 2}),
+		entity.NewColumnVarChar("title", []string{"a", "b"}),
+		entity.NewColumnInt32Array("tags", [][]int32{{1, 2}, {3}}),
+		entity.NewColumnFloatVector("vec", 2, [][]float32{{0.1, 0.2}, {0.3, 0.4}}),
+		entity.NewColumnFloat16Vector("fp16", 2, [][]byte{{1, 2, 3, 4}, {5, 6, 7, 8}}),
+		entity.NewColumnSparseVectors("sparse", []entity.SparseEmbedding{sparse, sparse}),
+		entity.NewColumnJSONBytes("", [][]byte{[]byte(`{"x":1}`), []byte(`{}`)}).WithIsDynamic(true),
+	}
+	rec, err := ToArrowRecord(schema, columns)
+	assert.NoError(t, err)
+	defer rec.Release()
+
+	//jsonl round trip
+	var buf bytes.Buffer
+	assert.NoError(t, array.RecordToJSON(rec, &buf))
+	rdr := array.NewJSONReader(&buf, schema, array.WithChunk(10))
+	defer rdr.Release()
+	assert.True(t, rdr.Next())
+
+	for _, r := range []arrow.Record{rec, rdr.Record()} {
+		result, err := ToMilvusColumns(r, fields, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, len(result))
+		assert.Equal(t, []int64{1, 2}, result[0].(*entity.ColumnInt64).Data())
+		assert.Equal(t, []string{"a", "b"}, result[1].(*entity.ColumnVarChar).Data())
+		assert.Equal(t, [][]int32{{1, 2}, {3}}, result[2].(*entity.ColumnInt32Array).Data())
+		assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, result[3].(*entity.ColumnFloatVector).Data())
+		assert.Equal(t, 2, result[4].(*entity.ColumnFloat16Vector).Dim())
+		assert.Equal(t, []byte{5, 6, 7, 8}, result[4].(*entity.ColumnFloat16Vector).Data()[1])
+		pos, v, ok := result[5].(*entity.ColumnSparseFloatVector).Data()[1].Get(1)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(5), pos)
+		assert.Equal(t, float32(1.5), v)
+		assert.True(t, result[6].(*entity.ColumnJSONBytes).IsDynamic())
+		assert.Equal(t, `{"x":1}`, string(result[6].(*entity.ColumnJSONBytes).Data()[0]))
+	}
+
+	_, err = ToMilvusColumns(rec, append(fields, &entity.Field{Name: "miss", DataType: entity.FieldTypeBool}), true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package pgvectorconvert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	convert "github.com/zilliztech/milvus-migration/core/transform/common"
+	"github.com/zilliztech/milvus-migration/core/type/pgvectortype"
+	"strconv"
+)
+
+// uuid text length
+const uuidLen = 36
+
+// pgvector types
+const (
+	Vector    = "vector"
+	HalfVec   = "halfvec"
+	SparseVec = "sparsevec"
+	Bit       = "bit"
+)
+
+// SupportPgTypeMap : key is pg_type.typname, date/time, uuid and network types migrate as their text value
+var SupportPgTypeMap = map[string]entity.FieldType{
+	"bool":        entity.FieldTypeBool,
+	"int2":        entity.FieldTypeInt16,
+	"int4":        entity.FieldTypeInt32,
+	"int8":        entity.FieldTypeInt64,
+	"float4":      entity.FieldTypeFloat,
+	"float8":      entity.FieldTypeDouble,
+	"numeric":     entity.FieldTypeDouble,
+	"text":        entity.FieldTypeVarChar,
+	"varchar":     entity.FieldTypeVarChar,
+	"bpchar":      entity.FieldTypeVarChar,
+	"name":        entity.FieldTypeVarChar,
+	"citext":      entity.FieldTypeVarChar,
+	"uuid":        entity.FieldTypeVarChar,
+	"date":        entity.FieldTypeVarChar,
+	"time":        entity.FieldTypeVarChar,
+	"timetz":      entity.FieldTypeVarChar,
+	"timestamp":   entity.FieldTypeVarChar,
+	"timestamptz": entity.FieldTypeVarChar,
+	"interval":    entity.FieldTypeVarChar,
+	"inet":        entity.FieldTypeVarChar,
+	"cidr":        entity.FieldTypeVarChar,
+	"macaddr":     entity.FieldTypeVarChar,
+	"json":        entity.FieldTypeJSON,
+	"jsonb":       entity.FieldTypeJSON,
+	Vector:        entity.FieldTypeFloatVector,
+	HalfVec:       entity.FieldTypeFloat16Vector,
+	SparseVec:     entity.FieldTypeSparseVector,
+	Bit:           entity.FieldTypeBinaryVector,
+}
+
+// SupportPgArrayElemMap : key is the array element typname, array type typname is '_' + element typname
+var SupportPgArrayElemMap = map[string]entity.FieldType{
+	"bool":    entity.FieldTypeBool,
+	"int2":    entity.FieldTypeInt16,
+	"int4":    entity.FieldTypeInt32,
+	"int8":    entity.FieldTypeInt64,
+	"float4":  entity.FieldTypeFloat,
+	"float8":  entity.FieldTypeDouble,
+	"text":    entity.FieldTypeVarChar,
+	"varchar": entity.FieldTypeVarChar,
+}
+
+// IsSupported : the column type can migrate to milvus
+func IsSupported(col *pgvectortype.Column) bool {
+	if col.ElemType != "" {
+		_, ok := SupportPgArrayElemMap[col.ElemType]
+		return ok
+	}
+	_, ok := SupportPgTypeMap[col.TypeName]
+	return ok
+}
+
+// IsDimType : pgvector types, the dim is the type modifier
+func IsDimType(typeName string) bool {
+	return typeName == Vector || typeName == HalfVec || typeName == SparseVec || typeName == Bit
+}
+
+func ToCollectionName(tableCfg *pgvectortype.TableCfg) string {
+	if tableCfg.MilvusCfg != nil && tableCfg.MilvusCfg.Collection != "" {
+		return tableCfg.MilvusCfg.Collection
+	}
+	_, name := tableCfg.SchemaAndName()
+	return name
+}
+
+// ToCollectionInfo : the target collection of the table, vector field will use AUTOINDEX if not config index
+func ToCollectionInfo(tableCfg *pgvectortype.TableCfg) (*common.CollectionInfo, error) {
+	fields, err := ToMilvusFields(tableCfg)
+	if err != nil {
+		return nil, err
+	}
+	milvusCfg := tableCfg.MilvusCfg
+	param := &common.CollectionParam{
+		CollectionName:     ToCollectionName(tableCfg),
+		ShardsNum:          milvusCfg.ShardNum,
+		EnableDynamicField: !milvusCfg.CloseDynamicField,
+		AutoId:             milvusCfg.AutoId == "true",
+		Description:        "Migration from pgvector table " + tableCfg.Table,
+		CreateIndex:        milvusCfg.CreateIndex,
+		LoadData:           milvusCfg.LoadData,
+	}
+	if param.ShardsNum <= 0 {
+		param.ShardsNum = common.DEF_SHARD_NUM
+	}
+	if len(milvusCfg.ConsistencyLevel) > 0 {
+		level, ok := convert.ConsistencyLevelMap[milvusCfg.ConsistencyLevel]
+		if !ok {
+			return nil, errors.New("pgvector transform to milvus consistencyLevel value invalid: " + milvusCfg.ConsistencyLevel)
+		}
+		param.ConsistencyLevel = &level
+	}
+	indexes := make(map[string]entity.Index)
+	for _, field := range fields {
+		var fieldCfg = tableCfg.GetField(field.Name)
+		if (fieldCfg == nil || fieldCfg.Index == nil) && !convert.IsVectorField(field) {
+			continue
+		}
+		var index entity.Index
+		if fieldCfg != nil {
+			index, err = convert.ToMilvusIndex(field, fieldCfg.Index)
+		} else {
+			index, err = convert.ToMilvusIndex(field, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		indexes[field.Name] = index
+	}
+	return &common.CollectionInfo{Param: param, Fields: fields, Indexes: indexes}, nil
+}
+
+// ToMilvusFields : milvus fields of the table InnerColumns, the pk column as the milvus primary key
+func ToMilvusFields(tableCfg *pgvectortype.TableCfg) ([]*entity.Field, error) {
+	autoId := tableCfg.MilvusCfg != nil && tableCfg.MilvusCfg.AutoId == "true"
+	fields := make([]*entity.Field, 0, len(tableCfg.InnerColumns))
+	hasVector := false
+	for _, col := range tableCfg.InnerColumns {
+		field, err := ToMilvusField(col, tableCfg.GetField(col.Name))
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", tableCfg.Table, err)
+		}
+		if col.PK {
+			field.PrimaryKey = true
+			field.AutoID = autoId
+		}
+		hasVector = hasVector || convert.IsVectorField(field)
+		fields = append(fields, field)
+	}
+	if !hasVector {
+		return nil, fmt.Errorf("table %s has no vector, halfvec, sparsevec or bit column, milvus collection need a vector field", tableCfg.Table)
+	}
+	return fields, nil
+}
+
+func ToMilvusField(col *pgvectortype.Column, fieldCfg *pgvectortype.FieldCfg) (*entity.Field, error) {
+	field := &entity.Field{Name: col.Name, TypeParams: make(map[string]string)}
+	if col.ElemType != "" {
+		elemType, ok := SupportPgArrayElemMap[col.ElemType]
+		if !ok {
+			return nil, fmt.Errorf("column %s array element type %s not support", col.Name, col.ElemType)
+		}
+		maxCapacity := convert.MaxArrayCapacity
+		if fieldCfg != nil && fieldCfg.MaxCapacity > 0 {
+			maxCapacity = fieldCfg.MaxCapacity
+		}
+		field.DataType = entity.FieldTypeArray
+		field.ElementType = elemType
+		field.TypeParams[entity.TypeParamMaxCapacity] = strconv.Itoa(maxCapacity)
+		if elemType == entity.FieldTypeVarChar {
+			field.TypeParams[entity.TypeParamMaxLength] = strconv.Itoa(varcharMaxLen(col, fieldCfg))
+		}
+		return field, nil
+	}
+	dataType, ok := SupportPgTypeMap[col.TypeName]
+	if !ok {
+		return nil, fmt.Errorf("column %s type %s not support", col.Name, col.TypeName)
+	}
+	field.DataType = dataType
+	switch dataType {
+	case entity.FieldTypeFloatVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBinaryVector:
+		if col.TypeMod <= 0 {
+			return nil, fmt.Errorf("column %s %s dim unknown", col.Name, col.TypeName)
+		}
+		if dataType == entity.FieldTypeBinaryVector && col.TypeMod%8 != 0 {
+			return nil, fmt.Errorf("column %s bit length %d need be a multiple of 8", col.Name, col.TypeMod)
+		}
+		field.TypeParams[entity.TypeParamDim] = strconv.Itoa(col.TypeMod)
+	case entity.FieldTypeVarChar:
+		field.TypeParams[entity.TypeParamMaxLength] = strconv.Itoa(varcharMaxLen(col, fieldCfg))
+	}
+	//milvus int primary key only support int64
+	if col.PK {
+		switch dataType {
+		case entity.FieldTypeInt16, entity.FieldTypeInt32, entity.FieldTypeInt64:
+			field.DataType = entity.FieldTypeInt64
+		case entity.FieldTypeVarChar:
+		default:
+			return nil, fmt.Errorf("pk column %s type %s not support, milvus primary key need int or varchar", col.Name, col.TypeName)
+		}
+	}
+	return field, nil
+}
+
+// varcharMaxLen : config maxLen first, then varchar(n) length, default 65535
+func varcharMaxLen(col *pgvectortype.Column, fieldCfg *pgvectortype.FieldCfg) int {
+	if fieldCfg != nil && fieldCfg.MaxLen > 0 {
+		return fieldCfg.MaxLen
+	}
+	if col.TypeName == "uuid" {
+		return uuidLen
+	}
+	//varchar and bpchar type modifier is the declared length + 4
+	if (col.TypeName == "varchar" || col.TypeName == "bpchar" || col.ElemType == "varchar") && col.TypeMod > 4 {
+		return col.TypeMod - 4
+	}
+	return convert.VarcharMaxLenNum
+}
+
+// SelectExpr : the select expression of the column, the value not have go native type read as its text
+func SelectExpr(col *pgvectortype.Column) string {
+	name := pq.QuoteIdentifier(col.Name)
+	if col.ElemType != "" {
+		return "array_to_json(" + name + ")::text"
+	}
+	switch col.TypeName {
+	case "bool", "int2", "int4", "int8", "float4", "float8", "text", "varchar":
+		return name
+	case "numeric":
+		return name + "::float8"
+	default:
+		return name + "::text"
+	}
+}
@@ -0,0 +1,104 @@
+package pgvectorconvert
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/core/type/pgvectortype"
+	"testing"
+)
+
+func TestToMilvusFields(t *testing.T) {
+	tableCfg := &pgvectortype.TableCfg{
+		Table:     "public.items",
+		Fields:    []pgvectortype.FieldCfg{{Name: "tags", MaxCapacity: 16}},
+		MilvusCfg: &milvustype.MilvusCfg{},
+		InnerColumns: []*pgvectortype.Column{
+			{Name: "id", TypeName: "int4", TypeMod: -1, PK: true},
+			{Name: "title", TypeName: "varchar", TypeMod: 68},
+			{Name: "price", TypeName: "numeric", TypeMod: -1},
+			{Name: "meta", TypeName: "jsonb", TypeMod: -1},
+			{Name: "tags", TypeName: "_text", ElemType: "text", TypeMod: -1},
+			{Name: "emb", TypeName: "vector", TypeMod: 3},
+			{Name: "half", TypeName: "halfvec", TypeMod: 4},
+			{Name: "sparse", TypeName: "sparsevec", TypeMod: 100},
+			{Name: "bits", TypeName: "bit", TypeMod: 16},
+		},
+	}
+	fields, err := ToMilvusFields(tableCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.FieldTypeInt64, fields[0].DataType)
+	assert.True(t, fields[0].PrimaryKey)
+	assert.Equal(t, "64", fields[1].TypeParams[entity.TypeParamMaxLength])
+	assert.Equal(t, entity.FieldTypeDouble, fields[2].DataType)
+	assert.Equal(t, entity.FieldTypeJSON, fields[3].DataType)
+	assert.Equal(t, entity.FieldTypeArray, fields[4].DataType)
+	assert.Equal(t, entity.FieldTypeVarChar, fields[4].ElementType)
+	assert.Equal(t, "16", fields[4].TypeParams[entity.TypeParamMaxCapacity])
+	assert.Equal(t, "3", fields[5].TypeParams[entity.TypeParamDim])
+	assert.Equal(t, entity.FieldTypeFloat16Vector, fields[6].DataType)
+	assert.Equal(t, entity.FieldTypeSparseVector, fields[7].DataType)
+	assert.Equal(t, entity.FieldTypeBinaryVector, fields[8].DataType)
+	assert.Equal(t, "array_to_json(\"tags\")::text", SelectExpr(tableCfg.InnerColumns[4]))
+	assert.Equal(t, "\"price\"::float8", SelectExpr(tableCfg.InnerColumns[2]))
+
+	info, err := ToCollectionInfo(tableCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "items", info.Param.CollectionName)
+	assert.Equal(t, 4, len(info.Indexes))
+
+	tableCfg.InnerColumns = tableCfg.InnerColumns[:2]
+	_, err = ToMilvusFields(tableCfg)
+	assert.Error(t, err)
+}
+
+func TestParseValue(t *testing.T) {
+	vector, err := ParseVector("[1,2.5,-3]")
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{1, 2.5, -3}, vector)
+
+	sparse, err := ParseSparseVec("{1:0.5,3:0.25}/10")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sparse.Len())
+	pos, val, _ := sparse.Get(1)
+	assert.Equal(t, uint32(2), pos)
+	assert.Equal(t, float32(0.25), val)
+	_, err = ParseSparseVec("{0:0.5}/10")
+	assert.Error(t, err)
+
+	bits, err := ParseBit("1000000000000011", 16)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x80, 0x03}, bits)
+
+	assert.Equal(t, uint16(0x3c00), Float32ToFloat16(1))
+	assert.Equal(t, uint16(0xc000), Float32ToFloat16(-2))
+	assert.Equal(t, uint16(0x7bff), Float32ToFloat16(65504))
+	assert.Equal(t, uint16(0x7c00), Float32ToFloat16(1e6))
+	assert.Equal(t, uint16(0x0002), Float32ToFloat16(1e-7))
+}
+
+func TestToMilvusColumns(t *testing.T) {
+	fields := []*entity.Field{
+		{Name: "id", DataType: entity.FieldTypeInt64},
+		{Name: "title", DataType: entity.FieldTypeVarChar},
+		{Name: "meta", DataType: entity.FieldTypeJSON},
+		{Name: "tags", DataType: entity.FieldTypeArray, ElementType: entity.FieldTypeInt32},
+		{Name: "emb", DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{entity.TypeParamDim: "2"}},
+	}
+	rows := [][]any{
+		{int64(1), "a", []byte(`{"k":1}`), []byte("[1,2]"), []byte("[0.5,1]")},
+		{int64(2), nil, nil, nil, []byte("[1,0]")},
+	}
+	columns, err := ToMilvusColumns(rows, fields)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, columns[0].Len())
+	title, _ := columns[1].GetAsString(1)
+	assert.Equal(t, "", title)
+	meta, _ := columns[2].Get(1)
+	assert.Equal(t, []byte("{}"), meta)
+	assert.Equal(t, [][]int32{{1, 2}, {}}, columns[3].(*entity.ColumnInt32Array).Data())
+
+	rows[1][4] = []byte("[1,0,2]")
+	_, err = ToMilvusColumns(rows, fields)
+	assert.Error(t, err)
+}
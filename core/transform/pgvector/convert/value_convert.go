@@ -0,0 +1,404 @@
+package pgvectorconvert
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tidwall/gjson"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ToMilvusColumns : rows are the scanned values of the data fields in order, NULL scalar migrate as zero value
+func ToMilvusColumns(rows [][]any, fields []*entity.Field) ([]entity.Column, error) {
+	columns := make([]entity.Column, 0, len(fields))
+	for i, field := range fields {
+		values := make([]any, len(rows))
+		for j, row := range rows {
+			values[j] = row[i]
+		}
+		column, err := toMilvusColumn(field, values)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func toMilvusColumn(field *entity.Field, values []any) (entity.Column, error) {
+	var err error
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		data := make([]bool, len(values))
+		for i, v := range values {
+			if data[i], err = toBool(v); err != nil {
+				return nil, err
+			}
+		}
+		return entity.NewColumnBool(field.Name, data), nil
+	case entity.FieldTypeInt16:
+		data := make([]int16, len(values))
+		for i, v := range values {
+			n, err := toInt64(v)
+			if err != nil {
+				return nil, err
+			}
+			data[i] = int16(n)
+		}
+		return entity.NewColumnInt16(field.Name, data), nil
+	case entity.FieldTypeInt32:
+		data := make([]int32, len(values))
+		for i, v := range values {
+			n, err := toInt64(v)
+			if err != nil {
+				return nil, err
+			}
+			data[i] = int32(n)
+		}
+		return entity.NewColumnInt32(field.Name, data), nil
+	case entity.FieldTypeInt64:
+		data := make([]int64, len(values))
+		for i, v := range values {
+			if data[i], err = toInt64(v); err != nil {
+				return nil, err
+			}
+		}
+		return entity.NewColumnInt64(field.Name, data), nil
+	case entity.FieldTypeFloat:
+		data := make([]float32, len(values))
+		for i, v := range values {
+			f, err := toFloat64(v)
+			if err != nil {
+				return nil, err
+			}
+			data[i] = float32(f)
+		}
+		return entity.NewColumnFloat(field.Name, data), nil
+	case entity.FieldTypeDouble:
+		data := make([]float64, len(values))
+		for i, v := range values {
+			if data[i], err = toFloat64(v); err != nil {
+				return nil, err
+			}
+		}
+		return entity.NewColumnDouble(field.Name, data), nil
+	case entity.FieldTypeVarChar:
+		data := make([]string, len(values))
+		for i, v := range values {
+			data[i] = toString(v)
+		}
+		return entity.NewColumnVarChar(field.Name, data), nil
+	case entity.FieldTypeJSON:
+		data := make([][]byte, len(values))
+		for i, v := range values {
+			data[i] = []byte(toString(v))
+			if v == nil {
+				data[i] = []byte("{}")
+			}
+		}
+		return entity.NewColumnJSONBytes(field.Name, data), nil
+	case entity.FieldTypeArray:
+		return toArrayColumn(field, values)
+	case entity.FieldTypeFloatVector, entity.FieldTypeFloat16Vector:
+		dim, _ := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+		vectors := make([][]float32, len(values))
+		for i, v := range values {
+			if v == nil {
+				return nil, errors.New("vector value is NULL")
+			}
+			if vectors[i], err = ParseVector(toString(v)); err != nil {
+				return nil, err
+			}
+			if len(vectors[i]) != dim {
+				return nil, fmt.Errorf("vector dim %d not match %d", len(vectors[i]), dim)
+			}
+		}
+		if field.DataType == entity.FieldTypeFloatVector {
+			return entity.NewColumnFloatVector(field.Name, dim, vectors), nil
+		}
+		data := make([][]byte, len(vectors))
+		for i, vector := range vectors {
+			data[i] = Float16Bytes(vector)
+		}
+		return entity.NewColumnFloat16Vector(field.Name, dim, data), nil
+	case entity.FieldTypeBinaryVector:
+		dim, _ := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+		data := make([][]byte, len(values))
+		for i, v := range values {
+			if v == nil {
+				return nil, errors.New("bit value is NULL")
+			}
+			if data[i], err = ParseBit(toString(v), dim); err != nil {
+				return nil, err
+			}
+		}
+		return entity.NewColumnBinaryVector(field.Name, dim, data), nil
+	case entity.FieldTypeSparseVector:
+		data := make([]entity.SparseEmbedding, len(values))
+		for i, v := range values {
+			if v == nil {
+				return nil, errors.New("sparsevec value is NULL")
+			}
+			if data[i], err = ParseSparseVec(toString(v)); err != nil {
+				return nil, err
+			}
+		}
+		return entity.NewColumnSparseVectors(field.Name, data), nil
+	}
+	return nil, fmt.Errorf("not support milvus type %s", field.DataType.String())
+}
+
+// toArrayColumn : the array value is read as array_to_json text, NULL array migrate as empty array
+func toArrayColumn(field *entity.Field, values []any) (entity.Column, error) {
+	elems := make([][]gjson.Result, len(values))
+	for i, v := range values {
+		if v != nil {
+			elems[i] = gjson.Parse(toString(v)).Array()
+		}
+	}
+	switch field.ElementType {
+	case entity.FieldTypeBool:
+		data := make([][]bool, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]bool, len(arr))
+			for j, e := range arr {
+				data[i][j] = e.Bool()
+			}
+		}
+		return entity.NewColumnBoolArray(field.Name, data), nil
+	case entity.FieldTypeInt16:
+		data := make([][]int16, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int16, len(arr))
+			for j, e := range arr {
+				data[i][j] = int16(e.Int())
+			}
+		}
+		return entity.NewColumnInt16Array(field.Name, data), nil
+	case entity.FieldTypeInt32:
+		data := make([][]int32, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int32, len(arr))
+			for j, e := range arr {
+				data[i][j] = int32(e.Int())
+			}
+		}
+		return entity.NewColumnInt32Array(field.Name, data), nil
+	case entity.FieldTypeInt64:
+		data := make([][]int64, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int64, len(arr))
+			for j, e := range arr {
+				data[i][j] = e.Int()
+			}
+		}
+		return entity.NewColumnInt64Array(field.Name, data), nil
+	case entity.FieldTypeFloat:
+		data := make([][]float32, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]float32, len(arr))
+			for j, e := range arr {
+				data[i][j] = float32(e.Float())
+			}
+		}
+		return entity.NewColumnFloatArray(field.Name, data), nil
+	case entity.FieldTypeDouble:
+		data := make([][]float64, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]float64, len(arr))
+			for j, e := range arr {
+				data[i][j] = e.Float()
+			}
+		}
+		return entity.NewColumnDoubleArray(field.Name, data), nil
+	case entity.FieldTypeVarChar:
+		data := make([][][]byte, len(elems))
+		for i, arr := range elems {
+			data[i] = make([][]byte, len(arr))
+			for j, e := range arr {
+				data[i][j] = []byte(e.String())
+			}
+		}
+		return entity.NewColumnVarCharArray(field.Name, data), nil
+	}
+	return nil, fmt.Errorf("not support array element type %s", field.ElementType.String())
+}
+
+// ParseVector : parse vector and halfvec text, eg: [1,2.5,3]
+func ParseVector(text string) ([]float32, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("invalid vector text: %s", text)
+	}
+	text = text[1 : len(text)-1]
+	if text == "" {
+		return []float32{}, nil
+	}
+	items := strings.Split(text, ",")
+	vector := make([]float32, len(items))
+	for i, item := range items {
+		f, err := strconv.ParseFloat(strings.TrimSpace(item), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector text: %w", err)
+		}
+		vector[i] = float32(f)
+	}
+	return vector, nil
+}
+
+// ParseSparseVec : parse sparsevec text, eg: {1:0.5,3:0.2}/10, pgvector index start from 1, milvus start from 0
+func ParseSparseVec(text string) (entity.SparseEmbedding, error) {
+	body, _, ok := strings.Cut(strings.TrimSpace(text), "/")
+	if !ok || !strings.HasPrefix(body, "{") || !strings.HasSuffix(body, "}") {
+		return nil, fmt.Errorf("invalid sparsevec text: %s", text)
+	}
+	body = body[1 : len(body)-1]
+	var positions []uint32
+	var values []float32
+	if body != "" {
+		for _, item := range strings.Split(body, ",") {
+			idx, val, ok := strings.Cut(item, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid sparsevec text: %s", text)
+			}
+			pos, err := strconv.ParseUint(strings.TrimSpace(idx), 10, 32)
+			if err != nil || pos == 0 {
+				return nil, fmt.Errorf("invalid sparsevec index: %s", idx)
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(val), 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sparsevec value: %w", err)
+			}
+			positions = append(positions, uint32(pos-1))
+			values = append(values, float32(f))
+		}
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
+
+// ParseBit : parse bit(n) text, eg: 01010001, pack to milvus binary vector bytes, the first bit is the highest bit
+func ParseBit(text string, dim int) ([]byte, error) {
+	if len(text) != dim {
+		return nil, fmt.Errorf("bit length %d not match dim %d", len(text), dim)
+	}
+	data := make([]byte, (dim+7)/8)
+	for i, c := range text {
+		switch c {
+		case '1':
+			data[i/8] |= 0x80 >> (i % 8)
+		case '0':
+		default:
+			return nil, fmt.Errorf("invalid bit text: %s", text)
+		}
+	}
+	return data, nil
+}
+
+// Float16Bytes : encode float32 vector to IEEE 754 half precision little endian bytes
+func Float16Bytes(vector []float32) []byte {
+	data := make([]byte, len(vector)*2)
+	for i, f := range vector {
+		binary.LittleEndian.PutUint16(data[i*2:], Float32ToFloat16(f))
+	}
+	return data
+}
+
+// Float32ToFloat16 : round to nearest even, overflow to inf
+func Float32ToFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	rawExp := int((bits >> 23) & 0xff)
+	mant := bits & 0x7fffff
+	exp := rawExp - 127 + 15
+	switch {
+	case rawExp == 0xff:
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	case exp >= 0x1f:
+		return sign | 0x7c00
+	case exp <= 0:
+		//half subnormal or zero
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem := mant & (1<<shift - 1)
+		mid := uint32(1) << (shift - 1)
+		if rem > mid || (rem == mid && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	//carry into the exponent is still right
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++
+	}
+	return half
+}
+
+func toInt64(v any) (int64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return x, nil
+	case float64:
+		return int64(x), nil
+	case []byte:
+		return strconv.ParseInt(string(x), 10, 64)
+	case string:
+		return strconv.ParseInt(x, 10, 64)
+	}
+	return 0, fmt.Errorf("invalid int value %v", v)
+}
+
+func toFloat64(v any) (float64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return x, nil
+	case int64:
+		return float64(x), nil
+	case []byte:
+		return strconv.ParseFloat(string(x), 64)
+	case string:
+		return strconv.ParseFloat(x, 64)
+	}
+	return 0, fmt.Errorf("invalid float value %v", v)
+}
+
+func toBool(v any) (bool, error) {
+	switch x := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return x, nil
+	case []byte:
+		return strconv.ParseBool(string(x))
+	case string:
+		return strconv.ParseBool(x)
+	}
+	return false, fmt.Errorf("invalid bool value %v", v)
+}
+
+func toString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	}
+	return fmt.Sprint(v)
+}
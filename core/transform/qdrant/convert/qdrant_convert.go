@@ -0,0 +1,205 @@
+package qdrantconvert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	convert "github.com/zilliztech/milvus-migration/core/transform/common"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// DefaultVectorField : milvus field name of the qdrant unnamed default vector
+const DefaultVectorField = "vector"
+
+// uuid text length, also longer than the max uint64 number text
+const uuidLen = 36
+
+// SupportPayloadTypeMap : key is the meta.fields type of the payload field
+var SupportPayloadTypeMap = map[string]entity.FieldType{
+	"bool":    entity.FieldTypeBool,
+	"int8":    entity.FieldTypeInt8,
+	"int16":   entity.FieldTypeInt16,
+	"int32":   entity.FieldTypeInt32,
+	"int64":   entity.FieldTypeInt64,
+	"float":   entity.FieldTypeFloat,
+	"double":  entity.FieldTypeDouble,
+	"varchar": entity.FieldTypeVarChar,
+	"json":    entity.FieldTypeJSON,
+	"array":   entity.FieldTypeArray,
+}
+
+// DistanceMetricMap : qdrant vector distance to milvus metric type, Manhattan not supported
+var DistanceMetricMap = map[string]entity.MetricType{
+	"Cosine": entity.COSINE,
+	"Euclid": entity.L2,
+	"Dot":    entity.IP,
+}
+
+// VectorFieldName : milvus field name of the qdrant vector
+func VectorFieldName(vector *qdranttype.Vector) string {
+	if vector.Name == "" {
+		return DefaultVectorField
+	}
+	return vector.Name
+}
+
+func ToCollectionName(collCfg *qdranttype.CollectionCfg) string {
+	if collCfg.MilvusCfg != nil && collCfg.MilvusCfg.Collection != "" {
+		return collCfg.MilvusCfg.Collection
+	}
+	return collCfg.Collection
+}
+
+// ToCollectionInfo : the target collection of the qdrant collection, vector field will use AUTOINDEX if not config index,
+// the vector distance is the metric type when the index config not specify it
+func ToCollectionInfo(collCfg *qdranttype.CollectionCfg) (*common.CollectionInfo, error) {
+	fields, err := ToMilvusFields(collCfg)
+	if err != nil {
+		return nil, err
+	}
+	milvusCfg := collCfg.MilvusCfg
+	param := &common.CollectionParam{
+		CollectionName:     ToCollectionName(collCfg),
+		ShardsNum:          milvusCfg.ShardNum,
+		EnableDynamicField: !milvusCfg.CloseDynamicField,
+		AutoId:             milvusCfg.AutoId == "true",
+		Description:        "Migration from qdrant collection " + collCfg.Collection,
+		CreateIndex:        milvusCfg.CreateIndex,
+		LoadData:           milvusCfg.LoadData,
+	}
+	if param.ShardsNum <= 0 {
+		param.ShardsNum = common.DEF_SHARD_NUM
+	}
+	if len(milvusCfg.ConsistencyLevel) > 0 {
+		level, ok := convert.ConsistencyLevelMap[milvusCfg.ConsistencyLevel]
+		if !ok {
+			return nil, errors.New("qdrant transform to milvus consistencyLevel value invalid: " + milvusCfg.ConsistencyLevel)
+		}
+		param.ConsistencyLevel = &level
+	}
+	distances := make(map[string]string)
+	for _, vector := range collCfg.InnerVectors {
+		distances[VectorFieldName(vector)] = vector.Distance
+	}
+	indexes := make(map[string]entity.Index)
+	for _, field := range fields {
+		var idxCfg *milvustype.IndexCfg
+		if fieldCfg := collCfg.GetField(field.Name); fieldCfg != nil {
+			idxCfg = fieldCfg.Index
+		}
+		if idxCfg == nil && !convert.IsVectorField(field) {
+			continue
+		}
+		if distance := distances[field.Name]; distance != "" && (idxCfg == nil || idxCfg.MetricType == "") {
+			metricType, ok := DistanceMetricMap[distance]
+			if ok {
+				withMetric := milvustype.IndexCfg{}
+				if idxCfg != nil {
+					withMetric = *idxCfg
+				}
+				withMetric.MetricType = string(metricType)
+				idxCfg = &withMetric
+			} else {
+				log.Warn("[Qdrant] vector distance not support, will use the default metric type",
+					zap.String("field", field.Name), zap.String("distance", distance))
+			}
+		}
+		index, err := convert.ToMilvusIndex(field, idxCfg)
+		if err != nil {
+			return nil, err
+		}
+		indexes[field.Name] = index
+	}
+	return &common.CollectionInfo{Param: param, Fields: fields, Indexes: indexes}, nil
+}
+
+// ToMilvusFields : the pk field, then vector fields in the collection config order, then the typed payload fields
+func ToMilvusFields(collCfg *qdranttype.CollectionCfg) ([]*entity.Field, error) {
+	pkField := &entity.Field{
+		Name:       collCfg.PkName,
+		PrimaryKey: true,
+		AutoID:     collCfg.MilvusCfg != nil && collCfg.MilvusCfg.AutoId == "true",
+		DataType:   entity.FieldTypeInt64,
+	}
+	if collCfg.PkType == qdranttype.PkTypeVarChar {
+		pkField.DataType = entity.FieldTypeVarChar
+		pkField.TypeParams = map[string]string{entity.TypeParamMaxLength: strconv.Itoa(uuidLen)}
+	}
+	fields := []*entity.Field{pkField}
+	names := map[string]bool{pkField.Name: true}
+	for _, vector := range collCfg.InnerVectors {
+		field := &entity.Field{Name: VectorFieldName(vector), DataType: entity.FieldTypeSparseVector}
+		if !vector.Sparse {
+			if vector.Size <= 0 {
+				return nil, fmt.Errorf("qdrant collection %s vector %s size unknown", collCfg.Collection, field.Name)
+			}
+			field.DataType = entity.FieldTypeFloatVector
+			field.TypeParams = map[string]string{entity.TypeParamDim: strconv.Itoa(vector.Size)}
+		}
+		if names[field.Name] {
+			return nil, fmt.Errorf("qdrant collection %s field name %s duplicated", collCfg.Collection, field.Name)
+		}
+		names[field.Name] = true
+		fields = append(fields, field)
+	}
+	if len(fields) == 1 {
+		return nil, fmt.Errorf("qdrant collection %s has no vector, milvus collection need a vector field", collCfg.Collection)
+	}
+	for i := range collCfg.Fields {
+		fieldCfg := &collCfg.Fields[i]
+		if fieldCfg.Type == "" {
+			//index config of the vector field
+			if !names[fieldCfg.Name] || fieldCfg.Name == pkField.Name {
+				return nil, fmt.Errorf("qdrant collection %s meta.fields %s need the payload type", collCfg.Collection, fieldCfg.Name)
+			}
+			continue
+		}
+		if names[fieldCfg.Name] {
+			return nil, fmt.Errorf("qdrant collection %s field name %s duplicated", collCfg.Collection, fieldCfg.Name)
+		}
+		field, err := ToPayloadField(fieldCfg)
+		if err != nil {
+			return nil, fmt.Errorf("qdrant collection %s: %w", collCfg.Collection, err)
+		}
+		names[field.Name] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// ToPayloadField : milvus field of the typed payload key
+func ToPayloadField(fieldCfg *qdranttype.FieldCfg) (*entity.Field, error) {
+	dataType, ok := SupportPayloadTypeMap[fieldCfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("payload field %s type %s not support", fieldCfg.Name, fieldCfg.Type)
+	}
+	field := &entity.Field{Name: fieldCfg.Name, DataType: dataType, TypeParams: make(map[string]string)}
+	maxLen := convert.VarcharMaxLenNum
+	if fieldCfg.MaxLen > 0 {
+		maxLen = fieldCfg.MaxLen
+	}
+	switch dataType {
+	case entity.FieldTypeVarChar:
+		field.TypeParams[entity.TypeParamMaxLength] = strconv.Itoa(maxLen)
+	case entity.FieldTypeArray:
+		elemType, ok := SupportPayloadTypeMap[fieldCfg.ElementType]
+		if !ok || elemType == entity.FieldTypeJSON || elemType == entity.FieldTypeArray {
+			return nil, fmt.Errorf("payload field %s array elementType %s not support", fieldCfg.Name, fieldCfg.ElementType)
+		}
+		maxCapacity := convert.MaxArrayCapacity
+		if fieldCfg.MaxCapacity > 0 {
+			maxCapacity = fieldCfg.MaxCapacity
+		}
+		field.ElementType = elemType
+		field.TypeParams[entity.TypeParamMaxCapacity] = strconv.Itoa(maxCapacity)
+		if elemType == entity.FieldTypeVarChar {
+			field.TypeParams[entity.TypeParamMaxLength] = strconv.Itoa(maxLen)
+		}
+	}
+	return field, nil
+}
@@ -0,0 +1,93 @@
+package qdrantconvert
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"testing"
+)
+
+func newCollCfg() *qdranttype.CollectionCfg {
+	return &qdranttype.CollectionCfg{
+		Collection: "items",
+		PkName:     "id",
+		PkType:     qdranttype.PkTypeInt64,
+		Fields: []qdranttype.FieldCfg{
+			{Name: "title", Type: "varchar", MaxLen: 128},
+			{Name: "tags", Type: "array", ElementType: "varchar", MaxCapacity: 8},
+			{Name: "image", Index: &milvustype.IndexCfg{IndexType: "HNSW"}},
+		},
+		MilvusCfg: &milvustype.MilvusCfg{},
+		InnerVectors: []*qdranttype.Vector{
+			{Name: "image", Size: 2, Distance: "Dot"},
+			{Name: "keywords", Sparse: true},
+		},
+	}
+}
+
+func TestToCollectionInfo(t *testing.T) {
+	collCfg := newCollCfg()
+	info, err := ToCollectionInfo(collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "items", info.Param.CollectionName)
+	fields := info.Fields
+	assert.Equal(t, 5, len(fields))
+	assert.True(t, fields[0].PrimaryKey)
+	assert.Equal(t, entity.FieldTypeInt64, fields[0].DataType)
+	assert.Equal(t, entity.FieldTypeFloatVector, fields[1].DataType)
+	assert.Equal(t, "2", fields[1].TypeParams[entity.TypeParamDim])
+	assert.Equal(t, entity.FieldTypeSparseVector, fields[2].DataType)
+	assert.Equal(t, "128", fields[3].TypeParams[entity.TypeParamMaxLength])
+	assert.Equal(t, entity.FieldTypeVarChar, fields[4].ElementType)
+	assert.Equal(t, "8", fields[4].TypeParams[entity.TypeParamMaxCapacity])
+	assert.Equal(t, string(entity.IP), info.Indexes["image"].Params()["metric_type"])
+	assert.Equal(t, "HNSW", string(info.Indexes["image"].IndexType()))
+	assert.Equal(t, string(entity.IP), info.Indexes["keywords"].Params()["metric_type"])
+
+	collCfg.PkType = qdranttype.PkTypeVarChar
+	collCfg.InnerVectors = []*qdranttype.Vector{{Size: 2, Distance: "Euclid"}}
+	collCfg.Fields = nil
+	info, err = ToCollectionInfo(collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.FieldTypeVarChar, info.Fields[0].DataType)
+	assert.Equal(t, DefaultVectorField, info.Fields[1].Name)
+	assert.Equal(t, string(entity.L2), info.Indexes[DefaultVectorField].Params()["metric_type"])
+
+	collCfg.Fields = []qdranttype.FieldCfg{{Name: "missing"}}
+	_, err = ToMilvusFields(collCfg)
+	assert.Error(t, err)
+	collCfg.Fields = []qdranttype.FieldCfg{{Name: "vector", Type: "int64"}}
+	_, err = ToMilvusFields(collCfg)
+	assert.Error(t, err)
+}
+
+func TestToMilvusColumns(t *testing.T) {
+	collCfg := newCollCfg()
+	fields, err := ToMilvusFields(collCfg)
+	assert.NoError(t, err)
+	points := gjson.Parse(`[
+		{"id":1,"payload":{"title":"a","tags":["x","y"],"color":"red"},
+			"vector":{"image":[0.5,1],"keywords":{"indices":[3,7],"values":[0.1,0.2]}}},
+		{"id":2,"payload":{"tags":"z","size":{"w":1}},"vector":{"image":[1,2]}}
+	]`).Array()
+	columns, err := ToMilvusColumns(points, fields, collCfg, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(columns))
+	assert.Equal(t, []int64{1, 2}, columns[0].(*entity.ColumnInt64).Data())
+	assert.Equal(t, [][]float32{{0.5, 1}, {1, 2}}, columns[1].(*entity.ColumnFloatVector).Data())
+	sparse := columns[2].(*entity.ColumnSparseFloatVector).Data()
+	assert.Equal(t, 2, sparse[0].Len())
+	assert.Equal(t, 0, sparse[1].Len())
+	assert.Equal(t, []string{"a", ""}, columns[3].(*entity.ColumnVarChar).Data())
+	assert.Equal(t, [][][]byte{{[]byte("x"), []byte("y")}, {[]byte("z")}}, columns[4].(*entity.ColumnVarCharArray).Data())
+	assert.Equal(t, [][]byte{[]byte(`{"color":"red"}`), []byte(`{"size":{"w":1}}`)}, columns[5].(*entity.ColumnJSONBytes).Data())
+
+	points = gjson.Parse(`[{"id":"3f0c7d6a-1c1e-4a4f-9d0a-5d3c2e1b0a01","vector":{"image":[1,2]}}]`).Array()
+	_, err = ToMilvusColumns(points, fields, collCfg, false)
+	assert.Error(t, err)
+	points = gjson.Parse(`[{"id":3,"vector":{"image":[1,2,3]}}]`).Array()
+	_, err = ToMilvusColumns(points, fields, collCfg, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,315 @@
+package qdrantconvert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"math"
+	"strings"
+)
+
+// ToMilvusColumns : convert the scroll points to the columns of fields, autoId pk field not in fields.
+// missing payload value migrate as zero value (json: {}, array: []), the payload keys not in fields
+// are the dynamic field value when dynamic is true
+func ToMilvusColumns(points []gjson.Result, fields []*entity.Field, collCfg *qdranttype.CollectionCfg, dynamic bool) ([]entity.Column, error) {
+	vectorNames := make(map[string]*qdranttype.Vector, len(collCfg.InnerVectors))
+	for _, vector := range collCfg.InnerVectors {
+		vectorNames[VectorFieldName(vector)] = vector
+	}
+	columns := make([]entity.Column, 0, len(fields)+1)
+	payloadKeys := make(map[string]bool)
+	for _, field := range fields {
+		var column entity.Column
+		var err error
+		if field.PrimaryKey {
+			column, err = toPkColumn(field, points)
+		} else if vector, ok := vectorNames[field.Name]; ok {
+			column, err = toVectorColumn(field, vector, points)
+		} else {
+			payloadKeys[field.Name] = true
+			values := make([]gjson.Result, len(points))
+			for i, point := range points {
+				values[i] = point.Get("payload").Get(gjson.Escape(field.Name))
+			}
+			column, err = toPayloadColumn(field, values)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		columns = append(columns, column)
+	}
+	if dynamic {
+		columns = append(columns, toDynamicColumn(points, payloadKeys))
+	}
+	return columns, nil
+}
+
+// toPkColumn : qdrant point id is an unsigned int or uuid string
+func toPkColumn(field *entity.Field, points []gjson.Result) (entity.Column, error) {
+	if field.DataType == entity.FieldTypeVarChar {
+		data := make([]string, len(points))
+		for i, point := range points {
+			id := point.Get("id")
+			if id.Type == gjson.String {
+				data[i] = id.Str
+			} else {
+				data[i] = id.Raw
+			}
+		}
+		return entity.NewColumnVarChar(field.Name, data), nil
+	}
+	data := make([]int64, len(points))
+	for i, point := range points {
+		id := point.Get("id")
+		if id.Type != gjson.Number {
+			return nil, fmt.Errorf("point id %s is not a number, uuid id need pkType varchar", id.Raw)
+		}
+		n := id.Uint()
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("point id %s overflow int64, need pkType varchar", id.Raw)
+		}
+		data[i] = int64(n)
+	}
+	return entity.NewColumnInt64(field.Name, data), nil
+}
+
+// pointVector : the unnamed vector is the point vector array, named vectors is the point vector object
+func pointVector(point gjson.Result, vector *qdranttype.Vector) gjson.Result {
+	value := point.Get("vector")
+	if vector.Name == "" {
+		return value
+	}
+	return value.Get(gjson.Escape(vector.Name))
+}
+
+func toVectorColumn(field *entity.Field, vector *qdranttype.Vector, points []gjson.Result) (entity.Column, error) {
+	if vector.Sparse {
+		data := make([]entity.SparseEmbedding, len(points))
+		for i, point := range points {
+			//sparse vector is optional of a point
+			sparse, err := ParseSparseVector(pointVector(point, vector))
+			if err != nil {
+				return nil, fmt.Errorf("point %s: %w", point.Get("id").Raw, err)
+			}
+			data[i] = sparse
+		}
+		return entity.NewColumnSparseVectors(field.Name, data), nil
+	}
+	vectors := make([][]float32, len(points))
+	for i, point := range points {
+		value := pointVector(point, vector)
+		if !value.IsArray() {
+			return nil, fmt.Errorf("point %s vector not exist or not a dense vector", point.Get("id").Raw)
+		}
+		elems := value.Array()
+		if len(elems) != vector.Size {
+			return nil, fmt.Errorf("point %s vector dim %d not match %d", point.Get("id").Raw, len(elems), vector.Size)
+		}
+		vectors[i] = make([]float32, len(elems))
+		for j, e := range elems {
+			vectors[i][j] = float32(e.Float())
+		}
+	}
+	return entity.NewColumnFloatVector(field.Name, vector.Size, vectors), nil
+}
+
+// ParseSparseVector : qdrant sparse vector is {"indices":[...],"values":[...]}, not exist as empty sparse vector
+func ParseSparseVector(value gjson.Result) (entity.SparseEmbedding, error) {
+	if !value.Exists() || value.Type == gjson.Null {
+		return entity.NewSliceSparseEmbedding(nil, nil)
+	}
+	if !value.IsObject() {
+		return nil, errors.New("sparse vector need be an object of indices and values")
+	}
+	indices := value.Get("indices").Array()
+	values := value.Get("values").Array()
+	if len(indices) != len(values) {
+		return nil, fmt.Errorf("sparse vector indices length %d not match values length %d", len(indices), len(values))
+	}
+	positions := make([]uint32, len(indices))
+	data := make([]float32, len(values))
+	for i := range indices {
+		positions[i] = uint32(indices[i].Uint())
+		data[i] = float32(values[i].Float())
+	}
+	return entity.NewSliceSparseEmbedding(positions, data)
+}
+
+func toPayloadColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	switch field.DataType {
+	case entity.FieldTypeBool:
+		data := make([]bool, len(values))
+		for i, v := range values {
+			data[i] = v.Bool()
+		}
+		return entity.NewColumnBool(field.Name, data), nil
+	case entity.FieldTypeInt8:
+		data := make([]int8, len(values))
+		for i, v := range values {
+			data[i] = int8(v.Int())
+		}
+		return entity.NewColumnInt8(field.Name, data), nil
+	case entity.FieldTypeInt16:
+		data := make([]int16, len(values))
+		for i, v := range values {
+			data[i] = int16(v.Int())
+		}
+		return entity.NewColumnInt16(field.Name, data), nil
+	case entity.FieldTypeInt32:
+		data := make([]int32, len(values))
+		for i, v := range values {
+			data[i] = int32(v.Int())
+		}
+		return entity.NewColumnInt32(field.Name, data), nil
+	case entity.FieldTypeInt64:
+		data := make([]int64, len(values))
+		for i, v := range values {
+			data[i] = v.Int()
+		}
+		return entity.NewColumnInt64(field.Name, data), nil
+	case entity.FieldTypeFloat:
+		data := make([]float32, len(values))
+		for i, v := range values {
+			data[i] = float32(v.Float())
+		}
+		return entity.NewColumnFloat(field.Name, data), nil
+	case entity.FieldTypeDouble:
+		data := make([]float64, len(values))
+		for i, v := range values {
+			data[i] = v.Float()
+		}
+		return entity.NewColumnDouble(field.Name, data), nil
+	case entity.FieldTypeVarChar:
+		data := make([]string, len(values))
+		for i, v := range values {
+			data[i] = v.String()
+		}
+		return entity.NewColumnVarChar(field.Name, data), nil
+	case entity.FieldTypeJSON:
+		data := make([][]byte, len(values))
+		for i, v := range values {
+			data[i] = []byte(v.Raw)
+			if !v.Exists() || v.Type == gjson.Null {
+				data[i] = []byte("{}")
+			}
+		}
+		return entity.NewColumnJSONBytes(field.Name, data), nil
+	case entity.FieldTypeArray:
+		return toArrayColumn(field, values)
+	}
+	return nil, fmt.Errorf("not support milvus type %s", field.DataType.String())
+}
+
+// toArrayColumn : single payload value migrate as one element array, missing value as empty array
+func toArrayColumn(field *entity.Field, values []gjson.Result) (entity.Column, error) {
+	elems := make([][]gjson.Result, len(values))
+	for i, v := range values {
+		if v.IsArray() {
+			elems[i] = v.Array()
+		} else if v.Exists() && v.Type != gjson.Null {
+			elems[i] = []gjson.Result{v}
+		}
+	}
+	switch field.ElementType {
+	case entity.FieldTypeBool:
+		data := make([][]bool, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]bool, len(arr))
+			for j, e := range arr {
+				data[i][j] = e.Bool()
+			}
+		}
+		return entity.NewColumnBoolArray(field.Name, data), nil
+	case entity.FieldTypeInt8:
+		data := make([][]int8, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int8, len(arr))
+			for j, e := range arr {
+				data[i][j] = int8(e.Int())
+			}
+		}
+		return entity.NewColumnInt8Array(field.Name, data), nil
+	case entity.FieldTypeInt16:
+		data := make([][]int16, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int16, len(arr))
+			for j, e := range arr {
+				data[i][j] = int16(e.Int())
+			}
+		}
+		return entity.NewColumnInt16Array(field.Name, data), nil
+	case entity.FieldTypeInt32:
+		data := make([][]int32, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int32, len(arr))
+			for j, e := range arr {
+				data[i][j] = int32(e.Int())
+			}
+		}
+		return entity.NewColumnInt32Array(field.Name, data), nil
+	case entity.FieldTypeInt64:
+		data := make([][]int64, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]int64, len(arr))
+			for j, e := range arr {
+				data[i][j] = e.Int()
+			}
+		}
+		return entity.NewColumnInt64Array(field.Name, data), nil
+	case entity.FieldTypeFloat:
+		data := make([][]float32, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]float32, len(arr))
+			for j, e := range arr {
+				data[i][j] = float32(e.Float())
+			}
+		}
+		return entity.NewColumnFloatArray(field.Name, data), nil
+	case entity.FieldTypeDouble:
+		data := make([][]float64, len(elems))
+		for i, arr := range elems {
+			data[i] = make([]float64, len(arr))
+			for j, e := range arr {
+				data[i][j] = e.Float()
+			}
+		}
+		return entity.NewColumnDoubleArray(field.Name, data), nil
+	case entity.FieldTypeVarChar:
+		data := make([][][]byte, len(elems))
+		for i, arr := range elems {
+			data[i] = make([][]byte, len(arr))
+			for j, e := range arr {
+				data[i][j] = []byte(e.String())
+			}
+		}
+		return entity.NewColumnVarCharArray(field.Name, data), nil
+	}
+	return nil, fmt.Errorf("not support array element type %s", field.ElementType.String())
+}
+
+// toDynamicColumn : the payload keys not typed fields as the $meta json object
+func toDynamicColumn(points []gjson.Result, payloadKeys map[string]bool) entity.Column {
+	data := make([][]byte, len(points))
+	for i, point := range points {
+		var sb strings.Builder
+		sb.WriteString("{")
+		point.Get("payload").ForEach(func(key, value gjson.Result) bool {
+			if payloadKeys[key.String()] {
+				return true
+			}
+			if sb.Len() > 1 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(key.Raw)
+			sb.WriteString(":")
+			sb.WriteString(value.Raw)
+			return true
+		})
+		sb.WriteString("}")
+		data[i] = []byte(sb.String())
+	}
+	return entity.NewColumnJSONBytes(common.MILVUS_META_FD, data).WithIsDynamic(true)
+}
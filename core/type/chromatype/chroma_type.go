@@ -0,0 +1,56 @@
+package chromatype
+
+import (
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+)
+
+type MetaJSON struct {
+	CollectionCfgs []*CollectionCfg `json:"collections"`
+	//config of the collections not in CollectionCfgs, used when migrate all collections of the persist directory
+	DefaultCfg *CollectionCfg `json:"-"`
+}
+
+// milvus fields of the chroma collection
+const (
+	FieldId        = "id"
+	FieldDocument  = "document"
+	FieldEmbedding = "embedding"
+	FieldMetadata  = "metadata"
+)
+
+type CollectionCfg struct {
+	Collection     string                `json:"collection"`     //chroma collection name
+	IdMaxLen       int                   `json:"idMaxLen"`       //id varchar max_length, default 65535
+	DocumentMaxLen int                   `json:"documentMaxLen"` //document varchar max_length, default 65535
+	MilvusCfg      *milvustype.MilvusCfg `json:"milvus"`
+	Rows           int64                 `json:"rows"`
+
+	//read from the chroma.sqlite3
+	InnerId              string `json:"-"` //chroma collection uuid
+	InnerDim             int    `json:"-"`
+	InnerSpace           string `json:"-"` //hnsw:space, l2(default), ip or cosine
+	InnerVectorSegment   string `json:"-"`
+	InnerMetadataSegment string `json:"-"`
+}
+
+// Record : a chroma embedding record, Metadata is the json object of the not internal metadata keys
+type Record struct {
+	Id        string
+	Document  string
+	Embedding []float32
+	Metadata  []byte
+}
+
+// NewCollectionCfg : config of the collection not in CollectionCfgs, copy from the DefaultCfg
+func (meta *MetaJSON) NewCollectionCfg(collection string) *CollectionCfg {
+	collCfg := &CollectionCfg{Collection: collection, MilvusCfg: &milvustype.MilvusCfg{}}
+	if meta.DefaultCfg != nil {
+		collCfg.IdMaxLen = meta.DefaultCfg.IdMaxLen
+		collCfg.DocumentMaxLen = meta.DefaultCfg.DocumentMaxLen
+		if meta.DefaultCfg.MilvusCfg != nil {
+			milvusCfg := *meta.DefaultCfg.MilvusCfg
+			collCfg.MilvusCfg = &milvusCfg
+		}
+	}
+	return collCfg
+}
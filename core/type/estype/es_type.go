@@ -16,11 +16,39 @@ type IdxCfg struct {
 	Fields    []FieldCfg            `json:"fields"`
 	MilvusCfg *milvustype.MilvusCfg `json:"milvus"`
 
+	Query          string `json:"query"`          //raw query dsl json, filter the migrate docs
+	MultiIndexMode string `json:"multiIndexMode"` //index is wildcard pattern or alias: merge(default) or split
+
+	AutoMapping            bool     `json:"autoMapping"`            //read fields from es index _mapping, config fields as override
+	UnsupportedFieldPolicy string   `json:"unsupportedFieldPolicy"` //auto mapping unsupported type field: drop or dynamic
+	DynamicFields          []string `json:"dynamicFields"`          //fields will migrate to milvus dynamic field
+
 	InnerPkField *FieldCfg
 	InnerPkType  *entity.FieldType
+	//sparse vector field name -> token vocabulary, will persist after the index dumped
+	InnerVocabularies map[string]*SparseVocabulary `json:"-"`
 	//InnerHasPK   bool
 }
 
+const (
+	UnsupportedFieldDrop    = "drop"
+	UnsupportedFieldDynamic = "dynamic"
+)
+
+// IdxCfg.MultiIndexMode : merge all matched indices into one collection, or split into one collection per concrete index
+const (
+	MultiIndexMerge = "merge"
+	MultiIndexSplit = "split"
+)
+
+// FieldCfg.Target : es date and geo_point field convert target
+const (
+	TargetInt64   = "int64"
+	TargetVarChar = "varchar"
+	TargetJSON    = "json"
+	TargetArray   = "array"
+)
+
 type FieldCfg struct {
 	/*
 		es type:
@@ -34,10 +62,28 @@ type FieldCfg struct {
 			complex type : array, object, nested,
 			geo-point, geo-shape
 			dense_vector
+			knn_vector (opensearch)
 	*/
 	Type   string `json:"type"`
 	Name   string `json:"name"`
-	Dims   int    `json:"dims"`   //dense_vector type have Dims info
+	Dims   int    `json:"dims"`   //dense_vector and knn_vector type have Dims info
 	MaxLen int    `json:"maxLen"` //text,keyword,string will as milvus varchar store, varchar need have the maxLen property
 	PK     bool   `json:"pk"`
+	//date: int64(default) or varchar, geo_point: json(default) or array
+	Target string `json:"target"`
+	//multi-valued field will as milvus array field when maxCapacity > 0
+	MaxCapacity int `json:"maxCapacity"`
+
+	Index *milvustype.IndexCfg `json:"index"` //target milvus field index config, work when milvus.createIndex=true
+
+	//opensearch knn_vector: data_type float(default), byte or binary, method space_type as the default index metric
+	VectorDataType string `json:"dataType"`
+	SpaceType      string `json:"spaceType"`
 }
+
+// FieldCfg.VectorDataType : opensearch knn_vector data_type
+const (
+	KnnDataFloat  = "float"
+	KnnDataByte   = "byte"
+	KnnDataBinary = "binary"
+)
@@ -0,0 +1,48 @@
+package estype
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// SparseVocabulary : es sparse_vector/rank_features field token -> milvus sparse vector index,
+// shared by all the dump workers of the index
+type SparseVocabulary struct {
+	Tokens map[string]uint32 `json:"tokens"`
+
+	lock sync.RWMutex
+}
+
+func NewSparseVocabulary() *SparseVocabulary {
+	return &SparseVocabulary{Tokens: make(map[string]uint32)}
+}
+
+// IndexOf : return the token index, new token will use the next index
+func (v *SparseVocabulary) IndexOf(token string) uint32 {
+	v.lock.RLock()
+	idx, ok := v.Tokens[token]
+	v.lock.RUnlock()
+	if ok {
+		return idx
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if idx, ok = v.Tokens[token]; ok {
+		return idx
+	}
+	idx = uint32(len(v.Tokens))
+	v.Tokens[token] = idx
+	return idx
+}
+
+func (v *SparseVocabulary) Size() int {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return len(v.Tokens)
+}
+
+func (v *SparseVocabulary) Marshal() ([]byte, error) {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return json.Marshal(v)
+}
@@ -0,0 +1,40 @@
+package filestype
+
+import (
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+)
+
+type MetaJSON struct {
+	CollectionCfgs []*CollectionCfg `json:"collections"`
+}
+
+type CollectionCfg struct {
+	Collection string                `json:"collection"` //target milvus collection name
+	Format     string                `json:"format"`     //jsonl, csv or parquet, default detect by the file extension
+	Files      []string              `json:"files"`      //files relative to the source dir, path end with / means all files in the dir, empty means the source dir
+	Fields     []FieldCfg            `json:"fields"`
+	MilvusCfg  *milvustype.MilvusCfg `json:"milvus"`
+	Rows       int64                 `json:"rows"`
+}
+
+// GetField : the config of the field, nil if not config
+func (collCfg *CollectionCfg) GetField(name string) *FieldCfg {
+	for i := range collCfg.Fields {
+		if collCfg.Fields[i].Name == name {
+			return &collCfg.Fields[i]
+		}
+	}
+	return nil
+}
+
+type FieldCfg struct {
+	/*
+		milvus type: Bool, Int8, Int16, Int32, Int64, Float, Double, VarChar, JSON, FloatVector, case insensitive
+	*/
+	Type   string               `json:"type"`
+	Name   string               `json:"name"`   //the jsonl key, csv header or parquet column name
+	Dims   int                  `json:"dims"`   //FloatVector need the dims
+	MaxLen int                  `json:"maxLen"` //VarChar max_length, default 65535
+	PK     bool                 `json:"pk"`
+	Index  *milvustype.IndexCfg `json:"index"`
+}
@@ -0,0 +1,59 @@
+package manifesttype
+
+// ManifestFileName : files target write it to the output dir root, the data file path in it is relative to the dir
+const ManifestFileName = "manifest.json"
+
+const ManifestVersion = "1.0"
+
+type Manifest struct {
+	Version     string           `json:"version"`
+	Format      string           `json:"format"` //parquet, jsonl
+	SourceType  string           `json:"sourceType"`
+	CreateTime  string           `json:"createTime"`
+	Collections []*CollectionCfg `json:"collections"`
+}
+
+type CollectionCfg struct {
+	Collection       string               `json:"collection"`
+	Source           string               `json:"source"` //source collection or es index
+	Description      string               `json:"description"`
+	ShardsNum        int                  `json:"shardsNum"`
+	ConsistencyLevel string               `json:"consistencyLevel"`
+	DynamicField     bool                 `json:"dynamicField"` //data files have the $meta column
+	PartitionKey     string               `json:"partitionKey"`
+	Rows             int64                `json:"rows"`
+	Fields           []*FieldCfg          `json:"fields"`
+	Indexes          map[string]*IndexCfg `json:"indexes"`
+	Partitions       []*PartitionCfg      `json:"partitions"`
+}
+
+// FieldCfg : autoId pk field only record in schema, its data not exported
+type FieldCfg struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	ElementType  string            `json:"elementType,omitempty"`
+	PK           bool              `json:"pk,omitempty"`
+	AutoID       bool              `json:"autoId,omitempty"`
+	PartitionKey bool              `json:"partitionKey,omitempty"`
+	Nullable     bool              `json:"nullable,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	TypeParams   map[string]string `json:"typeParams,omitempty"` //dim, max_length, max_capacity
+}
+
+type IndexCfg struct {
+	IndexType string            `json:"indexType"`
+	Params    map[string]string `json:"params"`
+}
+
+type PartitionCfg struct {
+	Name  string     `json:"name"`
+	Rows  int64      `json:"rows"`
+	Files []*FileCfg `json:"files"`
+}
+
+type FileCfg struct {
+	Path   string `json:"path"`
+	Rows   int64  `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
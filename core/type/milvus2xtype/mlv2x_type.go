@@ -3,6 +3,7 @@ package milvus2xtype
 import (
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"strings"
 )
 
 type MetaJSON struct {
@@ -15,11 +16,17 @@ type CollectionCfg struct {
 	Rows       int64                 `json:"rows"`
 	Fields     []FieldCfg            `json:"fields"`
 	MilvusCfg  *milvustype.MilvusCfg `json:"milvus"`
+	Filter     string                `json:"filter"` //source query expr, only migrate the filtered data, eg: "tenant_id == 1"
 
 	Partitions   []*entity.Partition
 	DynamicField bool //source collection Dynamic Field status, if it open, will sync $meta field data to target collection
 }
 
+// IsPattern : collection name is a glob pattern, like: "*", "user_*", will be expanded by source collections
+func (collCfg *CollectionCfg) IsPattern() bool {
+	return strings.ContainsAny(collCfg.Collection, "*?[")
+}
+
 type FieldCfg struct {
 	/*
 		milvus2x type: FloatVector, VarChar, Int64, ...
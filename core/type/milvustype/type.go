@@ -13,6 +13,13 @@ type MilvusCfg struct {
 	PkName            string `json:"pkName"`
 }
 
+// IndexCfg ：target milvus field index config, used when MilvusCfg.CreateIndex is true
+type IndexCfg struct {
+	IndexType  string            `json:"indexType"`  //default value: AUTOINDEX
+	MetricType string            `json:"metricType"` //vector field need, default value: L2(FloatVector), HAMMING(BinaryVector), IP(SparseFloatVector)
+	Params     map[string]string `json:"params"`     //index build params, eg: {"M":"16","efConstruction":"200"}
+}
+
 // SegColInfo 下面是Milvus1x结构
 type SegColInfo struct {
 	CollectionName string `json:"collection"`
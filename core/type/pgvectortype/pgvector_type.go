@@ -0,0 +1,57 @@
+package pgvectortype
+
+import (
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"strings"
+)
+
+type MetaJSON struct {
+	TableCfgs []*TableCfg `json:"tables"`
+}
+
+type TableCfg struct {
+	Table     string                `json:"table"`  //table name, can with schema prefix, eg: public.items
+	PK        string                `json:"pk"`     //keyset pagination column, default is the table primary key
+	Filter    string                `json:"filter"` //sql where condition, only migrate the filtered rows, eg: "tenant_id = 1"
+	Fields    []FieldCfg            `json:"fields"` //migrate columns, empty means all supported columns
+	MilvusCfg *milvustype.MilvusCfg `json:"milvus"`
+	Rows      int64                 `json:"rows"`
+
+	//columns of the migrate fields read from the table catalog, pk column always in it
+	InnerColumns []*Column `json:"-"`
+}
+
+// SchemaAndName : split the table to schema and table name, schema default is public
+func (tableCfg *TableCfg) SchemaAndName() (string, string) {
+	schema, name, ok := strings.Cut(tableCfg.Table, ".")
+	if !ok {
+		return "public", tableCfg.Table
+	}
+	return schema, name
+}
+
+// GetField : the config of the column, nil if not config
+func (tableCfg *TableCfg) GetField(name string) *FieldCfg {
+	for i := range tableCfg.Fields {
+		if tableCfg.Fields[i].Name == name {
+			return &tableCfg.Fields[i]
+		}
+	}
+	return nil
+}
+
+type FieldCfg struct {
+	Name        string               `json:"name"`
+	MaxLen      int                  `json:"maxLen"`      //varchar max_length, default is the column length or 65535
+	MaxCapacity int                  `json:"maxCapacity"` //array max_capacity, default 4096
+	Index       *milvustype.IndexCfg `json:"index"`       //target milvus field index config, work when milvus.createIndex=true
+}
+
+// Column : postgres column info read from pg_attribute
+type Column struct {
+	Name     string
+	TypeName string //pg_type.typname, eg: int8, varchar, vector, halfvec, sparsevec, _float4
+	ElemType string //element typname of array type
+	TypeMod  int    //vector, halfvec, sparsevec and bit: dim, varchar and bpchar: length + 4, -1 means not set
+	PK       bool
+}
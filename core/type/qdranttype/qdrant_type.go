@@ -0,0 +1,55 @@
+package qdranttype
+
+import (
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+)
+
+type MetaJSON struct {
+	CollectionCfgs []*CollectionCfg `json:"collections"`
+}
+
+// PK type of the target milvus collection
+const (
+	PkTypeInt64   = "int64"
+	PkTypeVarChar = "varchar"
+)
+
+type CollectionCfg struct {
+	Collection string                `json:"collection"` //qdrant collection name
+	PkName     string                `json:"pkName"`     //milvus primary key field name, default: id
+	PkType     string                `json:"pkType"`     //int64 or varchar(uuid id), default detect by the first point id
+	Filter     string                `json:"filter"`     //qdrant filter json, only migrate the filtered points
+	Fields     []FieldCfg            `json:"fields"`     //typed payload fields, and the index config of vector fields
+	MilvusCfg  *milvustype.MilvusCfg `json:"milvus"`
+	Rows       int64                 `json:"rows"`
+
+	//dense and sparse vectors read from the collection config
+	InnerVectors []*Vector `json:"-"`
+}
+
+// GetField : the config of the field, nil if not config
+func (collCfg *CollectionCfg) GetField(name string) *FieldCfg {
+	for i := range collCfg.Fields {
+		if collCfg.Fields[i].Name == name {
+			return &collCfg.Fields[i]
+		}
+	}
+	return nil
+}
+
+type FieldCfg struct {
+	Name        string               `json:"name"`        //payload key or vector field name
+	Type        string               `json:"type"`        //payload milvus type: bool, int8, int16, int32, int64, float, double, varchar, json, array
+	ElementType string               `json:"elementType"` //array element type
+	MaxLen      int                  `json:"maxLen"`      //varchar max_length, default 65535
+	MaxCapacity int                  `json:"maxCapacity"` //array max_capacity, default 4096
+	Index       *milvustype.IndexCfg `json:"index"`       //target milvus field index config, work when milvus.createIndex=true
+}
+
+// Vector : qdrant vector config, the unnamed default vector Name is empty
+type Vector struct {
+	Name     string
+	Size     int
+	Distance string //Cosine, Euclid, Dot, Manhattan
+	Sparse   bool
+}
@@ -61,12 +61,45 @@ func GenerateESDataFilePath(outputDir string, indexName string) string {
 }
 
 func GenerateESDataSubFileName(targetDir string, subTaskNum int, sort int) string {
+	return GenerateESDataSubFileNameWithExt(targetDir, subTaskNum, sort, "json")
+}
+
+func GenerateESDataSubFileNameWithExt(targetDir string, subTaskNum int, sort int, ext string) string {
 	//data_1_1.json, data_1_2.json , data_2_3.json, data_2_4.json
 	fileNmae := strings.Join([]string{"data", strconv.Itoa(subTaskNum), strconv.Itoa(sort)}, "_")
-	fileNmae = strings.Join([]string{fileNmae, "json"}, ".")
+	fileNmae = strings.Join([]string{fileNmae, ext}, ".")
 	return filepath.Join(targetDir, fileNmae)
 }
 
+// GenerateParquetFileName : milvus1x segment or faiss collection parquet files, data_1.parquet, data_2.parquet
+func GenerateParquetFileName(targetDir string, sort int) string {
+	return filepath.Join(targetDir, "data_"+strconv.Itoa(sort)+".parquet")
+}
+
+// GetParquetFileSort : the sort of parquet file generated by GenerateParquetFileName, -1 if not match
+func GetParquetFileSort(fileName string) int {
+	name := filepath.Base(fileName)
+	if !strings.HasPrefix(name, "data_") || !strings.HasSuffix(name, ".parquet") {
+		return -1
+	}
+	sort, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "data_"), ".parquet"))
+	if err != nil {
+		return -1
+	}
+	return sort
+}
+
+// GenerateExportFilePath : files target data file path relative to the output dir, collection/partition/data_1.parquet
+func GenerateExportFilePath(collection string, partition string, sort int, ext string) string {
+	return filepath.Join(collection, partition, "data_"+strconv.Itoa(sort)+"."+ext)
+}
+
+func GenerateESVocabularyFilePath(vocabularyDir string, jobId string, indexName string, fieldName string) (string, string) {
+	targetDir := filepath.Join(vocabularyDir, jobId, indexName)
+	fileName := filepath.Join(targetDir, fieldName+".json")
+	return targetDir, fileName
+}
+
 func GetAddressAndPortFromEndpoint(endpoint string) (string, string, error) {
 	if endpoint == "" {
 		return "", "", errors.New("endpoint cannot empty")
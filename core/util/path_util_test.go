@@ -64,3 +64,14 @@ func TestGetOutputRVFilePath(t *testing.T) {
 	assert.Equal(t, "target/tables/col/seg", targetDir)
 	assert.Equal(t, "target/tables/col/seg/data.npy", targetFileName)
 }
+
+func TestGenerateParquetFileName(t *testing.T) {
+	fileName := GenerateParquetFileName("target/tables/col/seg", 2)
+	assert.Equal(t, "target/tables/col/seg/data_2.parquet", fileName)
+	assert.Equal(t, 2, GetParquetFileSort(fileName))
+	assert.Equal(t, -1, GetParquetFileSort("target/tables/col/seg/data.npy"))
+}
+
+func TestGenerateExportFilePath(t *testing.T) {
+	assert.Equal(t, "col/_default/data_1.jsonl", GenerateExportFilePath("col", "_default", 1, "jsonl"))
+}
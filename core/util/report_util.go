@@ -0,0 +1,29 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// SaveJSONReport : write the report json to local file, create the parent dir if not exist
+func SaveJSONReport(file string, report any) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(file); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(file, b, 0644)
+}
+
+// PassText : the readable pass result of report
+func PassText(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
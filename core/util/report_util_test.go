@@ -0,0 +1,20 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveJSONReport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report", "verify.json")
+	err := SaveJSONReport(file, map[string]any{"passed": true})
+	assert.NoError(t, err)
+	b, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"passed\": true\n}", string(b))
+
+	assert.Equal(t, "PASS", PassText(true))
+	assert.Equal(t, "FAIL", PassText(false))
+}
@@ -0,0 +1,374 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// queryPKBatch : the sampled pks queried from target in batches, avoid the expr too long
+const queryPKBatch = 100
+
+// iterateBatchSize : target iterator batch size when compute the checksums
+const iterateBatchSize = 1000
+
+// sampleRow : one sampled source row, key is field name
+type sampleRow struct {
+	pk     any
+	values map[string]any
+}
+
+// collectionVerifier : collect the source side info of one collection by a single streaming pass,
+// then check with the target collection
+type collectionVerifier struct {
+	cfg     *config.VerifyConfig
+	report  *CollectionReport
+	pkName  string
+	skipped map[string]bool
+
+	mu         sync.Mutex
+	partitions map[string]int64
+	seen       int64
+	samples    []*sampleRow
+	checksums  map[string]*columnChecksum
+	random     *rand.Rand
+	pkMissing  bool //target autoId, the source data not contain pk
+}
+
+func newCollectionVerifier(cfg *config.VerifyConfig, source string, collection string, pkName string) *collectionVerifier {
+	return &collectionVerifier{
+		cfg: cfg,
+		report: &CollectionReport{
+			Source:     source,
+			Collection: collection,
+			Passed:     true,
+		},
+		pkName:     pkName,
+		skipped:    make(map[string]bool),
+		partitions: make(map[string]int64),
+		checksums:  make(map[string]*columnChecksum),
+		random:     rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// addPartition : register the partition, so the empty source partition still compare the count with target
+func (cv *collectionVerifier) addPartition(partition string) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	if _, ok := cv.partitions[partition]; !ok {
+		cv.partitions[partition] = 0
+	}
+}
+
+// skipField : the field not compared by sample and checksum, eg: es sparse vector encode by the job vocabulary
+func (cv *collectionVerifier) skipField(name string, reason string) {
+	if cv.skipped[name] {
+		return
+	}
+	cv.skipped[name] = true
+	cv.report.SkippedFields = append(cv.report.SkippedFields, name)
+	cv.report.Notes = append(cv.report.Notes, fmt.Sprintf("field %s skipped: %s", name, reason))
+}
+
+// AddBatch : count the rows, reservoir sample the rows and add the checksums, safe for concurrent call
+func (cv *collectionVerifier) AddBatch(partition string, columns []entity.Column) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	var pkCol entity.Column
+	for _, col := range columns {
+		if columnName(col) == cv.pkName {
+			pkCol = col
+		}
+	}
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.pkMissing = pkCol == nil
+	rows := columns[0].Len()
+	cv.partitions[partition] += int64(rows)
+	for i := 0; i < rows; i++ {
+		cv.seen++
+		if pkCol != nil {
+			err := cv.sample(pkCol, columns, i)
+			if err != nil {
+				return err
+			}
+		}
+		if cv.cfg.Checksum {
+			err := cv.addChecksum(cv.checksums, columns, i)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cv *collectionVerifier) sample(pkCol entity.Column, columns []entity.Column, idx int) error {
+	slot := len(cv.samples)
+	if slot >= cv.cfg.SampleSize {
+		slot = int(cv.random.Int63n(cv.seen))
+		if slot >= cv.cfg.SampleSize {
+			return nil
+		}
+	}
+	pk, err := columnValue(pkCol, idx)
+	if err != nil {
+		return err
+	}
+	row := &sampleRow{pk: pk, values: make(map[string]any, len(columns))}
+	for _, col := range columns {
+		name := columnName(col)
+		if cv.skipped[name] {
+			continue
+		}
+		row.values[name], err = columnValue(col, idx)
+		if err != nil {
+			return err
+		}
+	}
+	if slot == len(cv.samples) {
+		cv.samples = append(cv.samples, row)
+	} else {
+		cv.samples[slot] = row
+	}
+	return nil
+}
+
+func (cv *collectionVerifier) addChecksum(checksums map[string]*columnChecksum, columns []entity.Column, idx int) error {
+	for _, col := range columns {
+		name := columnName(col)
+		if cv.skipped[name] {
+			continue
+		}
+		v, err := columnValue(col, idx)
+		if err != nil {
+			return err
+		}
+		checksum, ok := checksums[name]
+		if !ok {
+			checksum = &columnChecksum{}
+			checksums[name] = checksum
+		}
+		checksum.add(v)
+	}
+	return nil
+}
+
+// check : compare the partition counts, the sampled rows and the checksums with target collection
+func (cv *collectionVerifier) check(ctx context.Context, targetCli *milvus2x.Milvus2xClient) (*CollectionReport, error) {
+	err := cv.checkCount(ctx, targetCli)
+	if err != nil {
+		return nil, err
+	}
+	if cv.pkMissing {
+		cv.report.Notes = append(cv.report.Notes, fmt.Sprintf("pk field %s not in source data, sample skipped", cv.pkName))
+	} else {
+		err = cv.checkSamples(ctx, targetCli)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cv.cfg.Checksum {
+		err = cv.checkChecksums(ctx, targetCli)
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.LL(ctx).Info("[Verify] verify collection finish", zap.String("collection", cv.report.Collection),
+		zap.Bool("passed", cv.report.Passed), zap.Int("sampleMismatches", cv.report.SampleMismatches))
+	return cv.report, nil
+}
+
+func (cv *collectionVerifier) checkCount(ctx context.Context, targetCli *milvus2x.Milvus2xClient) error {
+	names := make([]string, 0, len(cv.partitions))
+	for name := range cv.partitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collCfg := &milvus2xtype.CollectionCfg{Collection: cv.report.Collection}
+	for _, name := range names {
+		targetRows, err := targetCli.VerCli.CountPartition(ctx, collCfg, name)
+		if err != nil {
+			log.Error("[Verify] count target partition error", zap.String("collection", cv.report.Collection),
+				zap.String("partition", name), zap.Error(err))
+			return err
+		}
+		partReport := &PartitionReport{
+			Name:       name,
+			SourceRows: cv.partitions[name],
+			TargetRows: targetRows,
+			Passed:     cv.partitions[name] == targetRows,
+		}
+		if !partReport.Passed {
+			cv.addMismatch("partition %q count source=%d target=%d", name, partReport.SourceRows, targetRows)
+		}
+		cv.report.Partitions = append(cv.report.Partitions, partReport)
+	}
+	return nil
+}
+
+func (cv *collectionVerifier) checkSamples(ctx context.Context, targetCli *milvus2x.Milvus2xClient) error {
+	cv.report.SampleSize = len(cv.samples)
+	if len(cv.samples) == 0 {
+		return nil
+	}
+	outputFields := make([]string, 0, len(cv.samples[0].values))
+	for name := range cv.samples[0].values {
+		outputFields = append(outputFields, name)
+	}
+	sort.Strings(outputFields)
+
+	for start := 0; start < len(cv.samples); start += queryPKBatch {
+		end := start + queryPKBatch
+		if end > len(cv.samples) {
+			end = len(cv.samples)
+		}
+		batch := cv.samples[start:end]
+		pkValues := make([]string, 0, len(batch))
+		for _, row := range batch {
+			pkValues = append(pkValues, pkExprValue(row.pk))
+		}
+		expr := fmt.Sprintf("%s in [%s]", cv.pkName, strings.Join(pkValues, ","))
+		columns, err := targetCli.VerCli.Query(ctx, cv.report.Collection, common.EMPTY, expr, outputFields)
+		if err != nil {
+			log.Error("[Verify] query target sample rows error", zap.String("collection", cv.report.Collection), zap.Error(err))
+			return err
+		}
+		targetRows, err := toRowsByPK(columns, cv.pkName)
+		if err != nil {
+			return err
+		}
+		for _, row := range batch {
+			cv.compareRow(row, targetRows[pkExprValue(row.pk)])
+		}
+	}
+	return nil
+}
+
+func (cv *collectionVerifier) compareRow(row *sampleRow, targetRow map[string]any) {
+	if targetRow == nil {
+		cv.report.SampleMismatches++
+		cv.addMismatch("pk %v not found in target", row.pk)
+		return
+	}
+	mismatch := false
+	for name, value := range row.values {
+		if !valueEqual(value, targetRow[name], cv.cfg.Tolerance) {
+			mismatch = true
+			cv.addMismatch("pk %v field %s value not match, source=%v target=%v", row.pk, name,
+				shortValue(value), shortValue(targetRow[name]))
+		}
+	}
+	if mismatch {
+		cv.report.SampleMismatches++
+	}
+}
+
+func (cv *collectionVerifier) checkChecksums(ctx context.Context, targetCli *milvus2x.Milvus2xClient) error {
+	fieldNames := make([]string, 0, len(cv.checksums))
+	for name := range cv.checksums {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+	if len(fieldNames) == 0 {
+		return nil
+	}
+	collCfg := &milvus2xtype.CollectionCfg{Collection: cv.report.Collection}
+	err := targetCli.VerCli.InitIterator(ctx, collCfg, iterateBatchSize, common.EMPTY, common.EMPTY, fieldNames)
+	if err != nil {
+		return err
+	}
+	targetChecksums := make(map[string]*columnChecksum, len(fieldNames))
+	for {
+		data, err := targetCli.VerCli.IterateNext(ctx)
+		if err != nil {
+			log.Error("[Verify] iterate target collection error", zap.String("collection", cv.report.Collection), zap.Error(err))
+			return err
+		}
+		if data.IsEmpty {
+			break
+		}
+		columns := make([]entity.Column, 0, len(fieldNames))
+		for _, col := range data.Columns {
+			if _, ok := cv.checksums[columnName(col)]; ok {
+				columns = append(columns, col)
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		for i := 0; i < columns[0].Len(); i++ {
+			err = cv.addChecksum(targetChecksums, columns, i)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range fieldNames {
+		targetChecksum, ok := targetChecksums[name]
+		if !ok {
+			targetChecksum = &columnChecksum{}
+		}
+		checksumReport := &ChecksumReport{
+			Field:  name,
+			Source: cv.checksums[name].String(),
+			Target: targetChecksum.String(),
+			Passed: *cv.checksums[name] == *targetChecksum,
+		}
+		if !checksumReport.Passed {
+			cv.addMismatch("field %s checksum not match, source=%s target=%s", name, checksumReport.Source, checksumReport.Target)
+		}
+		cv.report.Checksums = append(cv.report.Checksums, checksumReport)
+	}
+	return nil
+}
+
+func (cv *collectionVerifier) addMismatch(format string, args ...any) {
+	cv.report.addMismatch(format, args...)
+}
+
+// toRowsByPK : target query result columns to rows, key is the pk expr value
+func toRowsByPK(columns []entity.Column, pkName string) (map[string]map[string]any, error) {
+	rows := make(map[string]map[string]any)
+	var pkCol entity.Column
+	for _, col := range columns {
+		if columnName(col) == pkName {
+			pkCol = col
+		}
+	}
+	if pkCol == nil {
+		return rows, nil
+	}
+	for i := 0; i < pkCol.Len(); i++ {
+		row := make(map[string]any, len(columns))
+		for _, col := range columns {
+			v, err := columnValue(col, i)
+			if err != nil {
+				return nil, err
+			}
+			row[columnName(col)] = v
+		}
+		rows[pkExprValue(row[pkName])] = row
+	}
+	return rows, nil
+}
+
+// shortValue : long vector value only print the head in report
+func shortValue(v any) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) > 64 {
+		return s[:64] + "..."
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"fmt"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"strings"
+)
+
+// maxMismatchDetails : the mismatch details recorded of each collection, the others only counted
+const maxMismatchDetails = 20
+
+// Report : verify result of all collections, Passed only when all collections passed
+type Report struct {
+	JobId       string              `json:"jobId"`
+	SourceType  string              `json:"sourceType"`
+	Passed      bool                `json:"passed"`
+	Collections []*CollectionReport `json:"collections"`
+}
+
+type CollectionReport struct {
+	Source           string             `json:"source"` //source collection, es index or faiss file
+	Collection       string             `json:"collection"`
+	Passed           bool               `json:"passed"`
+	Partitions       []*PartitionReport `json:"partitions"`
+	SampleSize       int                `json:"sampleSize"`
+	SampleMismatches int                `json:"sampleMismatches"`
+	SkippedFields    []string           `json:"skippedFields,omitempty"`
+	Checksums        []*ChecksumReport  `json:"checksums,omitempty"`
+	Notes            []string           `json:"notes,omitempty"`
+	Mismatches       []string           `json:"mismatches,omitempty"`
+}
+
+type PartitionReport struct {
+	Name       string `json:"name"` //empty means the whole collection
+	SourceRows int64  `json:"sourceRows"`
+	TargetRows int64  `json:"targetRows"`
+	Passed     bool   `json:"passed"`
+}
+
+type ChecksumReport struct {
+	Field  string `json:"field"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Passed bool   `json:"passed"`
+}
+
+func (cr *CollectionReport) addMismatch(format string, args ...any) {
+	cr.Passed = false
+	if len(cr.Mismatches) < maxMismatchDetails {
+		cr.Mismatches = append(cr.Mismatches, fmt.Sprintf(format, args...))
+	}
+}
+
+func (r *Report) addCollection(cr *CollectionReport) {
+	r.Collections = append(r.Collections, cr)
+	r.Passed = r.Passed && cr.Passed
+}
+
+// String : the readable report text
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Verify Job %s sourceType=%s result=%s\n", r.JobId, r.SourceType, util.PassText(r.Passed))
+	for _, cr := range r.Collections {
+		fmt.Fprintf(&sb, "  collection %s (source %s): %s\n", cr.Collection, cr.Source, util.PassText(cr.Passed))
+		for _, p := range cr.Partitions {
+			name := p.Name
+			if name == "" {
+				name = "*"
+			}
+			fmt.Fprintf(&sb, "    count partition=%s source=%d target=%d %s\n", name, p.SourceRows, p.TargetRows, util.PassText(p.Passed))
+		}
+		fmt.Fprintf(&sb, "    sample size=%d mismatches=%d\n", cr.SampleSize, cr.SampleMismatches)
+		for _, c := range cr.Checksums {
+			fmt.Fprintf(&sb, "    checksum field=%s source=%s target=%s %s\n", c.Field, c.Source, c.Target, util.PassText(c.Passed))
+		}
+		if len(cr.SkippedFields) > 0 {
+			fmt.Fprintf(&sb, "    skipped fields: %s\n", strings.Join(cr.SkippedFields, ","))
+		}
+		for _, note := range cr.Notes {
+			fmt.Fprintf(&sb, "    note: %s\n", note)
+		}
+		for _, m := range cr.Mismatches {
+			fmt.Fprintf(&sb, "    mismatch: %s\n", m)
+		}
+	}
+	return sb.String()
+}
+
+// Save : write the report json to local file
+func (r *Report) Save(file string) error {
+	return util.SaveJSONReport(file, r)
+}
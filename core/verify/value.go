@@ -0,0 +1,176 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// columnName : dynamic column name of source/target may be empty, use $meta as its name
+func columnName(col entity.Column) string {
+	if jsonCol, ok := col.(*entity.ColumnJSONBytes); ok && (jsonCol.IsDynamic() || col.Name() == common.EMPTY) {
+		return common.MILVUS_META_FD
+	}
+	return col.Name()
+}
+
+// columnValue : normalize the row value for compare, int types to int64, float types to float64,
+// json to the unmarshalled value, sparse vector to map, other vectors keep their slice
+func columnValue(col entity.Column, idx int) (any, error) {
+	switch c := col.(type) {
+	case *entity.ColumnBool:
+		return c.Data()[idx], nil
+	case *entity.ColumnInt8:
+		return int64(c.Data()[idx]), nil
+	case *entity.ColumnInt16:
+		return int64(c.Data()[idx]), nil
+	case *entity.ColumnInt32:
+		return int64(c.Data()[idx]), nil
+	case *entity.ColumnInt64:
+		return c.Data()[idx], nil
+	case *entity.ColumnFloat:
+		return float64(c.Data()[idx]), nil
+	case *entity.ColumnDouble:
+		return c.Data()[idx], nil
+	case *entity.ColumnVarChar:
+		return c.Data()[idx], nil
+	case *entity.ColumnString:
+		return c.Data()[idx], nil
+	case *entity.ColumnJSONBytes:
+		return jsonValue(c.Data()[idx]), nil
+	case *entity.ColumnFloatVector:
+		return c.Data()[idx], nil
+	case *entity.ColumnBinaryVector:
+		return c.Data()[idx], nil
+	case *entity.ColumnFloat16Vector:
+		return c.Data()[idx], nil
+	case *entity.ColumnBFloat16Vector:
+		return c.Data()[idx], nil
+	case *entity.ColumnSparseFloatVector:
+		embedding := c.Data()[idx]
+		sparse := make(map[uint32]float32, embedding.Len())
+		for i := 0; i < embedding.Len(); i++ {
+			pos, val, _ := embedding.Get(i)
+			sparse[pos] = val
+		}
+		return sparse, nil
+	case *entity.ColumnBoolArray:
+		return c.Data()[idx], nil
+	case *entity.ColumnInt8Array:
+		return toInt64s(c.Data()[idx]), nil
+	case *entity.ColumnInt16Array:
+		return toInt64s(c.Data()[idx]), nil
+	case *entity.ColumnInt32Array:
+		return toInt64s(c.Data()[idx]), nil
+	case *entity.ColumnInt64Array:
+		return c.Data()[idx], nil
+	case *entity.ColumnFloatArray:
+		return toFloat64s(c.Data()[idx]), nil
+	case *entity.ColumnDoubleArray:
+		return c.Data()[idx], nil
+	case *entity.ColumnVarCharArray:
+		values := make([]string, 0, len(c.Data()[idx]))
+		for _, v := range c.Data()[idx] {
+			values = append(values, string(v))
+		}
+		return values, nil
+	default:
+		return col.Get(idx)
+	}
+}
+
+func jsonValue(b []byte) any {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return string(b)
+	}
+	return v
+}
+
+func toInt64s[T int8 | int16 | int32](values []T) []int64 {
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		result = append(result, int64(v))
+	}
+	return result
+}
+
+func toFloat64s(values []float32) []float64 {
+	result := make([]float64, 0, len(values))
+	for _, v := range values {
+		result = append(result, float64(v))
+	}
+	return result
+}
+
+// valueEqual : scalar value compare exactly, float vector and sparse vector compare within the abs tolerance
+func valueEqual(a any, b any, tolerance float64) bool {
+	switch av := a.(type) {
+	case []float32:
+		bv, ok := b.([]float32)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if math.Abs(float64(av[i])-float64(bv[i])) > tolerance {
+				return false
+			}
+		}
+		return true
+	case map[uint32]float32:
+		bv, ok := b.(map[uint32]float32)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for pos, val := range av {
+			other, ok := bv[pos]
+			if !ok || math.Abs(float64(val)-float64(other)) > tolerance {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// valueHash : hash of the normalized value, json encoding sort the map keys so the same value has the same hash
+func valueHash(v any) uint64 {
+	h := fnv.New64a()
+	b, err := json.Marshal(v)
+	if err != nil {
+		b = []byte(fmt.Sprintf("%v", v))
+	}
+	h.Write(b)
+	return h.Sum64()
+}
+
+// pkExprValue : pk value in the query expr, int64 or varchar
+func pkExprValue(pk any) string {
+	switch v := pk.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return strconv.Quote(fmt.Sprintf("%v", v))
+	}
+}
+
+// columnChecksum : order-independent checksum, the wrapping sum of each row value hash
+type columnChecksum struct {
+	sum   uint64
+	count int64
+}
+
+func (c *columnChecksum) add(v any) {
+	c.sum += valueHash(v)
+	c.count++
+}
+
+func (c *columnChecksum) String() string {
+	return fmt.Sprintf("%016x/%d", c.sum, c.count)
+}
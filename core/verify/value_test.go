@@ -0,0 +1,73 @@
+package verify
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"testing"
+)
+
+func TestColumnValue(t *testing.T) {
+	v, err := columnValue(entity.NewColumnInt32("age", []int32{3, 4}), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), v)
+
+	v, err = columnValue(entity.NewColumnJSONBytes("", [][]byte{[]byte(`{"b":1,"a":"x"}`)}).WithIsDynamic(true), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": "x", "b": float64(1)}, v)
+
+	v, err = columnValue(entity.NewColumnVarCharArray("tags", [][][]byte{{[]byte("a"), []byte("b")}}), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, v)
+
+	assert.Equal(t, "$meta", columnName(entity.NewColumnJSONBytes("", nil).WithIsDynamic(true)))
+	assert.Equal(t, "attr", columnName(entity.NewColumnJSONBytes("attr", nil)))
+}
+
+func TestValueEqual(t *testing.T) {
+	assert.True(t, valueEqual([]float32{0.1, 0.2}, []float32{0.1, 0.2000001}, 1e-6))
+	assert.False(t, valueEqual([]float32{0.1, 0.2}, []float32{0.1, 0.21}, 1e-6))
+	assert.False(t, valueEqual([]float32{0.1}, []float32{0.1, 0.2}, 1e-6))
+	assert.True(t, valueEqual(map[uint32]float32{1: 0.5}, map[uint32]float32{1: 0.5}, 1e-6))
+	assert.False(t, valueEqual(map[uint32]float32{1: 0.5}, map[uint32]float32{2: 0.5}, 1e-6))
+	assert.True(t, valueEqual(int64(1), int64(1), 1e-6))
+	assert.False(t, valueEqual(int64(1), "1", 1e-6))
+
+	assert.Equal(t, "12", pkExprValue(int64(12)))
+	assert.Equal(t, `"a\"b"`, pkExprValue(`a"b`))
+}
+
+func TestChecksumOrderIndependent(t *testing.T) {
+	a, b := &columnChecksum{}, &columnChecksum{}
+	for _, v := range []any{int64(1), "x", map[string]any{"k": 1.0}} {
+		a.add(v)
+	}
+	for _, v := range []any{map[string]any{"k": 1.0}, "x", int64(1)} {
+		b.add(v)
+	}
+	assert.Equal(t, *a, *b)
+	assert.Equal(t, a.String(), b.String())
+}
+
+func TestCollectionVerifierAddBatch(t *testing.T) {
+	cv := newCollectionVerifier(&config.VerifyConfig{SampleSize: 3, Checksum: true}, "src", "dst", "id")
+	cv.addPartition("p0")
+	for i := 0; i < 4; i++ {
+		ids := []int64{int64(i*10 + 1), int64(i*10 + 2), int64(i*10 + 3)}
+		err := cv.AddBatch("p1", []entity.Column{
+			entity.NewColumnInt64("id", ids),
+			entity.NewColumnVarChar("name", []string{"a", "b", "c"}),
+		})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int64{"p0": 0, "p1": 12}, cv.partitions)
+	assert.Len(t, cv.samples, 3)
+	assert.False(t, cv.pkMissing)
+	assert.Equal(t, int64(12), cv.checksums["name"].count)
+
+	//target autoId, source data not contain pk
+	cv = newCollectionVerifier(&config.VerifyConfig{SampleSize: 3}, "src", "dst", "id")
+	assert.NoError(t, cv.AddBatch("", []entity.Column{entity.NewColumnVarChar("name", []string{"a"})}))
+	assert.True(t, cv.pkMissing)
+	assert.Empty(t, cv.samples)
+}
@@ -0,0 +1,260 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/dumper"
+	"github.com/zilliztech/milvus-migration/core/factory/milvus2x_factory"
+	"github.com/zilliztech/milvus-migration/core/meta"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
+	esparser "github.com/zilliztech/milvus-migration/core/transform/es/parser"
+	milvus2xconvert "github.com/zilliztech/milvus-migration/core/transform/milvus2x/convert"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/core/type/milvustype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"time"
+)
+
+// numpyPKField : pk field name of the faiss and milvus1x target collection
+const numpyPKField = "id"
+
+// Verifier : verify the migrated target collections with the source, the source data read by the migration source readers
+type Verifier struct {
+	cfg   *config.MigrationConfig
+	jobId string
+}
+
+func NewVerifier(cfg *config.MigrationConfig, jobId string) *Verifier {
+	return &Verifier{cfg: cfg, jobId: jobId}
+}
+
+// Run : verify all collections of the config, the report returned even some collections not passed
+func (v *Verifier) Run(ctx context.Context) (*Report, error) {
+	if v.cfg.TargetType == string(common.T_FILES) {
+		return nil, fmt.Errorf("verify not support files target")
+	}
+	start := time.Now()
+	report := &Report{JobId: v.jobId, SourceType: v.cfg.DumperWorkCfg.WorkMode, Passed: true}
+	var err error
+	switch common.DumpMode(v.cfg.DumperWorkCfg.WorkMode) {
+	case common.Milvus2x:
+		err = v.verifyMilvus2x(ctx, report)
+	case common.Elasticsearch:
+		err = v.verifyES(ctx, report)
+	case common.Faiss:
+		err = v.verifyFaiss(ctx, report)
+	case common.Milvus1x:
+		err = v.verifyMilvus1x(ctx, report)
+	default:
+		err = fmt.Errorf("verify not support WorkMode %s", v.cfg.DumperWorkCfg.WorkMode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.LL(ctx).Info("[Verify] verify finish", zap.String("jobId", v.jobId), zap.Bool("passed", report.Passed),
+		zap.Int("collections", len(report.Collections)), zap.Float64("Cost", time.Since(start).Seconds()))
+	return report, nil
+}
+
+func (v *Verifier) verifyMilvus2x(ctx context.Context, report *Report) error {
+	dp := dumper.NewDumperWithConfig(v.cfg, v.jobId)
+	collCfgsArray, err := dp.InitDumpInMilvus2xMode(ctx)
+	if err != nil {
+		return err
+	}
+	for _, collCfgs := range collCfgsArray {
+		for _, collCfg := range collCfgs {
+			cr, err := v.verifyMilvus2xCollection(ctx, dp, collCfg)
+			if err != nil {
+				log.Error("[Verify] verify Milvus2x collection err", zap.String("collection", collCfg.Collection), zap.Error(err))
+				return err
+			}
+			report.addCollection(cr)
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) verifyMilvus2xCollection(ctx context.Context, dp *dumper.Dumper, collCfg *milvus2xtype.CollectionCfg) (*CollectionReport, error) {
+	milvus2xCli := milvus2x_factory.GetMilvus2xCli(v.cfg.SourceMilvus2xConfig)
+	collectionInfo, err := milvus2xconvert.ToMilvusParam(ctx, collCfg, milvus2xCli)
+	if err != nil {
+		return nil, err
+	}
+	cv := newCollectionVerifier(v.cfg.VerifyCfg, collCfg.Collection, collectionInfo.Param.CollectionName, pkFieldName(collectionInfo.Fields))
+	for _, partition := range collCfg.Partitions {
+		cv.addPartition(partition.Name)
+	}
+	if collCfg.Partitions == nil {
+		cv.addPartition(common.EMPTY)
+	}
+
+	dataChannel := make(chan *milvus2x.Milvus2xData, 200)
+	g, subCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		for data := range dataChannel {
+			err := cv.AddBatch(data.Partition, data.Columns)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	g.Go(func() error {
+		err := dp.ReadData2Channel(subCtx, collCfg, dataChannel)
+		close(dataChannel)
+		return err
+	})
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return v.checkTarget(ctx, cv)
+}
+
+func (v *Verifier) verifyES(ctx context.Context, report *Report) error {
+	dp := dumper.NewDumperWithConfig(v.cfg, v.jobId)
+	idxListArray, err := dp.InitDumpInEsMode(ctx)
+	if err != nil {
+		return err
+	}
+	for _, idxList := range idxListArray {
+		for _, idxCfg := range idxList {
+			cr, err := v.verifyESIndex(ctx, idxCfg)
+			if err != nil {
+				log.Error("[Verify] verify ES index err", zap.String("index", idxCfg.Index), zap.Error(err))
+				return err
+			}
+			report.addCollection(cr)
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) verifyESIndex(ctx context.Context, idxCfg *estype.IdxCfg) (*CollectionReport, error) {
+	collectionInfo, err := esconvert.ToMilvusParam(idxCfg)
+	if err != nil {
+		return nil, err
+	}
+	cv := newCollectionVerifier(v.cfg.VerifyCfg, idxCfg.Index, collectionInfo.Param.CollectionName, pkFieldName(collectionInfo.Fields))
+	cv.addPartition(common.EMPTY)
+	for _, field := range collectionInfo.Fields {
+		if field.DataType == entity.FieldTypeSparseVector {
+			cv.skipField(field.Name, "es sparse vector indices encoded by the job vocabulary")
+		}
+	}
+	esSource := source.NewESSource(idxCfg, v.cfg)
+	err = esSource.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	enableDynamic := !idxCfg.MilvusCfg.CloseDynamicField
+	g, subCtx := errgroup.WithContext(ctx)
+	for i := 0; i < v.cfg.LoaderWorkLimit; i++ {
+		g.Go(func() error {
+			for data := range esSource.DataChannel {
+				if subCtx.Err() != nil {
+					return subCtx.Err()
+				}
+				if data.IsEmpty {
+					continue
+				}
+				columns, err := esparser.ToMilvusColumns(&data.Hits, idxCfg, collectionInfo.Fields, enableDynamic)
+				if err != nil {
+					return err
+				}
+				err = cv.AddBatch(common.EMPTY, columns)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		if esSource.IsPITMode() {
+			return esSource.ReadByPIT(subCtx)
+		}
+		return esSource.ReadByScroll(subCtx)
+	})
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return v.checkTarget(ctx, cv)
+}
+
+func (v *Verifier) verifyFaiss(ctx context.Context, report *Report) error {
+	collection := v.cfg.LoaderWorkCfg.CreateColCfg.CollectionName
+	cv := newCollectionVerifier(v.cfg.VerifyCfg, v.cfg.SourceFaissFile, collection, numpyPKField)
+	cv.addPartition(common.EMPTY)
+	err := dumper.ReadFaissColumns(ctx, v.cfg, func(columns []entity.Column) error {
+		return cv.AddBatch(common.EMPTY, columns)
+	})
+	if err != nil {
+		return err
+	}
+	cr, err := v.checkTarget(ctx, cv)
+	if err != nil {
+		return err
+	}
+	report.addCollection(cr)
+	return nil
+}
+
+func (v *Verifier) verifyMilvus1x(ctx context.Context, report *Report) error {
+	metaJson, err := meta.NewMetaHelperForDumper(v.cfg).ReadMeta(ctx)
+	if err != nil {
+		return err
+	}
+	for _, colInfo := range metaJson.Collections {
+		cr, err := v.verifyMilvus1xCollection(ctx, colInfo)
+		if err != nil {
+			log.Error("[Verify] verify Milvus1x collection err", zap.String("collection", colInfo.Collection), zap.Error(err))
+			return err
+		}
+		report.addCollection(cr)
+	}
+	return nil
+}
+
+func (v *Verifier) verifyMilvus1xCollection(ctx context.Context, colInfo milvustype.ColInfo) (*CollectionReport, error) {
+	cv := newCollectionVerifier(v.cfg.VerifyCfg, colInfo.Collection, colInfo.Collection, numpyPKField)
+	cv.addPartition(common.EMPTY)
+	for _, segment := range colInfo.Segments {
+		err := dumper.ReadMilvus1xColumns(ctx, v.cfg, segment, func(columns []entity.Column) error {
+			return cv.AddBatch(common.EMPTY, columns)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v.checkTarget(ctx, cv)
+}
+
+func (v *Verifier) checkTarget(ctx context.Context, cv *collectionVerifier) (*CollectionReport, error) {
+	targetCli, err := milvus2x.CreateMilvus2xClient(v.cfg.TargetMilvus2xCfg)
+	if err != nil {
+		return nil, err
+	}
+	defer targetCli.VerCli.Close()
+	return cv.check(ctx, targetCli)
+}
+
+func pkFieldName(fields []*entity.Field) string {
+	for _, field := range fields {
+		if field.PrimaryKey {
+			return field.Name
+		}
+	}
+	return common.EMPTY
+}
@@ -38,6 +38,17 @@ func newFaissIdReader(cfg *config.ReadConfig) (reader.Publisher, error) {
 	return idReader, nil
 }
 
+// ReadFaissHead : read the faiss index file header, return the vector dim and total rows
+func ReadFaissHead(cfg *config.ReadConfig) (int, int, error) {
+	idReader := reader.NewFaissIdReader(cfg.FileParam, cfg.BufSize)
+	readSource, err := newReadSource(cfg, cfg.FileParam)
+	if err != nil {
+		return 0, 0, err
+	}
+	idReader.SetReadSources(readSource)
+	return idReader.ReadHeadInfo()
+}
+
 func newFaissDataReader(cfg *config.ReadConfig) (reader.Publisher, error) {
 	idReader := reader.NewFaissDataReader(cfg.FileParam, cfg.BufSize)
 	readSource, err := newReadSource(cfg, cfg.FileParam)
@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/dataqueue"
+	"github.com/zilliztech/milvus-migration/core/reader"
+	npconvert "github.com/zilliztech/milvus-migration/core/transform/numpy"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/core/writer"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"io"
+	"strconv"
+)
+
+// milvus1x&faiss collection field name, same as the numpy file name
+const (
+	numpyIdField   = "id"
+	numpyDataField = "data"
+)
+
+// rows bytes of every batch read from numpy stream
+const numpyBatchBytes = 1024 * 1024 * 4
+
+// NewParquetWriter : parquet writer write to the local file or remote by WriteConfig
+func NewParquetWriter(ctx context.Context, cfg *config.WriteConfig, fields []*entity.Field, enableDynamic bool) (*writer.ParquetWriter, error) {
+	wr, err := newWriter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return writer.NewParquetWriter(ctx, wr, fields, enableDynamic)
+}
+
+// NumpyColumnWorker : combine the id and vector numpy stream of two readers by row to milvus columns batches
+type NumpyColumnWorker struct {
+	idReader   reader.Publisher
+	dataReader reader.Publisher
+}
+
+func NewNumpyColumnWorker(idReadCfg *config.ReadConfig, dataReadCfg *config.ReadConfig) (*NumpyColumnWorker, error) {
+	idReader, err := newReader(idReadCfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	dataReader, err := newReader(dataReadCfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NumpyColumnWorker{
+		idReader:   idReader,
+		dataReader: dataReader,
+	}, nil
+}
+
+// Work : handle is called by each batch in order, last is true for the last batch
+func (this *NumpyColumnWorker) Work(ctx context.Context, handle func(columns []entity.Column, last bool) error) error {
+	idQueue := dataqueue.NewIOQueue()
+	dataQueue := dataqueue.NewIOQueue()
+
+	g, subCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		err, _ := produce(subCtx, this.idReader, idQueue)
+		return err
+	})
+	g.Go(func() error {
+		err, _ := produce(subCtx, this.dataReader, dataQueue)
+		return err
+	})
+	g.Go(func() error {
+		err := this.combine(idQueue.GetReader(), dataQueue.GetReader(), handle)
+		if err != nil {
+			//stop the readers
+			idQueue.CloseReader(err)
+			dataQueue.CloseReader(err)
+		}
+		return err
+	})
+	return g.Wait()
+}
+
+func (this *NumpyColumnWorker) combine(idStream io.Reader, dataStream io.Reader, handle func(columns []entity.Column, last bool) error) error {
+	idR := bufio.NewReader(idStream)
+	dataR := bufio.NewReader(dataStream)
+	_, idShape, err := npconvert.ReadNumpyHead(idR)
+	if err != nil {
+		return err
+	}
+	_, dataShape, err := npconvert.ReadNumpyHead(dataR)
+	if err != nil {
+		return err
+	}
+	if len(idShape) != 1 || len(dataShape) != 2 || idShape[0] != dataShape[0] {
+		return fmt.Errorf("id shape %v not match data shape %v", idShape, dataShape)
+	}
+	rows, dim := idShape[0], dataShape[1]
+	batchRows := numpyBatchBytes / (8 + dim*4)
+	if batchRows <= 0 {
+		batchRows = 1
+	}
+
+	for read := 0; read < rows; {
+		n := batchRows
+		if rows-read < n {
+			n = rows - read
+		}
+		ids := make([]int64, n)
+		if err := binary.Read(idR, binary.LittleEndian, ids); err != nil {
+			return err
+		}
+		floats := make([]float32, n*dim)
+		if err := binary.Read(dataR, binary.LittleEndian, floats); err != nil {
+			return err
+		}
+		vectors := make([][]float32, 0, n)
+		for i := 0; i < n; i++ {
+			vectors = append(vectors, floats[i*dim:(i+1)*dim])
+		}
+		read += n
+
+		err = handle([]entity.Column{
+			entity.NewColumnInt64(numpyIdField, ids),
+			entity.NewColumnFloatVector(numpyDataField, dim, vectors),
+		}, read == rows)
+		if err != nil {
+			return err
+		}
+	}
+	//drain the remain bytes, avoid reader blocked on pipe
+	io.Copy(io.Discard, idR)
+	io.Copy(io.Discard, dataR)
+	return nil
+}
+
+// NumpyParquetWorker : combine the id and vector numpy stream of two readers by row to parquet files,
+// file will be split when size reach SUB_FILE_SIZE
+type NumpyParquetWorker struct {
+	*NumpyColumnWorker
+	writeCfg *config.WriteConfig
+}
+
+func NewNumpyParquetWorker(idReadCfg *config.ReadConfig, dataReadCfg *config.ReadConfig, writeCfg *config.WriteConfig) (*NumpyParquetWorker, error) {
+	columnWorker, err := NewNumpyColumnWorker(idReadCfg, dataReadCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NumpyParquetWorker{
+		NumpyColumnWorker: columnWorker,
+		writeCfg:          writeCfg,
+	}, nil
+}
+
+// Work : return the parquet files
+func (this *NumpyParquetWorker) Work(ctx context.Context) ([]string, error) {
+	var files []string
+	var pqWriter *writer.ParquetWriter
+	rows := 0
+	err := this.NumpyColumnWorker.Work(ctx, func(columns []entity.Column, last bool) error {
+		var err error
+		if pqWriter == nil {
+			fileName := util.GenerateParquetFileName(this.writeCfg.FileParam.FileDir, len(files)+1)
+			writeCfg := *this.writeCfg
+			fileParam := *this.writeCfg.FileParam
+			fileParam.FileFullName = fileName
+			writeCfg.FileParam = &fileParam
+			pqWriter, err = NewParquetWriter(ctx, &writeCfg, numpyFields(columns[1].(*entity.ColumnFloatVector).Dim()), false)
+			if err != nil {
+				return err
+			}
+			files = append(files, fileName)
+		}
+		err = pqWriter.Write(columns)
+		if err != nil {
+			return err
+		}
+		rows += columns[0].Len()
+		if pqWriter.Size() >= common.SUB_FILE_SIZE || last {
+			err = pqWriter.Close()
+			pqWriter = nil
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if pqWriter != nil {
+			pqWriter.Abort(err)
+		}
+		return nil, err
+	}
+	log.Info("[NumpyParquetWorker] write parquet files finish", zap.Int("rows", rows), zap.Strings("files", files))
+	return files, nil
+}
+
+func numpyFields(dim int) []*entity.Field {
+	return []*entity.Field{
+		{Name: numpyIdField, DataType: entity.FieldTypeInt64, PrimaryKey: true},
+		{Name: numpyDataField, DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{entity.TypeParamDim: strconv.Itoa(dim)}},
+	}
+}
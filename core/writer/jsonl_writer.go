@@ -0,0 +1,76 @@
+package writer
+
+import (
+	"bufio"
+	"context"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/transform/parquet"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+)
+
+// JSONLWriter : write milvus columns as json lines, one row one json object,
+// the value format is the same as the parquet column, json/sparse vector/$meta value is json string
+type JSONLWriter struct {
+	*pipeSink
+	schema *arrow.Schema
+	bw     *bufio.Writer
+	rows   int
+}
+
+func NewJSONLWriter(ctx context.Context, receiver Receiver, fields []*entity.Field, enableDynamic bool) (*JSONLWriter, error) {
+	schema, err := pqconvert.ToArrowSchema(fields, enableDynamic)
+	if err != nil {
+		return nil, err
+	}
+	sink := newPipeSink(ctx, receiver)
+	return &JSONLWriter{
+		pipeSink: sink,
+		schema:   schema,
+		bw:       bufio.NewWriterSize(sink.counter, defaultWriteBufSize),
+	}, nil
+}
+
+func (w *JSONLWriter) Write(columns []entity.Column) error {
+	if len(columns) == 0 || columns[0].Len() == 0 {
+		return nil
+	}
+	rec, err := pqconvert.ToArrowRecord(w.schema, columns)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+	err = array.RecordToJSON(rec, w.bw)
+	if err != nil {
+		return err
+	}
+	w.rows += int(rec.NumRows())
+	return nil
+}
+
+// Size : the file bytes, include the buffered bytes
+func (w *JSONLWriter) Size() int64 {
+	return w.counter.size + int64(w.bw.Buffered())
+}
+
+func (w *JSONLWriter) Rows() int {
+	return w.rows
+}
+
+// Close : flush the buffer and wait the Receiver finish
+func (w *JSONLWriter) Close() error {
+	err := w.bw.Flush()
+	if err != nil {
+		w.Abort(err)
+		return err
+	}
+	err = w.close()
+	if err != nil {
+		log.Error("[JSONL Writer] write jsonl file error", zap.Error(err))
+		return err
+	}
+	log.Info("[JSONL Writer] write jsonl file finish", zap.Int("rows", w.rows), zap.Int64("size", w.counter.size))
+	return nil
+}
@@ -0,0 +1,146 @@
+package writer
+
+import (
+	"context"
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/transform/parquet"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"io"
+)
+
+// flush the buffered row group when its size reach rowGroupSize
+const rowGroupSize = 1024 * 1024 * 64
+
+// ColumnWriter : write milvus columns to a file, the file bytes stream to the Receiver
+type ColumnWriter interface {
+	Write(columns []entity.Column) error
+	Size() int64
+	Rows() int
+	Close() error
+	Abort(err error)
+}
+
+// ParquetWriter : write milvus columns to a parquet file, the file bytes stream to the Receiver(local file or remote)
+type ParquetWriter struct {
+	*pipeSink
+	schema *arrow.Schema
+	fw     *pqarrow.FileWriter
+	rows   int
+}
+
+// pipeSink : the Receiver read the pipe in its own goroutine, close the pipe and wait the Receiver finish
+type pipeSink struct {
+	pw      *io.PipeWriter
+	counter *countWriter
+	done    chan error
+}
+
+// countWriter : record the bytes written, not implement io.Closer, the pipe closed by pipeSink
+type countWriter struct {
+	w    io.Writer
+	size int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.size += int64(n)
+	return n, err
+}
+
+func newPipeSink(ctx context.Context, receiver Receiver) *pipeSink {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := receiver.Execute(ctx, pr)
+		//receiver failed, make the writer return error
+		pr.CloseWithError(err)
+		done <- err
+	}()
+	return &pipeSink{
+		pw:      pw,
+		counter: &countWriter{w: pw},
+		done:    done,
+	}
+}
+
+func (s *pipeSink) close() error {
+	s.pw.Close()
+	return <-s.done
+}
+
+// Abort : stop write, the Receiver will get the error
+func (s *pipeSink) Abort(err error) {
+	s.pw.CloseWithError(err)
+	<-s.done
+}
+
+func NewParquetWriter(ctx context.Context, receiver Receiver, fields []*entity.Field, enableDynamic bool) (*ParquetWriter, error) {
+	schema, err := pqconvert.ToArrowSchema(fields, enableDynamic)
+	if err != nil {
+		return nil, err
+	}
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy),
+		parquet.WithDictionaryDefault(false))
+	//NewFileWriter write the magic header, the receiver must be reading already
+	sink := newPipeSink(ctx, receiver)
+	fw, err := pqarrow.NewFileWriter(schema, sink.counter, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		sink.Abort(err)
+		return nil, err
+	}
+	return &ParquetWriter{
+		pipeSink: sink,
+		schema:   schema,
+		fw:       fw,
+	}, nil
+}
+
+func (w *ParquetWriter) Write(columns []entity.Column) error {
+	if len(columns) == 0 || columns[0].Len() == 0 {
+		return nil
+	}
+	rec, err := pqconvert.ToArrowRecord(w.schema, columns)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+	err = w.fw.WriteBuffered(rec)
+	if err != nil {
+		return err
+	}
+	w.rows += int(rec.NumRows())
+	if w.fw.RowGroupTotalBytesWritten() >= rowGroupSize {
+		w.fw.NewBufferedRowGroup()
+	}
+	return nil
+}
+
+// Size : the parquet file bytes, include the buffered row group
+func (w *ParquetWriter) Size() int64 {
+	return w.counter.size + w.fw.RowGroupTotalBytesWritten()
+}
+
+func (w *ParquetWriter) Rows() int {
+	return w.rows
+}
+
+// Close : write parquet footer and wait the Receiver finish
+func (w *ParquetWriter) Close() error {
+	err := w.fw.Close()
+	if err != nil {
+		w.Abort(err)
+		return err
+	}
+	err = w.close()
+	if err != nil {
+		log.Error("[Parquet Writer] write parquet file error", zap.Error(err))
+		return err
+	}
+	log.Info("[Parquet Writer] write parquet file finish", zap.Int("rows", w.rows), zap.Int64("size", w.counter.size))
+	return nil
+}
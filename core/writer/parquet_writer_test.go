@@ -0,0 +1,45 @@
+package writer
+
+import (
+	"context"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"path/filepath"
+	"testing"
+)
+
+func TestParquetWriter(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "data_1.parquet")
+	fields := []*entity.Field{
+		{Name: "id", DataType: entity.FieldTypeInt64, PrimaryKey: true},
+		{Name: "vec", DataType: entity.FieldTypeFloatVector},
+	}
+	w, err := NewParquetWriter(context.Background(), NewDefaultFileWriter(common.FileParam{FileDir: dir, FileFullName: fileName}),
+		fields, false)
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		err = w.Write([]entity.Column{
+			entity.NewColumnInt64("id", []int64{int64(i*2 + 1), int64(i*2 + 2)}),
+			entity.NewColumnFloatVector("vec", 2, [][]float32{{0.1, 0.2}, {0.3, 0.4}}),
+		})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	assert.Equal(t, 6, w.Rows())
+
+	rdr, err := file.OpenParquetFile(fileName, false)
+	assert.NoError(t, err)
+	defer rdr.Close()
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	assert.NoError(t, err)
+	tbl, err := fr.ReadTable(context.Background())
+	assert.NoError(t, err)
+	defer tbl.Release()
+	assert.Equal(t, int64(6), tbl.NumRows())
+	assert.Equal(t, "vec", tbl.Schema().Field(1).Name)
+}
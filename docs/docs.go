@@ -75,6 +75,12 @@ const docTemplate = `{
                         "name": "jobId",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "return the bulk insert taskId and state of each file",
+                        "name": "withFiles",
+                        "in": "query"
                     }
                 ],
                 "responses": {
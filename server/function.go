@@ -76,6 +76,7 @@ func handleLoad(c *gin.Context) (interface{}, error) {
 // @Tags Migration
 // @Param RequestId header string false "RequestId"
 // @Param jobId query string true "jobId"
+// @Param withFiles query bool false "return the bulk insert taskId and state of each file"
 // @Produce json
 // @Success 200 {object} string
 // @Router /get_job [get]
@@ -86,15 +87,28 @@ func handleGetJob(c *gin.Context) (interface{}, error) {
 	}
 	info, err := gstore.GetJobInfo(jobId)
 	if err != nil {
-		return nil, err
-	}
-	ph := gstore.GetProcessHandler(jobId)
-	if ph != nil {
-		info.JobProcess = ph.CalcProcess()
+		//not run in current server process, try the job history
+		info, err = gstore.GetJobHistory(jobId)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		info.CalculateJobProcess()
+		ph := gstore.GetProcessHandler(jobId)
+		if ph != nil {
+			info.JobProcess = ph.CalcProcess()
+			info.CollProcess = ph.CalcCollectionsProcess()
+		} else {
+			info.CalculateJobProcess()
+		}
+	}
+	if c.Query("withFiles") != "true" {
+		return info, nil
+	}
+	files, err := gstore.ListFileTaskRecords(jobId)
+	if err != nil {
+		return nil, err
 	}
-	return info, nil
+	return param.NewJobDetailResponse(info, files), nil
 }
 
 // @Summary migration start
@@ -1,14 +1,23 @@
 package server
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 type ServerConfig struct {
-	port string
+	port      string
+	store     string        //job store: memory or sqlite
+	storeFile string        //sqlite job store file
+	retention time.Duration //finished job keep time, <= 0 keep forever
 }
 
 func newDefaultServerConfig() *ServerConfig {
 	return &ServerConfig{
-		port: ":8080",
+		port:      ":8080",
+		store:     "memory",
+		storeFile: "migration_jobs.db",
+		retention: time.Hour * 24 * 7,
 	}
 }
 
@@ -23,3 +32,22 @@ func Port(port string) ServerOption {
 		c.port = port
 	}
 }
+
+// Store : the job store type, memory or sqlite, sqlite keep the job history after server restart
+func Store(store string, storeFile string) ServerOption {
+	return func(c *ServerConfig) {
+		if store != "" {
+			c.store = strings.ToLower(store)
+		}
+		if storeFile != "" {
+			c.storeFile = storeFile
+		}
+	}
+}
+
+// Retention : the finished job older than retention will be removed from the job store
+func Retention(retention time.Duration) ServerOption {
+	return func(c *ServerConfig) {
+		c.retention = retention
+	}
+}
@@ -1,12 +1,15 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/zilliztech/milvus-migration/core/gstore"
 	_ "github.com/zilliztech/milvus-migration/docs"
 	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
 	"net/http"
 )
 
@@ -37,12 +40,40 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 	}, nil
 }
 
-func (s *Server) Init() {
+func (s *Server) Init() error {
 	// init global store
 	gstore.Init()
 
+	// init job store
+	err := s.initJobStore()
+	if err != nil {
+		return err
+	}
+
 	// register http
 	s.registerHTTPServer()
+	return nil
+}
+
+func (s *Server) initJobStore() error {
+	log.Info("[Server] init job store", zap.String("store", s.config.store),
+		zap.Duration("retention", s.config.retention))
+	switch s.config.store {
+	case gstore.StoreMemory:
+	case gstore.StoreSqlite:
+		store, err := gstore.NewSqliteJobStore(s.config.storeFile)
+		if err != nil {
+			return err
+		}
+		err = gstore.UseStore(store)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("not support job store %s, only support memory or sqlite", s.config.store)
+	}
+	gstore.StartRetention(context.Background(), s.config.retention)
+	return nil
 }
 
 func (s *Server) Start() {
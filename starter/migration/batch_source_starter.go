@@ -0,0 +1,164 @@
+package migration
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/gstore"
+	"github.com/zilliztech/milvus-migration/core/task"
+	"github.com/zilliztech/milvus-migration/core/util"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"time"
+)
+
+// unknownRows : the source can't count rows before read, process total size set after the source read finish
+const unknownRows int64 = -1
+
+// batchSource : a source collection migrated by batch insert, read its rows and convert to milvus columns
+type batchSource interface {
+	// Name : the source collection or table name
+	Name() string
+	// Describe : describe the source collection, return its rows or unknownRows
+	Describe(ctx context.Context) (int64, error)
+	// CollectionInfo : the target collection converted by the source collection, call after Describe
+	CollectionInfo() (*common.CollectionInfo, error)
+	// ReadAll : read all rows to the dataChannel, return the read rows, not close the dataChannel
+	ReadAll(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) (int64, error)
+}
+
+type batchCollection struct {
+	source batchSource
+	info   *common.CollectionInfo
+	rows   int64
+}
+
+// migrationBatchSources : describe all sources, then create the target collections and batch insert by DumperWorkLimit concurrently,
+// files target write the rows to files instead
+func (starter *Starter) migrationBatchSources(ctx context.Context, sourceName string, sources []batchSource) error {
+	start := time.Now()
+	colls := make([]*batchCollection, 0, len(sources))
+	for _, src := range sources {
+		rows, err := src.Describe(ctx)
+		if err != nil {
+			return err
+		}
+		info, err := src.CollectionInfo()
+		if err != nil {
+			return err
+		}
+		colls = append(colls, &batchCollection{source: src, info: info, rows: rows})
+	}
+	gstore.SetTotalTasks(starter.JobId, len(colls))
+	for _, coll := range colls {
+		ph := gstore.InitCollProcessHandler(starter.JobId, coll.info.Param.CollectionName)
+		if coll.rows != unknownRows {
+			ph.SetDumpTotalSize(coll.rows)
+			ph.SetLoadTotalSize(coll.rows)
+		}
+	}
+	for _, subColls := range util.SplitArray(colls, starter.MigrCfg.DumperWorkLimit) {
+		var g errgroup.Group
+		for _, coll := range subColls {
+			g.Go(func() error {
+				var err error
+				if starter.Exporter != nil {
+					err = starter.exportBatchCollection(ctx, coll)
+				} else {
+					err = starter.loadBatchCollection(ctx, coll)
+				}
+				if err != nil {
+					log.Error("[Starter] migration collection err", zap.String("source", sourceName),
+						zap.String("collection", coll.info.Param.CollectionName), zap.Error(err))
+					return err
+				}
+				gstore.AddFinishTasks(starter.JobId, 1)
+				return nil
+			})
+		}
+		err := g.Wait()
+		if err != nil {
+			return err
+		}
+	}
+	//files target job finished after the manifest saved
+	if starter.Exporter != nil {
+		log.Info("[Starter] export "+sourceName+" to files finish!!!", zap.Float64("Cost", time.Since(start).Seconds()))
+		return nil
+	}
+	gstore.GetProcessHandler(starter.JobId).SetDumpFinished()
+	gstore.GetProcessHandler(starter.JobId).SetLoadFinished()
+
+	log.Info("[Starter] migration "+sourceName+" to Milvus2x finish!!!", zap.Float64("Cost", time.Since(start).Seconds()))
+	return nil
+}
+
+func (starter *Starter) loadBatchCollection(ctx context.Context, coll *batchCollection) error {
+	collLoader := starter.Loader.Clone()
+	err := task.NewCollectionInitTasker(coll.info).Init(ctx, collLoader)
+	if err != nil {
+		return err
+	}
+	err = starter.writeBatchCollection(ctx, coll, collLoader.BatchWrite)
+	if err != nil {
+		return err
+	}
+	return collLoader.After(ctx)
+}
+
+// exportBatchCollection : files target, the source rows write to the files of the collection partitions
+func (starter *Starter) exportBatchCollection(ctx context.Context, coll *batchCollection) error {
+	collExporter := starter.Exporter.NewCollectionExporter(coll.info, coll.source.Name(), coll.info.Param.EnableDynamicField)
+	err := starter.writeBatchCollection(ctx, coll, func(ctx context.Context, data *milvus2x.Milvus2xData) error {
+		return collExporter.Write(ctx, data.Partition, data.Columns)
+	})
+	if err != nil {
+		collExporter.Abort(err)
+		return err
+	}
+	return collExporter.Close(ctx)
+}
+
+// writeBatchCollection : read the source rows, LoaderWorkLimit workers concurrent write the channel data by write
+func (starter *Starter) writeBatchCollection(ctx context.Context, coll *batchCollection,
+	write func(ctx context.Context, data *milvus2x.Milvus2xData) error) error {
+	dataChannel := make(chan *milvus2x.Milvus2xData, 200)
+	ph := gstore.GetCollProcessHandler(starter.JobId, coll.info.Param.CollectionName)
+	g, subCtx := errgroup.WithContext(ctx)
+	for i := 0; i < starter.MigrCfg.LoaderWorkLimit; i++ {
+		g.Go(func() error {
+			for data := range dataChannel {
+				if subCtx.Err() != nil {
+					return subCtx.Err()
+				}
+				ph.AddDumpedSize(data.Columns[0].Len(), ctx)
+				err := write(subCtx, data)
+				if err != nil {
+					return err
+				}
+				ph.AddLoadSize(data.Columns[0].Len(), ctx)
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		rows, err := coll.source.ReadAll(subCtx, dataChannel)
+		close(dataChannel)
+		if err != nil {
+			return err
+		}
+		if coll.rows == unknownRows {
+			ph.SetDumpTotalSize(rows)
+			ph.SetLoadTotalSize(rows)
+		}
+		ph.SetDumpFinished()
+		return nil
+	})
+	err := g.Wait()
+	if err != nil {
+		return err
+	}
+	ph.SetLoadFinished()
+	return nil
+}
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"sync"
+)
+
+// batchTracker : concurrent loader workers may finish batches out of order,
+// tracker only advance to the max contiguous finished batch, so the checkpoint never skip a unfinished batch
+type batchTracker[T any] struct {
+	recvLock sync.Mutex
+	nextSeq  int64
+
+	lock     sync.Mutex
+	doneSeq  int64 //all batches seq <= doneSeq are finished
+	finished map[int64]T
+}
+
+func newBatchTracker[T any]() *batchTracker[T] {
+	return &batchTracker[T]{
+		doneSeq:  -1,
+		finished: make(map[int64]T),
+	}
+}
+
+// take : receive a batch from channel and assign its seq, ok is false when channel closed
+func (t *batchTracker[T]) take(dataChannel chan T) (T, int64, bool) {
+	t.recvLock.Lock()
+	defer t.recvLock.Unlock()
+	data, ok := <-dataChannel
+	if !ok {
+		return data, 0, false
+	}
+	seq := t.nextSeq
+	t.nextSeq++
+	return data, seq, true
+}
+
+// done : mark the batch finished, commit the new contiguous finished batches in seq order,
+// not commit if not advanced. commit func will be called with the lock, keep the checkpoint save in order
+func (t *batchTracker[T]) done(seq int64, data T, commit func(batches []T) error) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.finished[seq] = data
+	var batches []T
+	for {
+		d, ok := t.finished[t.doneSeq+1]
+		if !ok {
+			break
+		}
+		delete(t.finished, t.doneSeq+1)
+		t.doneSeq++
+		batches = append(batches, d)
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+	return commit(batches)
+}
@@ -0,0 +1,89 @@
+package migration
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	chromaconvert "github.com/zilliztech/milvus-migration/core/transform/chroma/convert"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/chroma"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+)
+
+// migrationChroma : read the chroma persist directory offline, create collections and batch insert
+func (starter *Starter) migrationChroma(ctx context.Context) error {
+	dir, clean, err := source.FetchChromaDir(ctx, starter.MigrCfg)
+	if err != nil {
+		return err
+	}
+	defer clean()
+	cli, err := chroma.NewClient(dir)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	collCfgs, err := starter.chromaCollectionCfgs(ctx, cli)
+	if err != nil {
+		return err
+	}
+	sources := make([]batchSource, 0, len(collCfgs))
+	for _, collCfg := range collCfgs {
+		sources = append(sources, &chromaCollection{cli: cli, collCfg: collCfg, migrCfg: starter.MigrCfg})
+	}
+	return starter.migrationBatchSources(ctx, "chroma", sources)
+}
+
+// chromaCollectionCfgs : meta collections not configured means all collections of the persist directory
+func (starter *Starter) chromaCollectionCfgs(ctx context.Context, cli *chroma.Client) ([]*chromatype.CollectionCfg, error) {
+	meta := starter.MigrCfg.MetaConfig.ChromaMeta
+	if len(meta.CollectionCfgs) > 0 {
+		return meta.CollectionCfgs, nil
+	}
+	names, err := cli.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.LL(ctx).Info("[Starter] migration all chroma collections", zap.Strings("collections", names))
+	collCfgs := make([]*chromatype.CollectionCfg, 0, len(names))
+	for _, name := range names {
+		collCfg := meta.NewCollectionCfg(name)
+		//meta.milvus.collection only work for single collection
+		if len(names) > 1 {
+			collCfg.MilvusCfg.Collection = ""
+		}
+		collCfgs = append(collCfgs, collCfg)
+	}
+	meta.CollectionCfgs = collCfgs
+	return collCfgs, nil
+}
+
+// chromaCollection : batchSource of a collection in the chroma persist directory
+type chromaCollection struct {
+	cli     *chroma.Client
+	collCfg *chromatype.CollectionCfg
+	migrCfg *config.MigrationConfig
+}
+
+func (cc *chromaCollection) Name() string {
+	return cc.collCfg.Collection
+}
+
+func (cc *chromaCollection) Describe(ctx context.Context) (int64, error) {
+	err := cc.cli.DescribeCollection(ctx, cc.collCfg)
+	if err != nil {
+		return 0, err
+	}
+	cc.collCfg.Rows, err = cc.cli.Count(ctx, cc.collCfg)
+	return cc.collCfg.Rows, err
+}
+
+func (cc *chromaCollection) CollectionInfo() (*common.CollectionInfo, error) {
+	return chromaconvert.ToCollectionInfo(cc.collCfg)
+}
+
+func (cc *chromaCollection) ReadAll(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) (int64, error) {
+	return source.NewChromaSource(cc.cli, cc.collCfg, cc.migrCfg, dataChannel).ReadAll(ctx)
+}
@@ -0,0 +1,200 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/gstore"
+	"github.com/zilliztech/milvus-migration/core/loader"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	"github.com/zilliztech/milvus-migration/core/task"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
+	esparser "github.com/zilliztech/milvus-migration/core/transform/es/parser"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/es"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"time"
+)
+
+// migrationESByBatchInsert : es hits convert to milvus columns and insert/upsert to milvus directly, not dump json files
+func (starter *Starter) migrationESByBatchInsert(ctx context.Context) error {
+	idxListArray, err := starter.Dumper.InitDumpInEsMode(ctx)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for _, idxList := range idxListArray {
+		err = starter.DumpLoadBatchInESByInsert(ctx, idxList)
+		if err != nil {
+			return err
+		}
+	}
+	gstore.GetProcessHandler(starter.JobId).SetDumpFinished()
+	gstore.GetProcessHandler(starter.JobId).SetLoadFinished()
+
+	log.Info("[Starter] migration ES to Milvus by batch insert finish!!!", zap.Float64("Cost", time.Since(start).Seconds()))
+	return nil
+}
+
+func (starter *Starter) DumpLoadBatchInESByInsert(ctx context.Context, idxCfgs []*estype.IdxCfg) error {
+	var g errgroup.Group
+	for i := range idxCfgs {
+		finalI := i
+		g.Go(func() error {
+			if starter.isCollectionFinished(idxCfgs[finalI].Index) {
+				log.Info("[Starter] ES index already migrated by checkpoint, skip", zap.String("index", idxCfgs[finalI].Index))
+				gstore.AddFinishTasks(starter.JobId, 1)
+				return nil
+			}
+			err := starter.DumpLoadInESByInsert(ctx, idxCfgs[finalI])
+			if err != nil {
+				log.Error("[Starter] migration ES index err", zap.String("index", idxCfgs[finalI].Index), zap.Error(err))
+				return err
+			}
+			gstore.AddFinishTasks(starter.JobId, 1)
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func (starter *Starter) DumpLoadInESByInsert(ctx context.Context, idxCfg *estype.IdxCfg) error {
+	//每个index使用独立的loader, 避免runtime collection info互相覆盖
+	idxLoader := starter.Loader.Clone()
+	err := task.NewESInitTasker([]*estype.IdxCfg{idxCfg}).Init(ctx, idxLoader)
+	if err != nil {
+		return err
+	}
+	fields, err := esconvert.ToMilvusFields(idxCfg)
+	if err != nil {
+		return err
+	}
+
+	esSource := source.NewESSource(idxCfg, starter.MigrCfg)
+	err = starter.resumeESSlices(esSource)
+	if err != nil {
+		return err
+	}
+	err = esSource.Count()
+	if err != nil {
+		return err
+	}
+	ph := gstore.InitCollProcessHandler(starter.JobId, esconvert.ToMilvusCollectionName(idxCfg))
+	ph.SetDumpTotalSize(idxCfg.Rows)
+	ph.SetLoadTotalSize(idxCfg.Rows)
+
+	g, subCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		err := starter.loadESByBatchInsert(subCtx, esSource, idxLoader, fields)
+		if err != nil {
+			log.Error("[Starter] LoadESByBatchInsert err", zap.String("index", idxCfg.Index), zap.Error(err))
+		}
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		if esSource.IsPITMode() {
+			err = esSource.ReadByPIT(subCtx)
+		} else {
+			err = esSource.ReadByScroll(subCtx)
+		}
+		if err != nil {
+			log.Error("[Starter] read ES index err", zap.String("index", idxCfg.Index), zap.Error(err))
+		}
+		return err
+	})
+	err = g.Wait()
+	if err != nil {
+		return err
+	}
+	ph.SetDumpFinished()
+	ph.SetLoadFinished()
+
+	err = idxLoader.After(ctx)
+	if err != nil {
+		return err
+	}
+	err = starter.Dumper.SaveSparseVocabularies(ctx, idxCfg)
+	if err != nil {
+		return err
+	}
+	return starter.finishCheckpoint(ctx, idxCfg.Index)
+}
+
+// resumeESSlices : resume job continue every pit slice from the search_after recorded by checkpoint,
+// scroll read index can't continue, the slices number must be same as the failed job
+func (starter *Starter) resumeESSlices(esSource *source.ESSource) error {
+	ckpt := gstore.GetCheckpoint(starter.JobId)
+	if ckpt == nil {
+		return nil
+	}
+	collCkpt := ckpt.GetCollection(esSource.IdxCfg.Index)
+	if collCkpt == nil {
+		return nil
+	}
+	if !esSource.IsPITMode() {
+		return fmt.Errorf("es index %s read by scroll can't resume, need pit readMode", esSource.IdxCfg.Index)
+	}
+	if collCkpt.SliceMax != esSource.SliceMax() {
+		return fmt.Errorf("es index %s checkpoint slices %d not equal to source.es.slices %d", esSource.IdxCfg.Index,
+			collCkpt.SliceMax, esSource.SliceMax())
+	}
+	esSource.SliceSearchAfter = collCkpt.SliceSearchAfter
+	return nil
+}
+
+// loadESByBatchInsert : LoaderWorkLimit workers concurrent convert the hits to milvus columns and write to target milvus
+func (starter *Starter) loadESByBatchInsert(ctx context.Context, esSource *source.ESSource,
+	idxLoader *loader.CustomMilvus2xLoader, fields []*entity.Field) error {
+	ph := gstore.GetCollProcessHandler(starter.JobId, esconvert.ToMilvusCollectionName(esSource.IdxCfg))
+	enableDynamic := !esSource.IdxCfg.MilvusCfg.CloseDynamicField
+	tracker := newBatchTracker[*es.SearchRes]()
+	g, subCtx := errgroup.WithContext(ctx)
+	for i := 0; i < starter.MigrCfg.LoaderWorkLimit; i++ {
+		g.Go(func() error {
+			for {
+				data, seq, ok := tracker.take(esSource.DataChannel)
+				if !ok {
+					return nil
+				}
+				//other worker failed, stop write
+				if subCtx.Err() != nil {
+					return subCtx.Err()
+				}
+				columns, err := esparser.ToMilvusColumns(&data.Hits, esSource.IdxCfg, fields, enableDynamic)
+				if err != nil {
+					return err
+				}
+				rows := columns[0].Len()
+				ph.AddDumpedSize(rows, ctx)
+				err = idxLoader.BatchWrite(subCtx, &milvus2x.Milvus2xData{Columns: columns})
+				if err != nil {
+					return err
+				}
+				ph.AddLoadSize(rows, ctx)
+				err = tracker.done(seq, data, func(batches []*es.SearchRes) error {
+					return starter.saveESCheckpoint(ctx, esSource, batches)
+				})
+				if err != nil {
+					return err
+				}
+			}
+		})
+	}
+	return g.Wait()
+}
+
+// saveESCheckpoint : record the search_after of every pit slice after the hits written, scroll mode not record
+func (starter *Starter) saveESCheckpoint(ctx context.Context, esSource *source.ESSource, batches []*es.SearchRes) error {
+	if starter.CkptStore == nil || !esSource.IsPITMode() {
+		return nil
+	}
+	ckpt := gstore.GetCheckpoint(starter.JobId)
+	for _, data := range batches {
+		ckpt.UpdateSlice(esSource.IdxCfg.Index, esSource.SliceMax(), data.SliceId, data.SearchAfter)
+	}
+	return starter.CkptStore.Save(ctx, ckpt)
+}
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	filesconvert "github.com/zilliztech/milvus-migration/core/transform/files/convert"
+	"github.com/zilliztech/milvus-migration/core/type/filestype"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+)
+
+// migrationFiles : create collections by the meta.fields schema, read jsonl, csv or parquet files and batch insert
+func (starter *Starter) migrationFiles(ctx context.Context) error {
+	collCfgs := starter.MigrCfg.MetaConfig.FilesMeta.CollectionCfgs
+	sources := make([]batchSource, 0, len(collCfgs))
+	for _, collCfg := range collCfgs {
+		sources = append(sources, &filesCollection{collCfg: collCfg, migrCfg: starter.MigrCfg})
+	}
+	return starter.migrationBatchSources(ctx, "files", sources)
+}
+
+// filesCollection : batchSource of the data files of a collection, rows of the files unknown before read
+type filesCollection struct {
+	collCfg *filestype.CollectionCfg
+	migrCfg *config.MigrationConfig
+	files   []string
+}
+
+func (fc *filesCollection) Name() string {
+	return fc.collCfg.Collection
+}
+
+func (fc *filesCollection) Describe(ctx context.Context) (int64, error) {
+	files, err := source.ListDataFiles(ctx, fc.migrCfg, fc.collCfg)
+	if err != nil {
+		return 0, err
+	}
+	fc.files = files
+	return unknownRows, nil
+}
+
+func (fc *filesCollection) CollectionInfo() (*common.CollectionInfo, error) {
+	return filesconvert.ToCollectionInfo(fc.collCfg)
+}
+
+func (fc *filesCollection) ReadAll(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) (int64, error) {
+	filesSource, err := source.NewFilesSource(fc.collCfg, fc.files, fc.migrCfg, dataChannel)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := filesSource.ReadAll(ctx)
+	if err != nil {
+		return rows, err
+	}
+	fc.collCfg.Rows = rows
+	return rows, nil
+}
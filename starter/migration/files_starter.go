@@ -0,0 +1,207 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/factory/milvus2x_factory"
+	"github.com/zilliztech/milvus-migration/core/gstore"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	esconvert "github.com/zilliztech/milvus-migration/core/transform/es/convert"
+	esparser "github.com/zilliztech/milvus-migration/core/transform/es/parser"
+	milvus2xconvert "github.com/zilliztech/milvus-migration/core/transform/milvus2x/convert"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"time"
+)
+
+// exportToFiles : files target, source data write to parquet/jsonl files instead of target milvus,
+// the batch insert sources run by the batch source runner with the exporter, the manifest.json saved after all collections exported
+func (starter *Starter) exportToFiles(ctx context.Context) error {
+	start := time.Now()
+	var err error
+	switch common.DumpMode(starter.WorkMode) {
+	case common.Milvus2x:
+		err = starter.exportMilvus2x(ctx)
+	case common.Elasticsearch:
+		err = starter.exportES(ctx)
+	case common.Manifest:
+		err = starter.migrationManifest(ctx)
+	case common.Pgvector:
+		err = starter.migrationPgvector(ctx)
+	case common.Qdrant:
+		err = starter.migrationQdrant(ctx)
+	case common.Chroma:
+		err = starter.migrationChroma(ctx)
+	case common.Files:
+		err = starter.migrationFiles(ctx)
+	default:
+		err = fmt.Errorf("files target not support WorkMode %s", starter.WorkMode)
+	}
+	if err != nil {
+		return err
+	}
+	err = starter.Exporter.SaveManifest(ctx)
+	if err != nil {
+		return err
+	}
+	gstore.GetProcessHandler(starter.JobId).SetDumpFinished()
+	gstore.GetProcessHandler(starter.JobId).SetLoadFinished()
+
+	log.Info("[Starter] export to files finish!!!", zap.String("outputDir", starter.MigrCfg.TargetOutputDir),
+		zap.Float64("Cost", time.Since(start).Seconds()))
+	return nil
+}
+
+func (starter *Starter) exportMilvus2x(ctx context.Context) error {
+	collCfgsArray, err := starter.Dumper.InitDumpInMilvus2xMode(ctx)
+	if err != nil {
+		return err
+	}
+	for _, collCfgs := range collCfgsArray {
+		var g errgroup.Group
+		for _, collCfg := range collCfgs {
+			g.Go(func() error {
+				err := starter.exportMilvus2xCollection(ctx, collCfg)
+				if err != nil {
+					log.Error("[Starter] export Milvus2x collection err", zap.String("collection", collCfg.Collection), zap.Error(err))
+					return err
+				}
+				gstore.AddFinishTasks(starter.JobId, 1)
+				return nil
+			})
+		}
+		err = g.Wait()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (starter *Starter) exportMilvus2xCollection(ctx context.Context, collCfg *milvus2xtype.CollectionCfg) error {
+	milvus2xCli := milvus2x_factory.GetMilvus2xCli(starter.MigrCfg.SourceMilvus2xConfig)
+	collectionInfo, err := milvus2xconvert.ToMilvusParam(ctx, collCfg, milvus2xCli)
+	if err != nil {
+		return err
+	}
+	collExporter := starter.Exporter.NewCollectionExporter(collectionInfo, collCfg.Collection, collCfg.DynamicField)
+	ph := gstore.GetCollProcessHandler(starter.JobId, collCfg.Collection)
+
+	dataChannel := make(chan *milvus2x.Milvus2xData, 200)
+	g, subCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		for data := range dataChannel {
+			err := collExporter.Write(subCtx, data.Partition, data.Columns)
+			if err != nil {
+				return err
+			}
+			ph.AddLoadSize(data.Columns[0].Len(), ctx)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		err := starter.Dumper.WorkInMilvus2x(subCtx, collCfg, dataChannel)
+		close(dataChannel)
+		return err
+	})
+	err = g.Wait()
+	if err != nil {
+		collExporter.Abort(err)
+		return err
+	}
+	ph.SetLoadFinished()
+	return collExporter.Close(ctx)
+}
+
+func (starter *Starter) exportES(ctx context.Context) error {
+	idxListArray, err := starter.Dumper.InitDumpInEsMode(ctx)
+	if err != nil {
+		return err
+	}
+	for _, idxList := range idxListArray {
+		var g errgroup.Group
+		for _, idxCfg := range idxList {
+			g.Go(func() error {
+				err := starter.exportESIndex(ctx, idxCfg)
+				if err != nil {
+					log.Error("[Starter] export ES index err", zap.String("index", idxCfg.Index), zap.Error(err))
+					return err
+				}
+				gstore.AddFinishTasks(starter.JobId, 1)
+				return nil
+			})
+		}
+		err = g.Wait()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (starter *Starter) exportESIndex(ctx context.Context, idxCfg *estype.IdxCfg) error {
+	collectionInfo, err := esconvert.ToMilvusParam(idxCfg)
+	if err != nil {
+		return err
+	}
+	esSource := source.NewESSource(idxCfg, starter.MigrCfg)
+	err = esSource.Count()
+	if err != nil {
+		return err
+	}
+	ph := gstore.InitCollProcessHandler(starter.JobId, collectionInfo.Param.CollectionName)
+	ph.SetDumpTotalSize(idxCfg.Rows)
+	ph.SetLoadTotalSize(idxCfg.Rows)
+
+	enableDynamic := !idxCfg.MilvusCfg.CloseDynamicField
+	collExporter := starter.Exporter.NewCollectionExporter(collectionInfo, idxCfg.Index, enableDynamic)
+	g, subCtx := errgroup.WithContext(ctx)
+	//LoaderWorkLimit workers concurrent convert the hits to milvus columns
+	for i := 0; i < starter.MigrCfg.LoaderWorkLimit; i++ {
+		g.Go(func() error {
+			for data := range esSource.DataChannel {
+				if subCtx.Err() != nil {
+					return subCtx.Err()
+				}
+				if data.IsEmpty {
+					continue
+				}
+				columns, err := esparser.ToMilvusColumns(&data.Hits, idxCfg, collectionInfo.Fields, enableDynamic)
+				if err != nil {
+					return err
+				}
+				rows := columns[0].Len()
+				ph.AddDumpedSize(rows, ctx)
+				err = collExporter.Write(subCtx, common.EMPTY, columns)
+				if err != nil {
+					return err
+				}
+				ph.AddLoadSize(rows, ctx)
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		if esSource.IsPITMode() {
+			return esSource.ReadByPIT(subCtx)
+		}
+		return esSource.ReadByScroll(subCtx)
+	})
+	err = g.Wait()
+	if err != nil {
+		collExporter.Abort(err)
+		return err
+	}
+	ph.SetDumpFinished()
+	ph.SetLoadFinished()
+	err = collExporter.Close(ctx)
+	if err != nil {
+		return err
+	}
+	return starter.Dumper.SaveSparseVocabularies(ctx, idxCfg)
+}
@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	manifestconvert "github.com/zilliztech/milvus-migration/core/transform/manifest/convert"
+	"github.com/zilliztech/milvus-migration/core/type/manifesttype"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+)
+
+// migrationManifest : read the files exported by files target, create collections by the manifest schema and batch insert
+func (starter *Starter) migrationManifest(ctx context.Context) error {
+	manifest, err := source.LoadManifest(ctx, starter.MigrCfg)
+	if err != nil {
+		return err
+	}
+	sources := make([]batchSource, 0, len(manifest.Collections))
+	for _, collCfg := range manifest.Collections {
+		sources = append(sources, &manifestCollection{manifest: manifest, collCfg: collCfg, migrCfg: starter.MigrCfg})
+	}
+	return starter.migrationBatchSources(ctx, "manifest files", sources)
+}
+
+// manifestCollection : batchSource of a collection in the manifest, rows recorded by the manifest
+type manifestCollection struct {
+	manifest *manifesttype.Manifest
+	collCfg  *manifesttype.CollectionCfg
+	migrCfg  *config.MigrationConfig
+}
+
+func (mc *manifestCollection) Name() string {
+	return mc.collCfg.Collection
+}
+
+func (mc *manifestCollection) Describe(ctx context.Context) (int64, error) {
+	return mc.collCfg.Rows, nil
+}
+
+func (mc *manifestCollection) CollectionInfo() (*common.CollectionInfo, error) {
+	return manifestconvert.ToCollectionInfo(mc.collCfg)
+}
+
+func (mc *manifestCollection) ReadAll(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) (int64, error) {
+	manifestSource, err := source.NewManifestSource(mc.manifest, mc.collCfg, mc.migrCfg, dataChannel)
+	if err != nil {
+		return 0, err
+	}
+	return manifestSource.ReadAll(ctx)
+}
@@ -3,6 +3,7 @@ package migration
 import (
 	"context"
 	"github.com/zilliztech/milvus-migration/core/gstore"
+	"github.com/zilliztech/milvus-migration/core/loader"
 	"github.com/zilliztech/milvus-migration/core/task"
 	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
 	"github.com/zilliztech/milvus-migration/internal/log"
@@ -13,33 +14,63 @@ import (
 )
 
 func (starter *Starter) migrationMilvus2x(ctx context.Context) error {
-	collCfg, err := starter.Dumper.InitDumpInMilvus2xMode(ctx)
+	collCfgsArray, err := starter.Dumper.InitDumpInMilvus2xMode(ctx)
 	if err != nil {
 		return err
 	}
 	start := time.Now()
-
-	err = starter.DumpLoadInMilvus2x(ctx, collCfg)
-	if err != nil {
-		return err
+	for _, collCfgs := range collCfgsArray {
+		err = starter.DumpLoadBatchInMilvus2x(ctx, collCfgs)
+		if err != nil {
+			return err
+		}
 	}
+	gstore.GetProcessHandler(starter.JobId).SetDumpFinished()
+	gstore.GetProcessHandler(starter.JobId).SetLoadFinished()
 
 	log.Info("[Starter] migration Milvus2x to Milvus2x finish!!!", zap.Float64("Cost", time.Since(start).Seconds()))
 	return nil
 }
 
+func (starter *Starter) DumpLoadBatchInMilvus2x(ctx context.Context, collCfgs []*milvus2xtype.CollectionCfg) error {
+	var g errgroup.Group
+	for i := range collCfgs {
+		finalI := i
+		g.Go(func() error {
+			err := starter.DumpLoadInMilvus2x(ctx, collCfgs[finalI])
+			if err != nil {
+				log.Error("[Starter] migration Milvus2x collection err", zap.String("collection", collCfgs[finalI].Collection), zap.Error(err))
+				return err
+			}
+			gstore.AddFinishTasks(starter.JobId, 1)
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
 func (starter *Starter) DumpLoadInMilvus2x(ctx context.Context, collCfg *milvus2xtype.CollectionCfg) error {
 
+	if starter.isCollectionFinished(collCfg.Collection) {
+		log.Info("[Starter] resume job, skip finished collection", zap.String("collection", collCfg.Collection))
+		ph := gstore.GetCollProcessHandler(starter.JobId, collCfg.Collection)
+		ph.SetDumpFinished()
+		ph.SetLoadFinished()
+		return nil
+	}
+
+	//每个collection使用独立的loader, 避免runtime collection info互相覆盖
+	collLoader := starter.Loader.Clone()
 	initTask := task.NewMilvus2xInitTasker(collCfg, starter.MigrCfg.SourceMilvus2xConfig)
-	err := initTask.Init(ctx, starter.Loader)
+	err := initTask.Init(ctx, collLoader)
 	if err != nil {
 		return err
 	}
 
 	dataChannel := make(chan *milvus2x.Milvus2xData, 200)
-	var g errgroup.Group
+	g, subCtx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		err := starter.loadByBatchInsert(ctx, dataChannel)
+		err := starter.loadByBatchInsert(subCtx, collCfg, collLoader, dataChannel)
 		if err != nil {
 			log.Error("LoadByBatchInsert err", zap.Error(err))
 		}
@@ -47,7 +78,7 @@ func (starter *Starter) DumpLoadInMilvus2x(ctx context.Context, collCfg *milvus2
 	})
 
 	g.Go(func() error {
-		err := starter.dumpByIterator(ctx, collCfg, dataChannel)
+		err := starter.dumpByIterator(subCtx, collCfg, dataChannel)
 		close(dataChannel) //放在线程结束处close
 		if err != nil {
 			log.Error("DumpByIterator err", zap.Error(err))
@@ -59,7 +90,11 @@ func (starter *Starter) DumpLoadInMilvus2x(ctx context.Context, collCfg *milvus2
 	if err != nil {
 		return err
 	}
-	return nil
+	err = collLoader.After(ctx)
+	if err != nil {
+		return err
+	}
+	return starter.finishCheckpoint(ctx, collCfg.Collection)
 }
 
 func (starter *Starter) dumpByIterator(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, dataChannel chan *milvus2x.Milvus2xData) error {
@@ -70,14 +105,84 @@ func (starter *Starter) dumpByIterator(ctx context.Context, collCfg *milvus2xtyp
 	return nil
 }
 
-func (starter *Starter) loadByBatchInsert(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) error {
-	for data := range dataChannel {
-		err := starter.Loader.BatchWrite(ctx, data)
+// loadByBatchInsert : LoaderWorkLimit workers concurrent write the channel data to target milvus
+func (starter *Starter) loadByBatchInsert(ctx context.Context, collCfg *milvus2xtype.CollectionCfg,
+	collLoader *loader.CustomMilvus2xLoader, dataChannel chan *milvus2x.Milvus2xData) error {
+	ph := gstore.GetCollProcessHandler(starter.JobId, collCfg.Collection)
+	tracker := newBatchTracker[*milvus2x.Milvus2xData]()
+	g, subCtx := errgroup.WithContext(ctx)
+	for i := 0; i < starter.MigrCfg.LoaderWorkLimit; i++ {
+		g.Go(func() error {
+			for {
+				data, seq, ok := tracker.take(dataChannel)
+				if !ok {
+					return nil
+				}
+				//other worker failed, stop write
+				if subCtx.Err() != nil {
+					return subCtx.Err()
+				}
+				err := collLoader.BatchWrite(subCtx, data)
+				if err != nil {
+					return err
+				}
+				ph.AddLoadSize(data.Columns[0].Len(), ctx)
+				err = tracker.done(seq, data, func(batches []*milvus2x.Milvus2xData) error {
+					return starter.saveCheckpoint(ctx, collCfg, batches)
+				})
+				if err != nil {
+					return err
+				}
+			}
+		})
+	}
+	err := g.Wait()
+	if err != nil {
+		return err
+	}
+	ph.SetLoadFinished()
+	return nil
+}
+
+func (starter *Starter) isCollectionFinished(collection string) bool {
+	ckpt := gstore.GetCheckpoint(starter.JobId)
+	if ckpt == nil {
+		return false
+	}
+	collCkpt := ckpt.GetCollection(collection)
+	return collCkpt != nil && collCkpt.Finished
+}
+
+// saveCheckpoint : record the last pk after batches written success, resume job will continue from it.
+// pk range parallel read record the last pk of every range
+func (starter *Starter) saveCheckpoint(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, batches []*milvus2x.Milvus2xData) error {
+	if starter.CkptStore == nil {
+		return nil
+	}
+	ckpt := gstore.GetCheckpoint(starter.JobId)
+	updated := false
+	for _, data := range batches {
+		if data.LastPK == nil {
+			continue
+		}
+		err := ckpt.UpdateRange(collCfg.Collection, data.Partition, data.PKRanges, data.PKRange, collCfg.MilvusCfg.PkName, data.LastPK)
 		if err != nil {
 			return err
 		}
-		gstore.GetProcessHandler(starter.JobId).AddLoadSize(data.Columns[0].Len(), ctx)
+		updated = true
 	}
-	gstore.GetProcessHandler(starter.JobId).SetLoadFinished()
-	return nil
+	if !updated {
+		return nil
+	}
+	return starter.CkptStore.Save(ctx, ckpt)
+}
+
+// finishCheckpoint : collection is the source collection or es index
+func (starter *Starter) finishCheckpoint(ctx context.Context, collection string) error {
+	if starter.CkptStore == nil {
+		return nil
+	}
+	ckpt := gstore.GetCheckpoint(starter.JobId)
+	ckpt.FinishCollection(collection)
+	return starter.CkptStore.Save(ctx, ckpt)
 }
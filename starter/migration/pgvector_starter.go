@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	pgvectorconvert "github.com/zilliztech/milvus-migration/core/transform/pgvector/convert"
+	"github.com/zilliztech/milvus-migration/core/type/pgvectortype"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"github.com/zilliztech/milvus-migration/storage/pgvector"
+)
+
+// migrationPgvector : create collections by the postgres table columns, read rows by keyset pagination and batch insert
+func (starter *Starter) migrationPgvector(ctx context.Context) error {
+	cli, err := pgvector.NewClient(starter.MigrCfg.SourcePgvectorConfig)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	tableCfgs := starter.MigrCfg.MetaConfig.PgvectorMeta.TableCfgs
+	sources := make([]batchSource, 0, len(tableCfgs))
+	for _, tableCfg := range tableCfgs {
+		sources = append(sources, &pgvectorTable{cli: cli, tableCfg: tableCfg, migrCfg: starter.MigrCfg})
+	}
+	return starter.migrationBatchSources(ctx, "pgvector", sources)
+}
+
+// pgvectorTable : batchSource of a postgres table
+type pgvectorTable struct {
+	cli      *pgvector.Client
+	tableCfg *pgvectortype.TableCfg
+	migrCfg  *config.MigrationConfig
+}
+
+func (pt *pgvectorTable) Name() string {
+	return pt.tableCfg.Table
+}
+
+func (pt *pgvectorTable) Describe(ctx context.Context) (int64, error) {
+	err := pt.cli.DescribeTable(ctx, pt.tableCfg)
+	if err != nil {
+		return 0, err
+	}
+	pt.tableCfg.Rows, err = pt.cli.Count(ctx, pt.tableCfg)
+	return pt.tableCfg.Rows, err
+}
+
+func (pt *pgvectorTable) CollectionInfo() (*common.CollectionInfo, error) {
+	return pgvectorconvert.ToCollectionInfo(pt.tableCfg)
+}
+
+func (pt *pgvectorTable) ReadAll(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) (int64, error) {
+	pgSource, err := source.NewPgvectorSource(pt.cli, pt.tableCfg, pt.migrCfg, dataChannel)
+	if err != nil {
+		return 0, err
+	}
+	return pgSource.ReadAll(ctx)
+}
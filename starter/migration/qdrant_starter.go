@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"context"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/reader/source"
+	qdrantconvert "github.com/zilliztech/milvus-migration/core/transform/qdrant/convert"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"github.com/zilliztech/milvus-migration/storage/milvus2x"
+	"github.com/zilliztech/milvus-migration/storage/qdrant"
+)
+
+// migrationQdrant : create collections by the qdrant collection vectors config, scroll points and batch insert
+func (starter *Starter) migrationQdrant(ctx context.Context) error {
+	cli, err := qdrant.NewClient(starter.MigrCfg.SourceQdrantConfig)
+	if err != nil {
+		return err
+	}
+	collCfgs := starter.MigrCfg.MetaConfig.QdrantMeta.CollectionCfgs
+	sources := make([]batchSource, 0, len(collCfgs))
+	for _, collCfg := range collCfgs {
+		sources = append(sources, &qdrantCollection{cli: cli, collCfg: collCfg, migrCfg: starter.MigrCfg})
+	}
+	return starter.migrationBatchSources(ctx, "qdrant", sources)
+}
+
+// qdrantCollection : batchSource of a qdrant collection
+type qdrantCollection struct {
+	cli     *qdrant.Client
+	collCfg *qdranttype.CollectionCfg
+	migrCfg *config.MigrationConfig
+}
+
+func (qc *qdrantCollection) Name() string {
+	return qc.collCfg.Collection
+}
+
+func (qc *qdrantCollection) Describe(ctx context.Context) (int64, error) {
+	err := qc.cli.DescribeCollection(ctx, qc.collCfg)
+	if err != nil {
+		return 0, err
+	}
+	qc.collCfg.Rows, err = qc.cli.Count(ctx, qc.collCfg)
+	return qc.collCfg.Rows, err
+}
+
+func (qc *qdrantCollection) CollectionInfo() (*common.CollectionInfo, error) {
+	return qdrantconvert.ToCollectionInfo(qc.collCfg)
+}
+
+func (qc *qdrantCollection) ReadAll(ctx context.Context, dataChannel chan *milvus2x.Milvus2xData) (int64, error) {
+	qdSource, err := source.NewQdrantSource(qc.cli, qc.collCfg, qc.migrCfg, dataChannel)
+	if err != nil {
+		return 0, err
+	}
+	return qdSource.ReadAll(ctx)
+}
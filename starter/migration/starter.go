@@ -3,9 +3,12 @@ package migration
 import (
 	"context"
 	"fmt"
+	"github.com/zilliztech/milvus-migration/core/checkpoint"
 	"github.com/zilliztech/milvus-migration/core/common"
 	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/data"
 	"github.com/zilliztech/milvus-migration/core/dumper"
+	"github.com/zilliztech/milvus-migration/core/exporter"
 	"github.com/zilliztech/milvus-migration/core/gstore"
 	"github.com/zilliztech/milvus-migration/core/loader"
 	"github.com/zilliztech/milvus-migration/internal/log"
@@ -14,8 +17,10 @@ import (
 )
 
 type Starter struct {
-	Dumper *dumper.Dumper
-	Loader *loader.CustomMilvus2xLoader
+	Dumper    *dumper.Dumper
+	Loader    *loader.CustomMilvus2xLoader
+	Exporter  *exporter.Exporter //files target not have loader
+	CkptStore checkpoint.Store
 	//Submitter   *task.ChanTasker
 	MigrCfg  *config.MigrationConfig
 	WorkMode string
@@ -24,16 +29,37 @@ type Starter struct {
 
 func NewStarter(migrCfg *config.MigrationConfig, jobId string) (*Starter, error) {
 	dumper := dumper.NewDumperWithConfig(migrCfg, jobId)
+	if migrCfg.TargetType == string(common.T_FILES) {
+		return &Starter{
+			Dumper:   dumper,
+			Exporter: exporter.NewExporter(migrCfg),
+			MigrCfg:  migrCfg,
+			JobId:    jobId,
+			WorkMode: migrCfg.DumperWorkCfg.WorkMode,
+		}, nil
+	}
 	loader, err := loader.NewCusFieldMilvus2xLoader(migrCfg)
 	if err != nil {
 		return nil, err
 	}
+	var ckptStore checkpoint.Store
+	if migrCfg.CheckpointCfg != nil {
+		ckptStore, err = checkpoint.NewStore(migrCfg.CheckpointCfg)
+		if err != nil {
+			return nil, err
+		}
+		//resume job will load checkpoint before new starter
+		if gstore.GetCheckpoint(jobId) == nil {
+			gstore.SetCheckpoint(jobId, data.NewCheckpoint(jobId))
+		}
+	}
 	return &Starter{
-		Dumper:   dumper,
-		Loader:   loader,
-		MigrCfg:  migrCfg,
-		JobId:    jobId,
-		WorkMode: migrCfg.DumperWorkCfg.WorkMode,
+		Dumper:    dumper,
+		Loader:    loader,
+		CkptStore: ckptStore,
+		MigrCfg:   migrCfg,
+		JobId:     jobId,
+		WorkMode:  migrCfg.DumperWorkCfg.WorkMode,
 	}, nil
 }
 
@@ -51,11 +77,27 @@ func (starter *Starter) Run(ctx context.Context) error {
 }
 
 func (starter *Starter) doByWorkMode(ctx context.Context) error {
+	if starter.Exporter != nil {
+		return starter.exportToFiles(ctx)
+	}
 	switch common.DumpMode(starter.WorkMode) {
 	case common.Elasticsearch:
+		if starter.MigrCfg.LoaderWorkCfg.InsertMode == string(common.BatchInsert) {
+			return starter.migrationESByBatchInsert(ctx)
+		}
 		return starter.migrationES(ctx)
 	case common.Milvus2x:
 		return starter.migrationMilvus2x(ctx)
+	case common.Manifest:
+		return starter.migrationManifest(ctx)
+	case common.Pgvector:
+		return starter.migrationPgvector(ctx)
+	case common.Qdrant:
+		return starter.migrationQdrant(ctx)
+	case common.Chroma:
+		return starter.migrationChroma(ctx)
+	case common.Files:
+		return starter.migrationFiles(ctx)
 	default:
 		return fmt.Errorf("not support Starter WorkMode %s", starter.WorkMode)
 	}
@@ -1,5 +1,10 @@
 package param
 
+import (
+	"github.com/zilliztech/milvus-migration/core/data"
+	"github.com/zilliztech/milvus-migration/core/gstore"
+)
+
 type JobResponse struct {
 	JobId string `json:"jobId"`
 }
@@ -9,3 +14,16 @@ func NewJobResponse(jobId string) *JobResponse {
 		JobId: jobId,
 	}
 }
+
+// JobDetailResponse : job info with the bulk insert taskId and state of each file
+type JobDetailResponse struct {
+	*data.JobInfo
+	Files []*gstore.FileTaskRecord `json:"files"`
+}
+
+func NewJobDetailResponse(info *data.JobInfo, files []*gstore.FileTaskRecord) *JobDetailResponse {
+	return &JobDetailResponse{
+		JobInfo: info,
+		Files:   files,
+	}
+}
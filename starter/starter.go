@@ -4,15 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/zilliztech/milvus-migration/core/checkpoint"
 	"github.com/zilliztech/milvus-migration/core/cleaner"
 	"github.com/zilliztech/milvus-migration/core/common"
 	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/dumper"
 	"github.com/zilliztech/milvus-migration/core/gstore"
 	"github.com/zilliztech/milvus-migration/core/loader"
+	"github.com/zilliztech/milvus-migration/core/parity"
+	"github.com/zilliztech/milvus-migration/core/plan"
+	"github.com/zilliztech/milvus-migration/core/verify"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"github.com/zilliztech/milvus-migration/starter/migration"
 	"github.com/zilliztech/milvus-migration/starter/param"
+	"go.uber.org/zap"
 	"time"
 )
 
@@ -102,13 +107,202 @@ func Start(ctx context.Context, configFile string, collection string, jobId stri
 		replaceCollectionName(migrCfg, collection)
 	}
 
-	if migrCfg.DumperWorkCfg.WorkMode == string(common.Elasticsearch) {
-		//record: es dump will split many small json file task
-		gstore.InitFileTask(jobId)
+	err = runMigration(ctx, migrCfg, jobId)
+	if err != nil {
+		return err
+	}
+
+	if collection != "" {
+		fmt.Printf("Migration CmdParam Collection: %s Done!", collection)
+	}
+
+	fmt.Printf("Migration Success! Job %s cost=[%f]\n", jobId, time.Since(start).Seconds())
+	printStartJobMessage(jobId)
+
+	if migrCfg.VerifyCfg.Enable {
+		//verify use its own job process, not overwrite the migration job process
+		verifyJobId := jobId + "-verify"
+		err = gstore.NewJobInfo(verifyJobId)
+		if err != nil {
+			return err
+		}
+		return runVerify(ctx, migrCfg, verifyJobId)
+	}
+	return nil
+}
+
+// Verify : compare the target collections with the source by count, sampled rows and optional checksums
+func Verify(ctx context.Context, configFile string, collection string, jobId string) error {
+	err := stepStore(jobId)
+	if err != nil {
+		return err
+	}
+
+	migrCfg, err := stepConfig(configFile)
+	if err != nil {
+		return err
+	}
+	if collection != "" {
+		replaceCollectionName(migrCfg, collection)
+	}
+	return runVerify(ctx, migrCfg, jobId)
+}
+
+// Parity : search the random source vectors on both source and target, report recall@k and rank overlap
+func Parity(ctx context.Context, configFile string, collection string, jobId string) error {
+	err := stepStore(jobId)
+	if err != nil {
+		return err
+	}
+
+	migrCfg, err := stepConfig(configFile)
+	if err != nil {
+		return err
+	}
+	if collection != "" {
+		replaceCollectionName(migrCfg, collection)
+	}
+
+	log.LL(ctx).Info("[Starter] begin to do parity check...")
+	report, err := parity.NewChecker(migrCfg, jobId).Run(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report.String())
+	if migrCfg.ParityCfg.ReportFile != "" {
+		err = report.Save(migrCfg.ParityCfg.ReportFile)
+		if err != nil {
+			log.Error("[Starter] save parity report error", zap.String("file", migrCfg.ParityCfg.ReportFile), zap.Error(err))
+			return err
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("parity job %s not passed, see the report for the recall of each query", jobId)
+	}
+	fmt.Printf("Parity Success! Job %s\n", jobId)
+	return nil
+}
+
+// Plan : dry-run, print the target schemas, row counts, estimated file sizes and warnings, not write anything,
+// so the job not registered to the job store
+func Plan(ctx context.Context, configFile string, collection string, jobId string) error {
+	migrCfg, err := stepConfig(configFile)
+	if err != nil {
+		return err
+	}
+	if collection != "" {
+		replaceCollectionName(migrCfg, collection)
+	}
+
+	log.LL(ctx).Info("[Starter] begin to plan migration...")
+	migrPlan, err := plan.NewPlanner(migrCfg, jobId).Run(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Print(migrPlan.String())
+	fmt.Printf("Plan Success! Job %s collections=%d warnings=%d\n", jobId, len(migrPlan.Collections), migrPlan.WarningCount())
+	return nil
+}
+
+func runVerify(ctx context.Context, migrCfg *config.MigrationConfig, jobId string) error {
+	//source read by the milvus2x like streaming readers, process calc by read rows
+	gstore.InitProcessHandler(jobId, string(common.Milvus2x))
+
+	log.LL(ctx).Info("[Starter] begin to do verify...")
+	report, err := verify.NewVerifier(migrCfg, jobId).Run(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report.String())
+	if migrCfg.VerifyCfg.ReportFile != "" {
+		err = report.Save(migrCfg.VerifyCfg.ReportFile)
+		if err != nil {
+			log.Error("[Starter] save verify report error", zap.String("file", migrCfg.VerifyCfg.ReportFile), zap.Error(err))
+			return err
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("verify job %s not passed, see the report for the mismatches", jobId)
+	}
+	fmt.Printf("Verify Success! Job %s\n", jobId)
+	return nil
+}
+
+// Resume : continue the failed milvus2x or es pit job from its checkpoint, need use the same config file of the job
+func Resume(ctx context.Context, configFile string, collection string, jobId string) error {
+
+	start := time.Now()
+
+	err := stepStore(jobId)
+	if err != nil {
+		return err
+	}
+
+	migrCfg, err := stepConfig(configFile)
+	if err != nil {
+		return err
+	}
+	workMode := common.DumpMode(migrCfg.DumperWorkCfg.WorkMode)
+	if workMode != common.Milvus2x && workMode != common.Elasticsearch {
+		return fmt.Errorf("resume only support milvus2x and elasticsearch workMode, but %s", workMode)
+	}
+	//files target not record checkpoint, only batch insert to target milvus2x can resume, es need pit readMode
+	if migrCfg.TargetType == string(common.T_FILES) || migrCfg.CheckpointCfg == nil {
+		return fmt.Errorf("resume only support milvus2x target with checkpoint (elasticsearch need batchInsert and pit readMode), "+
+			"but target type is %s", migrCfg.TargetType)
+	}
+	if collection != "" {
+		replaceCollectionName(migrCfg, collection)
+	}
+	config.ForceResumeAppend(migrCfg)
+
+	ckptStore, err := checkpoint.NewStore(migrCfg.CheckpointCfg)
+	if err != nil {
+		return err
+	}
+	ckpt, err := ckptStore.Load(ctx, jobId)
+	if err != nil {
+		log.Error("[Starter] load job checkpoint error", zap.String("jobId", jobId), zap.Error(err))
+		return err
+	}
+	gstore.SetCheckpoint(jobId, ckpt)
+
+	err = runMigration(ctx, migrCfg, jobId)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Migration Resume Success! Job %s cost=[%f]\n", jobId, time.Since(start).Seconds())
+	printStartJobMessage(jobId)
+	return nil
+}
+
+// batchInsertModes : work modes migrated by the batch source runner
+var batchInsertModes = map[common.DumpMode]bool{
+	common.Manifest: true,
+	common.Pgvector: true,
+	common.Qdrant:   true,
+	common.Chroma:   true,
+	common.Files:    true,
+}
+
+func runMigration(ctx context.Context, migrCfg *config.MigrationConfig, jobId string) error {
+	processMode := migrCfg.DumperWorkCfg.WorkMode
+	if migrCfg.TargetType == string(common.T_FILES) || batchInsertModes[common.DumpMode(processMode)] {
+		//files target and batch insert sources process calc by exported/inserted rows like milvus2x
+		processMode = string(common.Milvus2x)
+	} else if processMode == string(common.Elasticsearch) {
+		if migrCfg.LoaderWorkCfg.InsertMode == string(common.BatchInsert) {
+			//es batch insert not dump json files, process calc by inserted rows like milvus2x
+			processMode = string(common.Milvus2x)
+		} else {
+			//record: es dump will split many small json file task
+			gstore.InitFileTask(jobId)
+		}
 	}
 
 	//管理进度处理器
-	gstore.InitProcessHandler(jobId, migrCfg.DumperWorkCfg.WorkMode)
+	gstore.InitProcessHandler(jobId, processMode)
 
 	starter, err := migration.NewStarter(migrCfg, jobId)
 	if err != nil {
@@ -127,17 +321,13 @@ func Start(ctx context.Context, configFile string, collection string, jobId stri
 	}
 	log.LL(ctx).Info("[Cleaner] clean file success!")
 
-	if collection != "" {
-		fmt.Printf("Migration CmdParam Collection: %s Done!", collection)
-	}
-
-	fmt.Printf("Migration Success! Job %s cost=[%f]\n", jobId, time.Since(start).Seconds())
-	printStartJobMessage(jobId)
 	return nil
 }
 
 func replaceCollectionName(migrCfg *config.MigrationConfig, collection string) {
 	if migrCfg.MetaConfig.Milvus2xMeta != nil {
+		//指定了collection时只迁移该collection
+		migrCfg.MetaConfig.Milvus2xMeta.CollCfgs = migrCfg.MetaConfig.Milvus2xMeta.CollCfgs[:1]
 		migrCfg.MetaConfig.Milvus2xMeta.CollCfgs[0].Collection = collection
 		if migrCfg.MetaConfig.Milvus2xMeta.CollCfgs[0].MilvusCfg != nil {
 			migrCfg.MetaConfig.Milvus2xMeta.CollCfgs[0].MilvusCfg.Collection = collection
@@ -146,6 +336,26 @@ func replaceCollectionName(migrCfg *config.MigrationConfig, collection string) {
 	if migrCfg.MetaConfig.EsMeta != nil {
 		migrCfg.MetaConfig.EsMeta.IdxCfgs[0].MilvusCfg.Collection = collection
 	}
+	if migrCfg.MetaConfig.PgvectorMeta != nil {
+		migrCfg.MetaConfig.PgvectorMeta.TableCfgs = migrCfg.MetaConfig.PgvectorMeta.TableCfgs[:1]
+		migrCfg.MetaConfig.PgvectorMeta.TableCfgs[0].MilvusCfg.Collection = collection
+	}
+	if migrCfg.MetaConfig.QdrantMeta != nil {
+		migrCfg.MetaConfig.QdrantMeta.CollectionCfgs = migrCfg.MetaConfig.QdrantMeta.CollectionCfgs[:1]
+		migrCfg.MetaConfig.QdrantMeta.CollectionCfgs[0].MilvusCfg.Collection = collection
+	}
+	if migrCfg.MetaConfig.ChromaMeta != nil {
+		if len(migrCfg.MetaConfig.ChromaMeta.CollectionCfgs) == 0 {
+			migrCfg.MetaConfig.ChromaMeta.DefaultCfg.MilvusCfg.Collection = collection
+		} else {
+			migrCfg.MetaConfig.ChromaMeta.CollectionCfgs = migrCfg.MetaConfig.ChromaMeta.CollectionCfgs[:1]
+			migrCfg.MetaConfig.ChromaMeta.CollectionCfgs[0].MilvusCfg.Collection = collection
+		}
+	}
+	if migrCfg.MetaConfig.FilesMeta != nil {
+		migrCfg.MetaConfig.FilesMeta.CollectionCfgs = migrCfg.MetaConfig.FilesMeta.CollectionCfgs[:1]
+		migrCfg.MetaConfig.FilesMeta.CollectionCfgs[0].MilvusCfg.Collection = collection
+	}
 }
 
 func printStartJobMessage(jobId string) {
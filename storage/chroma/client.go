@@ -0,0 +1,336 @@
+package chroma
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+const SqliteFile = "chroma.sqlite3"
+
+// documentKey : chroma store the document as this embedding_metadata key, other chroma: prefix keys are internal too
+const documentKey = "chroma:document"
+
+// embeddings_queue operation
+const (
+	opAdd    = 0
+	opUpdate = 1
+	opUpsert = 2
+	opDelete = 3
+)
+
+// Client : read the chroma persist directory offline, the collections, segments, documents and metadata are in the
+// chroma.sqlite3, the persisted vectors are in the hnsw segment dir, the not persisted vectors are in the embeddings_queue
+type Client struct {
+	dir string
+	db  *gorm.DB
+	//embedding_metadata have bool_value column, old version store bool as int_value
+	hasBoolValue bool
+}
+
+func NewClient(persistDir string) (*Client, error) {
+	file := filepath.Join(persistDir, SqliteFile)
+	db, err := gorm.Open(sqlite.Open("file:"+file+"?mode=ro"), &gorm.Config{})
+	if err != nil {
+		log.Error("[Chroma] open chroma sqlite error", zap.String("file", file), zap.Error(err))
+		return nil, err
+	}
+	cli := &Client{dir: persistDir, db: db}
+	var columns []string
+	err = db.Raw("SELECT name FROM pragma_table_info('embedding_metadata')").Scan(&columns).Error
+	if err != nil {
+		cli.Close()
+		return nil, fmt.Errorf("read chroma sqlite %s: %w", file, err)
+	}
+	if len(columns) == 0 {
+		cli.Close()
+		return nil, fmt.Errorf("%s is not a chroma sqlite, table embedding_metadata not exist", file)
+	}
+	for _, column := range columns {
+		cli.hasBoolValue = cli.hasBoolValue || column == "bool_value"
+	}
+	return cli, nil
+}
+
+func (cli *Client) Close() error {
+	db, err := cli.db.DB()
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// ListCollections : names of all collections
+func (cli *Client) ListCollections(ctx context.Context) ([]string, error) {
+	var names []string
+	err := cli.db.WithContext(ctx).Raw("SELECT name FROM collections ORDER BY name").Scan(&names).Error
+	return names, err
+}
+
+// DescribeCollection : read the collection uuid, dimension, hnsw:space and segment ids of collCfg.Collection
+func (cli *Client) DescribeCollection(ctx context.Context, collCfg *chromatype.CollectionCfg) error {
+	db := cli.db.WithContext(ctx)
+	var colls []struct {
+		Id        string
+		Dimension sql.NullInt64
+	}
+	err := db.Raw("SELECT id, dimension FROM collections WHERE name = ?", collCfg.Collection).Scan(&colls).Error
+	if err != nil {
+		return err
+	}
+	if len(colls) != 1 {
+		return fmt.Errorf("chroma collection %s found %d, need exactly one", collCfg.Collection, len(colls))
+	}
+	collCfg.InnerId = colls[0].Id
+	collCfg.InnerDim = int(colls[0].Dimension.Int64)
+
+	var spaces []string
+	//chroma 1.x keep the hnsw space in collections.config_json_str, not have the key here
+	err = db.Raw("SELECT str_value FROM collection_metadata WHERE collection_id = ? AND key = 'hnsw:space'", collCfg.InnerId).
+		Scan(&spaces).Error
+	if err == nil && len(spaces) > 0 {
+		collCfg.InnerSpace = spaces[0]
+	}
+
+	var segments []struct {
+		Id    string
+		Scope string
+	}
+	err = db.Raw("SELECT id, scope FROM segments WHERE collection = ?", collCfg.InnerId).Scan(&segments).Error
+	if err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		switch seg.Scope {
+		case "VECTOR":
+			collCfg.InnerVectorSegment = seg.Id
+		case "METADATA":
+			collCfg.InnerMetadataSegment = seg.Id
+		}
+	}
+	if collCfg.InnerVectorSegment == "" || collCfg.InnerMetadataSegment == "" {
+		return fmt.Errorf("chroma collection %s not have vector and metadata segment", collCfg.Collection)
+	}
+	if collCfg.InnerDim == 0 {
+		collCfg.InnerDim, err = HnswDim(filepath.Join(cli.dir, collCfg.InnerVectorSegment))
+		if err != nil {
+			return err
+		}
+	}
+	log.LL(ctx).Info("[Chroma] describe collection", zap.String("collection", collCfg.Collection),
+		zap.String("id", collCfg.InnerId), zap.Int("dim", collCfg.InnerDim), zap.String("space", collCfg.InnerSpace))
+	return nil
+}
+
+// Count : records of the collection metadata segment
+func (cli *Client) Count(ctx context.Context, collCfg *chromatype.CollectionCfg) (int64, error) {
+	var count int64
+	err := cli.db.WithContext(ctx).Raw("SELECT count(*) FROM embeddings WHERE segment_id = ?", collCfg.InnerMetadataSegment).
+		Scan(&count).Error
+	return count, err
+}
+
+// queueEmbeddings : the latest vectors in embeddings_queue after the seq_id, deleted id value is nil
+func (cli *Client) queueEmbeddings(ctx context.Context, collCfg *chromatype.CollectionCfg, afterSeqId int64) (map[string][]float32, error) {
+	rows, err := cli.db.WithContext(ctx).Raw(`SELECT operation, id, vector, encoding FROM embeddings_queue
+WHERE topic LIKE ? AND seq_id > ? ORDER BY seq_id`, "%"+collCfg.InnerId, afterSeqId).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	embeddings := make(map[string][]float32)
+	for rows.Next() {
+		var op int
+		var id string
+		var vector []byte
+		var encoding sql.NullString
+		err = rows.Scan(&op, &id, &vector, &encoding)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opDelete:
+			embeddings[id] = nil
+		case opAdd, opUpdate, opUpsert:
+			//update without embedding not change the vector
+			if vector == nil {
+				continue
+			}
+			embeddings[id], err = decodeVector(vector, encoding.String)
+			if err != nil {
+				return nil, fmt.Errorf("embeddings_queue id %s: %w", id, err)
+			}
+		}
+	}
+	return embeddings, rows.Err()
+}
+
+// decodeVector : embeddings_queue vector is the struct packed float32 or int32 array
+func decodeVector(buf []byte, encoding string) ([]float32, error) {
+	if len(buf)%4 != 0 {
+		return nil, fmt.Errorf("invalid vector length %d", len(buf))
+	}
+	switch strings.ToUpper(encoding) {
+	case "", "FLOAT32":
+		return decodeFloat32s(buf), nil
+	case "INT32":
+		vector := make([]float32, len(buf)/4)
+		for i := range vector {
+			vector[i] = float32(int32(binary.LittleEndian.Uint32(buf[i*4:])))
+		}
+		return vector, nil
+	}
+	return nil, fmt.Errorf("vector encoding %s not support", encoding)
+}
+
+// CollectionReader : read the records of a collection in embeddings.id order, the embedding is from the
+// embeddings_queue if it's newer than the hnsw segment
+type CollectionReader struct {
+	cli     *Client
+	collCfg *chromatype.CollectionCfg
+	seg     *HnswSegment
+	pending map[string][]float32
+}
+
+func (cli *Client) NewCollectionReader(ctx context.Context, collCfg *chromatype.CollectionCfg) (*CollectionReader, error) {
+	seg, err := OpenHnswSegment(filepath.Join(cli.dir, collCfg.InnerVectorSegment))
+	if err != nil {
+		return nil, fmt.Errorf("chroma collection %s open hnsw segment: %w", collCfg.Collection, err)
+	}
+	var maxSeqId int64
+	if seg != nil {
+		maxSeqId = seg.MaxSeqId
+		if seg.Dim() != collCfg.InnerDim {
+			seg.Close()
+			return nil, fmt.Errorf("chroma collection %s hnsw dim %d not match %d", collCfg.Collection, seg.Dim(), collCfg.InnerDim)
+		}
+	}
+	pending, err := cli.queueEmbeddings(ctx, collCfg, maxSeqId)
+	if err != nil {
+		if seg != nil {
+			seg.Close()
+		}
+		return nil, err
+	}
+	log.LL(ctx).Info("[Chroma] open collection", zap.String("collection", collCfg.Collection),
+		zap.Bool("persisted", seg != nil), zap.Int64("maxSeqId", maxSeqId), zap.Int("pending", len(pending)))
+	return &CollectionReader{cli: cli, collCfg: collCfg, seg: seg, pending: pending}, nil
+}
+
+// ReadPage : the records after the embeddings.id lastKey, return the id of the last record as the next lastKey
+func (cr *CollectionReader) ReadPage(ctx context.Context, lastKey int64, size int) ([]*chromatype.Record, int64, error) {
+	db := cr.cli.db.WithContext(ctx)
+	var rows []struct {
+		Id          int64
+		EmbeddingId string
+	}
+	err := db.Raw("SELECT id, embedding_id FROM embeddings WHERE segment_id = ? AND id > ? ORDER BY id LIMIT ?",
+		cr.collCfg.InnerMetadataSegment, lastKey, size).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, lastKey, err
+	}
+	records := make([]*chromatype.Record, len(rows))
+	metadatas := make(map[int64]map[string]any, len(rows))
+	index := make(map[int64]int, len(rows))
+	for i, row := range rows {
+		records[i] = &chromatype.Record{Id: row.EmbeddingId}
+		metadatas[row.Id] = make(map[string]any)
+		index[row.Id] = i
+	}
+	err = cr.readMetadata(ctx, rows[0].Id, rows[len(rows)-1].Id, func(key int64, name string, value any) {
+		i, ok := index[key]
+		if !ok {
+			return
+		}
+		if name == documentKey {
+			records[i].Document, _ = value.(string)
+		} else if !strings.HasPrefix(name, "chroma:") {
+			metadatas[key][name] = value
+		}
+	})
+	if err != nil {
+		return nil, lastKey, err
+	}
+	for i, row := range rows {
+		records[i].Metadata, err = json.Marshal(metadatas[row.Id])
+		if err != nil {
+			return nil, lastKey, err
+		}
+		records[i].Embedding, err = cr.embedding(row.EmbeddingId)
+		if err != nil {
+			return nil, lastKey, err
+		}
+	}
+	return records, rows[len(rows)-1].Id, nil
+}
+
+// readMetadata : embedding_metadata rows of the embeddings.id range
+func (cr *CollectionReader) readMetadata(ctx context.Context, fromKey int64, toKey int64, fn func(int64, string, any)) error {
+	columns := "id, key, string_value, int_value, float_value"
+	if cr.cli.hasBoolValue {
+		columns += ", bool_value"
+	}
+	rows, err := cr.cli.db.WithContext(ctx).Raw("SELECT "+columns+" FROM embedding_metadata WHERE id >= ? AND id <= ?",
+		fromKey, toKey).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key int64
+		var name string
+		var strValue sql.NullString
+		var intValue sql.NullInt64
+		var floatValue sql.NullFloat64
+		var boolValue sql.NullBool
+		dest := []any{&key, &name, &strValue, &intValue, &floatValue}
+		if cr.cli.hasBoolValue {
+			dest = append(dest, &boolValue)
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+		switch {
+		case strValue.Valid:
+			fn(key, name, strValue.String)
+		case intValue.Valid:
+			fn(key, name, intValue.Int64)
+		case floatValue.Valid && !math.IsNaN(floatValue.Float64) && !math.IsInf(floatValue.Float64, 0):
+			fn(key, name, floatValue.Float64)
+		case boolValue.Valid:
+			fn(key, name, boolValue.Bool)
+		}
+	}
+	return rows.Err()
+}
+
+func (cr *CollectionReader) embedding(id string) ([]float32, error) {
+	if vector, ok := cr.pending[id]; ok && vector != nil {
+		return vector, nil
+	}
+	if cr.seg != nil {
+		vector, ok, err := cr.seg.Embedding(id)
+		if err != nil || ok {
+			return vector, err
+		}
+	}
+	return nil, errors.New("chroma embedding " + id + " not found in hnsw segment and embeddings_queue")
+}
+
+func (cr *CollectionReader) Close() error {
+	if cr.seg != nil {
+		return cr.seg.Close()
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package chroma
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/type/chromatype"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// indexMetadata : python pickle.dumps(PersistentData, protocol=4) of the ids a, b, c with label 1, 2, 3 and max_seq_id 3
+const indexMetadata = "800495d0000000000000008c156c6f63616c5f70657273697374656e745f686e7377948c0e50657273697374656e74446174619493942981947d94288c0e64696d656e73696f6e616c697479944b028c14746f74616c5f656c656d656e74735f6164646564944b038c0a6d61785f7365715f6964944b038c0b69645f746f5f6c6162656c947d94288c0161944b018c0162944b028c0163944b03758c0b6c6162656c5f746f5f6964947d94284b01680a4b02680b4b03680c758c0c69645f746f5f7365715f6964947d9428680a4b01680b4b02680c4b037575622e"
+
+var fixtureSchema = []string{
+	"CREATE TABLE collections (id TEXT PRIMARY KEY, name TEXT, dimension INTEGER)",
+	"CREATE TABLE collection_metadata (collection_id TEXT, key TEXT, str_value TEXT, int_value INTEGER, float_value REAL)",
+	"CREATE TABLE segments (id TEXT PRIMARY KEY, type TEXT, scope TEXT, collection TEXT)",
+	"CREATE TABLE embeddings (id INTEGER PRIMARY KEY, segment_id TEXT, embedding_id TEXT, seq_id BLOB)",
+	"CREATE TABLE embedding_metadata (id INTEGER, key TEXT, string_value TEXT, int_value INTEGER, float_value REAL, bool_value INTEGER)",
+	"CREATE TABLE embeddings_queue (seq_id INTEGER PRIMARY KEY, created_at TIMESTAMP, operation INTEGER, topic TEXT, id TEXT, vector BLOB, encoding TEXT, metadata TEXT)",
+}
+
+func float32Bytes(vector ...float32) []byte {
+	buf := make([]byte, len(vector)*4)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// writeFixture : collection docs of dim 2, a and b persisted in hnsw segment, c deleted, b updated and d added after persist
+func writeFixture(t *testing.T, dir string) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(dir, SqliteFile)), &gorm.Config{})
+	assert.NoError(t, err)
+	exec := func(sql string, values ...any) {
+		assert.NoError(t, db.Exec(sql, values...).Error)
+	}
+	for _, sql := range fixtureSchema {
+		exec(sql)
+	}
+	exec("INSERT INTO collections VALUES ('c1', 'docs', 2)")
+	exec("INSERT INTO collection_metadata VALUES ('c1', 'hnsw:space', 'cosine', NULL, NULL)")
+	exec("INSERT INTO segments VALUES ('vs', 'urn:chroma:segment/vector/hnsw-local-persisted', 'VECTOR', 'c1')")
+	exec("INSERT INTO segments VALUES ('ms', 'urn:chroma:segment/metadata/sqlite', 'METADATA', 'c1')")
+	exec("INSERT INTO embeddings VALUES (1, 'ms', 'a', NULL), (2, 'ms', 'b', NULL), (4, 'ms', 'd', NULL)")
+	exec(`INSERT INTO embedding_metadata VALUES (1, 'chroma:document', 'doc a', NULL, NULL, NULL),
+		(1, 'year', NULL, 2024, NULL, NULL), (1, 'ok', NULL, NULL, NULL, 1),
+		(2, 'score', NULL, NULL, 0.5, NULL), (4, 'chroma:document', 'doc d', NULL, NULL, NULL)`)
+	topic := "persistent://default/default/c1"
+	exec("INSERT INTO embeddings_queue VALUES (3, NULL, 3, ?, 'c', NULL, NULL, NULL)", topic)
+	exec("INSERT INTO embeddings_queue VALUES (4, NULL, 1, ?, 'b', ?, 'FLOAT32', NULL)", topic, float32Bytes(5, 6))
+	exec("INSERT INTO embeddings_queue VALUES (5, NULL, 0, ?, 'd', ?, 'FLOAT32', NULL)", topic, float32Bytes(7, 8))
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+
+	segDir := filepath.Join(dir, "vs")
+	assert.NoError(t, os.MkdirAll(segDir, 0755))
+	//element: 8 bytes link list, 2 float32 vector, 8 bytes label
+	header := hnswHeader{MaxElements: 10, CurElementCount: 3, SizeDataPerElement: 24, LabelOffset: 16, OffsetData: 8}
+	headerBuf := make([]byte, 48)
+	for i, v := range []uint64{header.OffsetLevel0, header.MaxElements, header.CurElementCount,
+		header.SizeDataPerElement, header.LabelOffset, header.OffsetData} {
+		binary.LittleEndian.PutUint64(headerBuf[i*8:], v)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(segDir, hnswHeaderFile), headerBuf, 0644))
+	var data []byte
+	for label, vector := range [][]float32{{1, 2}, {3, 4}, {9, 9}} {
+		element := make([]byte, 24)
+		copy(element[8:], float32Bytes(vector...))
+		binary.LittleEndian.PutUint64(element[16:], uint64(label+1))
+		data = append(data, element...)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(segDir, hnswDataFile), data, 0644))
+	pickle, _ := hex.DecodeString(indexMetadata)
+	assert.NoError(t, os.WriteFile(filepath.Join(segDir, hnswMetadataFile), pickle, 0644))
+}
+
+func TestCollectionReader(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir)
+	ctx := context.Background()
+	cli, err := NewClient(dir)
+	assert.NoError(t, err)
+	defer cli.Close()
+
+	names, err := cli.ListCollections(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, names)
+
+	collCfg := &chromatype.CollectionCfg{Collection: "docs"}
+	assert.NoError(t, cli.DescribeCollection(ctx, collCfg))
+	assert.Equal(t, 2, collCfg.InnerDim)
+	assert.Equal(t, "cosine", collCfg.InnerSpace)
+	count, err := cli.Count(ctx, collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	reader, err := cli.NewCollectionReader(ctx, collCfg)
+	assert.NoError(t, err)
+	defer reader.Close()
+	records, lastKey, err := reader.ReadPage(ctx, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), lastKey)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "a", records[0].Id)
+	assert.Equal(t, "doc a", records[0].Document)
+	assert.Equal(t, []float32{1, 2}, records[0].Embedding)
+	var metadata map[string]any
+	assert.NoError(t, json.Unmarshal(records[0].Metadata, &metadata))
+	assert.Equal(t, map[string]any{"year": float64(2024), "ok": true}, metadata)
+	//b updated after the segment persisted
+	assert.Equal(t, []float32{5, 6}, records[1].Embedding)
+	assert.JSONEq(t, `{"score":0.5}`, string(records[1].Metadata))
+
+	records, lastKey, err = reader.ReadPage(ctx, lastKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), lastKey)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "d", records[0].Id)
+	assert.Equal(t, "doc d", records[0].Document)
+	assert.Equal(t, []float32{7, 8}, records[0].Embedding)
+
+	records, _, err = reader.ReadPage(ctx, lastKey, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestUnpickle(t *testing.T) {
+	pickle, _ := hex.DecodeString(indexMetadata)
+	v, err := unpickle(bytes.NewReader(pickle))
+	assert.NoError(t, err)
+	obj, ok := v.(*pickleObject)
+	assert.True(t, ok)
+	assert.Equal(t, "local_persistent_hnsw.PersistentData", obj.Class)
+	state := obj.State.(pickleDict)
+	assert.Equal(t, int64(3), state["max_seq_id"])
+	assert.Equal(t, pickleDict{"a": int64(1), "b": int64(2), "c": int64(3)}, state["id_to_label"])
+	assert.Equal(t, pickleDict{int64(1): "a", int64(2): "b", int64(3): "c"}, state["label_to_id"])
+}
+
+func TestDecodeVector(t *testing.T) {
+	vector, err := decodeVector(float32Bytes(1.5, -2), "FLOAT32")
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{1.5, -2}, vector)
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf, uint32(3))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(0xffffffff))
+	vector, err = decodeVector(buf, "INT32")
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{3, -1}, vector)
+	_, err = decodeVector([]byte{1, 2, 3}, "FLOAT32")
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package chroma
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// hnsw segment files of the chroma persist directory, named by the vector segment id
+const (
+	hnswHeaderFile   = "header.bin"
+	hnswDataFile     = "data_level0.bin"
+	hnswMetadataFile = "index_metadata.pickle"
+)
+
+// hnswHeader : the hnswlib index header fields before maxlevel, all are size_t
+type hnswHeader struct {
+	OffsetLevel0       uint64
+	MaxElements        uint64
+	CurElementCount    uint64
+	SizeDataPerElement uint64
+	LabelOffset        uint64
+	OffsetData         uint64
+}
+
+// HnswSegment : read the embeddings of the persisted hnswlib level0 data, the chroma id to hnsw label mapping
+// is the index_metadata.pickle
+type HnswSegment struct {
+	header   hnswHeader
+	dim      int
+	data     *os.File
+	idLabels map[string]int64 //chroma embedding id -> hnsw label
+	offsets  map[int64]int64  //hnsw label -> element offset of data_level0.bin
+	MaxSeqId int64            //the embeddings_queue seq_id persisted in the segment
+}
+
+// OpenHnswSegment : segment dir not exist means the collection vectors not persisted, all in the embeddings_queue
+func OpenHnswSegment(dir string) (*HnswSegment, error) {
+	if _, err := os.Stat(filepath.Join(dir, hnswHeaderFile)); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	seg := &HnswSegment{}
+	var err error
+	seg.header, seg.dim, err = readHnswHeader(filepath.Join(dir, hnswHeaderFile))
+	if err != nil {
+		return nil, err
+	}
+	err = seg.readMetadata(filepath.Join(dir, hnswMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	seg.data, err = os.Open(filepath.Join(dir, hnswDataFile))
+	if err != nil {
+		return nil, err
+	}
+	err = seg.scanLabels()
+	if err != nil {
+		seg.data.Close()
+		return nil, err
+	}
+	return seg, nil
+}
+
+// readHnswHeader : return the header and the vector dim
+func readHnswHeader(file string) (hnswHeader, int, error) {
+	var h hnswHeader
+	f, err := os.Open(file)
+	if err != nil {
+		return h, 0, err
+	}
+	defer f.Close()
+	err = binary.Read(f, binary.LittleEndian, &h)
+	if err != nil {
+		return h, 0, fmt.Errorf("read hnsw header %s: %w", file, err)
+	}
+	if h.LabelOffset+8 > h.SizeDataPerElement || h.OffsetData > h.LabelOffset || (h.LabelOffset-h.OffsetData)%4 != 0 {
+		return h, 0, fmt.Errorf("invalid hnsw header %s", file)
+	}
+	return h, int((h.LabelOffset - h.OffsetData) / 4), nil
+}
+
+// HnswDim : dim of the persisted hnsw segment, 0 if the segment not persisted
+func HnswDim(dir string) (int, error) {
+	file := filepath.Join(dir, hnswHeaderFile)
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	_, dim, err := readHnswHeader(file)
+	return dim, err
+}
+
+// readMetadata : PersistentData pickle, state dict have id_to_label, label_to_id, id_to_seq_id and max_seq_id
+func (seg *HnswSegment) readMetadata(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	v, err := unpickle(f)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", file, err)
+	}
+	state := pickleDict{}
+	switch obj := v.(type) {
+	case *pickleObject:
+		state, _ = obj.State.(pickleDict)
+	case pickleDict:
+		state = obj
+	}
+	idToLabel, ok := state["id_to_label"].(pickleDict)
+	if !ok {
+		return fmt.Errorf("%s not have id_to_label", file)
+	}
+	seg.idLabels = make(map[string]int64, len(idToLabel))
+	for id, label := range idToLabel {
+		n, ok := label.(int64)
+		if !ok {
+			return fmt.Errorf("%s invalid label of id %v", file, id)
+		}
+		seg.idLabels[fmt.Sprint(id)] = n
+	}
+	seg.MaxSeqId, _ = state["max_seq_id"].(int64)
+	return nil
+}
+
+// scanLabels : read the label of each element, deleted element label also read but chroma removed it from id_to_label
+func (seg *HnswSegment) scanLabels() error {
+	h := seg.header
+	seg.offsets = make(map[int64]int64, h.CurElementCount)
+	br := bufio.NewReaderSize(seg.data, 1<<20)
+	element := make([]byte, h.SizeDataPerElement)
+	for i := uint64(0); i < h.CurElementCount; i++ {
+		_, err := io.ReadFull(br, element)
+		if err != nil {
+			return fmt.Errorf("read hnsw element %d: %w", i, err)
+		}
+		label := int64(binary.LittleEndian.Uint64(element[h.LabelOffset:]))
+		seg.offsets[label] = int64(i * h.SizeDataPerElement)
+	}
+	return nil
+}
+
+func (seg *HnswSegment) Dim() int {
+	return seg.dim
+}
+
+// Embedding : the persisted embedding of the chroma id, false if not in the segment.
+// cosine space vectors are normalized by hnswlib when added
+func (seg *HnswSegment) Embedding(id string) ([]float32, bool, error) {
+	label, ok := seg.idLabels[id]
+	if !ok {
+		return nil, false, nil
+	}
+	offset, ok := seg.offsets[label]
+	if !ok {
+		return nil, false, nil
+	}
+	buf := make([]byte, seg.dim*4)
+	_, err := seg.data.ReadAt(buf, offset+int64(seg.header.OffsetData))
+	if err != nil {
+		return nil, false, err
+	}
+	return decodeFloat32s(buf), true, nil
+}
+
+func (seg *HnswSegment) Close() error {
+	return seg.data.Close()
+}
+
+// decodeFloat32s : little endian float32 array
+func decodeFloat32s(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return vector
+}
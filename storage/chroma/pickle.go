@@ -0,0 +1,304 @@
+package chroma
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+)
+
+// pickleObject : python object created by NEWOBJ or REDUCE, State is set by BUILD
+type pickleObject struct {
+	Class string
+	Args  []any
+	State any
+}
+
+// pickleDict : python dict, the key is string, int64 or the fmt text of the other hashable value
+type pickleDict map[any]any
+
+type pickleMark struct{}
+
+// unpickle : a minimal python pickle decoder of protocol 2-5, only the opcodes of basic values, list, tuple, dict
+// and plain objects are supported, enough to read the chroma hnsw index_metadata.pickle
+func unpickle(r io.Reader) (any, error) {
+	br := bufio.NewReader(r)
+	var stack []any
+	memo := make(map[int]any)
+	pop := func() (any, error) {
+		if len(stack) == 0 {
+			return nil, errors.New("pickle stack underflow")
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v, nil
+	}
+	//popMark : pop the items after the last mark
+	popMark := func() ([]any, error) {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if _, ok := stack[i].(pickleMark); ok {
+				items := append([]any{}, stack[i+1:]...)
+				stack = stack[:i]
+				return items, nil
+			}
+		}
+		return nil, errors.New("pickle mark not found")
+	}
+	readN := func(n int) ([]byte, error) {
+		buf := make([]byte, n)
+		_, err := io.ReadFull(br, buf)
+		return buf, err
+	}
+	readUint := func(size int) (uint64, error) {
+		buf, err := readN(size)
+		if err != nil {
+			return 0, err
+		}
+		var v uint64
+		for i := size - 1; i >= 0; i-- {
+			v = v<<8 | uint64(buf[i])
+		}
+		return v, nil
+	}
+	for {
+		op, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("read pickle opcode: %w", err)
+		}
+		switch op {
+		case 0x80: //PROTO
+			if _, err = br.ReadByte(); err != nil {
+				return nil, err
+			}
+		case 0x95: //FRAME
+			if _, err = readN(8); err != nil {
+				return nil, err
+			}
+		case '.': //STOP
+			return pop()
+		case '(': //MARK
+			stack = append(stack, pickleMark{})
+		case 'N': //NONE
+			stack = append(stack, nil)
+		case 0x88: //NEWTRUE
+			stack = append(stack, true)
+		case 0x89: //NEWFALSE
+			stack = append(stack, false)
+		case 'K', 'M', 'J': //BININT1, BININT2, BININT
+			size := map[byte]int{'K': 1, 'M': 2, 'J': 4}[op]
+			v, err := readUint(size)
+			if err != nil {
+				return nil, err
+			}
+			if op == 'J' {
+				stack = append(stack, int64(int32(uint32(v))))
+			} else {
+				stack = append(stack, int64(v))
+			}
+		case 0x8a, 0x8b: //LONG1, LONG4
+			size := 1
+			if op == 0x8b {
+				size = 4
+			}
+			n, err := readUint(size)
+			if err != nil {
+				return nil, err
+			}
+			buf, err := readN(int(n))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, decodeLong(buf))
+		case 'G': //BINFLOAT, big endian
+			buf, err := readN(8)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, math.Float64frombits(binary.BigEndian.Uint64(buf)))
+		case 0x8c, 'X', 0x8d, 'C', 'B', 0x8e: //SHORT_BINUNICODE, BINUNICODE, BINUNICODE8, SHORT_BINBYTES, BINBYTES, BINBYTES8
+			size := map[byte]int{0x8c: 1, 'X': 4, 0x8d: 8, 'C': 1, 'B': 4, 0x8e: 8}[op]
+			n, err := readUint(size)
+			if err != nil {
+				return nil, err
+			}
+			buf, err := readN(int(n))
+			if err != nil {
+				return nil, err
+			}
+			if op == 0x8c || op == 'X' || op == 0x8d {
+				stack = append(stack, string(buf))
+			} else {
+				stack = append(stack, buf)
+			}
+		case 0x94: //MEMOIZE
+			if len(stack) == 0 {
+				return nil, errors.New("pickle memoize empty stack")
+			}
+			memo[len(memo)] = stack[len(stack)-1]
+		case 'q', 'r': //BINPUT, LONG_BINPUT
+			size := 1
+			if op == 'r' {
+				size = 4
+			}
+			idx, err := readUint(size)
+			if err != nil {
+				return nil, err
+			}
+			if len(stack) == 0 {
+				return nil, errors.New("pickle put empty stack")
+			}
+			memo[int(idx)] = stack[len(stack)-1]
+		case 'h', 'j': //BINGET, LONG_BINGET
+			size := 1
+			if op == 'j' {
+				size = 4
+			}
+			idx, err := readUint(size)
+			if err != nil {
+				return nil, err
+			}
+			v, ok := memo[int(idx)]
+			if !ok {
+				return nil, fmt.Errorf("pickle memo %d not found", idx)
+			}
+			stack = append(stack, v)
+		case '}': //EMPTY_DICT
+			stack = append(stack, pickleDict{})
+		case ']', 0x8f: //EMPTY_LIST, EMPTY_SET
+			stack = append(stack, &[]any{})
+		case ')': //EMPTY_TUPLE
+			stack = append(stack, []any{})
+		case 0x85, 0x86, 0x87: //TUPLE1, TUPLE2, TUPLE3
+			n := int(op - 0x84)
+			if len(stack) < n {
+				return nil, errors.New("pickle stack underflow")
+			}
+			tuple := append([]any{}, stack[len(stack)-n:]...)
+			stack = append(stack[:len(stack)-n], tuple)
+		case 't': //TUPLE
+			items, err := popMark()
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, items)
+		case 'a', 'e', 0x90: //APPEND, APPENDS, ADDITEMS
+			var items []any
+			if op == 'a' {
+				item, err := pop()
+				if err != nil {
+					return nil, err
+				}
+				items = []any{item}
+			} else if items, err = popMark(); err != nil {
+				return nil, err
+			}
+			if len(stack) == 0 {
+				return nil, errors.New("pickle stack underflow")
+			}
+			list, ok := stack[len(stack)-1].(*[]any)
+			if !ok {
+				return nil, errors.New("pickle append to not a list")
+			}
+			*list = append(*list, items...)
+		case 's', 'u': //SETITEM, SETITEMS
+			var items []any
+			if op == 's' {
+				if len(stack) < 2 {
+					return nil, errors.New("pickle stack underflow")
+				}
+				items = append([]any{}, stack[len(stack)-2:]...)
+				stack = stack[:len(stack)-2]
+			} else if items, err = popMark(); err != nil {
+				return nil, err
+			}
+			if len(stack) == 0 {
+				return nil, errors.New("pickle stack underflow")
+			}
+			dict, ok := stack[len(stack)-1].(pickleDict)
+			if !ok {
+				return nil, errors.New("pickle setitem to not a dict")
+			}
+			for i := 0; i+1 < len(items); i += 2 {
+				dict[dictKey(items[i])] = items[i+1]
+			}
+		case 0x93: //STACK_GLOBAL
+			name, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			module, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, fmt.Sprintf("%v.%v", module, name))
+		case 'c': //GLOBAL
+			module, err := br.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			name, err := br.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, module[:len(module)-1]+"."+name[:len(name)-1])
+		case 0x81, 'R': //NEWOBJ, REDUCE
+			args, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			class, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			tuple, _ := args.([]any)
+			stack = append(stack, &pickleObject{Class: fmt.Sprint(class), Args: tuple})
+		case 'b': //BUILD
+			state, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			if len(stack) == 0 {
+				return nil, errors.New("pickle stack underflow")
+			}
+			obj, ok := stack[len(stack)-1].(*pickleObject)
+			if !ok {
+				return nil, errors.New("pickle build not an object")
+			}
+			obj.State = state
+		default:
+			return nil, fmt.Errorf("pickle opcode 0x%x not support", op)
+		}
+	}
+}
+
+// decodeLong : little endian two's complement integer, value out of int64 as *big.Int
+func decodeLong(buf []byte) any {
+	if len(buf) == 0 {
+		return int64(0)
+	}
+	be := make([]byte, len(buf))
+	for i, b := range buf {
+		be[len(buf)-1-i] = b
+	}
+	n := new(big.Int).SetBytes(be)
+	if buf[len(buf)-1]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(buf)*8)))
+	}
+	if n.IsInt64() {
+		return n.Int64()
+	}
+	return n
+}
+
+// dictKey : the comparable go value of the python dict key
+func dictKey(key any) any {
+	switch k := key.(type) {
+	case string, int64, bool, float64, nil:
+		return k
+	default:
+		return fmt.Sprint(k)
+	}
+}
@@ -2,36 +2,122 @@ package es
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/tidwall/gjson"
 	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/type/estype"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"go.uber.org/zap"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 const VER7 = "7"
 const VER8 = "8"
+const VEROpenSearch = "opensearch"
 
 type ESServerClient interface {
 	InitScroll(idxCfg *estype.IdxCfg, batchSize int) (*SearchRes, error)
 	NextScroll(scrollID string) (*SearchRes, error)
 	Close(scrollId string) error
+	GetMapping(index string) (gjson.Result, error)
+	ListIndices(index string) ([]string, error)
+	Count(idxCfg *estype.IdxCfg) error
+
+	OpenPIT(index string) (string, error)
+	SearchAfter(idxCfg *estype.IdxCfg, req *PITRequest) (*SearchRes, error)
+	ClosePIT(pitId string) error
+
+	RandomDocs(idxCfg *estype.IdxCfg, size int, fields []string) (*SearchRes, error)
+	KnnSearch(idxCfg *estype.IdxCfg, req *KnnRequest) (*SearchRes, error)
 }
 
 type SearchRes struct {
 	ScrollId string
 	Hits     gjson.Result
 	IsEmpty  bool
+
+	PitId       string //pit mode: the newest pit id
+	SearchAfter string //pit mode: sort values of the last hit, json array
+	SliceId     int    //pit mode: the slice of the hits
+}
+
+// PITRequest : point in time + search_after request of a slice
+type PITRequest struct {
+	PitId       string
+	BatchSize   int
+	SliceId     int
+	SliceMax    int    //slice only work when SliceMax > 1
+	SearchAfter string //json array, empty mean the first page
+}
+
+// KnnRequest : top-k search of the dense_vector field, MetricType used by the exact script_score search
+type KnnRequest struct {
+	Field      string
+	Vector     []float32
+	K          int
+	MetricType string //L2, IP or COSINE
+	Fields     []string
 }
 
 type ESClient struct {
 	Cli     ESServerClient
 	Version string
+
+	pitOnce    sync.Once
+	supportPIT bool
+}
+
+// SupportPIT : pit search sort by _shard_doc, which es support since 7.12, the older es need read by scroll
+func (esClient *ESClient) SupportPIT() bool {
+	esClient.pitOnce.Do(func() {
+		esClient.supportPIT = true
+		es7, ok := esClient.Cli.(*ES7ServerClient)
+		if !ok {
+			return
+		}
+		version, err := es7.ServerVersion()
+		if err != nil {
+			log.Warn("get es server version error, can not check pit support", zap.Error(err))
+			esClient.supportPIT = false
+			return
+		}
+		esClient.supportPIT = !isBeforeVer(version, 7, 12)
+		log.Info("es server version", zap.String("version", version), zap.Bool("supportPIT", esClient.supportPIT))
+	})
+	return esClient.supportPIT
+}
+
+// isBeforeVer : version like 7.10.2 is before major.minor
+func isBeforeVer(version string, major int, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	verMajor, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return true
+	}
+	verMinor := 0
+	if len(parts) > 1 {
+		verMinor, _ = strconv.Atoi(parts[1])
+	}
+	return verMajor < major || (verMajor == major && verMinor < minor)
 }
 
 // CreateESClient : will create by factory
 func CreateESClient(esCfg *config.ESConfig) (*ESClient, error) {
+	if esCfg.Distribution == config.ESDistributionOpenSearch {
+		cli, err := NewOpenSearchServerCli(esCfg)
+		if err != nil {
+			log.Error("create OpenSearch Client error", zap.Error(err))
+			return nil, err
+		}
+		return &ESClient{Cli: cli, Version: VEROpenSearch}, nil
+	}
 	bigVer := VER7 //default
 	if len(esCfg.Version) > 0 {
 		bigVer = esCfg.Version[:1]
@@ -56,15 +142,191 @@ func CreateESClient(esCfg *config.ESConfig) (*ESClient, error) {
 	return &esClient, nil
 }
 
+// toKeepAlive : es time unit format, default 1m
+func toKeepAlive(keepAlive time.Duration) string {
+	if keepAlive <= 0 {
+		return "1m"
+	}
+	return fmt.Sprintf("%ds", int64(keepAlive.Seconds()))
+}
+
+// buildPITSearchBody : sort by _shard_doc, the most efficient sort for pit, need es 7.12+
+func buildPITSearchBody(idxCfg *estype.IdxCfg, req *PITRequest, keepAlive string) (string, error) {
+	return buildPITBody(idxCfg, req, keepAlive, "_shard_doc")
+}
+
+func buildPITBody(idxCfg *estype.IdxCfg, req *PITRequest, keepAlive string, sortFields ...string) (string, error) {
+	sorts := make([]any, 0, len(sortFields))
+	for _, sortField := range sortFields {
+		sorts = append(sorts, map[string]string{sortField: "asc"})
+	}
+	body := map[string]any{
+		"size": req.BatchSize,
+		"pit":  map[string]any{"id": req.PitId, "keep_alive": keepAlive},
+		"sort": sorts,
+	}
+	if len(idxCfg.Fields) > 0 {
+		body["_source"] = getFieldNames(idxCfg)
+	}
+	if req.SliceMax > 1 {
+		body["slice"] = map[string]int{"id": req.SliceId, "max": req.SliceMax}
+	}
+	if req.SearchAfter != "" {
+		body["search_after"] = json.RawMessage(req.SearchAfter)
+	}
+	if idxCfg.Query != "" {
+		body["query"] = json.RawMessage(idxCfg.Query)
+	}
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+// buildRandomDocsBody : random_score the docs matched the index query
+func buildRandomDocsBody(idxCfg *estype.IdxCfg, size int, fields []string) (string, error) {
+	query := json.RawMessage(`{"match_all":{}}`)
+	if idxCfg.Query != "" {
+		query = json.RawMessage(idxCfg.Query)
+	}
+	body := map[string]any{
+		"size":    size,
+		"_source": sourceFilter(fields),
+		"query": map[string]any{
+			"function_score": map[string]any{"query": query, "random_score": map[string]any{}},
+		},
+	}
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+// buildKnnSearchBody : es8 approximate knn search, need the dense_vector field indexed
+func buildKnnSearchBody(idxCfg *estype.IdxCfg, req *KnnRequest) (string, error) {
+	knn := map[string]any{
+		"field":          req.Field,
+		"query_vector":   req.Vector,
+		"k":              req.K,
+		"num_candidates": max(100, req.K*10),
+	}
+	if idxCfg.Query != "" {
+		knn["filter"] = json.RawMessage(idxCfg.Query)
+	}
+	body := map[string]any{
+		"size":    req.K,
+		"_source": sourceFilter(req.Fields),
+		"knn":     knn,
+	}
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+// buildScriptScoreBody : exact brute-force search by script_score, the score need be non-negative and the larger the closer
+func buildScriptScoreBody(idxCfg *estype.IdxCfg, req *KnnRequest) (string, error) {
+	var source string
+	field := strconv.Quote(req.Field)
+	switch strings.ToUpper(req.MetricType) {
+	case "IP":
+		source = "double v = dotProduct(params.query_vector, " + field + "); return v < 0 ? 1 / (1 - v) : 1 + v;"
+	case "COSINE":
+		source = "cosineSimilarity(params.query_vector, " + field + ") + 1.0"
+	default:
+		source = "1 / (1 + l2norm(params.query_vector, " + field + "))"
+	}
+	query := json.RawMessage(`{"match_all":{}}`)
+	if idxCfg.Query != "" {
+		query = json.RawMessage(idxCfg.Query)
+	}
+	body := map[string]any{
+		"size":    req.K,
+		"_source": sourceFilter(req.Fields),
+		"query": map[string]any{
+			"script_score": map[string]any{
+				"query":  query,
+				"script": map[string]any{"source": source, "params": map[string]any{"query_vector": req.Vector}},
+			},
+		},
+	}
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+// sourceFilter : only the _id needed when no fields
+func sourceFilter(fields []string) any {
+	if len(fields) == 0 {
+		return false
+	}
+	return fields
+}
+
+func packPITResult(data string) (*SearchRes, error) {
+	if strings.HasPrefix(data, `{"error"`) {
+		log.Error("ES response error", zap.String("Response", data))
+		return nil, errors.New(data)
+	}
+	hits := gjson.Get(data, "hits.hits")
+	arr := hits.Array()
+	res := &SearchRes{
+		Hits:    hits,
+		IsEmpty: len(arr) <= 0,
+		PitId:   gjson.Get(data, "pit_id").String(),
+	}
+	if !res.IsEmpty {
+		res.SearchAfter = arr[len(arr)-1].Get("sort").Raw
+	}
+	return res, nil
+}
+
 func read(r io.Reader) string {
 	var b bytes.Buffer
 	b.ReadFrom(r)
 	return b.String()
 }
 func getFieldNames(idxCfg *estype.IdxCfg) []string {
-	fields := make([]string, 0, len(idxCfg.Fields))
+	fields := make([]string, 0, len(idxCfg.Fields)+len(idxCfg.DynamicFields))
 	for _, f := range idxCfg.Fields {
 		fields = append(fields, f.Name)
 	}
-	return fields
+	//unsupported type fields, will be stored in milvus dynamic field
+	return append(fields, idxCfg.DynamicFields...)
+}
+
+// getMappingProperties : mapping response like {"realIndex":{"mappings":{"properties":{...}}}},
+// index may be a pattern or an alias, the properties of all matched indices will be merged, the first index field wins
+func getMappingProperties(index string, data string) (gjson.Result, error) {
+	var sb strings.Builder
+	fieldSet := make(map[string]bool)
+	gjson.Parse(data).ForEach(func(_, idxMapping gjson.Result) bool {
+		idxMapping.Get("mappings.properties").ForEach(func(name, property gjson.Result) bool {
+			if fieldSet[name.String()] {
+				return true
+			}
+			fieldSet[name.String()] = true
+			if sb.Len() > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(name.Raw)
+			sb.WriteString(":")
+			sb.WriteString(property.Raw)
+			return true
+		})
+		return true
+	})
+	if len(fieldSet) == 0 {
+		return gjson.Result{}, errors.New("ES index mapping properties not found, Index:" + index)
+	}
+	return gjson.Parse("{" + sb.String() + "}"), nil
+}
+
+// getMappingIndices : the concrete index names of the mapping response
+func getMappingIndices(data string) []string {
+	indices := make([]string, 0)
+	gjson.Parse(data).ForEach(func(idxName, _ gjson.Result) bool {
+		indices = append(indices, idxName.String())
+		return true
+	})
+	sort.Strings(indices)
+	return indices
+}
+
+// toQueryBody : wrap the query dsl as search or count request body
+func toQueryBody(query string) string {
+	return `{"query":` + query + "}"
 }
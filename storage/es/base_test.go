@@ -0,0 +1,109 @@
+package es
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBuildPITSearchBody(t *testing.T) {
+	idxCfg := &estype.IdxCfg{Fields: []estype.FieldCfg{{Name: "id"}, {Name: "vec"}}}
+	body, err := buildPITSearchBody(idxCfg, &PITRequest{PitId: "p1", BatchSize: 100, SliceId: 1, SliceMax: 4,
+		SearchAfter: `[10]`}, toKeepAlive(5*time.Minute))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":100,"pit":{"id":"p1","keep_alive":"300s"},"sort":[{"_shard_doc":"asc"}],`+
+		`"_source":["id","vec"],"slice":{"id":1,"max":4},"search_after":[10]}`, body)
+
+	body, err = buildPITSearchBody(&estype.IdxCfg{}, &PITRequest{PitId: "p1", BatchSize: 100, SliceMax: 1}, "1m")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":100,"pit":{"id":"p1","keep_alive":"1m"},"sort":[{"_shard_doc":"asc"}]}`, body)
+}
+
+func TestPackPITResult(t *testing.T) {
+	res, err := packPITResult(`{"pit_id":"p2","hits":{"hits":[{"_id":"a","sort":[1]},{"_id":"b","sort":[5]}]}}`)
+	assert.NoError(t, err)
+	assert.False(t, res.IsEmpty)
+	assert.Equal(t, "p2", res.PitId)
+	assert.Equal(t, "[5]", res.SearchAfter)
+
+	res, err = packPITResult(`{"pit_id":"p2","hits":{"hits":[]}}`)
+	assert.NoError(t, err)
+	assert.True(t, res.IsEmpty)
+}
+
+func TestGetMappingProperties(t *testing.T) {
+	data := `{"logs-2":{"mappings":{"properties":{"id":{"type":"long"},"tags":{"type":"keyword"}}}},` +
+		`"logs-1":{"mappings":{"properties":{"id":{"type":"keyword"},"vec":{"type":"dense_vector","dims":8}}}}}`
+	properties, err := getMappingProperties("logs-*", data)
+	assert.NoError(t, err)
+	assert.Equal(t, "long", properties.Get("id.type").String())
+	assert.Equal(t, "keyword", properties.Get("tags.type").String())
+	assert.Equal(t, int64(8), properties.Get("vec.dims").Int())
+	assert.Equal(t, []string{"logs-1", "logs-2"}, getMappingIndices(data))
+
+	_, err = getMappingProperties("empty", `{"empty":{"mappings":{}}}`)
+	assert.Error(t, err)
+}
+
+func TestBuildKnnSearchBody(t *testing.T) {
+	idxCfg := &estype.IdxCfg{Query: `{"term":{"tag":"a"}}`}
+	req := &KnnRequest{Field: "vec", Vector: []float32{0.5, 1}, K: 5, MetricType: "IP", Fields: []string{"id"}}
+	body, err := buildKnnSearchBody(idxCfg, req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":5,"_source":["id"],"knn":{"field":"vec","query_vector":[0.5,1],"k":5,`+
+		`"num_candidates":100,"filter":{"term":{"tag":"a"}}}}`, body)
+
+	body, err = buildScriptScoreBody(&estype.IdxCfg{}, req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":5,"_source":["id"],"query":{"script_score":{"query":{"match_all":{}},"script":{`+
+		`"source":"double v = dotProduct(params.query_vector, \"vec\"); return v < 0 ? 1 / (1 - v) : 1 + v;",`+
+		`"params":{"query_vector":[0.5,1]}}}}}`, body)
+
+	body, err = buildRandomDocsBody(idxCfg, 3, []string{"vec"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":3,"_source":["vec"],"query":{"function_score":{"query":{"term":{"tag":"a"}},"random_score":{}}}}`, body)
+}
+
+func TestOpenSearchBody(t *testing.T) {
+	idxCfg := &estype.IdxCfg{InnerPkField: &estype.FieldCfg{Name: "doc_id", Type: "keyword", PK: true}}
+	body, err := buildPITBody(idxCfg, &PITRequest{PitId: "p1", BatchSize: 10}, "1m", pitSortFields(idxCfg)...)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":10,"pit":{"id":"p1","keep_alive":"1m"},"sort":[{"doc_id":"asc"},{"_id":"asc"}]}`, body)
+	assert.Equal(t, []string{"_id"}, pitSortFields(&estype.IdxCfg{}))
+
+	req := &KnnRequest{Field: "vec", Vector: []float32{0.5, 1}, K: 5, MetricType: "COSINE"}
+	body, err = buildOpenSearchKnnBody(&estype.IdxCfg{}, req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":5,"_source":false,"query":{"knn":{"vec":{"vector":[0.5,1],"k":5}}}}`, body)
+	body, err = buildKnnScoreBody(&estype.IdxCfg{}, req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"size":5,"_source":false,"query":{"script_score":{"query":{"match_all":{}},"script":{"source":"knn_score",`+
+		`"lang":"knn","params":{"field":"vec","query_value":[0.5,1],"space_type":"cosinesimil"}}}}}`, body)
+}
+
+// TestSigV4Sign : the get-vanilla and get-vanilla-query-order-key-case cases of aws sigv4 test suite
+func TestSigV4Sign(t *testing.T) {
+	signer := newSigV4Signer("us-east-1", "service", "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "")
+	signer.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	signer.Sign(req, nil)
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+
+	req, _ = http.NewRequest(http.MethodGet, "https://example.amazonaws.com/?Param2=value2&Param1=value1", nil)
+	signer.Sign(req, nil)
+	assert.Contains(t, req.Header.Get("Authorization"), "Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500")
+}
+
+func TestIsBeforeVer(t *testing.T) {
+	assert.True(t, isBeforeVer("7.10.2", 7, 12))
+	assert.True(t, isBeforeVer("7.11", 7, 12))
+	assert.True(t, isBeforeVer("6.8.0", 7, 12))
+	assert.False(t, isBeforeVer("7.12.0", 7, 12))
+	assert.False(t, isBeforeVer("8.1.0", 7, 12))
+	assert.True(t, isBeforeVer("", 7, 12))
+}
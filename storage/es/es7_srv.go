@@ -11,12 +11,14 @@ import (
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"go.uber.org/zap"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type ES7ServerClient struct {
-	_client *elasticsearch.Client
+	_client   *elasticsearch.Client
+	keepAlive time.Duration
 }
 
 func (es7 *ES7ServerClient) Close(scrollId string) error {
@@ -34,7 +36,8 @@ func NewES7ServerCli(esConfig *config.ESConfig) (ESServerClient, error) {
 		return nil, err
 	}
 	return &ES7ServerClient{
-		_client: es7Cli,
+		_client:   es7Cli,
+		keepAlive: esConfig.KeepAlive,
 	}, nil
 }
 
@@ -70,7 +73,10 @@ func (es7 *ES7ServerClient) InitScroll(idxCfg *estype.IdxCfg, batchSize int) (*S
 	var searchReqs []func(*esapi.SearchRequest)
 	searchReqs = append(searchReqs, es7._client.Search.WithIndex(idxCfg.Index),
 		es7._client.Search.WithSort("_doc"), es7._client.Search.WithSize(batchSize),
-		es7._client.Search.WithScroll(time.Minute))
+		es7._client.Search.WithScroll(es7.scrollKeepAlive()))
+	if idxCfg.Query != "" {
+		searchReqs = append(searchReqs, es7._client.Search.WithBody(strings.NewReader(toQueryBody(idxCfg.Query))))
+	}
 	filterFieldReq := es7.filterField(idxCfg)
 	if filterFieldReq != nil {
 		searchReqs = append(searchReqs, filterFieldReq)
@@ -91,7 +97,7 @@ func (es7 *ES7ServerClient) NextScroll(scrollID string) (*SearchRes, error) {
 	}
 
 	resp, err := es7._client.Scroll(es7._client.Scroll.WithScrollID(scrollID),
-		es7._client.Scroll.WithScroll(time.Minute))
+		es7._client.Scroll.WithScroll(es7.scrollKeepAlive()))
 
 	if common.DEBUG {
 		log.Info("[ES] 1 NextScroll data ======>", zap.Float64("Cost", time.Since(start).Seconds()))
@@ -114,7 +120,11 @@ func (es7 *ES7ServerClient) NextScroll(scrollID string) (*SearchRes, error) {
 }
 
 func (es7 *ES7ServerClient) Count(idxCfg *estype.IdxCfg) error {
-	resp, err := es7._client.Count(es7._client.Count.WithIndex(idxCfg.Index))
+	countReqs := []func(*esapi.CountRequest){es7._client.Count.WithIndex(idxCfg.Index)}
+	if idxCfg.Query != "" {
+		countReqs = append(countReqs, es7._client.Count.WithBody(strings.NewReader(toQueryBody(idxCfg.Query))))
+	}
+	resp, err := es7._client.Count(countReqs...)
 	if err != nil {
 		log.Error("Count ES Index Response Error",
 			zap.String("Index", idxCfg.Index), zap.Error(err))
@@ -136,6 +146,140 @@ func (es7 *ES7ServerClient) Count(idxCfg *estype.IdxCfg) error {
 	return nil
 }
 
+func (es7 *ES7ServerClient) GetMapping(index string) (gjson.Result, error) {
+	data, err := es7.getMappingData(index)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return getMappingProperties(index, data)
+}
+
+// ListIndices : the concrete indices of the index pattern or alias
+func (es7 *ES7ServerClient) ListIndices(index string) ([]string, error) {
+	data, err := es7.getMappingData(index)
+	if err != nil {
+		return nil, err
+	}
+	return getMappingIndices(data), nil
+}
+
+func (es7 *ES7ServerClient) getMappingData(index string) (string, error) {
+	resp, err := es7._client.Indices.GetMapping(es7._client.Indices.GetMapping.WithIndex(index))
+	if err != nil {
+		log.Error("Get ES Index Mapping Error", zap.String("Index", index), zap.Error(err))
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("Get ES Index Mapping Response Error", zap.Int("code", resp.StatusCode),
+			zap.String("Index", index), zap.String("error", resp.String()))
+		return "", errors.New(resp.String())
+	}
+	return read(resp.Body), nil
+}
+
+func (es7 *ES7ServerClient) scrollKeepAlive() time.Duration {
+	if es7.keepAlive <= 0 {
+		return time.Minute
+	}
+	return es7.keepAlive
+}
+
+// ServerVersion : the version number of the es server, like 7.10.2
+func (es7 *ES7ServerClient) ServerVersion() (string, error) {
+	resp, err := es7._client.Info()
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return "", errors.New(resp.String())
+	}
+	return gjson.Get(read(resp.Body), "version.number").String(), nil
+}
+
+func (es7 *ES7ServerClient) OpenPIT(index string) (string, error) {
+	resp, err := es7._client.OpenPointInTime([]string{index}, toKeepAlive(es7.keepAlive))
+	if err != nil {
+		log.Error("Open ES point in time Error", zap.String("Index", index), zap.Error(err))
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("Open ES point in time Response Error", zap.Int("code", resp.StatusCode),
+			zap.String("Index", index), zap.String("error", resp.String()))
+		return "", errors.New(resp.String())
+	}
+	pitId := gjson.Get(read(resp.Body), "id").String()
+	log.Info("open es point in time", zap.String("index", index), zap.String("pitId", pitId))
+	return pitId, nil
+}
+
+func (es7 *ES7ServerClient) SearchAfter(idxCfg *estype.IdxCfg, req *PITRequest) (*SearchRes, error) {
+	body, err := buildPITSearchBody(idxCfg, req, toKeepAlive(es7.keepAlive))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := es7._client.Search(es7._client.Search.WithBody(strings.NewReader(body)))
+	if err != nil {
+		log.Error("es pit search err", zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("es pit search Error response", zap.String("status", resp.Status()),
+			zap.Int("code", resp.StatusCode), zap.String("info", resp.String()))
+		return nil, errors.New(resp.String())
+	}
+	return packPITResult(read(resp.Body))
+}
+
+func (es7 *ES7ServerClient) ClosePIT(pitId string) error {
+	body := `{"id":` + strconv.Quote(pitId) + "}"
+	resp, err := es7._client.ClosePointInTime(es7._client.ClosePointInTime.WithBody(strings.NewReader(body)))
+	if err != nil {
+		log.Warn("close es point in time error", zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Warn("close es point in time error response", zap.String("info", resp.String()))
+		return errors.New(resp.String())
+	}
+	return nil
+}
+
+func (es7 *ES7ServerClient) RandomDocs(idxCfg *estype.IdxCfg, size int, fields []string) (*SearchRes, error) {
+	body, err := buildRandomDocsBody(idxCfg, size, fields)
+	if err != nil {
+		return nil, err
+	}
+	return es7.search(idxCfg.Index, body)
+}
+
+func (es7 *ES7ServerClient) KnnSearch(idxCfg *estype.IdxCfg, req *KnnRequest) (*SearchRes, error) {
+	body, err := buildScriptScoreBody(idxCfg, req)
+	if err != nil {
+		return nil, err
+	}
+	return es7.search(idxCfg.Index, body)
+}
+
+func (es7 *ES7ServerClient) search(index string, body string) (*SearchRes, error) {
+	resp, err := es7._client.Search(es7._client.Search.WithIndex(index), es7._client.Search.WithBody(strings.NewReader(body)))
+	if err != nil {
+		log.Error("es search err", zap.String("index", index), zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("es search Error response", zap.String("status", resp.Status()),
+			zap.Int("code", resp.StatusCode), zap.String("info", resp.String()))
+		return nil, errors.New(resp.String())
+	}
+	return es7.packResult(resp)
+}
+
 func (es7 *ES7ServerClient) filterField(idxCfg *estype.IdxCfg) func(*esapi.SearchRequest) {
 	if idxCfg.Fields == nil || len(idxCfg.Fields) <= 0 {
 		return nil
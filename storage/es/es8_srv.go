@@ -10,12 +10,14 @@ import (
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"go.uber.org/zap"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type ES8ServerClient struct {
-	_client *elasticsearch.Client
+	_client   *elasticsearch.Client
+	keepAlive time.Duration
 }
 
 func (es8 *ES8ServerClient) Close(scrollId string) error {
@@ -31,7 +33,8 @@ func NewES8ServerCli(esConfig *config.ESConfig) (ESServerClient, error) {
 		return nil, err
 	}
 	return &ES8ServerClient{
-		_client: es8Cli,
+		_client:   es8Cli,
+		keepAlive: esConfig.KeepAlive,
 	}, nil
 }
 
@@ -66,7 +69,10 @@ func (es8 *ES8ServerClient) InitScroll(idxCfg *estype.IdxCfg, batchSize int) (*S
 	var searchReqs []func(*esapi.SearchRequest)
 	searchReqs = append(searchReqs, es8._client.Search.WithIndex(idxCfg.Index),
 		es8._client.Search.WithSort("_doc"), es8._client.Search.WithSize(batchSize),
-		es8._client.Search.WithScroll(time.Minute))
+		es8._client.Search.WithScroll(es8.scrollKeepAlive()))
+	if idxCfg.Query != "" {
+		searchReqs = append(searchReqs, es8._client.Search.WithBody(strings.NewReader(toQueryBody(idxCfg.Query))))
+	}
 	filterFieldReq := es8.filterField(idxCfg)
 	if filterFieldReq != nil {
 		searchReqs = append(searchReqs, filterFieldReq)
@@ -81,7 +87,7 @@ func (es8 *ES8ServerClient) InitScroll(idxCfg *estype.IdxCfg, batchSize int) (*S
 
 func (es8 *ES8ServerClient) NextScroll(scrollID string) (*SearchRes, error) {
 	resp, err := es8._client.Scroll(es8._client.Scroll.WithScrollID(scrollID),
-		es8._client.Scroll.WithScroll(time.Minute))
+		es8._client.Scroll.WithScroll(es8.scrollKeepAlive()))
 	if err != nil {
 		log.Error("next es8 scroll Error", zap.Error(err))
 		return nil, err
@@ -94,7 +100,11 @@ func (es8 *ES8ServerClient) NextScroll(scrollID string) (*SearchRes, error) {
 }
 
 func (es7 *ES8ServerClient) Count(idxCfg *estype.IdxCfg) error {
-	resp, err := es7._client.Count(es7._client.Count.WithIndex(idxCfg.Index))
+	countReqs := []func(*esapi.CountRequest){es7._client.Count.WithIndex(idxCfg.Index)}
+	if idxCfg.Query != "" {
+		countReqs = append(countReqs, es7._client.Count.WithBody(strings.NewReader(toQueryBody(idxCfg.Query))))
+	}
+	resp, err := es7._client.Count(countReqs...)
 	if err != nil {
 		log.Error("Count ES Index Response Error",
 			zap.String("Index", idxCfg.Index), zap.Error(err))
@@ -116,6 +126,137 @@ func (es7 *ES8ServerClient) Count(idxCfg *estype.IdxCfg) error {
 	return nil
 }
 
+func (es8 *ES8ServerClient) GetMapping(index string) (gjson.Result, error) {
+	data, err := es8.getMappingData(index)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return getMappingProperties(index, data)
+}
+
+// ListIndices : the concrete indices of the index pattern or alias
+func (es8 *ES8ServerClient) ListIndices(index string) ([]string, error) {
+	data, err := es8.getMappingData(index)
+	if err != nil {
+		return nil, err
+	}
+	return getMappingIndices(data), nil
+}
+
+func (es8 *ES8ServerClient) getMappingData(index string) (string, error) {
+	resp, err := es8._client.Indices.GetMapping(es8._client.Indices.GetMapping.WithIndex(index))
+	if err != nil {
+		log.Error("Get ES Index Mapping Error", zap.String("Index", index), zap.Error(err))
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("Get ES Index Mapping Response Error", zap.Int("code", resp.StatusCode),
+			zap.String("Index", index), zap.String("error", resp.String()))
+		return "", errors.New(resp.String())
+	}
+	return read(resp.Body), nil
+}
+
+func (es8 *ES8ServerClient) scrollKeepAlive() time.Duration {
+	if es8.keepAlive <= 0 {
+		return time.Minute
+	}
+	return es8.keepAlive
+}
+
+func (es8 *ES8ServerClient) OpenPIT(index string) (string, error) {
+	resp, err := es8._client.OpenPointInTime([]string{index}, toKeepAlive(es8.keepAlive))
+	if err != nil {
+		log.Error("Open ES point in time Error", zap.String("Index", index), zap.Error(err))
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("Open ES point in time Response Error", zap.Int("code", resp.StatusCode),
+			zap.String("Index", index), zap.String("error", resp.String()))
+		return "", errors.New(resp.String())
+	}
+	pitId := gjson.Get(read(resp.Body), "id").String()
+	log.Info("open es point in time", zap.String("index", index), zap.String("pitId", pitId))
+	return pitId, nil
+}
+
+func (es8 *ES8ServerClient) SearchAfter(idxCfg *estype.IdxCfg, req *PITRequest) (*SearchRes, error) {
+	body, err := buildPITSearchBody(idxCfg, req, toKeepAlive(es8.keepAlive))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := es8._client.Search(es8._client.Search.WithBody(strings.NewReader(body)))
+	if err != nil {
+		log.Error("es pit search err", zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("es pit search Error response", zap.String("status", resp.Status()),
+			zap.Int("code", resp.StatusCode), zap.String("info", resp.String()))
+		return nil, errors.New(resp.String())
+	}
+	return packPITResult(read(resp.Body))
+}
+
+func (es8 *ES8ServerClient) ClosePIT(pitId string) error {
+	body := `{"id":` + strconv.Quote(pitId) + "}"
+	resp, err := es8._client.ClosePointInTime(es8._client.ClosePointInTime.WithBody(strings.NewReader(body)))
+	if err != nil {
+		log.Warn("close es point in time error", zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Warn("close es point in time error response", zap.String("info", resp.String()))
+		return errors.New(resp.String())
+	}
+	return nil
+}
+
+func (es8 *ES8ServerClient) RandomDocs(idxCfg *estype.IdxCfg, size int, fields []string) (*SearchRes, error) {
+	body, err := buildRandomDocsBody(idxCfg, size, fields)
+	if err != nil {
+		return nil, err
+	}
+	return es8.search(idxCfg.Index, body)
+}
+
+// KnnSearch : approximate knn search, the not indexed dense_vector field fallback to exact script_score search
+func (es8 *ES8ServerClient) KnnSearch(idxCfg *estype.IdxCfg, req *KnnRequest) (*SearchRes, error) {
+	body, err := buildKnnSearchBody(idxCfg, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := es8.search(idxCfg.Index, body)
+	if err == nil {
+		return res, nil
+	}
+	log.Warn("es knn search error, will use script_score search", zap.String("index", idxCfg.Index), zap.Error(err))
+	body, err = buildScriptScoreBody(idxCfg, req)
+	if err != nil {
+		return nil, err
+	}
+	return es8.search(idxCfg.Index, body)
+}
+
+func (es8 *ES8ServerClient) search(index string, body string) (*SearchRes, error) {
+	resp, err := es8._client.Search(es8._client.Search.WithIndex(index), es8._client.Search.WithBody(strings.NewReader(body)))
+	if err != nil {
+		log.Error("es search err", zap.String("index", index), zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		log.Error("es search Error response", zap.String("status", resp.Status()),
+			zap.Int("code", resp.StatusCode), zap.String("info", resp.String()))
+		return nil, errors.New(resp.String())
+	}
+	return es8.packResult(resp)
+}
+
 func (es8 *ES8ServerClient) filterField(idxCfg *estype.IdxCfg) func(*esapi.SearchRequest) {
 	if idxCfg.Fields == nil || len(idxCfg.Fields) <= 0 {
 		return nil
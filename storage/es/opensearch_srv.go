@@ -0,0 +1,337 @@
+package es
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/estype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// OpenSearchServerClient : the elasticsearch go clients refuse the not elasticsearch server,
+// opensearch use the rest api directly, support basic auth and aws sigv4 signing
+type OpenSearchServerClient struct {
+	urls      []string
+	httpCli   *http.Client
+	username  string
+	password  string
+	signer    *sigV4Signer
+	keepAlive time.Duration
+	next      atomic.Uint32
+}
+
+func NewOpenSearchServerCli(esConfig *config.ESConfig) (ESServerClient, error) {
+	if len(esConfig.Urls) == 0 {
+		return nil, errors.New("opensearch urls is empty")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if esConfig.Cert != "" {
+		cert, err := os.ReadFile(esConfig.Cert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cert) {
+			return nil, errors.New("opensearch cert file invalid: " + esConfig.Cert)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	cli := &OpenSearchServerClient{
+		urls:      esConfig.Urls,
+		httpCli:   &http.Client{Transport: transport},
+		username:  esConfig.Username,
+		password:  esConfig.Password,
+		keepAlive: esConfig.KeepAlive,
+	}
+	if esConfig.AwsRegion != "" {
+		cli.signer = newSigV4Signer(esConfig.AwsRegion, esConfig.AwsService, esConfig.AwsAccessKey,
+			esConfig.AwsSecretKey, esConfig.AwsSessionToken)
+	}
+	return cli, nil
+}
+
+func (ops *OpenSearchServerClient) InitScroll(idxCfg *estype.IdxCfg, batchSize int) (*SearchRes, error) {
+	err := ops.Count(idxCfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("start opensearch scrolling index", zap.String("index", idxCfg.Index), zap.Int("BatchSize", batchSize))
+	body := map[string]any{"sort": []string{"_doc"}}
+	if len(idxCfg.Fields) > 0 {
+		body["_source"] = getFieldNames(idxCfg)
+	}
+	if idxCfg.Query != "" {
+		body["query"] = json.RawMessage(idxCfg.Query)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{"scroll": {toKeepAlive(ops.keepAlive)}, "size": {strconv.Itoa(batchSize)}}
+	data, err := ops.do(http.MethodPost, indexPath(idxCfg.Index, "_search"), query, b)
+	if err != nil {
+		log.Error("init opensearch search err", zap.Error(err))
+		return nil, err
+	}
+	return packSearchResult(data)
+}
+
+func (ops *OpenSearchServerClient) NextScroll(scrollID string) (*SearchRes, error) {
+	b, _ := json.Marshal(map[string]string{"scroll": toKeepAlive(ops.keepAlive), "scroll_id": scrollID})
+	data, err := ops.do(http.MethodPost, "/_search/scroll", nil, b)
+	if err != nil {
+		log.Error("next opensearch scroll Error", zap.Error(err))
+		return nil, err
+	}
+	return packSearchResult(data)
+}
+
+func (ops *OpenSearchServerClient) Close(scrollId string) error {
+	if scrollId == "" {
+		return nil
+	}
+	b, _ := json.Marshal(map[string][]string{"scroll_id": {scrollId}})
+	_, err := ops.do(http.MethodDelete, "/_search/scroll", nil, b)
+	if err != nil {
+		log.Warn("clear opensearch scroll error", zap.Error(err))
+	}
+	return nil
+}
+
+func (ops *OpenSearchServerClient) Count(idxCfg *estype.IdxCfg) error {
+	var body []byte
+	if idxCfg.Query != "" {
+		body = []byte(toQueryBody(idxCfg.Query))
+	}
+	data, err := ops.do(http.MethodPost, indexPath(idxCfg.Index, "_count"), nil, body)
+	if err != nil {
+		log.Error("Count OpenSearch Index Response Error", zap.String("Index", idxCfg.Index), zap.Error(err))
+		return err
+	}
+	log.Info("[Count OpenSearch Info]", zap.String("Index", idxCfg.Index), zap.String("CountInfo", data))
+	count := gjson.Get(data, "count").Int()
+	if count <= 0 {
+		log.Warn("Count OpenSearch data is empty", zap.String("Index", idxCfg.Index))
+		return errors.New("Count OpenSearch data is empty, Index:" + idxCfg.Index)
+	}
+	idxCfg.Rows = count
+	return nil
+}
+
+func (ops *OpenSearchServerClient) GetMapping(index string) (gjson.Result, error) {
+	data, err := ops.do(http.MethodGet, indexPath(index, "_mapping"), nil, nil)
+	if err != nil {
+		log.Error("Get OpenSearch Index Mapping Error", zap.String("Index", index), zap.Error(err))
+		return gjson.Result{}, err
+	}
+	return getMappingProperties(index, data)
+}
+
+// ListIndices : the concrete indices of the index pattern or alias
+func (ops *OpenSearchServerClient) ListIndices(index string) ([]string, error) {
+	data, err := ops.do(http.MethodGet, indexPath(index, "_mapping"), nil, nil)
+	if err != nil {
+		log.Error("Get OpenSearch Index Mapping Error", zap.String("Index", index), zap.Error(err))
+		return nil, err
+	}
+	return getMappingIndices(data), nil
+}
+
+// OpenPIT : opensearch 2.4+ support point in time
+func (ops *OpenSearchServerClient) OpenPIT(index string) (string, error) {
+	query := url.Values{"keep_alive": {toKeepAlive(ops.keepAlive)}}
+	data, err := ops.do(http.MethodPost, indexPath(index, "_search/point_in_time"), query, nil)
+	if err != nil {
+		log.Error("Open OpenSearch point in time Error", zap.String("Index", index), zap.Error(err))
+		return "", err
+	}
+	pitId := gjson.Get(data, "pit_id").String()
+	log.Info("open opensearch point in time", zap.String("index", index), zap.String("pitId", pitId))
+	return pitId, nil
+}
+
+// SearchAfter : opensearch not have _shard_doc, sort by the pk field and _id for search_after
+func (ops *OpenSearchServerClient) SearchAfter(idxCfg *estype.IdxCfg, req *PITRequest) (*SearchRes, error) {
+	body, err := buildPITBody(idxCfg, req, toKeepAlive(ops.keepAlive), pitSortFields(idxCfg)...)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ops.do(http.MethodPost, "/_search", nil, []byte(body))
+	if err != nil {
+		log.Error("opensearch pit search err", zap.Error(err))
+		return nil, err
+	}
+	return packPITResult(data)
+}
+
+func (ops *OpenSearchServerClient) ClosePIT(pitId string) error {
+	b, _ := json.Marshal(map[string][]string{"pit_id": {pitId}})
+	_, err := ops.do(http.MethodDelete, "/_search/point_in_time", nil, b)
+	if err != nil {
+		log.Warn("close opensearch point in time error", zap.Error(err))
+	}
+	return err
+}
+
+func (ops *OpenSearchServerClient) RandomDocs(idxCfg *estype.IdxCfg, size int, fields []string) (*SearchRes, error) {
+	body, err := buildRandomDocsBody(idxCfg, size, fields)
+	if err != nil {
+		return nil, err
+	}
+	return ops.search(idxCfg.Index, body)
+}
+
+// KnnSearch : k-NN plugin approximate search, the field not indexed fallback to exact knn_score script search
+func (ops *OpenSearchServerClient) KnnSearch(idxCfg *estype.IdxCfg, req *KnnRequest) (*SearchRes, error) {
+	body, err := buildOpenSearchKnnBody(idxCfg, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := ops.search(idxCfg.Index, body)
+	if err == nil {
+		return res, nil
+	}
+	log.Warn("opensearch knn search error, will use knn_score search", zap.String("index", idxCfg.Index), zap.Error(err))
+	body, err = buildKnnScoreBody(idxCfg, req)
+	if err != nil {
+		return nil, err
+	}
+	return ops.search(idxCfg.Index, body)
+}
+
+func (ops *OpenSearchServerClient) search(index string, body string) (*SearchRes, error) {
+	data, err := ops.do(http.MethodPost, indexPath(index, "_search"), nil, []byte(body))
+	if err != nil {
+		log.Error("opensearch search err", zap.String("index", index), zap.Error(err))
+		return nil, err
+	}
+	return packSearchResult(data)
+}
+
+// do : send the request to the urls by turns, the next url will be tried when the connection failed
+func (ops *OpenSearchServerClient) do(method string, path string, query url.Values, body []byte) (string, error) {
+	var lastErr error
+	start := int(ops.next.Add(1))
+	for i := 0; i < len(ops.urls); i++ {
+		baseUrl := strings.TrimRight(ops.urls[(start+i)%len(ops.urls)], "/")
+		reqUrl := baseUrl + path
+		if len(query) > 0 {
+			reqUrl += "?" + query.Encode()
+		}
+		req, err := http.NewRequest(method, reqUrl, bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		if len(body) > 0 {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if ops.username != "" {
+			req.SetBasicAuth(ops.username, ops.password)
+		}
+		if ops.signer != nil {
+			ops.signer.Sign(req, body)
+		}
+		resp, err := ops.httpCli.Do(req)
+		if err != nil {
+			log.Warn("opensearch request error, will try next url", zap.String("url", baseUrl), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			log.Error("opensearch Error response", zap.String("method", method), zap.String("path", path),
+				zap.Int("code", resp.StatusCode), zap.String("info", string(data)))
+			return "", fmt.Errorf("opensearch %s %s response [%d] %s", method, path, resp.StatusCode, string(data))
+		}
+		return string(data), nil
+	}
+	return "", lastErr
+}
+
+func indexPath(index string, api string) string {
+	return "/" + url.PathEscape(index) + "/" + api
+}
+
+// pitSortFields : sort by the keyword or numeric pk field, always end with _id as tie-breaker,
+// the pk field value may be duplicated or missing in some docs, search_after would skip them
+func pitSortFields(idxCfg *estype.IdxCfg) []string {
+	pk := idxCfg.InnerPkField
+	if pk == nil || pk.Name == "_id" || pk.Type == "text" || pk.Type == "string" {
+		return []string{"_id"}
+	}
+	return []string{pk.Name, "_id"}
+}
+
+// buildOpenSearchKnnBody : k-NN plugin knn query, the filter work with the lucene and faiss engine
+func buildOpenSearchKnnBody(idxCfg *estype.IdxCfg, req *KnnRequest) (string, error) {
+	knn := map[string]any{"vector": req.Vector, "k": req.K}
+	if idxCfg.Query != "" {
+		knn["filter"] = json.RawMessage(idxCfg.Query)
+	}
+	body := map[string]any{
+		"size":    req.K,
+		"_source": sourceFilter(req.Fields),
+		"query":   map[string]any{"knn": map[string]any{req.Field: knn}},
+	}
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+// buildKnnScoreBody : exact brute-force search by the k-NN plugin knn_score script, the score is larger the closer
+func buildKnnScoreBody(idxCfg *estype.IdxCfg, req *KnnRequest) (string, error) {
+	spaceType := "l2"
+	switch strings.ToUpper(req.MetricType) {
+	case "IP":
+		spaceType = "innerproduct"
+	case "COSINE":
+		spaceType = "cosinesimil"
+	}
+	query := json.RawMessage(`{"match_all":{}}`)
+	if idxCfg.Query != "" {
+		query = json.RawMessage(idxCfg.Query)
+	}
+	body := map[string]any{
+		"size":    req.K,
+		"_source": sourceFilter(req.Fields),
+		"query": map[string]any{
+			"script_score": map[string]any{
+				"query": query,
+				"script": map[string]any{"source": "knn_score", "lang": "knn",
+					"params": map[string]any{"field": req.Field, "query_value": req.Vector, "space_type": spaceType}},
+			},
+		},
+	}
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+func packSearchResult(data string) (*SearchRes, error) {
+	hits := gjson.Get(data, "hits.hits")
+	isEmpty := len(hits.Array()) <= 0
+	if isEmpty {
+		log.Info("Finished opensearch scrolling")
+	}
+	return &SearchRes{
+		ScrollId: gjson.Get(data, "_scroll_id").String(),
+		Hits:     hits,
+		IsEmpty:  isEmpty,
+	}, nil
+}
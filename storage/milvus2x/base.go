@@ -2,28 +2,47 @@ package milvus2x
 
 import (
 	"context"
+	"fmt"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/zilliztech/milvus-migration/core/config"
 	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
 	"github.com/zilliztech/milvus-migration/internal/log"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const VER_2_3 = "2.3"
+const (
+	VER_2_2 = "2.2"
+	VER_2_3 = "2.3"
+)
 
 type Milvus2xVersClient interface {
 	Count(ctx context.Context, collCfg *milvus2xtype.CollectionCfg) (int64, error)
-	InitIterator(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, batchSize int, partition string, fieldNames []string) error
+	InitIterator(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, batchSize int, partition string, expr string, fieldNames []string) error
 	IterateNext(ctx context.Context) (*Milvus2xData, error)
 	Close() error
 	DescCollection(ctx context.Context, collectionName string) (*entity.Collection, error)
 	ShowPartitions(ctx context.Context, collectionName string) ([]*entity.Partition, error)
+	DescIndex(ctx context.Context, collectionName string, fieldName string) ([]entity.Index, error)
+	ListCollections(ctx context.Context) ([]string, error)
+	QueryPK(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partition string, expr string, limit int64) (entity.Column, error)
+	CountPartition(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partition string) (int64, error)
+	Query(ctx context.Context, collection string, partition string, expr string, outputFields []string) ([]entity.Column, error)
+	Search(ctx context.Context, collection string, expr string, vectorField string, metricType string, vectors [][]float32,
+		topK int, params map[string]any) ([][]any, error)
 }
 
 type Milvus2xData struct {
 	Columns   []entity.Column
 	IsEmpty   bool
 	Partition string
+	LastPK    any      //last pk of this batch data, int64 or string, used for checkpoint
+	PKRanges  []string //pk range parallel read: all ranges of the partition, used for checkpoint
+	PKRange   string   //pk range parallel read: the range of this batch data
 }
 
 type Milvus2xClient struct {
@@ -32,21 +51,69 @@ type Milvus2xClient struct {
 }
 
 // will create by factory
+// version: use meta.version config, if empty will get the source server version by GetVersion
 func CreateMilvus2xClient(mlv2xCfg *config.Milvus2xConfig) (*Milvus2xClient, error) {
-	milvus2xClient := Milvus2xClient{
-		Version: mlv2xCfg.Version,
-	}
-	var err error
-	switch mlv2xCfg.Version {
-	case VER_2_3:
-		milvus2xClient.VerCli, err = NewMilvus23VerCli(mlv2xCfg)
-	default:
-		log.Warn("milvus2x version not contain, will use default sdk version", zap.String("Version", mlv2xCfg.Version))
-		milvus2xClient.VerCli, err = NewMilvus23VerCli(mlv2xCfg)
-	}
+	verCli, err := _createMilvus23VerClient(mlv2xCfg)
 	if err != nil {
 		log.Error("create milvus2x Client error", zap.String("version", mlv2xCfg.Version), zap.Error(err))
 		return nil, err
 	}
+	version := mlv2xCfg.Version
+	if version == "" {
+		version, err = getServerVersion(verCli)
+		if err != nil {
+			verCli.Close()
+			return nil, fmt.Errorf("get milvus2x server version error, please set meta.version of the source milvus: %w", err)
+		}
+	}
+	milvus2xClient := Milvus2xClient{
+		Version: version,
+	}
+	if IsBeforeVer23(version) {
+		log.Info("milvus2x version not support QueryIterator, will use page query client", zap.String("Version", version))
+		milvus2xClient.VerCli = &Milvus22VerClient{Milvus23VerClient: verCli}
+	} else {
+		milvus2xClient.VerCli = verCli
+	}
 	return &milvus2xClient, nil
 }
+
+// getServerVersion : milvus server before 2.2 not implement GetVersion, treat it as old version
+func getServerVersion(verCli *Milvus23VerClient) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	version, err := verCli._milvus.GetVersion(ctx)
+	if err != nil {
+		if !isUnimplemented(err) {
+			return "", err
+		}
+		log.Warn("milvus2x server not support GetVersion, will use page query client", zap.Error(err))
+		return VER_2_2, nil
+	}
+	log.Info("get milvus2x server version", zap.String("Version", version))
+	return version, nil
+}
+
+func isUnimplemented(err error) bool {
+	if status.Code(err) == codes.Unimplemented {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unimplemented") || strings.Contains(msg, "unknown method")
+}
+
+// IsBeforeVer23 : version like: 2.2, 2.2.16, v2.3.0, v2.4.1-xxx
+func IsBeforeVer23(version string) bool {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		log.Warn("milvus2x version invalid, will use default sdk version", zap.String("Version", version))
+		return false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err1 != nil || err2 != nil {
+		log.Warn("milvus2x version invalid, will use default sdk version", zap.String("Version", version))
+		return false
+	}
+	return major < 2 || (major == 2 && minor < 3)
+}
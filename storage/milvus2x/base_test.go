@@ -0,0 +1,65 @@
+package milvus2x
+
+import (
+	"context"
+	"errors"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestIsBeforeVer23(t *testing.T) {
+	assert.True(t, IsBeforeVer23("2.2"))
+	assert.True(t, IsBeforeVer23("v2.2.16"))
+	assert.True(t, IsBeforeVer23("2.1.4"))
+	assert.False(t, IsBeforeVer23("2.3.0"))
+	assert.False(t, IsBeforeVer23("v2.4.1-dev"))
+	assert.False(t, IsBeforeVer23("3.0"))
+	assert.False(t, IsBeforeVer23("invalid"))
+}
+
+func TestPKOrder(t *testing.T) {
+	sorted, maxIdx, err := pkOrder(entity.NewColumnInt64("id", []int64{1, 3, 5}))
+	assert.NoError(t, err)
+	assert.True(t, sorted)
+	assert.Equal(t, 2, maxIdx)
+
+	sorted, maxIdx, err = pkOrder(entity.NewColumnVarChar("id", []string{"b", "c", "a"}))
+	assert.NoError(t, err)
+	assert.False(t, sorted)
+	assert.Equal(t, 1, maxIdx)
+
+	_, _, err = pkOrder(entity.NewColumnFloat("id", []float32{1}))
+	assert.Error(t, err)
+}
+
+type fakeVersionMilvus struct {
+	client.Client
+	version string
+	err     error
+}
+
+func (f *fakeVersionMilvus) GetVersion(_ context.Context) (string, error) {
+	return f.version, f.err
+}
+
+func TestGetServerVersion(t *testing.T) {
+	version, err := getServerVersion(&Milvus23VerClient{_milvus: &fakeVersionMilvus{version: "v2.3.4"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "v2.3.4", version)
+
+	version, err = getServerVersion(&Milvus23VerClient{_milvus: &fakeVersionMilvus{
+		err: status.Error(codes.Unimplemented, "unknown method GetVersion")}})
+	assert.NoError(t, err)
+	assert.Equal(t, VER_2_2, version)
+
+	_, err = getServerVersion(&Milvus23VerClient{_milvus: &fakeVersionMilvus{
+		err: status.Error(codes.Unavailable, "connection refused")}})
+	assert.Error(t, err)
+
+	_, err = getServerVersion(&Milvus23VerClient{_milvus: &fakeVersionMilvus{err: errors.New("access denied")}})
+	assert.Error(t, err)
+}
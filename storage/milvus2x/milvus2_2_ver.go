@@ -0,0 +1,315 @@
+package milvus2x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zilliztech/milvus-migration/core/common"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// query result window limit of milvus server default config: (offset+limit) <= 16384
+const maxQueryLimit = 16384
+
+// Milvus22VerClient : milvus 2.2 and below not support QueryIterator, and 2.0/2.1 not support query limit.
+// int64 pk: page the collection by bounded pk window query 'pk >= lo and pk <= hi' without limit,
+// query the window pks first, sort them in client and trim the page to batchSize, then query the page rows,
+// the window size adapt to the pk density.
+// varchar pk can't be split to windows, page by 'pk > cursor' query expr with limit, need milvus 2.2
+type Milvus22VerClient struct {
+	*Milvus23VerClient
+
+	collCfg      *milvus2xtype.CollectionCfg
+	partitions   []string
+	expr         string
+	outputFields []string
+	batchSize    int64
+	pkField      *entity.Field
+	cursor       string //varchar pk: last pk expr value of the last page, empty mean the first page
+	windowLo     int64  //int64 pk: inclusive lower bound of the next window
+	window       uint64 //int64 pk: pk count of the next window
+	windowEnd    bool   //int64 pk: the window reach the max int64
+}
+
+func (milvus22 *Milvus22VerClient) InitIterator(ctx context.Context, collCfg *milvus2xtype.CollectionCfg,
+	batchSize int, partition string, expr string, fieldNames []string) error {
+
+	log.Info("[Milvus22x] start page query milvus collection", zap.String("collection", collCfg.Collection),
+		zap.Int("BatchSize", batchSize), zap.String("CurrPartition", partition), zap.String("Expr", expr))
+
+	pkField, err := milvus22.getPKField(ctx, collCfg.Collection)
+	if err != nil {
+		return err
+	}
+	milvus22.partitions = nil
+	if partition != common.EMPTY {
+		milvus22.partitions = []string{partition}
+	}
+	milvus22.collCfg = collCfg
+	milvus22.expr = expr
+	milvus22.pkField = pkField
+	milvus22.outputFields = withPKField(fieldNames, pkField.Name)
+	milvus22.batchSize = int64(batchSize)
+	if milvus22.batchSize <= 0 || milvus22.batchSize > maxQueryLimit {
+		milvus22.batchSize = maxQueryLimit
+	}
+	milvus22.cursor = ""
+	milvus22.windowLo = math.MinInt64
+	milvus22.window = uint64(milvus22.batchSize)
+	milvus22.windowEnd = false
+	return nil
+}
+
+func (milvus22 *Milvus22VerClient) IterateNext(ctx context.Context) (*Milvus2xData, error) {
+	if milvus22.pkField == nil {
+		return nil, errors.New("[Milvus22x] iterator not init")
+	}
+	if milvus22.pkField.DataType == entity.FieldTypeInt64 {
+		return milvus22.nextWindowPage(ctx)
+	}
+	return milvus22.nextLimitPage(ctx)
+}
+
+// nextWindowPage : empty window grow the next window, a window more than batchSize rows trimmed to batchSize rows,
+// and the next window shrink to the trimmed size
+func (milvus22 *Milvus22VerClient) nextWindowPage(ctx context.Context) (*Milvus2xData, error) {
+	for !milvus22.windowEnd {
+		lo := milvus22.windowLo
+		hi := windowHi(lo, milvus22.window)
+		pks, err := milvus22.queryWindowPKs(ctx, lo, hi)
+		if err != nil {
+			//pks of the window exceed the grpc message size, retry by a smaller window
+			if milvus22.window > 1 && isResourceExhausted(err) {
+				log.Warn("[Milvus22x] window pks too large, shrink the window", zap.Int64("lo", lo),
+					zap.Uint64("window", milvus22.window), zap.Error(err))
+				milvus22.window /= 2
+				continue
+			}
+			return nil, err
+		}
+		if len(pks) == 0 {
+			milvus22.moveWindow(hi)
+			milvus22.window = growWindow(milvus22.window)
+			continue
+		}
+		slices.Sort(pks)
+		upper := hi
+		if int64(len(pks)) > milvus22.batchSize {
+			upper = pks[milvus22.batchSize-1]
+			milvus22.window = uint64(upper) - uint64(lo) + 1
+		} else if int64(len(pks)) < milvus22.batchSize/2 {
+			milvus22.window = growWindow(milvus22.window)
+		}
+		rs, err := milvus22._milvus.Query(ctx, milvus22.collCfg.Collection, milvus22.partitions,
+			milvus22.windowExpr(lo, upper), milvus22.outputFields)
+		if err != nil {
+			return nil, err
+		}
+		milvus22.moveWindow(upper)
+		pkCol := rs.GetColumn(milvus22.pkField.Name)
+		//rows deleted after the window pks queried
+		if pkCol == nil || pkCol.Len() == 0 {
+			continue
+		}
+		return &Milvus2xData{Columns: toMilvus2xColumns(rs), IsEmpty: false}, nil
+	}
+	log.Info("[Milvus22x] milvus no data, window page query reach end")
+	return &Milvus2xData{IsEmpty: true}, nil
+}
+
+func (milvus22 *Milvus22VerClient) queryWindowPKs(ctx context.Context, lo int64, hi int64) ([]int64, error) {
+	rs, err := milvus22._milvus.Query(ctx, milvus22.collCfg.Collection, milvus22.partitions,
+		milvus22.windowExpr(lo, hi), []string{milvus22.pkField.Name})
+	if err != nil {
+		return nil, err
+	}
+	pkCol, ok := rs.GetColumn(milvus22.pkField.Name).(*entity.ColumnInt64)
+	if !ok {
+		return nil, nil
+	}
+	return pkCol.Data(), nil
+}
+
+func (milvus22 *Milvus22VerClient) windowExpr(lo int64, hi int64) string {
+	windowExpr := fmt.Sprintf("%s >= %d and %s <= %d", milvus22.pkField.Name, lo, milvus22.pkField.Name, hi)
+	if milvus22.expr == "" {
+		return windowExpr
+	}
+	return "(" + milvus22.expr + ") and " + windowExpr
+}
+
+func (milvus22 *Milvus22VerClient) moveWindow(hi int64) {
+	if hi == math.MaxInt64 {
+		milvus22.windowEnd = true
+		return
+	}
+	milvus22.windowLo = hi + 1
+}
+
+// windowHi : inclusive upper bound of the window, not overflow max int64
+func windowHi(lo int64, window uint64) int64 {
+	if window-1 >= uint64(math.MaxInt64)-uint64(lo) {
+		return math.MaxInt64
+	}
+	return lo + int64(window-1)
+}
+
+func growWindow(window uint64) uint64 {
+	if window >= 1<<63 {
+		return math.MaxUint64
+	}
+	return window * 2
+}
+
+func isResourceExhausted(err error) bool {
+	return status.Code(err) == codes.ResourceExhausted || strings.Contains(err.Error(), "larger than max")
+}
+
+// nextLimitPage : varchar pk page query with limit, a full page must be ordered by pk,
+// otherwise the rows between the cursor and the page max pk may not be returned
+func (milvus22 *Milvus22VerClient) nextLimitPage(ctx context.Context) (*Milvus2xData, error) {
+	rs, err := milvus22._milvus.Query(ctx, milvus22.collCfg.Collection, milvus22.partitions, milvus22.pageExpr(),
+		milvus22.outputFields, client.WithLimit(milvus22.batchSize))
+	if err != nil {
+		return nil, fmt.Errorf("[Milvus22x] varchar pk page query with limit fail, milvus server may not support query limit: %w", err)
+	}
+	pkCol := rs.GetColumn(milvus22.pkField.Name)
+	if pkCol == nil || pkCol.Len() == 0 {
+		log.Info("[Milvus22x] milvus no data, page query reach end")
+		return &Milvus2xData{IsEmpty: true}, nil
+	}
+	if int64(pkCol.Len()) > milvus22.batchSize {
+		return nil, fmt.Errorf("[Milvus22x] page query return %d rows more than limit %d, milvus server not support query limit, "+
+			"varchar pk collection need milvus server 2.2+", pkCol.Len(), milvus22.batchSize)
+	}
+	sorted, maxIdx, err := pkOrder(pkCol)
+	if err != nil {
+		return nil, err
+	}
+	//a page less than limit is the last page, it contains all the rest rows in any order
+	if !sorted && int64(pkCol.Len()) == milvus22.batchSize {
+		return nil, fmt.Errorf("[Milvus22x] page query result not ordered by pk, rows after cursor %s may be skipped, "+
+			"please use milvus server 2.3+ QueryIterator", milvus22.cursor)
+	}
+	cursor, err := toPKExprValue(pkCol, maxIdx)
+	if err != nil {
+		return nil, err
+	}
+	milvus22.cursor = cursor
+	return &Milvus2xData{Columns: toMilvus2xColumns(rs), IsEmpty: false}, nil
+}
+
+func (milvus22 *Milvus22VerClient) pageExpr() string {
+	if milvus22.cursor == "" {
+		return milvus22.expr
+	}
+	cursorExpr := fmt.Sprintf("%s > %s", milvus22.pkField.Name, milvus22.cursor)
+	if milvus22.expr == "" {
+		return cursorExpr
+	}
+	return "(" + milvus22.expr + ") and " + cursorExpr
+}
+
+// Count : count(*) is supported since milvus 2.2.x later version, if not support will page query pk to count
+func (milvus22 *Milvus22VerClient) Count(ctx context.Context, collCfg *milvus2xtype.CollectionCfg) (int64, error) {
+	return milvus22.CountPartition(ctx, collCfg, common.EMPTY)
+}
+
+func (milvus22 *Milvus22VerClient) CountPartition(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partition string) (int64, error) {
+	row, err := milvus22.Milvus23VerClient.CountPartition(ctx, collCfg, partition)
+	if err == nil {
+		return row, nil
+	}
+	log.Warn("[Milvus22x] count(*) not support, will page query pk to count", zap.String("collection", collCfg.Collection),
+		zap.Error(err))
+
+	//only query pk field
+	err = milvus22.InitIterator(ctx, collCfg, maxQueryLimit, partition, collCfg.Filter, nil)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for {
+		data, err := milvus22.IterateNext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if data.IsEmpty {
+			break
+		}
+		count += int64(data.Columns[0].Len())
+	}
+	log.Info("[Milvus22x] Count by page query ===>", zap.String("collection", collCfg.Collection),
+		zap.String("partition", partition), zap.String("filter", collCfg.Filter), zap.Int64("row", count))
+	return count, nil
+}
+
+func (milvus22 *Milvus22VerClient) getPKField(ctx context.Context, collection string) (*entity.Field, error) {
+	collEntity, err := milvus22.DescCollection(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range collEntity.Schema.Fields {
+		if field.PrimaryKey {
+			return field, nil
+		}
+	}
+	return nil, errors.New("[Milvus22x] not found primary key field, collection: " + collection)
+}
+
+func withPKField(fieldNames []string, pkName string) []string {
+	for _, name := range fieldNames {
+		if name == pkName {
+			return fieldNames
+		}
+	}
+	return append(append(make([]string, 0, len(fieldNames)+1), fieldNames...), pkName)
+}
+
+func toPKExprValue(pkCol entity.Column, idx int) (string, error) {
+	switch col := pkCol.(type) {
+	case *entity.ColumnInt64:
+		return strconv.FormatInt(col.Data()[idx], 10), nil
+	case *entity.ColumnVarChar:
+		return strconv.Quote(col.Data()[idx]), nil
+	default:
+		return "", fmt.Errorf("[Milvus22x] not support pk type %T", pkCol)
+	}
+}
+
+// pkOrder : whether the pk column is in ascending order, and the index of the max pk
+func pkOrder(pkCol entity.Column) (bool, int, error) {
+	switch col := pkCol.(type) {
+	case *entity.ColumnInt64:
+		sorted, maxIdx := sliceOrder(col.Data())
+		return sorted, maxIdx, nil
+	case *entity.ColumnVarChar:
+		sorted, maxIdx := sliceOrder(col.Data())
+		return sorted, maxIdx, nil
+	default:
+		return false, 0, fmt.Errorf("[Milvus22x] not support pk type %T", pkCol)
+	}
+}
+
+func sliceOrder[T int64 | string](data []T) (bool, int) {
+	sorted, maxIdx := true, 0
+	for i := 1; i < len(data); i++ {
+		if data[i] <= data[i-1] {
+			sorted = false
+		}
+		if data[i] > data[maxIdx] {
+			maxIdx = i
+		}
+	}
+	return sorted, maxIdx
+}
@@ -0,0 +1,118 @@
+package milvus2x
+
+import (
+	"context"
+	"errors"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/zilliztech/milvus-migration/core/type/milvus2xtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+var windowExprReg = regexp.MustCompile(`id >= (-?\d+) and id <= (-?\d+)`)
+
+// fakeMilvus20 : milvus 2.0 server ignore query limit and not support count(*), return the window rows unordered
+type fakeMilvus20 struct {
+	client.Client
+	pks      []int64
+	maxRows  int
+	queryCnt int
+}
+
+func (f *fakeMilvus20) DescribeCollection(_ context.Context, collName string) (*entity.Collection, error) {
+	schema := entity.NewSchema().WithName(collName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("num").WithDataType(entity.FieldTypeInt64))
+	return &entity.Collection{Name: collName, Schema: schema}, nil
+}
+
+func (f *fakeMilvus20) Query(_ context.Context, _ string, _ []string, expr string, outputFields []string, _ ...client.SearchQueryOptionFunc) (client.ResultSet, error) {
+	f.queryCnt++
+	if slices.Contains(outputFields, "count(*)") {
+		return nil, errors.New("count(*) not support")
+	}
+	match := windowExprReg.FindStringSubmatch(expr)
+	if match == nil {
+		return nil, errors.New("window expr not found: " + expr)
+	}
+	lo, _ := strconv.ParseInt(match[1], 10, 64)
+	hi, _ := strconv.ParseInt(match[2], 10, 64)
+	var ids, nums []int64
+	for i := len(f.pks) - 1; i >= 0; i-- {
+		if f.pks[i] >= lo && f.pks[i] <= hi {
+			ids = append(ids, f.pks[i])
+			nums = append(nums, f.pks[i]*10)
+		}
+	}
+	if f.maxRows > 0 && len(ids) > f.maxRows {
+		return nil, status.Error(codes.ResourceExhausted, "grpc: received message larger than max")
+	}
+	rs := client.ResultSet{entity.NewColumnInt64("id", ids)}
+	if slices.Contains(outputFields, "num") {
+		rs = append(rs, entity.NewColumnInt64("num", nums))
+	}
+	return rs, nil
+}
+
+func iterateAllPKs(t *testing.T, cli *Milvus22VerClient, batchSize int) []int64 {
+	collCfg := &milvus2xtype.CollectionCfg{Collection: "coll"}
+	err := cli.InitIterator(context.Background(), collCfg, batchSize, "", "", []string{"num"})
+	assert.NoError(t, err)
+	var pks []int64
+	for {
+		data, err := cli.IterateNext(context.Background())
+		assert.NoError(t, err)
+		if data.IsEmpty {
+			break
+		}
+		for _, col := range data.Columns {
+			if col.Name() == "id" {
+				assert.LessOrEqual(t, col.Len(), batchSize)
+				pks = append(pks, col.(*entity.ColumnInt64).Data()...)
+			}
+		}
+	}
+	slices.Sort(pks)
+	return pks
+}
+
+func TestWindowPageWithoutQueryLimit(t *testing.T) {
+	var pks []int64
+	for i := int64(0); i < 100; i++ {
+		pks = append(pks, i*7-300)
+	}
+	pks = append(pks, math.MinInt64, math.MaxInt64, 1<<40)
+	slices.Sort(pks)
+
+	fake := &fakeMilvus20{pks: pks}
+	cli := &Milvus22VerClient{Milvus23VerClient: &Milvus23VerClient{_milvus: fake}}
+	assert.Equal(t, pks, iterateAllPKs(t, cli, 10))
+
+	count, err := cli.CountPartition(context.Background(), &milvus2xtype.CollectionCfg{Collection: "coll"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(pks)), count)
+}
+
+func TestWindowPageShrinkOnResourceExhausted(t *testing.T) {
+	var pks []int64
+	for i := int64(0); i < 50; i++ {
+		pks = append(pks, i)
+	}
+	fake := &fakeMilvus20{pks: pks, maxRows: 8}
+	cli := &Milvus22VerClient{Milvus23VerClient: &Milvus23VerClient{_milvus: fake}}
+	assert.Equal(t, pks, iterateAllPKs(t, cli, 20))
+}
+
+func TestWindowHi(t *testing.T) {
+	assert.Equal(t, int64(9), windowHi(0, 10))
+	assert.Equal(t, int64(math.MaxInt64), windowHi(math.MaxInt64-3, 10))
+	assert.Equal(t, int64(math.MaxInt64-1), windowHi(math.MinInt64, math.MaxUint64))
+	assert.Equal(t, int64(math.MinInt64), windowHi(math.MinInt64, 1))
+}
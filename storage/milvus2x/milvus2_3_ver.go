@@ -35,15 +35,15 @@ func NewMilvus23VerCli(Milvus2xConfig *config.Milvus2xConfig) (Milvus2xVersClien
 }
 
 func (milvus23 *Milvus23VerClient) InitIterator(ctx context.Context, collCfg *milvus2xtype.CollectionCfg,
-	batchSize int, partition string, fieldNames []string) error {
+	batchSize int, partition string, expr string, fieldNames []string) error {
 
 	log.Info("start iterator milvus collection", zap.String("collection", collCfg.Collection),
-		zap.Int("BatchSize", batchSize), zap.String("CurrPartition", partition))
+		zap.Int("BatchSize", batchSize), zap.String("CurrPartition", partition), zap.String("Expr", expr))
 	var iteratorParam *client.QueryIteratorOption
 	if partition != common.EMPTY {
-		iteratorParam = client.NewQueryIteratorOption(collCfg.Collection).WithBatchSize(batchSize).WithExpr(common.EMPTY).WithPartitions(partition).WithOutputFields(fieldNames...)
+		iteratorParam = client.NewQueryIteratorOption(collCfg.Collection).WithBatchSize(batchSize).WithExpr(expr).WithPartitions(partition).WithOutputFields(fieldNames...)
 	} else {
-		iteratorParam = client.NewQueryIteratorOption(collCfg.Collection).WithBatchSize(batchSize).WithExpr(common.EMPTY).WithOutputFields(fieldNames...)
+		iteratorParam = client.NewQueryIteratorOption(collCfg.Collection).WithBatchSize(batchSize).WithExpr(expr).WithOutputFields(fieldNames...)
 	}
 	//iteratorParam := client.NewQueryIteratorOption(collCfg.Collection).WithBatchSize(batchSize).WithExpr(common.EMPTY).WithOutputFields("*")
 	iterator, err := milvus23._milvus.QueryIterator(ctx, iteratorParam)
@@ -68,6 +68,15 @@ func (milvus23 *Milvus23VerClient) IterateNext(ctx context.Context) (*Milvus2xDa
 		}
 		return nil, err
 	}
+	columns := toMilvus2xColumns(rs)
+	if common.DEBUG {
+		log.Info("[Milvus2x] iterateNext data ======>", zap.Float64("Cost", time.Since(start).Seconds()))
+	}
+	return &Milvus2xData{Columns: columns, IsEmpty: false}, nil
+}
+
+// toMilvus2xColumns : source $meta column convert to dynamic column
+func toMilvus2xColumns(rs []entity.Column) []entity.Column {
 	columns := make([]entity.Column, 0, len(rs))
 	for _, col := range rs {
 		if col.Name() == common.MILVUS_META_FD {
@@ -85,21 +94,11 @@ func (milvus23 *Milvus23VerClient) IterateNext(ctx context.Context) (*Milvus2xDa
 			log.Info("[Milvus2x] iterateNext data ======>", zap.String("colName", col.Name()), zap.Any("FieldDataVal", col.FieldData().String()))
 		}
 	}
-	if common.DEBUG {
-		log.Info("[Milvus2x] iterateNext data ======>", zap.Float64("Cost", time.Since(start).Seconds()))
-	}
-	return &Milvus2xData{Columns: columns, IsEmpty: false}, nil
+	return columns
 }
 
 func (milvus23 *Milvus23VerClient) Count(ctx context.Context, collCfg *milvus2xtype.CollectionCfg) (int64, error) {
-
-	rs, err := milvus23._milvus.Query(ctx, collCfg.Collection, nil, "", []string{"count(*)"})
-	if err != nil {
-		return 0, err
-	}
-	row := rs.GetColumn("count(*)").(*entity.ColumnInt64).Data()[0]
-	log.Info("[Milvus23x] Count(*) ===>", zap.String("collection", collCfg.Collection), zap.Any("row", row))
-	return row, nil
+	return milvus23.CountPartition(ctx, collCfg, common.EMPTY)
 
 	//下面方式不准：没考虑删除和growing
 	//stats, err := milvus23._milvus.GetCollectionStatistics(ctx, collCfg.Collection)
@@ -112,6 +111,111 @@ func (milvus23 *Milvus23VerClient) Count(ctx context.Context, collCfg *milvus2xt
 	//return strconv.ParseInt(count, 10, 64)
 }
 
+// CountPartition : count(*) of the partition by collCfg.Filter, empty partition means the whole collection
+func (milvus23 *Milvus23VerClient) CountPartition(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partition string) (int64, error) {
+	var partitions []string
+	if partition != common.EMPTY {
+		partitions = []string{partition}
+	}
+	rs, err := milvus23._milvus.Query(ctx, collCfg.Collection, partitions, collCfg.Filter, []string{"count(*)"})
+	if err != nil {
+		return 0, err
+	}
+	row := rs.GetColumn("count(*)").(*entity.ColumnInt64).Data()[0]
+	log.Info("[Milvus23x] Count(*) ===>", zap.String("collection", collCfg.Collection), zap.String("partition", partition),
+		zap.String("filter", collCfg.Filter), zap.Any("row", row))
+	return row, nil
+}
+
+// Query : query the output fields by expr, $meta column convert to dynamic column
+func (milvus23 *Milvus23VerClient) Query(ctx context.Context, collection string, partition string, expr string,
+	outputFields []string) ([]entity.Column, error) {
+	var partitions []string
+	if partition != common.EMPTY {
+		partitions = []string{partition}
+	}
+	rs, err := milvus23._milvus.Query(ctx, collection, partitions, expr, outputFields)
+	if err != nil {
+		return nil, err
+	}
+	return toMilvus2xColumns(rs), nil
+}
+
+// Search : top-k float vector search, return the result pks of each query vector in rank order,
+// empty metricType will use the metric type of the field index
+func (milvus23 *Milvus23VerClient) Search(ctx context.Context, collection string, expr string, vectorField string,
+	metricType string, vectors [][]float32, topK int, params map[string]any) ([][]any, error) {
+	queries := make([]entity.Vector, 0, len(vectors))
+	for _, vector := range vectors {
+		queries = append(queries, entity.FloatVector(vector))
+	}
+	rs, err := milvus23._milvus.Search(ctx, collection, nil, expr, nil, queries, vectorField,
+		entity.MetricType(metricType), topK, newSearchParam(params))
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]any, 0, len(rs))
+	for _, r := range rs {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		pks := make([]any, 0, r.ResultCount)
+		for i := 0; i < r.ResultCount; i++ {
+			pk, err := r.IDs.Get(i)
+			if err != nil {
+				return nil, err
+			}
+			pks = append(pks, pk)
+		}
+		results = append(results, pks)
+	}
+	return results, nil
+}
+
+// searchParam : index search params from config, eg: {"ef": 64} or {"nprobe": 16}
+type searchParam struct {
+	params map[string]any
+}
+
+func newSearchParam(params map[string]any) *searchParam {
+	sp := &searchParam{params: make(map[string]any, len(params))}
+	for k, v := range params {
+		sp.params[k] = v
+	}
+	return sp
+}
+
+func (sp *searchParam) Params() map[string]any {
+	return sp.params
+}
+
+func (sp *searchParam) AddRadius(radius float64) {
+	sp.params["radius"] = radius
+}
+
+func (sp *searchParam) AddRangeFilter(rangeFilter float64) {
+	sp.params["range_filter"] = rangeFilter
+}
+
+func (sp *searchParam) AddPageRetainOrder(pageRetainOrder bool) {
+	sp.params["page_retain_order"] = pageRetainOrder
+}
+
+// QueryPK : query the pk column order by pk, used for split pk ranges
+func (milvus23 *Milvus23VerClient) QueryPK(ctx context.Context, collCfg *milvus2xtype.CollectionCfg, partition string,
+	expr string, limit int64) (entity.Column, error) {
+	var partitions []string
+	if partition != common.EMPTY {
+		partitions = []string{partition}
+	}
+	pkName := collCfg.MilvusCfg.PkName
+	rs, err := milvus23._milvus.Query(ctx, collCfg.Collection, partitions, expr, []string{pkName}, client.WithLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return rs.GetColumn(pkName), nil
+}
+
 func (milvus23 *Milvus23VerClient) DescCollection(ctx context.Context, collectionName string) (*entity.Collection, error) {
 	collEntity, err := milvus23._milvus.DescribeCollection(ctx, collectionName)
 	if err != nil {
@@ -128,6 +232,26 @@ func (milvus23 *Milvus23VerClient) ShowPartitions(ctx context.Context, collectio
 	return partition, nil
 }
 
+func (milvus23 *Milvus23VerClient) DescIndex(ctx context.Context, collectionName string, fieldName string) ([]entity.Index, error) {
+	indexes, err := milvus23._milvus.DescribeIndex(ctx, collectionName, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+func (milvus23 *Milvus23VerClient) ListCollections(ctx context.Context) ([]string, error) {
+	collEntities, err := milvus23._milvus.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	collNames := make([]string, 0, len(collEntities))
+	for _, collEntity := range collEntities {
+		collNames = append(collNames, collEntity.Name)
+	}
+	return collNames, nil
+}
+
 // 这里统一给source创建milvus client, 和target区分开
 func _createMilvus23VerClient(cfg *config.Milvus2xConfig) (*Milvus23VerClient, error) {
 
@@ -0,0 +1,238 @@
+package pgvector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/zilliztech/milvus-migration/core/config"
+	pgvectorconvert "github.com/zilliztech/milvus-migration/core/transform/pgvector/convert"
+	"github.com/zilliztech/milvus-migration/core/type/pgvectortype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"strings"
+)
+
+const columnsSql = `SELECT a.attname, t.typname, COALESCE(et.typname, ''), a.atttypmod
+FROM pg_attribute a
+JOIN pg_type t ON a.atttypid = t.oid
+LEFT JOIN pg_type et ON t.typcategory = 'A' AND t.typelem = et.oid
+WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+const primaryKeySql = `SELECT a.attname
+FROM pg_index i
+JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+WHERE i.indrelid = $1::regclass AND i.indisprimary`
+
+type Client struct {
+	db *sql.DB
+}
+
+func NewClient(cfg *config.PgvectorConfig) (*Client, error) {
+	db, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		log.Error("[Pgvector] connect postgres error", zap.Error(err))
+		return nil, err
+	}
+	return &Client{db: db}, nil
+}
+
+func (cli *Client) Close() error {
+	return cli.db.Close()
+}
+
+// DescribeTable : read the migrate columns into tableCfg.InnerColumns, the keyset pagination pk column is always the first one
+func (cli *Client) DescribeTable(ctx context.Context, tableCfg *pgvectortype.TableCfg) error {
+	table := QuoteTable(tableCfg)
+	columns, names, err := cli.listColumns(ctx, table)
+	if err != nil {
+		return fmt.Errorf("describe table %s: %w", tableCfg.Table, err)
+	}
+	if tableCfg.PK == "" {
+		tableCfg.PK, err = cli.primaryKey(ctx, table, tableCfg.Table)
+		if err != nil {
+			return err
+		}
+	}
+	pkCol, ok := columns[tableCfg.PK]
+	if !ok {
+		return fmt.Errorf("table %s pk column %s not exist", tableCfg.Table, tableCfg.PK)
+	}
+	pkCol.PK = true
+	tableCfg.InnerColumns = []*pgvectortype.Column{pkCol}
+
+	if len(tableCfg.Fields) > 0 {
+		names = names[:0]
+		for _, field := range tableCfg.Fields {
+			col, ok := columns[field.Name]
+			if !ok {
+				return fmt.Errorf("table %s column %s not exist", tableCfg.Table, field.Name)
+			}
+			if !pgvectorconvert.IsSupported(col) {
+				return fmt.Errorf("table %s column %s type %s not support", tableCfg.Table, col.Name, col.TypeName)
+			}
+			names = append(names, field.Name)
+		}
+	}
+	for _, name := range names {
+		col := columns[name]
+		if col.PK {
+			continue
+		}
+		if !pgvectorconvert.IsSupported(col) {
+			log.Warn("[Pgvector] skip not support type column", zap.String("table", tableCfg.Table),
+				zap.String("column", col.Name), zap.String("type", col.TypeName))
+			continue
+		}
+		if pgvectorconvert.IsDimType(col.TypeName) && col.TypeMod <= 0 {
+			col.TypeMod, err = cli.probeDim(ctx, table, col)
+			if err != nil {
+				return fmt.Errorf("table %s column %s: %w", tableCfg.Table, col.Name, err)
+			}
+		}
+		tableCfg.InnerColumns = append(tableCfg.InnerColumns, col)
+	}
+	log.LL(ctx).Info("[Pgvector] describe table", zap.String("table", tableCfg.Table), zap.String("pk", tableCfg.PK),
+		zap.Int("columns", len(tableCfg.InnerColumns)))
+	return nil
+}
+
+// listColumns : columns of the table and the column names in table order
+func (cli *Client) listColumns(ctx context.Context, table string) (map[string]*pgvectortype.Column, []string, error) {
+	rows, err := cli.db.QueryContext(ctx, columnsSql, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	columns := make(map[string]*pgvectortype.Column)
+	var names []string
+	for rows.Next() {
+		col := &pgvectortype.Column{}
+		err = rows.Scan(&col.Name, &col.TypeName, &col.ElemType, &col.TypeMod)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns[col.Name] = col
+		names = append(names, col.Name)
+	}
+	return columns, names, rows.Err()
+}
+
+// primaryKey : the single column primary key of the table, composite primary key need config meta.pk
+func (cli *Client) primaryKey(ctx context.Context, table string, tableName string) (string, error) {
+	rows, err := cli.db.QueryContext(ctx, primaryKeySql, table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var pks []string
+	for rows.Next() {
+		var pk string
+		if err = rows.Scan(&pk); err != nil {
+			return "", err
+		}
+		pks = append(pks, pk)
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+	if len(pks) != 1 {
+		return "", fmt.Errorf("table %s has %d primary key columns, please config a unique column as meta.pk", tableName, len(pks))
+	}
+	return pks[0], nil
+}
+
+// probeDim : the vector column declared without dim, use the dim of the first not null value
+func (cli *Client) probeDim(ctx context.Context, table string, col *pgvectortype.Column) (int, error) {
+	name := pq.QuoteIdentifier(col.Name)
+	expr := "vector_dims(" + name + ")"
+	switch col.TypeName {
+	case pgvectorconvert.SparseVec:
+		//milvus sparse vector not need dim
+		return 0, nil
+	case pgvectorconvert.Bit:
+		expr = "length(" + name + ")"
+	}
+	var dim int
+	err := cli.db.QueryRowContext(ctx, "SELECT "+expr+" FROM "+table+" WHERE "+name+" IS NOT NULL LIMIT 1").Scan(&dim)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%s column has no dim and no value to probe the dim", col.TypeName)
+	}
+	return dim, err
+}
+
+// Count : rows of the table match the filter
+func (cli *Client) Count(ctx context.Context, tableCfg *pgvectortype.TableCfg) (int64, error) {
+	var count int64
+	query := "SELECT count(*) FROM " + QuoteTable(tableCfg)
+	if tableCfg.Filter != "" {
+		query += " WHERE (" + tableCfg.Filter + ")"
+	}
+	err := cli.db.QueryRowContext(ctx, query).Scan(&count)
+	return count, err
+}
+
+// ReadPage : read the next page after lastPK order by pk, nil lastPK means the first page.
+// columns are the select columns, return values of each row in columns order and the pk of the last row
+func (cli *Client) ReadPage(ctx context.Context, tableCfg *pgvectortype.TableCfg, columns []*pgvectortype.Column,
+	lastPK any, size int) ([][]any, any, error) {
+	exprs := make([]string, 0, len(columns)+1)
+	for _, col := range columns {
+		exprs = append(exprs, pgvectorconvert.SelectExpr(col))
+	}
+	pk := pq.QuoteIdentifier(tableCfg.PK)
+	exprs = append(exprs, pk)
+
+	var conds []string
+	var args []any
+	if tableCfg.Filter != "" {
+		conds = append(conds, "("+tableCfg.Filter+")")
+	}
+	if lastPK != nil {
+		args = append(args, lastPK)
+		conds = append(conds, fmt.Sprintf("%s > $%d", pk, len(args)))
+	}
+	query := "SELECT " + strings.Join(exprs, ", ") + " FROM " + QuoteTable(tableCfg)
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, size)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", pk, len(args))
+
+	rows, err := cli.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var result [][]any
+	var nextPK any
+	for rows.Next() {
+		values := make([]any, len(exprs))
+		dest := make([]any, len(exprs))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		nextPK = values[len(values)-1]
+		//text pk read as bytes, bytes param will be sent as bytea
+		if b, ok := nextPK.([]byte); ok {
+			nextPK = string(b)
+		}
+		result = append(result, values[:len(columns)])
+	}
+	return result, nextPK, rows.Err()
+}
+
+// QuoteTable : quoted schema.table
+func QuoteTable(tableCfg *pgvectortype.TableCfg) string {
+	schema, name := tableCfg.SchemaAndName()
+	return pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(name)
+}
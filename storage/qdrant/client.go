@@ -0,0 +1,180 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"github.com/zilliztech/milvus-migration/internal/log"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Client : qdrant rest api client
+type Client struct {
+	url     string
+	apiKey  string
+	httpCli *http.Client
+}
+
+func NewClient(cfg *config.QdrantConfig) (*Client, error) {
+	cli := &Client{
+		url:     cfg.Url,
+		apiKey:  cfg.ApiKey,
+		httpCli: &http.Client{Timeout: cfg.Timeout},
+	}
+	_, err := cli.do(context.Background(), http.MethodGet, "/collections", nil)
+	if err != nil {
+		log.Error("[Qdrant] connect qdrant error", zap.String("url", cfg.Url), zap.Error(err))
+		return nil, err
+	}
+	return cli, nil
+}
+
+// DescribeCollection : read the dense and sparse vectors into collCfg.InnerVectors, detect the pk type if not config
+func (cli *Client) DescribeCollection(ctx context.Context, collCfg *qdranttype.CollectionCfg) error {
+	result, err := cli.do(ctx, http.MethodGet, collectionPath(collCfg.Collection), nil)
+	if err != nil {
+		return fmt.Errorf("describe qdrant collection %s: %w", collCfg.Collection, err)
+	}
+	params := result.Get("config.params")
+	collCfg.InnerVectors, err = ParseVectors(params)
+	if err != nil {
+		return fmt.Errorf("qdrant collection %s: %w", collCfg.Collection, err)
+	}
+	if collCfg.PkType == "" {
+		points, _, err := cli.scroll(ctx, collCfg, "", 1, false)
+		if err != nil {
+			return err
+		}
+		collCfg.PkType = qdranttype.PkTypeInt64
+		if len(points) > 0 && points[0].Get("id").Type == gjson.String {
+			collCfg.PkType = qdranttype.PkTypeVarChar
+		}
+	}
+	log.LL(ctx).Info("[Qdrant] describe collection", zap.String("collection", collCfg.Collection),
+		zap.String("pkType", collCfg.PkType), zap.Int("vectors", len(collCfg.InnerVectors)))
+	return nil
+}
+
+// ParseVectors : config.params of the collection info, vectors is a single unnamed vector config or named vectors map
+func ParseVectors(params gjson.Result) ([]*qdranttype.Vector, error) {
+	var vectors []*qdranttype.Vector
+	var err error
+	toVector := func(name string, cfg gjson.Result) *qdranttype.Vector {
+		if cfg.Get("multivector_config").IsObject() {
+			err = fmt.Errorf("vector %s is multivector, not support", name)
+		}
+		return &qdranttype.Vector{
+			Name:     name,
+			Size:     int(cfg.Get("size").Int()),
+			Distance: cfg.Get("distance").String(),
+		}
+	}
+	dense := params.Get("vectors")
+	if dense.Get("size").Exists() {
+		vectors = append(vectors, toVector("", dense))
+	} else {
+		dense.ForEach(func(key, value gjson.Result) bool {
+			vectors = append(vectors, toVector(key.String(), value))
+			return err == nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	params.Get("sparse_vectors").ForEach(func(key, value gjson.Result) bool {
+		vectors = append(vectors, &qdranttype.Vector{Name: key.String(), Sparse: true})
+		return true
+	})
+	return vectors, nil
+}
+
+// Count : exact points count of the collection match the filter
+func (cli *Client) Count(ctx context.Context, collCfg *qdranttype.CollectionCfg) (int64, error) {
+	body := map[string]any{"exact": true}
+	if collCfg.Filter != "" {
+		body["filter"] = json.RawMessage(collCfg.Filter)
+	}
+	result, err := cli.do(ctx, http.MethodPost, collectionPath(collCfg.Collection)+"/points/count", body)
+	if err != nil {
+		return 0, err
+	}
+	return result.Get("count").Int(), nil
+}
+
+// Scroll : read the next page from offset with payload and vectors, empty offset means the first page.
+// offset is the raw json of the next_page_offset, return empty next offset when no more points
+func (cli *Client) Scroll(ctx context.Context, collCfg *qdranttype.CollectionCfg, offset string, limit int) ([]gjson.Result, string, error) {
+	return cli.scroll(ctx, collCfg, offset, limit, true)
+}
+
+func (cli *Client) scroll(ctx context.Context, collCfg *qdranttype.CollectionCfg, offset string, limit int,
+	withData bool) ([]gjson.Result, string, error) {
+	body := map[string]any{
+		"limit":        limit,
+		"with_payload": withData,
+		"with_vector":  withData,
+	}
+	if offset != "" {
+		body["offset"] = json.RawMessage(offset)
+	}
+	if collCfg.Filter != "" {
+		body["filter"] = json.RawMessage(collCfg.Filter)
+	}
+	result, err := cli.do(ctx, http.MethodPost, collectionPath(collCfg.Collection)+"/points/scroll", body)
+	if err != nil {
+		return nil, "", err
+	}
+	next := result.Get("next_page_offset")
+	if !next.Exists() || next.Type == gjson.Null {
+		return result.Get("points").Array(), "", nil
+	}
+	return result.Get("points").Array(), next.Raw, nil
+}
+
+// do : send the request and return the result of the response
+func (cli *Client) do(ctx context.Context, method string, path string, body any) (gjson.Result, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return gjson.Result{}, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cli.url+path, reader)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cli.apiKey != "" {
+		req.Header.Set("api-key", cli.apiKey)
+	}
+	resp, err := cli.httpCli.Do(req)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg := gjson.GetBytes(data, "status.error").String()
+		if msg == "" {
+			msg = string(data)
+		}
+		return gjson.Result{}, fmt.Errorf("qdrant %s %s status %d: %s", method, path, resp.StatusCode, msg)
+	}
+	return gjson.GetBytes(data, "result"), nil
+}
+
+func collectionPath(collection string) string {
+	return "/collections/" + url.PathEscape(collection)
+}
@@ -0,0 +1,96 @@
+package qdrant
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"github.com/zilliztech/milvus-migration/core/config"
+	"github.com/zilliztech/milvus-migration/core/type/qdranttype"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const collectionInfo = `{"result":{"points_count":3,"config":{"params":{
+	"vectors":{"image":{"size":4,"distance":"Dot"},"text":{"size":2,"distance":"Cosine"}},
+	"sparse_vectors":{"keywords":{}}}}},"status":"ok"}`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"status":{"error":"Invalid api-key"}}`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /collections":
+			io.WriteString(w, `{"result":{"collections":[{"name":"items"}]},"status":"ok"}`)
+		case "GET /collections/items":
+			io.WriteString(w, collectionInfo)
+		case "POST /collections/items/points/count":
+			assert.True(t, gjson.GetBytes(body, "exact").Bool())
+			io.WriteString(w, `{"result":{"count":3},"status":"ok"}`)
+		case "POST /collections/items/points/scroll":
+			if !gjson.GetBytes(body, "offset").Exists() {
+				io.WriteString(w, `{"result":{"points":[
+					{"id":"3f0c7d6a-1c1e-4a4f-9d0a-5d3c2e1b0a01","payload":{"a":1},"vector":{"image":[1,2,3,4]}},
+					{"id":"3f0c7d6a-1c1e-4a4f-9d0a-5d3c2e1b0a02","payload":{"a":2},"vector":{"image":[1,2,3,4]}}],
+					"next_page_offset":"3f0c7d6a-1c1e-4a4f-9d0a-5d3c2e1b0a03"},"status":"ok"}`)
+				return
+			}
+			assert.Equal(t, "3f0c7d6a-1c1e-4a4f-9d0a-5d3c2e1b0a03", gjson.GetBytes(body, "offset").String())
+			io.WriteString(w, `{"result":{"points":[
+				{"id":"3f0c7d6a-1c1e-4a4f-9d0a-5d3c2e1b0a03","payload":{"a":3},"vector":{"image":[1,2,3,4]}}],
+				"next_page_offset":null},"status":"ok"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"status":{"error":"Not found"}}`)
+		}
+	}))
+}
+
+func TestClient(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	_, err := NewClient(&config.QdrantConfig{Url: srv.URL, ApiKey: "wrong", Timeout: time.Second})
+	assert.ErrorContains(t, err, "Invalid api-key")
+
+	cli, err := NewClient(&config.QdrantConfig{Url: srv.URL, ApiKey: "secret", Timeout: time.Second})
+	assert.NoError(t, err)
+	ctx := context.Background()
+	collCfg := &qdranttype.CollectionCfg{Collection: "items"}
+	assert.NoError(t, cli.DescribeCollection(ctx, collCfg))
+	assert.Equal(t, qdranttype.PkTypeVarChar, collCfg.PkType)
+	assert.Equal(t, []*qdranttype.Vector{
+		{Name: "image", Size: 4, Distance: "Dot"},
+		{Name: "text", Size: 2, Distance: "Cosine"},
+		{Name: "keywords", Sparse: true},
+	}, collCfg.InnerVectors)
+
+	count, err := cli.Count(ctx, collCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	points, next, err := cli.Scroll(ctx, collCfg, "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(points))
+	points, next, err = cli.Scroll(ctx, collCfg, next, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(points))
+	assert.Equal(t, "", next)
+
+	assert.Error(t, cli.DescribeCollection(ctx, &qdranttype.CollectionCfg{Collection: "missing"}))
+}
+
+func TestParseVectors(t *testing.T) {
+	vectors, err := ParseVectors(gjson.Parse(`{"vectors":{"size":8,"distance":"Euclid"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*qdranttype.Vector{{Size: 8, Distance: "Euclid"}}, vectors)
+
+	_, err = ParseVectors(gjson.Parse(`{"vectors":{"colbert":{"size":8,"distance":"Dot","multivector_config":{"comparator":"max_sim"}}}}`))
+	assert.Error(t, err)
+}